@@ -92,6 +92,47 @@ func (o *Accounting) AuthJwtHs256Ref() *corev1.SecretKeySelector {
 	}
 }
 
+// AuthJwtHs256NewRef returns the key which the JWT HS256 key is being rotated to, if any.
+func (o *Accounting) AuthJwtHs256NewRef() *corev1.SecretKeySelector {
+	if o.Spec.JwtHs256KeyRotation == nil {
+		return nil
+	}
+	ref := o.Spec.JwtHs256KeyRotation.NewKeyRef
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: ref.Name,
+		},
+		Key: ref.Key,
+	}
+}
+
+// AuthJwtRs256Ref returns the JWT RS256 private key, if any.
+func (o *Accounting) AuthJwtRs256Ref() *corev1.SecretKeySelector {
+	if o.Spec.JwtRs256KeyRef == nil {
+		return nil
+	}
+	ref := o.Spec.JwtRs256KeyRef
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: ref.Name,
+		},
+		Key: ref.Key,
+	}
+}
+
+// HasJwks returns true if Slurm verifies JWTs with a JWKS, which is when an
+// RS256 key is used. Slurm only loads RS256 keys from a JWKS.
+func (o *Accounting) HasJwks() bool {
+	return o.AuthJwtRs256Ref() != nil
+}
+
+func (o *Accounting) JwksKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-jwks", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
 func (o *Accounting) ConfigKey() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      fmt.Sprintf("%s-accounting", o.Name),
@@ -15,6 +15,14 @@ const (
 	AccountingKind = "Accounting"
 )
 
+// Accounting condition types and reasons.
+const (
+	// AccountingConditionOidcJwksReady reports whether the JWKS of the OIDC
+	// issuers of the RestApis of the Controllers of the Accounting were last
+	// fetched successfully. It uses the OidcJwksReason reasons.
+	AccountingConditionOidcJwksReady = "OidcJwksReady"
+)
+
 var (
 	AccountingGVK        = GroupVersion.WithKind(AccountingKind)
 	AccountingAPIVersion = GroupVersion.String()
@@ -33,6 +41,18 @@ type AccountingSpec struct {
 	// +required
 	JwtHs256KeyRef corev1.SecretKeySelector `json:"jwtHs256KeyRef,omitzero"`
 
+	// JwtHs256KeyRotation starts a rotation of the JWT HS256 key.
+	// Once complete, JwtHs256KeyRef can be changed to the new key.
+	// +optional
+	JwtHs256KeyRotation *JwtKeyRotation `json:"jwtHs256KeyRotation,omitempty"`
+
+	// Slurm `auth/jwt` JWT RS256 key authentication, in addition to HS256.
+	// Slurm is configured with a JWKS of only the public key, so the private
+	// key is never mounted into Slurm pods. This should reference the same key
+	// as the Controller, which generates it.
+	// +optional
+	JwtRs256KeyRef *corev1.SecretKeySelector `json:"jwtRs256KeyRef,omitempty"`
+
 	// The slurmdbd container configuration.
 	// See corev1.Container spec.
 	// Ref: https://github.com/kubernetes/api/blob/master/core/v1/types.go#L2885
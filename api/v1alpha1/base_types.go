@@ -52,6 +52,31 @@ type JwtSecretKeySelector struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// JwtKeyRotation describes a rotation of a Slurm `auth/jwt` key.
+type JwtKeyRotation struct {
+	// NewKeyRef is the key which will replace the current key. While set, JWTs
+	// are reissued with the RS256 key, then Slurm is restarted to verify with it.
+	// +required
+	NewKeyRef corev1.SecretKeySelector `json:"newKeyRef,omitzero"`
+}
+
+// SlurmKeyRotation describes a rotation of the Slurm `auth/slurm` key.
+type SlurmKeyRotation struct {
+	// NewKeyRef is the key which will replace the current key. While set, both
+	// keys are accepted by Slurm, published as a JWKS.
+	// +required
+	NewKeyRef corev1.SecretKeySelector `json:"newKeyRef,omitzero"`
+
+	// ProgressDeadlineSeconds is the maximum time for all Slurm pods to be
+	// rolled out in each phase, after which the rotation is rolled back.
+	// NodeSet pods are replaced as their nodes drain, so this should allow for
+	// the longest running jobs. Zero disables the deadline.
+	// +optional
+	// +kubebuilder:default:=86400
+	// +kubebuilder:validation:Minimum=0
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
 // PodTemplate describes a template for creating copies of a predefined pod.
 type PodTemplate struct {
 	// Standard object's metadata.
@@ -5,9 +5,11 @@ package v1alpha1
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/SlinkyProject/slurm-operator/internal/utils/domainname"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -18,6 +20,25 @@ func (o *Controller) ClusterName() string {
 	return fmt.Sprintf("%s_%s", o.Namespace, o.Name)
 }
 
+// IsExternal returns true if slurmctld runs outside of Kubernetes.
+func (o *Controller) IsExternal() bool {
+	return o.Spec.External != nil
+}
+
+// ExternalSlurmrestd returns the slurmrestd of the external cluster, if any.
+func (o *Controller) ExternalSlurmrestd() *ExternalSlurmrestd {
+	if o.Spec.External == nil {
+		return nil
+	}
+	return o.Spec.External.Slurmrestd
+}
+
+// IsNamespaceAllowed returns true if NodeSets and LoginSets in the namespace
+// may reference the Controller.
+func (o *Controller) IsNamespaceAllowed(namespace string) bool {
+	return namespace == o.Namespace || slices.Contains(o.Spec.AllowedNamespaces, namespace)
+}
+
 func (o *Controller) Key() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      fmt.Sprintf("%s-controller", o.Name),
@@ -48,6 +69,20 @@ func (o *Controller) ServiceFQDNShort() string {
 	return domainname.FqdnShort(s.Name, s.Namespace)
 }
 
+// InternalRestapiServiceKey is the Service of the internal slurmrestd sidecar.
+func (o *Controller) InternalRestapiServiceKey() types.NamespacedName {
+	key := o.Key()
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-slurmrestd", key.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+func (o *Controller) InternalRestapiServiceFQDNShort() string {
+	s := o.InternalRestapiServiceKey()
+	return domainname.FqdnShort(s.Name, s.Namespace)
+}
+
 func (o *Controller) AuthSlurmKey() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      o.Spec.SlurmKeyRef.Name,
@@ -65,6 +100,60 @@ func (o *Controller) AuthSlurmRef() *corev1.SecretKeySelector {
 	}
 }
 
+// AuthSlurmNewRef returns the key which the Slurm key is being rotated to, if any.
+func (o *Controller) AuthSlurmNewRef() *corev1.SecretKeySelector {
+	if o.Spec.SlurmKeyRotation == nil {
+		return nil
+	}
+	ref := o.Spec.SlurmKeyRotation.NewKeyRef
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: ref.Name,
+		},
+		Key: ref.Key,
+	}
+}
+
+// HasSlurmJwks returns true if Slurm pods are configured with the Slurm JWKS,
+// which holds both keys during a Slurm key rotation until it is rolled back.
+func (o *Controller) HasSlurmJwks() bool {
+	if o.AuthSlurmNewRef() == nil {
+		return false
+	}
+	cond := meta.FindStatusCondition(o.Status.Conditions, ControllerConditionSlurmKeyRotation)
+	if cond == nil {
+		return true
+	}
+	switch cond.Reason {
+	case SlurmKeyRotationReasonRollingBack, SlurmKeyRotationReasonRolledBack:
+		return false
+	default:
+		return true
+	}
+}
+
+// AuthSlurmSigningRef returns the Slurm key which daemons should sign with.
+// During a key rotation, this is the new key once every daemon accepts it.
+func (o *Controller) AuthSlurmSigningRef() *corev1.SecretKeySelector {
+	cond := meta.FindStatusCondition(o.Status.Conditions, ControllerConditionSlurmKeyRotation)
+	if o.Spec.SlurmKeyRotation == nil || cond == nil {
+		return o.AuthSlurmRef()
+	}
+	switch cond.Reason {
+	case SlurmKeyRotationReasonSwitching, SlurmKeyRotationReasonReadyToRetire:
+		return o.AuthSlurmNewRef()
+	default:
+		return o.AuthSlurmRef()
+	}
+}
+
+func (o *Controller) SlurmJwksKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-slurm-jwks", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
 func (o *Controller) AuthJwtHs256Key() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      o.Spec.JwtHs256KeyRef.Name,
@@ -82,9 +171,151 @@ func (o *Controller) AuthJwtHs256Ref() *corev1.SecretKeySelector {
 	}
 }
 
+// AuthJwtHs256NewRef returns the key which the JWT HS256 key is being rotated to, if any.
+func (o *Controller) AuthJwtHs256NewRef() *corev1.SecretKeySelector {
+	if o.Spec.JwtHs256KeyRotation == nil {
+		return nil
+	}
+	ref := o.Spec.JwtHs256KeyRotation.NewKeyRef
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: ref.Name,
+		},
+		Key: ref.Key,
+	}
+}
+
+// AuthJwtHs256SigningRef returns the key which Tokens of the JWT HS256 key
+// should be signed with. During a key rotation, this is the RS256 key until
+// Slurm verifies with the new key, as Slurm accepts it along with either HS256
+// key, then the new key.
+func (o *Controller) AuthJwtHs256SigningRef() *corev1.SecretKeySelector {
+	cond := meta.FindStatusCondition(o.Status.Conditions, ControllerConditionJwtHs256KeyRotation)
+	if o.Spec.JwtHs256KeyRotation == nil || cond == nil {
+		return o.AuthJwtHs256Ref()
+	}
+	switch cond.Reason {
+	case JwtKeyRotationReasonReissuingTokens, JwtKeyRotationReasonRollingOut:
+		if ref := o.AuthJwtRs256Ref(); ref != nil {
+			return ref
+		}
+		return o.AuthJwtHs256NewRef()
+	case JwtKeyRotationReasonReadyToRetire:
+		return o.AuthJwtHs256NewRef()
+	default:
+		return o.AuthJwtHs256Ref()
+	}
+}
+
+// AuthJwtHs256VerifyingRef returns the JWT HS256 key which Slurm verifies JWTs
+// with, as its `jwt_key`. Slurm only loads a single HS256 key, so during a key
+// rotation this is the new key once Tokens were reissued with the RS256 key.
+func (o *Controller) AuthJwtHs256VerifyingRef() *corev1.SecretKeySelector {
+	cond := meta.FindStatusCondition(o.Status.Conditions, ControllerConditionJwtHs256KeyRotation)
+	if o.Spec.JwtHs256KeyRotation == nil || cond == nil {
+		return o.AuthJwtHs256Ref()
+	}
+	switch cond.Reason {
+	case JwtKeyRotationReasonRollingOut, JwtKeyRotationReasonReadyToRetire:
+		return o.AuthJwtHs256NewRef()
+	default:
+		return o.AuthJwtHs256Ref()
+	}
+}
+
+// AuthJwtRs256Ref returns the JWT RS256 private key, if any.
+func (o *Controller) AuthJwtRs256Ref() *corev1.SecretKeySelector {
+	if o.Spec.JwtRs256KeyRef == nil {
+		return nil
+	}
+	ref := o.Spec.JwtRs256KeyRef
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: ref.Name,
+		},
+		Key: ref.Key,
+	}
+}
+
+// HasJwks returns true if Slurm verifies JWTs with a JWKS, which is when an
+// RS256 key is used. Slurm only loads RS256 keys from a JWKS.
+func (o *Controller) HasJwks() bool {
+	return o.AuthJwtRs256Ref() != nil
+}
+
+// AuthJwtSigningRef returns the key which the JWTs of the operator should be
+// signed with. This is the RS256 key if any, otherwise the HS256 key which
+// Slurm currently verifies with.
+func (o *Controller) AuthJwtSigningRef() *corev1.SecretKeySelector {
+	if ref := o.AuthJwtRs256Ref(); ref != nil {
+		return ref
+	}
+	return o.AuthJwtHs256VerifyingRef()
+}
+
+func (o *Controller) JwksKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-jwks", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// UsersKey is the ConfigMap of the users provisioned by the LoginSets of the
+// controller.
+func (o *Controller) UsersKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-users", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// TenantAuthKey is the Secret of the Slurm key, copied into another namespace
+// of NodeSets or LoginSets of the controller.
+func (o *Controller) TenantAuthKey(namespace string) types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s-auth-slurm", o.Namespace, o.Name),
+		Namespace: namespace,
+	}
+}
+
+// TenantUsersKey is the ConfigMap of the users, copied into another namespace
+// of NodeSets or LoginSets of the controller.
+func (o *Controller) TenantUsersKey(namespace string) types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s-users", o.Namespace, o.Name),
+		Namespace: namespace,
+	}
+}
+
 func (o *Controller) ConfigKey() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      fmt.Sprintf("%s-config", o.Name),
 		Namespace: o.Namespace,
 	}
 }
+
+// SharedVolumeClaimKey is the PersistentVolumeClaim of the shared volume, for
+// a pod in the namespace. Pods can only mount claims in their own namespace.
+func (o *Controller) SharedVolumeClaimKey(volume *SharedVolume, namespace string) types.NamespacedName {
+	if volume.ExistingClaim != "" {
+		return types.NamespacedName{
+			Name:      volume.ExistingClaim,
+			Namespace: namespace,
+		}
+	}
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s", o.Name, volume.Name),
+		Namespace: namespace,
+	}
+}
+
+// SharedVolumesFor returns the shared volumes mounted by the component.
+func (o *Controller) SharedVolumesFor(component SharedVolumeComponent) []SharedVolume {
+	out := []SharedVolume{}
+	for _, volume := range o.Spec.SharedVolumes {
+		if len(volume.Components) == 0 || slices.Contains(volume.Components, component) {
+			out = append(out, volume)
+		}
+	}
+	return out
+}
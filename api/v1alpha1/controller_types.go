@@ -20,6 +20,51 @@ var (
 	ControllerAPIVersion = GroupVersion.String()
 )
 
+// Controller condition types and reasons.
+const (
+	// ControllerConditionJwtHs256KeyRotation reports the progress of a JWT HS256 key rotation.
+	ControllerConditionJwtHs256KeyRotation = "JwtHs256KeyRotation"
+
+	// JwtKeyRotationReasonReissuingTokens indicates JWTs are being reissued with the RS256 key.
+	JwtKeyRotationReasonReissuingTokens = "ReissuingTokens"
+	// JwtKeyRotationReasonRollingOut indicates Slurm is being rolled out to verify with the new key.
+	JwtKeyRotationReasonRollingOut = "RollingOut"
+	// JwtKeyRotationReasonReadyToRetire indicates the old key is no longer used and can be retired.
+	JwtKeyRotationReasonReadyToRetire = "ReadyToRetire"
+
+	// ControllerConditionSlurmKeyRotation reports the progress of a Slurm `auth/slurm` key rotation.
+	ControllerConditionSlurmKeyRotation = "SlurmKeyRotation"
+
+	// SlurmKeyRotationReasonDistributing indicates all Slurm pods are being rolled out to accept both keys.
+	SlurmKeyRotationReasonDistributing = "Distributing"
+	// SlurmKeyRotationReasonSwitching indicates all Slurm pods are being rolled out to sign with the new key.
+	SlurmKeyRotationReasonSwitching = "Switching"
+	// SlurmKeyRotationReasonReadyToRetire indicates the old key is no longer used and can be retired.
+	SlurmKeyRotationReasonReadyToRetire = "ReadyToRetire"
+	// SlurmKeyRotationReasonSwitchingBack indicates the Switching phase missed its deadline and all
+	// Slurm pods are being rolled out to sign with the current key again, still accepting the new key.
+	SlurmKeyRotationReasonSwitchingBack = "SwitchingBack"
+	// SlurmKeyRotationReasonRollingBack indicates a phase missed its deadline and all Slurm pods are
+	// being rolled out with only the current key again.
+	SlurmKeyRotationReasonRollingBack = "RollingBack"
+	// SlurmKeyRotationReasonRolledBack indicates the rotation was rolled back and can be removed.
+	SlurmKeyRotationReasonRolledBack = "RolledBack"
+
+	// ControllerConditionSlurmClientReady reports whether the operator has a
+	// Slurm client for the Controller. It uses the SlurmClientReason reasons.
+	ControllerConditionSlurmClientReady = "SlurmClientReady"
+
+	// ControllerConditionOidcJwksReady reports whether the JWKS of the OIDC
+	// issuers of the RestApis of the Controller were last fetched successfully.
+	ControllerConditionOidcJwksReady = "OidcJwksReady"
+
+	// OidcJwksReasonFetched indicates the JWKS of all OIDC issuers were fetched.
+	OidcJwksReasonFetched = "Fetched"
+	// OidcJwksReasonFetchFailed indicates the JWKS of an OIDC issuer could not be
+	// fetched, and its last fetched JWKS, if any, is still used.
+	OidcJwksReasonFetchFailed = "FetchFailed"
+)
+
 // ControllerSpec defines the desired state of Controller
 type ControllerSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -31,14 +76,38 @@ type ControllerSpec struct {
 	// +optional
 	ClusterName string `json:"clusterName,omitzero"`
 
+	// External is a slurmctld which runs outside of Kubernetes (e.g. an
+	// on-prem cluster). The operator does not deploy slurmctld, nor render
+	// its `slurm.conf`, and the NodeSets, LoginSets, and RestApis of the
+	// Controller use the external slurmctld instead.
+	// +optional
+	External *ExternalController `json:"external,omitempty"`
+
 	// Slurm `auth/slurm` key authentication.
 	// +required
 	SlurmKeyRef corev1.SecretKeySelector `json:"slurmKeyRef,omitzero"`
 
+	// SlurmKeyRotation starts a rotation of the Slurm `auth/slurm` key.
+	// Once complete, SlurmKeyRef can be changed to the new key.
+	// +optional
+	SlurmKeyRotation *SlurmKeyRotation `json:"slurmKeyRotation,omitempty"`
+
 	// Slurm `auth/jwt` JWT HS256 key authentication.
 	// +required
 	JwtHs256KeyRef corev1.SecretKeySelector `json:"jwtHs256KeyRef,omitzero"`
 
+	// JwtHs256KeyRotation starts a rotation of the JWT HS256 key.
+	// Once complete, JwtHs256KeyRef can be changed to the new key.
+	// +optional
+	JwtHs256KeyRotation *JwtKeyRotation `json:"jwtHs256KeyRotation,omitempty"`
+
+	// Slurm `auth/jwt` JWT RS256 key authentication, in addition to HS256.
+	// Slurm is configured with a JWKS of only the public key, so the private
+	// key is never mounted into Slurm pods. If the Secret does not exist, the
+	// Controller generates an RSA key pair into it.
+	// +optional
+	JwtRs256KeyRef *corev1.SecretKeySelector `json:"jwtRs256KeyRef,omitempty"`
+
 	// accountingRef is a reference to the Accounting CR to which this has membership.
 	// +optional
 	AccountingRef ObjectReference `json:"accountingRef"`
@@ -57,6 +126,11 @@ type ControllerSpec struct {
 	// +optional
 	LogFile ContainerMinimal `json:"logfile,omitzero"`
 
+	// InternalSlurmrestd runs slurmrestd as a sidecar of slurmctld, which the
+	// operator uses to manage the cluster when no RestApi is ready.
+	// +optional
+	InternalSlurmrestd *ContainerMinimal `json:"internalSlurmrestd,omitempty"`
+
 	// Template is the object that describes the pod that will be created if
 	// insufficient replicas are detected.
 	// More info: https://kubernetes.io/docs/concepts/workloads/controllers/replicationcontroller#pod-template
@@ -101,6 +175,101 @@ type ControllerSpec struct {
 	// Service defines a template for a Kubernetes Service object.
 	// +optional
 	Service ServiceSpec `json:"service,omitzero"`
+
+	// SharedVolumes is a list of volumes mounted in the login and worker pods
+	// of the cluster, (e.g. `/home`, `/scratch`).
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SharedVolumes []SharedVolume `json:"sharedVolumes,omitempty"`
+
+	// AllowedNamespaces is the list of other namespaces whose NodeSets and
+	// LoginSets may reference this Controller. The Slurm key, and the users
+	// of its LoginSets, are copied into those namespaces. NodeSets and
+	// LoginSets in any other namespace are ignored.
+	// +optional
+	// +listType=set
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// ExternalController is a slurmctld which runs outside of Kubernetes.
+type ExternalController struct {
+	// Host is the hostname or IP address of slurmctld, which must be
+	// reachable from the pods of the Controller.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port is the port of slurmctld.
+	// +optional
+	// +default:=6817
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// Slurmrestd is the slurmrestd of the external cluster, which the operator
+	// uses to manage the Slurm nodes of NodeSets.
+	// +optional
+	Slurmrestd *ExternalSlurmrestd `json:"slurmrestd,omitempty"`
+}
+
+// ExternalSlurmrestd is a slurmrestd which runs outside of Kubernetes.
+type ExternalSlurmrestd struct {
+	// URL is the URL of slurmrestd (e.g. `https://slurm.example.com:6820`).
+	// +required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// TokenRef is a JWT which the operator authenticates to slurmrestd with,
+	// instead of JWTs signed with the JwtHs256KeyRef. It is read again
+	// periodically, so it can be rotated in place.
+	// +optional
+	TokenRef *corev1.SecretKeySelector `json:"tokenRef,omitempty"`
+}
+
+// SharedVolumeComponent is a component of the cluster which mounts a shared volume.
+// +kubebuilder:validation:Enum=login;worker
+type SharedVolumeComponent string
+
+const (
+	SharedVolumeComponentLogin  SharedVolumeComponent = "login"
+	SharedVolumeComponentWorker SharedVolumeComponent = "worker"
+)
+
+// SharedVolume is a volume mounted in several components of the cluster.
+type SharedVolume struct {
+	// Name is the name of the shared volume.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=48
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// MountPath is the absolute path where the volume is mounted.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// ReadOnly mounts the volume read-only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// ExistingClaim is the name of an existing `PersistentVolumeClaim` to use.
+	// +optional
+	ExistingClaim string `json:"existingClaim,omitempty"`
+
+	// ClaimTemplate is the spec of a `PersistentVolumeClaim` created for the
+	// volume, named `<controller>-<name>`. The claim is not deleted with the
+	// Controller. The access modes should allow the volume to be mounted by
+	// many nodes (e.g. `ReadWriteMany`).
+	// +optional
+	ClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"claimTemplate,omitempty"`
+
+	// Components is the list of components which mount the volume.
+	// Defaults to all components.
+	// +optional
+	// +listType=set
+	Components []SharedVolumeComponent `json:"components,omitempty"`
 }
 
 type ControllerPersistence struct {
@@ -122,6 +291,10 @@ type ControllerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Federation is the Federation which the cluster is a member of.
+	// +optional
+	Federation *ObjectReference `json:"federation,omitempty"`
+
 	// Represents the latest available observations of a Controller's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (o *Federation) Key() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      o.Name,
+		Namespace: o.Namespace,
+	}
+}
+
+// FederationName is the name of the federation in slurmdbd.
+func (o *Federation) FederationName() string {
+	return fmt.Sprintf("%s_%s", o.Namespace, o.Name)
+}
+
+// RegistrationKey is the Job which registers the federation in slurmdbd.
+func (o *Federation) RegistrationKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-federation", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// RemovalKey is the Job which removes the federation from slurmdbd, when the
+// Federation is deleted.
+func (o *Federation) RemovalKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-federation-remove", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// HasController returns whether the Controller is a member cluster.
+func (o *Federation) HasController(key types.NamespacedName) bool {
+	for _, cluster := range o.Spec.Clusters {
+		if cluster.ControllerRef.IsMatch(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	FederationKind = "Federation"
+)
+
+var (
+	FederationGVK        = GroupVersion.WithKind(FederationKind)
+	FederationAPIVersion = GroupVersion.String()
+)
+
+// FederationFinalizer holds the deletion of a Federation until it is removed
+// from slurmdbd.
+const FederationFinalizer = SlinkyPrefix + "federation"
+
+// Federation condition types and reasons.
+const (
+	// FederationConditionRegistered reports whether the federation, and its
+	// member clusters, are registered in slurmdbd.
+	FederationConditionRegistered = "Registered"
+
+	// FederationReasonRegistered indicates the federation is registered in slurmdbd.
+	FederationReasonRegistered = "Registered"
+	// FederationReasonPending indicates the federation is being registered in slurmdbd.
+	FederationReasonPending = "Pending"
+	// FederationReasonFailed indicates the registration of the federation failed.
+	FederationReasonFailed = "Failed"
+	// FederationReasonInvalidMember indicates a member Controller is missing, or
+	// does not use the Accounting of the federation.
+	FederationReasonInvalidMember = "InvalidMember"
+)
+
+// FederationSpec defines the desired state of Federation
+type FederationSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// AccountingRef is a reference to the Accounting shared by all member
+	// clusters, which stores the federation.
+	// +required
+	AccountingRef ObjectReference `json:"accountingRef"`
+
+	// Clusters are the member clusters of the federation.
+	// Ref: https://slurm.schedmd.com/federation.html
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	// +required
+	Clusters []FederationCluster `json:"clusters"`
+
+	// Parameters are the `FederationParameters` of the member clusters.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_FederationParameters
+	// +optional
+	// +listType=set
+	Parameters []string `json:"parameters,omitempty"`
+
+	// Registration is the container which registers the federation in
+	// slurmdbd, with `sacctmgr`. Defaults to the slurmctld image of the first
+	// member cluster.
+	// +optional
+	Registration ContainerMinimal `json:"registration,omitempty"`
+}
+
+// FederationCluster is a member cluster of a Federation.
+type FederationCluster struct {
+	// ControllerRef is a reference to the Controller of the cluster.
+	// +required
+	ControllerRef ObjectReference `json:"controllerRef"`
+
+	// Features of the cluster, which jobs can request with `--cluster-constraint`.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Features
+	// +optional
+	// +listType=set
+	Features []string `json:"features,omitempty"`
+}
+
+// FederationStatus defines the observed state of Federation
+type FederationStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Clusters are the names of the member clusters, as registered in slurmdbd.
+	// +optional
+	// +listType=atomic
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Represents the latest available observations of a Federation's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=fed
+// +kubebuilder:printcolumn:name="CLUSTERS",type="string",JSONPath=".status.clusters",description="The registered member clusters."
+// +kubebuilder:printcolumn:name="REGISTERED",type="string",JSONPath=".status.conditions[?(@.type==\"Registered\")].status",description="Whether the federation is registered in slurmdbd."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Federation is the Schema for the federations API
+type Federation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FederationSpec   `json:"spec,omitempty"`
+	Status FederationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FederationList contains a list of Federation
+type FederationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Federation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Federation{}, &FederationList{})
+}
@@ -5,6 +5,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/SlinkyProject/slurm-operator/internal/utils/domainname"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +37,11 @@ func (o *LoginSet) ServiceFQDNShort() string {
 	return domainname.FqdnShort(s.Name, s.Namespace)
 }
 
+// HasSssd returns true if SSSD is configured.
+func (o *LoginSet) HasSssd() bool {
+	return o.Spec.SssdConfRef.Name != ""
+}
+
 func (o *LoginSet) SssdSecretKey() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      o.Spec.SssdConfRef.Name,
@@ -67,3 +73,182 @@ func (o *LoginSet) SshHostKeys() types.NamespacedName {
 		Namespace: o.Namespace,
 	}
 }
+
+const (
+	// SshUserCaKeyFile is the key of the user CA private key, in the secret of
+	// the SSH CAs generated by the operator.
+	SshUserCaKeyFile = "ssh_user_ca_key"
+	// SshUserCaPublicKeyFile is the key of the user CA public key, in the
+	// secret of the SSH CAs generated by the operator.
+	SshUserCaPublicKeyFile = SshUserCaKeyFile + ".pub"
+	// SshHostCaKeyFile is the key of the host CA private key, in the secret of
+	// the SSH CAs generated by the operator.
+	SshHostCaKeyFile = "ssh_host_ca_key"
+)
+
+// HasSshCa returns true if sshd trusts user certificates signed by a user CA.
+func (o *LoginSet) HasSshCa() bool {
+	return o.Spec.SshCertificateAuthority != nil
+}
+
+// HasSshHostCertificates returns true if the SSH host keys are signed by a host CA.
+func (o *LoginSet) HasSshHostCertificates() bool {
+	return o.HasSshCa() && o.Spec.SshCertificateAuthority.SignHostKeys
+}
+
+// GeneratesSshCa returns true if the operator generates any SSH CA, because it
+// is not referenced.
+func (o *LoginSet) GeneratesSshCa() bool {
+	if !o.HasSshCa() {
+		return false
+	}
+	ca := o.Spec.SshCertificateAuthority
+	return ca.UserCaPublicKeyRef == nil || (ca.SignHostKeys && ca.HostCaKeyRef == nil)
+}
+
+// SshCaKey is the secret of the SSH CAs generated by the operator.
+func (o *LoginSet) SshCaKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-ssh-ca", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// SshUserCaPublicKeyRef returns the user CA public key, if any.
+func (o *LoginSet) SshUserCaPublicKeyRef() *corev1.SecretKeySelector {
+	if !o.HasSshCa() {
+		return nil
+	}
+	if ref := o.Spec.SshCertificateAuthority.UserCaPublicKeyRef; ref != nil {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: ref.Name,
+			},
+			Key: ref.Key,
+		}
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: o.SshCaKey().Name,
+		},
+		Key: SshUserCaPublicKeyFile,
+	}
+}
+
+// SshHostCaRef returns the host CA private key, if any.
+func (o *LoginSet) SshHostCaRef() *corev1.SecretKeySelector {
+	if !o.HasSshHostCertificates() {
+		return nil
+	}
+	if ref := o.Spec.SshCertificateAuthority.HostCaKeyRef; ref != nil {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: ref.Name,
+			},
+			Key: ref.Key,
+		}
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: o.SshCaKey().Name,
+		},
+		Key: SshHostCaKeyFile,
+	}
+}
+
+// SshHostCertificatesKey is the secret of the host certificates of the SSH
+// host keys.
+func (o *LoginSet) SshHostCertificatesKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-ssh-host-certs", o.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// SshHostCertificatePrincipals returns the host names which the host
+// certificates are valid for, which are the names of the LoginSet service.
+func (o *LoginSet) SshHostCertificatePrincipals() []string {
+	s := o.ServiceKey()
+	return []string{
+		s.Name,
+		o.ServiceFQDNShort(),
+		o.ServiceFQDN(),
+	}
+}
+
+// HasAutoscaling returns true if the replicas are scaled on the load of the
+// login pods.
+func (o *LoginSet) HasAutoscaling() bool {
+	return o.Spec.Autoscaling != nil
+}
+
+// TracksSessions returns true if the SSH sessions of all login pods are
+// counted.
+func (o *LoginSet) TracksSessions() bool {
+	return o.HasSessionDrain() || (o.HasAutoscaling() && o.Spec.Autoscaling.TargetSessionsPerPod != nil)
+}
+
+// MinReplicasOrDefault returns the lower limit for the number of autoscaled replicas.
+func (o *LoginSetAutoscaling) MinReplicasOrDefault() int32 {
+	if o.MinReplicas != nil {
+		return *o.MinReplicas
+	}
+	return 1
+}
+
+// ScaleDownDelayOrDefault returns the time since the last scaling before the
+// replicas may be scaled down.
+func (o *LoginSetAutoscaling) ScaleDownDelayOrDefault() time.Duration {
+	if o.ScaleDownDelay != nil {
+		return o.ScaleDownDelay.Duration
+	}
+	return 5 * time.Minute
+}
+
+// HasSessionDrain returns true if old login pods wait for their SSH sessions
+// to end before being deleted.
+func (o *LoginSet) HasSessionDrain() bool {
+	return o.Spec.UpdateStrategy.Type == SessionDrainLoginSetStrategyType
+}
+
+// DrainTimeout returns how long an old login pod waits for its SSH sessions to
+// end before being deleted.
+func (o *LoginSet) DrainTimeout() time.Duration {
+	timeout := 24 * time.Hour
+	if o.Spec.UpdateStrategy.SessionDrain != nil && o.Spec.UpdateStrategy.SessionDrain.DrainTimeout != nil {
+		timeout = o.Spec.UpdateStrategy.SessionDrain.DrainTimeout.Duration
+	}
+	return timeout
+}
+
+// UserGid returns the ID of the primary group of the user.
+func (o *LoginSetUser) UserGid() int64 {
+	if o.Gid != nil {
+		return *o.Gid
+	}
+	return o.Uid
+}
+
+// UserGroup returns the name of the primary group of the user.
+func (o *LoginSetUser) UserGroup() string {
+	if o.Group != "" {
+		return o.Group
+	}
+	return o.Name
+}
+
+// UserHome returns the home directory of the user.
+func (o *LoginSetUser) UserHome() string {
+	if o.Home != "" {
+		return o.Home
+	}
+	return "/home/" + o.Name
+}
+
+// UserShell returns the login shell of the user.
+func (o *LoginSetUser) UserShell() string {
+	if o.Shell != "" {
+		return o.Shell
+	}
+	return "/bin/bash"
+}
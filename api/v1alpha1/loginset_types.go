@@ -36,6 +36,11 @@ type LoginSetSpec struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling scales the replicas between MinReplicas and MaxReplicas on
+	// the load of the login pods. When set, replicas is managed by the operator.
+	// +optional
+	Autoscaling *LoginSetAutoscaling `json:"autoscaling,omitempty"`
+
 	// The login container configuration.
 	// See corev1.Container spec.
 	// Ref: https://github.com/kubernetes/api/blob/master/core/v1/types.go#L2885
@@ -57,13 +62,172 @@ type LoginSetSpec struct {
 	// +optional
 	ExtraSshdConfig string `json:"extraSshdConfig,omitzero"`
 
+	// SshCertificateAuthority configures sshd to trust user certificates
+	// signed by a user CA, and optionally to present host certificates signed
+	// by a host CA.
+	// Ref: https://man7.org/linux/man-pages/man1/ssh-keygen.1.html#CERTIFICATES
+	// +optional
+	SshCertificateAuthority *LoginSetSshCertificateAuthority `json:"sshCertificateAuthority,omitempty"`
+
+	// Users are provisioned on the pods of all LoginSets and NodeSets of the
+	// Controller, so they have the same uid and gid on login and compute nodes.
+	// Users which conflict with users of the image are skipped.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Users []LoginSetUser `json:"users,omitempty"`
+
 	// SssdConfRef is a reference to a secret containing the `sssd.conf`.
-	// +required
+	// If unset, SSSD is not configured, and only the `users` are provisioned.
+	// +optional
 	SssdConfRef corev1.SecretKeySelector `json:"sssdConfRef,omitzero"`
 
 	// Service defines a template for a Kubernetes Service object.
 	// +optional
 	Service ServiceSpec `json:"service,omitzero"`
+
+	// updateStrategy indicates the LoginSetUpdateStrategy that will be
+	// employed to replace login pods when a change is made to the pod template.
+	// +optional
+	UpdateStrategy LoginSetUpdateStrategy `json:"updateStrategy,omitzero"`
+}
+
+// LoginSetUpdateStrategy indicates the strategy that the LoginSet
+// controller will be used to perform updates. It includes any additional
+// parameters necessary to perform the update for the indicated strategy.
+type LoginSetUpdateStrategy struct {
+	// Type indicates the type of the LoginSetUpdateStrategy.
+	// Default is RollingUpdate.
+	// +optional
+	// +kubebuilder:validation:Enum=RollingUpdate;SessionDrain
+	Type LoginSetUpdateStrategyType `json:"type,omitempty"`
+
+	// SessionDrain is used to communicate parameters when Type is
+	// SessionDrainLoginSetStrategyType.
+	// +optional
+	SessionDrain *SessionDrainLoginSetStrategy `json:"sessionDrain,omitempty"`
+}
+
+// LoginSetUpdateStrategyType is a string enumeration type that enumerates
+// all possible update strategies for the LoginSet controller.
+// +enum
+type LoginSetUpdateStrategyType string
+
+const (
+	// RollingUpdateLoginSetStrategyType indicates that login pods will be
+	// replaced by a rolling update of the Deployment, which ends the SSH
+	// sessions of the old pods.
+	RollingUpdateLoginSetStrategyType LoginSetUpdateStrategyType = "RollingUpdate"
+
+	// SessionDrainLoginSetStrategyType indicates that old login pods with
+	// active SSH sessions will stop receiving new connections, and will only be
+	// deleted once their SSH sessions have ended or the drain timeout expired.
+	SessionDrainLoginSetStrategyType LoginSetUpdateStrategyType = "SessionDrain"
+)
+
+// SessionDrainLoginSetStrategy is used to communicate parameters for
+// SessionDrainLoginSetStrategyType.
+type SessionDrainLoginSetStrategy struct {
+	// DrainTimeout is how long an old login pod waits for its SSH sessions to
+	// end, after which the pod is deleted regardless.
+	// Defaults to 24h.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+}
+
+// LoginSetAutoscaling defines how the replicas of a LoginSet are scaled on the
+// load of the login pods. When several targets are set, the largest number of
+// replicas is used.
+type LoginSetAutoscaling struct {
+	// MinReplicas is the lower limit for the number of replicas.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetSessionsPerPod is the target average number of active SSH sessions
+	// per login pod.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetSessionsPerPod *int32 `json:"targetSessionsPerPod,omitempty"`
+
+	// TargetCPUUtilizationPercentage is the target average CPU utilization of
+	// the login container, as a percentage of its requested CPU.
+	// Requires the Metrics Server, and CPU requests on the login container.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// ScaleDownDelay is the time since the last scaling, or since the scale
+	// down was first wanted if never scaled, before the replicas may be scaled
+	// down. Scaling up is immediate.
+	// Defaults to 5m.
+	// +optional
+	ScaleDownDelay *metav1.Duration `json:"scaleDownDelay,omitempty"`
+}
+
+// LoginSetSshCertificateAuthority defines the SSH CAs of a LoginSet.
+type LoginSetSshCertificateAuthority struct {
+	// UserCaPublicKeyRef is a reference to a secret containing the public key
+	// of the user CA, in `authorized_keys` format. Users authenticate with
+	// certificates signed by this CA (`TrustedUserCAKeys`). The private key of
+	// the user CA is never needed.
+	// If unset, the operator generates the user CA.
+	// +optional
+	UserCaPublicKeyRef *corev1.SecretKeySelector `json:"userCaPublicKeyRef,omitempty"`
+
+	// SignHostKeys will sign the SSH host keys with the host CA, so clients can
+	// trust hosts by the host CA (`@cert-authority` in `known_hosts`).
+	// +optional
+	SignHostKeys bool `json:"signHostKeys,omitzero"`
+
+	// HostCaKeyRef is a reference to a secret containing the private key of
+	// the host CA, in PEM format. Only used when `signHostKeys` is set.
+	// If unset, the operator generates the host CA.
+	// +optional
+	HostCaKeyRef *corev1.SecretKeySelector `json:"hostCaKeyRef,omitempty"`
+}
+
+// LoginSetUser defines a user which is provisioned without SSSD.
+type LoginSetUser struct {
+	// Name is the username.
+	// +required
+	Name string `json:"name"`
+
+	// Uid is the user ID.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Uid int64 `json:"uid"`
+
+	// Gid is the ID of the primary group.
+	// If unset, the uid is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Gid *int64 `json:"gid,omitempty"`
+
+	// Group is the name of the primary group.
+	// If unset, the username is used.
+	// +optional
+	Group string `json:"group,omitzero"`
+
+	// Home is the home directory.
+	// If unset, `/home/<name>` is used.
+	// +optional
+	Home string `json:"home,omitzero"`
+
+	// Shell is the login shell.
+	// If unset, `/bin/bash` is used.
+	// +optional
+	Shell string `json:"shell,omitzero"`
+
+	// SshAuthorizedKeys is the `authorized_keys` of the user.
+	// +optional
+	SshAuthorizedKeys string `json:"sshAuthorizedKeys,omitzero"`
 }
 
 // LoginSetStatus defines the observed state of LoginSet
@@ -75,6 +239,27 @@ type LoginSetStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
 
+	// The number of old login pods which are waiting for their SSH sessions to
+	// end before being deleted. They are not targeted by the Selector.
+	// +optional
+	DrainingReplicas int32 `json:"drainingReplicas,omitempty"`
+
+	// The total number of active SSH sessions on the login pods.
+	// Only reported with the SessionDrain update strategy, or autoscaling on sessions.
+	// +optional
+	Sessions int32 `json:"sessions,omitempty"`
+
+	// The number of active SSH sessions of each login pod.
+	// Only reported with the SessionDrain update strategy, or autoscaling on sessions.
+	// +optional
+	// +listType=map
+	// +listMapKey=podName
+	PodSessions []LoginSetPodSessions `json:"podSessions,omitempty"`
+
+	// The last time the replicas were scaled by autoscaling.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
 	// Represents the latest available observations of a LoginSet's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -87,11 +272,28 @@ type LoginSetStatus struct {
 	Selector string `json:"selector"`
 }
 
+// LoginSetPodSessions is the number of active SSH sessions of a login pod.
+type LoginSetPodSessions struct {
+	// PodName is the name of the login pod.
+	// +required
+	PodName string `json:"podName"`
+
+	// Sessions is the number of active SSH sessions.
+	// +required
+	Sessions int32 `json:"sessions"`
+
+	// Draining indicates that the pod does not receive new connections, and
+	// will be deleted once its SSH sessions have ended.
+	// +optional
+	Draining bool `json:"draining,omitzero"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=loginsets;lss;sackd
 // +kubebuilder:subresource:scale:specpath=".spec.replicas",statuspath=".status.replicas",selectorpath=".status.selector"
 // +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".status.replicas",priority=0,description="The current number of pods."
+// +kubebuilder:printcolumn:name="SESSIONS",type="integer",JSONPath=".status.sessions",priority=1,description="The number of active SSH sessions."
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
 // LoginSet is the Schema for the loginsets API
@@ -21,6 +21,19 @@ var (
 	NodeSetAPIVersion = GroupVersion.String()
 )
 
+// NodeSet condition types and reasons.
+const (
+	// NodeSetConditionSlurmClientReady reports whether the operator has a Slurm
+	// client for the Controller of the NodeSet. Without one, Slurm nodes are
+	// neither drained nor undrained, and their status is not observed.
+	NodeSetConditionSlurmClientReady = "SlurmClientReady"
+
+	// SlurmClientReasonAvailable indicates the Slurm client is available.
+	SlurmClientReasonAvailable = "Available"
+	// SlurmClientReasonUnavailable indicates the Controller has no ready RestApi, nor an internal slurmrestd.
+	SlurmClientReasonUnavailable = "Unavailable"
+)
+
 // NodeSetSpec defines the desired state of NodeSet
 type NodeSetSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -62,6 +75,15 @@ type NodeSetSpec struct {
 	// +optional
 	Partition NodeSetPartition `json:"partition,omitzero"`
 
+	// NodeRegistration indicates how NodeSet pods register with Slurm as nodes.
+	// `Dynamic` starts slurmd with `-Z`, such that nodes register themselves.
+	// `Static` renders explicit `NodeName` lines in `slurm.conf` for each ordinal.
+	// Defaults to Dynamic.
+	// Ref: https://slurm.schedmd.com/dynamic_nodes.html
+	// +kubebuilder:validation:Enum=Dynamic;Static
+	// +optional
+	NodeRegistration NodeSetNodeRegistrationType `json:"nodeRegistration,omitempty"`
+
 	// volumeClaimTemplates is a list of claims that pods are allowed to reference.
 	// The NodeSet controller is responsible for mapping network identities to
 	// claims in a way that maintains the identity of a pod. Every claim in
@@ -97,6 +119,18 @@ type NodeSetSpec struct {
 	// Defaults to 0 (pod will be considered available as soon as it is ready).
 	// +optional
 	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// JobRequeuePolicy indicates what happens to the running Slurm jobs of a
+	// NodeSet pod which terminates without being drained by the operator
+	// (e.g. evicted, or its Kubernetes node is lost).
+	// `None` leaves the jobs to fail when slurmd stops.
+	// `Requeue` sets the Slurm node DOWN, such that slurmctld requeues the jobs
+	// which may be requeued, and records the affected jobs in an event.
+	// Defaults to None.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_JobRequeue
+	// +kubebuilder:validation:Enum=None;Requeue
+	// +optional
+	JobRequeuePolicy NodeSetJobRequeuePolicyType `json:"jobRequeuePolicy,omitempty"`
 }
 
 // NodeSetPartition defines the Slurm partition configuration for the NodeSet.
@@ -111,6 +145,37 @@ type NodeSetPartition struct {
 	Config string `json:"config,omitzero"`
 }
 
+// NodeSetNodeRegistrationType is a string enumeration type that enumerates
+// all possible Slurm node registration modes for NodeSet pods.
+// +enum
+type NodeSetNodeRegistrationType string
+
+const (
+	// DynamicNodeSetNodeRegistrationType indicates that NodeSet pods will
+	// dynamically register with Slurm (e.g. `slurmd -Z`).
+	DynamicNodeSetNodeRegistrationType NodeSetNodeRegistrationType = "Dynamic"
+
+	// StaticNodeSetNodeRegistrationType indicates that NodeSet pods will be
+	// defined in `slurm.conf` as static nodes (e.g. `NodeName=`).
+	StaticNodeSetNodeRegistrationType NodeSetNodeRegistrationType = "Static"
+)
+
+// NodeSetJobRequeuePolicyType is a string enumeration type that enumerates
+// all possible policies for the running Slurm jobs of terminated NodeSet pods.
+// +enum
+type NodeSetJobRequeuePolicyType string
+
+const (
+	// NoneNodeSetJobRequeuePolicyType indicates that the running Slurm jobs
+	// of terminated NodeSet pods are left to fail.
+	NoneNodeSetJobRequeuePolicyType NodeSetJobRequeuePolicyType = "None"
+
+	// RequeueNodeSetJobRequeuePolicyType indicates that the running Slurm
+	// jobs of terminated NodeSet pods are requeued by slurmctld, by setting
+	// their Slurm nodes DOWN.
+	RequeueNodeSetJobRequeuePolicyType NodeSetJobRequeuePolicyType = "Requeue"
+)
+
 // NodeSetUpdateStrategy indicates the strategy that the NodeSet
 // controller will be used to perform updates. It includes any additional
 // parameters necessary to perform the update for the indicated strategy.
@@ -34,3 +34,31 @@ func (o *RestApi) ServiceFQDNShort() string {
 	s := o.ServiceKey()
 	return domainname.FqdnShort(s.Name, s.Namespace)
 }
+
+// HasOidc returns true if Slurm accepts JWTs of an external OIDC issuer.
+func (o *RestApi) HasOidc() bool {
+	return o.Spec.Oidc != nil
+}
+
+// TlsSecretKey is the `kubernetes.io/tls` Secret of slurmrestd.
+func (o *RestApi) TlsSecretKey() types.NamespacedName {
+	name := fmt.Sprintf("%s-tls", o.Key().Name)
+	if o.Spec.Tls != nil && o.Spec.Tls.SecretName != "" {
+		name = o.Spec.Tls.SecretName
+	}
+	return types.NamespacedName{
+		Name:      name,
+		Namespace: o.Namespace,
+	}
+}
+
+// HasTls returns true if TLS is terminated in front of slurmrestd.
+func (o *RestApi) HasTls() bool {
+	return o.Spec.Tls != nil
+}
+
+// GeneratesTlsSecret returns true if the RestApi generates a self-signed
+// certificate into its TLS Secret, because the Secret is not named.
+func (o *RestApi) GeneratesTlsSecret() bool {
+	return o.HasTls() && o.Spec.Tls.SecretName == ""
+}
@@ -19,6 +19,19 @@ var (
 	RestApiAPIVersion = GroupVersion.String()
 )
 
+// RestApi condition types and reasons.
+const (
+	// RestApiConditionTlsSecretReady reports whether the TLS Secret of the
+	// RestApi exists.
+	RestApiConditionTlsSecretReady = "TlsSecretReady"
+
+	// TlsSecretReasonAvailable indicates the TLS Secret exists.
+	TlsSecretReasonAvailable = "Available"
+	// TlsSecretReasonNotFound indicates the TLS Secret named by `secretName`
+	// does not exist yet (e.g. is not yet issued by cert-manager).
+	TlsSecretReasonNotFound = "NotFound"
+)
+
 // RestApiSpec defines the desired state of RestApi
 type RestApiSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -49,6 +62,52 @@ type RestApiSpec struct {
 	// Service defines a template for a Kubernetes Service object.
 	// +optional
 	Service ServiceSpec `json:"service,omitzero"`
+
+	// oidc configures Slurm to accept JWTs issued by an external OIDC issuer,
+	// so users can authenticate to slurmrestd with the tokens of their
+	// identity provider instead of operator-minted JWTs.
+	// Ref: https://slurm.schedmd.com/jwt.html#compatibility
+	// +optional
+	Oidc *RestApiOidc `json:"oidc,omitempty"`
+
+	// tls terminates TLS in front of slurmrestd, so JWTs are never sent in
+	// clear text across the cluster network.
+	// +optional
+	Tls *RestApiTls `json:"tls,omitempty"`
+}
+
+// RestApiTls defines the TLS termination of slurmrestd.
+type RestApiTls struct {
+	// secretName is the name of a `kubernetes.io/tls` Secret with the
+	// `tls.crt`, `tls.key` and `ca.crt` keys (e.g. issued by cert-manager).
+	// The RestApi waits for the Secret, and never generates it.
+	// If unset, the RestApi generates a self-signed certificate into the
+	// `<restapi>-tls` Secret.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// proxy is the sidecar which terminates TLS and forwards requests to
+	// slurmrestd. The image must provide `socat`.
+	// +required
+	Proxy ContainerMinimal `json:"proxy"`
+}
+
+// RestApiOidc defines an external OIDC issuer whose JWTs Slurm accepts.
+// Exactly one of jwksUrl and jwks must be set.
+type RestApiOidc struct {
+	// jwksUrl is the URL of the JWKS of the issuer (e.g. its `jwks_uri`).
+	// The operator fetches the JWKS, and refreshes it periodically.
+	// +optional
+	JwksUrl string `json:"jwksUrl,omitempty"`
+
+	// jwks is the JWKS of the issuer, in JSON format.
+	// +optional
+	Jwks string `json:"jwks,omitempty"`
+
+	// usernameClaim is the JWT claim which is mapped to the Slurm username.
+	// If unspecified, Slurm uses the `sun` claim.
+	// +optional
+	UsernameClaim string `json:"usernameClaim,omitempty"`
 }
 
 // RestApiStatus defines the observed state of Restapi
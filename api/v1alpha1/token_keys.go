@@ -5,6 +5,8 @@ package v1alpha1
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -53,6 +55,27 @@ func (o *Token) JwtHs256Ref() *JwtSecretKeySelector {
 	return &ref
 }
 
+// JwtKey returns the key which the JWT is signed with, RS256 if set.
+func (o *Token) JwtKey() types.NamespacedName {
+	ref := o.JwtRef()
+	return types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+	}
+}
+
+// JwtRef returns the key which the JWT is signed with, RS256 if set.
+func (o *Token) JwtRef() *JwtSecretKeySelector {
+	if o.Spec.JwtRs256KeyRef == nil {
+		return o.JwtHs256Ref()
+	}
+	ref := *o.Spec.JwtRs256KeyRef
+	if ref.Namespace == "" {
+		ref.Namespace = o.Namespace
+	}
+	return &ref
+}
+
 func (o *Token) SecretKey() types.NamespacedName {
 	name := fmt.Sprintf("%s-jwt-%s", o.Name, o.Spec.Username)
 	if o.Spec.SecretRef != nil {
@@ -77,3 +100,58 @@ func (o *Token) SecretRef() *corev1.SecretKeySelector {
 		Key: key,
 	}
 }
+
+// TargetKey returns the Secret of the target, defaulting its namespace.
+func (o *Token) TargetKey(target TokenTarget) types.NamespacedName {
+	namespace := target.Namespace
+	if namespace == "" {
+		namespace = o.Namespace
+	}
+	return types.NamespacedName{
+		Name:      target.Name,
+		Namespace: namespace,
+	}
+}
+
+// TargetDataKey returns the key of the target Secret, defaulting it by format.
+func (o *Token) TargetDataKey(target TokenTarget) string {
+	if target.Key != "" {
+		return target.Key
+	}
+	switch target.Format {
+	case TokenFormatEnv:
+		return "slurm.env"
+	case TokenFormatJson:
+		return "slurm.json"
+	default:
+		return "SLURM_JWT"
+	}
+}
+
+// TargetRestapiKey returns the RestApi of the target, defaulting its namespace.
+func (o *Token) TargetRestapiKey(target TokenTarget) types.NamespacedName {
+	if target.RestapiRef == nil {
+		return types.NamespacedName{}
+	}
+	namespace := target.RestapiRef.Namespace
+	if namespace == "" {
+		namespace = o.Namespace
+	}
+	return types.NamespacedName{
+		Name:      target.RestapiRef.Name,
+		Namespace: namespace,
+	}
+}
+
+// IsTargetNamespaceAllowed returns true if the Token may deliver JWTs into
+// the namespace, which is always true for its own namespace.
+func (o *Token) IsTargetNamespaceAllowed(namespace *corev1.Namespace) bool {
+	if namespace.Name == o.Namespace {
+		return true
+	}
+	allowed := strings.Split(namespace.Annotations[AnnotationTokenSourceNamespaces], ",")
+	for i := range allowed {
+		allowed[i] = strings.TrimSpace(allowed[i])
+	}
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, o.Namespace)
+}
@@ -20,15 +20,78 @@ var (
 	TokenAPIVersion = GroupVersion.String()
 )
 
+// Token condition types and reasons.
+const (
+	// TokenConditionTargetsReady reports whether the JWT was delivered to every target.
+	TokenConditionTargetsReady = "TargetsReady"
+
+	// TokenTargetsReasonDelivered indicates the JWT was delivered to every target.
+	TokenTargetsReasonDelivered = "Delivered"
+	// TokenTargetsReasonPending indicates the JWT is being delivered to a target.
+	TokenTargetsReasonPending = "Pending"
+	// TokenTargetsReasonNotAllowed indicates the namespace of a target does not allow the namespace of the Token.
+	TokenTargetsReasonNotAllowed = "NotAllowed"
+	// TokenTargetsReasonConflict indicates a target Secret exists, but was not created by the Token.
+	TokenTargetsReasonConflict = "Conflict"
+)
+
+// TokenFormat is the format in which the JWT is written to a Secret.
+// +kubebuilder:validation:Enum=Raw;Env;Json
+type TokenFormat string
+
+const (
+	// TokenFormatRaw writes only the JWT.
+	TokenFormatRaw TokenFormat = "Raw"
+	// TokenFormatEnv writes a `SLURM_JWT=<jwt>` env file, ready to be sourced.
+	TokenFormatEnv TokenFormat = "Env"
+	// TokenFormatJson writes a JSON document with the JWT, username, and slurmrestd URL.
+	TokenFormatJson TokenFormat = "Json"
+)
+
+// TokenTarget describes an additional Secret which the JWT is delivered to.
+type TokenTarget struct {
+	// Name of the Secret.
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Defaults to the namespace of the Token.
+	// Another namespace must allow the namespace of the Token by the
+	// `token.slinky.slurm.net/source-namespaces` annotation.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key in the Secret. Defaults to `SLURM_JWT` for Raw, `slurm.env` for Env,
+	// and `slurm.json` for Json.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Format of the JWT in the Secret.
+	// +optional
+	// +kubebuilder:default:=Raw
+	Format TokenFormat `json:"format,omitempty"`
+
+	// RestapiRef is the RestApi whose slurmrestd URL is written with the JWT,
+	// for the Env and Json formats. Defaults to the namespace of the Token.
+	// +optional
+	RestapiRef *ObjectReference `json:"restapiRef,omitempty"`
+}
+
 // TokenSpec defines the desired state of Token
 type TokenSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// Slurm `auth/jwt` JWT HS256 key authentication.
-	// +required
+	// Exactly one of JwtHs256KeyRef or JwtRs256KeyRef is required.
+	// +optional
 	JwtHs256KeyRef JwtSecretKeySelector `json:"jwtHs256KeyRef,omitzero"`
 
+	// Slurm `auth/jwt` JWT RS256 key authentication, referencing the RSA
+	// private key in PEM format.
+	// Exactly one of JwtHs256KeyRef or JwtRs256KeyRef is required.
+	// +optional
+	JwtRs256KeyRef *JwtSecretKeySelector `json:"jwtRs256KeyRef,omitempty"`
+
 	// The username whom the token is created for.
 	// +required
 	Username string `json:"username,omitzero"`
@@ -46,6 +109,22 @@ type TokenSpec struct {
 	// SecretRef describes how to create the secret containing the JWT.
 	// +optional
 	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// Groups are added to the JWT as the `groups` claim.
+	// +optional
+	// +listType=set
+	Groups []string `json:"groups,omitempty"`
+
+	// Audience is added to the JWT as the `aud` claim.
+	// +optional
+	// +listType=set
+	Audience []string `json:"audience,omitempty"`
+
+	// Targets are additional Secrets which the JWT is delivered to, in
+	// addition to SecretRef.
+	// +optional
+	// +listType=atomic
+	Targets []TokenTarget `json:"targets,omitempty"`
 }
 
 // TokenStatus defines the observed state of Token
@@ -56,6 +135,19 @@ type TokenStatus struct {
 	// IssuedAt indicates the time when the JWT was issued.
 	IssuedAt *metav1.Time `json:"issuedAt,omitempty"`
 
+	// ExpiresAt indicates the time when the JWT will expire.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// LastRefreshTime indicates the last time the JWT was reissued, replacing
+	// a previous JWT.
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+
+	// KeyFingerprint is the fingerprint of the key used to sign the JWT.
+	// +optional
+	KeyFingerprint string `json:"keyFingerprint,omitempty"`
+
 	// Represents the latest available observations of a Restapi's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -70,6 +162,7 @@ type TokenStatus struct {
 // +kubebuilder:resource:shortName=tokens;jwt
 // +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".spec.username",description="The username issued to the JWT."
 // +kubebuilder:printcolumn:name="IAT",type="date",JSONPath=".status.issuedAt",description="The JWT Issued At time."
+// +kubebuilder:printcolumn:name="EXP",type="date",JSONPath=".status.expiresAt",description="The JWT Expiration time."
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Token is the Schema for the tokens API
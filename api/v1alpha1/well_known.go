@@ -7,8 +7,10 @@ package v1alpha1
 const (
 	SlinkyPrefix = "slinky.slurm.net/"
 
-	NodeSetPrefix  = "nodeset." + SlinkyPrefix
-	LoginSetPrefix = "loginset." + SlinkyPrefix
+	NodeSetPrefix    = "nodeset." + SlinkyPrefix
+	LoginSetPrefix   = "loginset." + SlinkyPrefix
+	TokenPrefix      = "token." + SlinkyPrefix
+	ControllerPrefix = "controller." + SlinkyPrefix
 )
 
 // Well Known Annotations
@@ -16,6 +18,10 @@ const (
 	// AnnotationPodCordon indicates NodeSet Pods that should be DRAIN[ING|ED] in Slurm.
 	AnnotationPodCordon = NodeSetPrefix + "pod-cordon"
 
+	// AnnotationPodCordonReason is the reason given to Slurm when DRAIN[ING|ED] a cordoned NodeSet Pod.
+	// NOTE: this is only honored when the pod is cordoned (see AnnotationPodCordon).
+	AnnotationPodCordonReason = NodeSetPrefix + "pod-cordon-reason"
+
 	// LabelPodDeletionCost can be used to set to an int32 that represent the cost of deleting a pod compared to other
 	// pods belonging to the same ReplicaSet. Pods with lower deletion cost are preferred to be deleted before pods
 	// with higher deletion cost.
@@ -27,6 +33,15 @@ const (
 	// workload by. Pods with an earlier deadline are preferred to be deleted before pods with a later deadline.
 	// NOTE: this is honored on a best-effort basis, and does not offer guarantees on pod deletion order.
 	AnnotationPodDeadline = NodeSetPrefix + "pod-deadline"
+
+	// AnnotationLoginSetPodDrainStart stores a time.RFC3339 timestamp, indicating when the login pod started to
+	// wait for its SSH sessions to end.
+	// NOTE: Set by the LoginSet controller.
+	AnnotationLoginSetPodDrainStart = LoginSetPrefix + "pod-drain-start"
+
+	// AnnotationTokenSourceNamespaces is set on a Namespace to allow Tokens of other namespaces to deliver JWTs into it.
+	// The value is a comma separated list of namespaces, or `*` to allow all namespaces.
+	AnnotationTokenSourceNamespaces = TokenPrefix + "source-namespaces"
 )
 
 // Well Known Labels
@@ -46,4 +61,26 @@ const (
 	// LabelNodeSetPodProtect indicates whether the pod is protected against eviction using a PodDisruptionBudget
 	// NOTE: Set by the NodeSet controller
 	LabelNodeSetPodProtect = NodeSetPrefix + "pod-protect"
+
+	// LabelLoginSetPodDraining indicates the name of the LoginSet of an old login pod, which no longer receives new
+	// connections and waits for its SSH sessions to end.
+	// NOTE: Set by the LoginSet controller.
+	LabelLoginSetPodDraining = LoginSetPrefix + "pod-draining"
+
+	// LabelTokenName indicates the name of the Token which delivered the JWT into the Secret.
+	// NOTE: Set by the Token controller.
+	LabelTokenName = TokenPrefix + "name"
+
+	// LabelTokenNamespace indicates the namespace of the Token which delivered the JWT into the Secret.
+	// NOTE: Set by the Token controller.
+	LabelTokenNamespace = TokenPrefix + "namespace"
+
+	// LabelControllerName indicates the name of the Controller which copied the Slurm key or users into the namespace.
+	// NOTE: Set by the Controller controller.
+	LabelControllerName = ControllerPrefix + "name"
+
+	// LabelControllerNamespace indicates the namespace of the Controller which copied the Slurm key or users into the
+	// namespace.
+	// NOTE: Set by the Controller controller.
+	LabelControllerNamespace = ControllerPrefix + "namespace"
 )
@@ -8,8 +8,8 @@
 package v1alpha1
 
 import (
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -78,6 +78,16 @@ func (in *AccountingSpec) DeepCopyInto(out *AccountingSpec) {
 	*out = *in
 	in.SlurmKeyRef.DeepCopyInto(&out.SlurmKeyRef)
 	in.JwtHs256KeyRef.DeepCopyInto(&out.JwtHs256KeyRef)
+	if in.JwtHs256KeyRotation != nil {
+		in, out := &in.JwtHs256KeyRotation, &out.JwtHs256KeyRotation
+		*out = new(JwtKeyRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JwtRs256KeyRef != nil {
+		in, out := &in.JwtRs256KeyRef, &out.JwtRs256KeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Slurmdbd.DeepCopyInto(&out.Slurmdbd)
 	in.InitConf.DeepCopyInto(&out.InitConf)
 	in.Template.DeepCopyInto(&out.Template)
@@ -100,7 +110,7 @@ func (in *AccountingStatus) DeepCopyInto(out *AccountingStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -217,12 +227,37 @@ func (in *ControllerPersistence) DeepCopy() *ControllerPersistence {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 	*out = *in
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalController)
+		(*in).DeepCopyInto(*out)
+	}
 	in.SlurmKeyRef.DeepCopyInto(&out.SlurmKeyRef)
+	if in.SlurmKeyRotation != nil {
+		in, out := &in.SlurmKeyRotation, &out.SlurmKeyRotation
+		*out = new(SlurmKeyRotation)
+		(*in).DeepCopyInto(*out)
+	}
 	in.JwtHs256KeyRef.DeepCopyInto(&out.JwtHs256KeyRef)
+	if in.JwtHs256KeyRotation != nil {
+		in, out := &in.JwtHs256KeyRotation, &out.JwtHs256KeyRotation
+		*out = new(JwtKeyRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JwtRs256KeyRef != nil {
+		in, out := &in.JwtRs256KeyRef, &out.JwtRs256KeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	out.AccountingRef = in.AccountingRef
 	in.Slurmctld.DeepCopyInto(&out.Slurmctld)
 	in.Reconfigure.DeepCopyInto(&out.Reconfigure)
 	in.LogFile.DeepCopyInto(&out.LogFile)
+	if in.InternalSlurmrestd != nil {
+		in, out := &in.InternalSlurmrestd, &out.InternalSlurmrestd
+		*out = new(ContainerMinimal)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Template.DeepCopyInto(&out.Template)
 	if in.ConfigFileRefs != nil {
 		in, out := &in.ConfigFileRefs, &out.ConfigFileRefs
@@ -251,6 +286,18 @@ func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 	}
 	in.Persistence.DeepCopyInto(&out.Persistence)
 	in.Service.DeepCopyInto(&out.Service)
+	if in.SharedVolumes != nil {
+		in, out := &in.SharedVolumes, &out.SharedVolumes
+		*out = make([]SharedVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerSpec.
@@ -266,9 +313,14 @@ func (in *ControllerSpec) DeepCopy() *ControllerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerStatus) DeepCopyInto(out *ControllerStatus) {
 	*out = *in
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(ObjectReference)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -285,6 +337,198 @@ func (in *ControllerStatus) DeepCopy() *ControllerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalController) DeepCopyInto(out *ExternalController) {
+	*out = *in
+	if in.Slurmrestd != nil {
+		in, out := &in.Slurmrestd, &out.Slurmrestd
+		*out = new(ExternalSlurmrestd)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalController.
+func (in *ExternalController) DeepCopy() *ExternalController {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalController)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSlurmrestd) DeepCopyInto(out *ExternalSlurmrestd) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSlurmrestd.
+func (in *ExternalSlurmrestd) DeepCopy() *ExternalSlurmrestd {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSlurmrestd)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Federation) DeepCopyInto(out *Federation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Federation.
+func (in *Federation) DeepCopy() *Federation {
+	if in == nil {
+		return nil
+	}
+	out := new(Federation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Federation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationCluster) DeepCopyInto(out *FederationCluster) {
+	*out = *in
+	out.ControllerRef = in.ControllerRef
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationCluster.
+func (in *FederationCluster) DeepCopy() *FederationCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationList) DeepCopyInto(out *FederationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Federation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationList.
+func (in *FederationList) DeepCopy() *FederationList {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationSpec) DeepCopyInto(out *FederationSpec) {
+	*out = *in
+	out.AccountingRef = in.AccountingRef
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]FederationCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Registration.DeepCopyInto(&out.Registration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationSpec.
+func (in *FederationSpec) DeepCopy() *FederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationStatus) DeepCopyInto(out *FederationStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationStatus.
+func (in *FederationStatus) DeepCopy() *FederationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JwtKeyRotation) DeepCopyInto(out *JwtKeyRotation) {
+	*out = *in
+	in.NewKeyRef.DeepCopyInto(&out.NewKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JwtKeyRotation.
+func (in *JwtKeyRotation) DeepCopy() *JwtKeyRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(JwtKeyRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JwtSecretKeySelector) DeepCopyInto(out *JwtSecretKeySelector) {
 	*out = *in
@@ -328,6 +572,41 @@ func (in *LoginSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginSetAutoscaling) DeepCopyInto(out *LoginSetAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetSessionsPerPod != nil {
+		in, out := &in.TargetSessionsPerPod, &out.TargetSessionsPerPod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownDelay != nil {
+		in, out := &in.ScaleDownDelay, &out.ScaleDownDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSetAutoscaling.
+func (in *LoginSetAutoscaling) DeepCopy() *LoginSetAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginSetAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoginSetList) DeepCopyInto(out *LoginSetList) {
 	*out = *in
@@ -360,6 +639,21 @@ func (in *LoginSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginSetPodSessions) DeepCopyInto(out *LoginSetPodSessions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSetPodSessions.
+func (in *LoginSetPodSessions) DeepCopy() *LoginSetPodSessions {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginSetPodSessions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoginSetSpec) DeepCopyInto(out *LoginSetSpec) {
 	*out = *in
@@ -369,10 +663,28 @@ func (in *LoginSetSpec) DeepCopyInto(out *LoginSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(LoginSetAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Login.DeepCopyInto(&out.Login)
 	in.Template.DeepCopyInto(&out.Template)
+	if in.SshCertificateAuthority != nil {
+		in, out := &in.SshCertificateAuthority, &out.SshCertificateAuthority
+		*out = new(LoginSetSshCertificateAuthority)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]LoginSetUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.SssdConfRef.DeepCopyInto(&out.SssdConfRef)
 	in.Service.DeepCopyInto(&out.Service)
+	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSetSpec.
@@ -385,12 +697,86 @@ func (in *LoginSetSpec) DeepCopy() *LoginSetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginSetSshCertificateAuthority) DeepCopyInto(out *LoginSetSshCertificateAuthority) {
+	*out = *in
+	if in.UserCaPublicKeyRef != nil {
+		in, out := &in.UserCaPublicKeyRef, &out.UserCaPublicKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostCaKeyRef != nil {
+		in, out := &in.HostCaKeyRef, &out.HostCaKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSetSshCertificateAuthority.
+func (in *LoginSetSshCertificateAuthority) DeepCopy() *LoginSetSshCertificateAuthority {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginSetSshCertificateAuthority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginSetUpdateStrategy) DeepCopyInto(out *LoginSetUpdateStrategy) {
+	*out = *in
+	if in.SessionDrain != nil {
+		in, out := &in.SessionDrain, &out.SessionDrain
+		*out = new(SessionDrainLoginSetStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSetUpdateStrategy.
+func (in *LoginSetUpdateStrategy) DeepCopy() *LoginSetUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginSetUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginSetUser) DeepCopyInto(out *LoginSetUser) {
+	*out = *in
+	if in.Gid != nil {
+		in, out := &in.Gid, &out.Gid
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSetUser.
+func (in *LoginSetUser) DeepCopy() *LoginSetUser {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginSetUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoginSetStatus) DeepCopyInto(out *LoginSetStatus) {
 	*out = *in
+	if in.PodSessions != nil {
+		in, out := &in.PodSessions, &out.PodSessions
+		*out = make([]LoginSetPodSessions, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -540,7 +926,7 @@ func (in *NodeSetSpec) DeepCopyInto(out *NodeSetSpec) {
 	out.Partition = in.Partition
 	if in.VolumeClaimTemplates != nil {
 		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
-		*out = make([]corev1.PersistentVolumeClaim, len(*in))
+		*out = make([]v1.PersistentVolumeClaim, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -578,7 +964,7 @@ func (in *NodeSetStatus) DeepCopyInto(out *NodeSetStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -712,6 +1098,21 @@ func (in *RestApiList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestApiOidc) DeepCopyInto(out *RestApiOidc) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestApiOidc.
+func (in *RestApiOidc) DeepCopy() *RestApiOidc {
+	if in == nil {
+		return nil
+	}
+	out := new(RestApiOidc)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestApiSpec) DeepCopyInto(out *RestApiSpec) {
 	*out = *in
@@ -724,6 +1125,16 @@ func (in *RestApiSpec) DeepCopyInto(out *RestApiSpec) {
 	in.Slurmrestd.DeepCopyInto(&out.Slurmrestd)
 	in.Template.DeepCopyInto(&out.Template)
 	in.Service.DeepCopyInto(&out.Service)
+	if in.Oidc != nil {
+		in, out := &in.Oidc, &out.Oidc
+		*out = new(RestApiOidc)
+		**out = **in
+	}
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(RestApiTls)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestApiSpec.
@@ -736,12 +1147,28 @@ func (in *RestApiSpec) DeepCopy() *RestApiSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestApiTls) DeepCopyInto(out *RestApiTls) {
+	*out = *in
+	in.Proxy.DeepCopyInto(&out.Proxy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestApiTls.
+func (in *RestApiTls) DeepCopy() *RestApiTls {
+	if in == nil {
+		return nil
+	}
+	out := new(RestApiTls)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestApiStatus) DeepCopyInto(out *RestApiStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -800,6 +1227,67 @@ func (in *ServiceSpecWrapper) DeepCopyInto(out *ServiceSpecWrapper) {
 	*out = *clone
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionDrainLoginSetStrategy) DeepCopyInto(out *SessionDrainLoginSetStrategy) {
+	*out = *in
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionDrainLoginSetStrategy.
+func (in *SessionDrainLoginSetStrategy) DeepCopy() *SessionDrainLoginSetStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionDrainLoginSetStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolume) DeepCopyInto(out *SharedVolume) {
+	*out = *in
+	if in.ClaimTemplate != nil {
+		in, out := &in.ClaimTemplate, &out.ClaimTemplate
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]SharedVolumeComponent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolume.
+func (in *SharedVolume) DeepCopy() *SharedVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmKeyRotation) DeepCopyInto(out *SlurmKeyRotation) {
+	*out = *in
+	in.NewKeyRef.DeepCopyInto(&out.NewKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmKeyRotation.
+func (in *SlurmKeyRotation) DeepCopy() *SlurmKeyRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmKeyRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
 	*out = *in
@@ -879,16 +1367,38 @@ func (in *TokenList) DeepCopyObject() runtime.Object {
 func (in *TokenSpec) DeepCopyInto(out *TokenSpec) {
 	*out = *in
 	in.JwtHs256KeyRef.DeepCopyInto(&out.JwtHs256KeyRef)
+	if in.JwtRs256KeyRef != nil {
+		in, out := &in.JwtRs256KeyRef, &out.JwtRs256KeyRef
+		*out = new(JwtSecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Lifetime != nil {
 		in, out := &in.Lifetime, &out.Lifetime
-		*out = new(v1.Duration)
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 	if in.SecretRef != nil {
 		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(corev1.SecretKeySelector)
+		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audience != nil {
+		in, out := &in.Audience, &out.Audience
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TokenTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSpec.
@@ -908,9 +1418,17 @@ func (in *TokenStatus) DeepCopyInto(out *TokenStatus) {
 		in, out := &in.IssuedAt, &out.IssuedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -926,3 +1444,23 @@ func (in *TokenStatus) DeepCopy() *TokenStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenTarget) DeepCopyInto(out *TokenTarget) {
+	*out = *in
+	if in.RestapiRef != nil {
+		in, out := &in.RestapiRef, &out.RestapiRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenTarget.
+func (in *TokenTarget) DeepCopy() *TokenTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenTarget)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+const (
+	slurmConfFile = "slurm.conf"
+)
+
+func listOptions(opts *Options) []client.ListOption {
+	if opts.AllNamespaces {
+		return nil
+	}
+	return []client.ListOption{client.InNamespace(opts.Namespace)}
+}
+
+func requireArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return fmt.Errorf("expected arguments %v, got %v", names, args)
+	}
+	return nil
+}
+
+// runClusters lists Controllers with a summary of Slurm node states across
+// their NodeSets.
+func runClusters(ctx context.Context, opts *Options, args []string) error {
+	if err := requireArgs(args); err != nil {
+		return err
+	}
+
+	controllerList := &slinkyv1alpha1.ControllerList{}
+	if err := opts.Client.List(ctx, controllerList, listOptions(opts)...); err != nil {
+		return err
+	}
+	nodesetList := &slinkyv1alpha1.NodeSetList{}
+	if err := opts.Client.List(ctx, nodesetList); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(opts.Out, 0, 8, 3, ' ', 0)
+	if opts.AllNamespaces {
+		fmt.Fprint(tw, "NAMESPACE\t")
+	}
+	fmt.Fprintln(tw, "NAME\tCLUSTER\tNODESETS\tREPLICAS\tREADY\tIDLE\tALLOCATED\tDOWN\tDRAIN")
+	for _, controller := range controllerList.Items {
+		var nodesets int
+		var status slinkyv1alpha1.NodeSetStatus
+		for _, nodeset := range nodesetList.Items {
+			if !nodeset.Spec.ControllerRef.IsMatch(objectutils.NamespacedName(&controller)) {
+				continue
+			}
+			nodesets++
+			status.Replicas += nodeset.Status.Replicas
+			status.ReadyReplicas += nodeset.Status.ReadyReplicas
+			status.SlurmIdle += nodeset.Status.SlurmIdle
+			status.SlurmAllocated += nodeset.Status.SlurmAllocated
+			status.SlurmDown += nodeset.Status.SlurmDown
+			status.SlurmDrain += nodeset.Status.SlurmDrain
+		}
+		if opts.AllNamespaces {
+			fmt.Fprintf(tw, "%s\t", controller.Namespace)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			controller.Name, controller.ClusterName(), nodesets,
+			status.Replicas, status.ReadyReplicas,
+			status.SlurmIdle, status.SlurmAllocated, status.SlurmDown, status.SlurmDrain)
+	}
+	return tw.Flush()
+}
+
+// getNodeSetPod gets the named pod, which must be a member of a NodeSet.
+func getNodeSetPod(ctx context.Context, opts *Options, name string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	key := types.NamespacedName{Namespace: opts.Namespace, Name: name}
+	if err := opts.Client.Get(ctx, key, pod); err != nil {
+		return nil, err
+	}
+	if _, ok := pod.Labels[slinkyv1alpha1.LabelNodeSetPodName]; !ok {
+		return nil, fmt.Errorf("pod (%s) is not a NodeSet pod", key)
+	}
+	return pod, nil
+}
+
+// setPodCordon patches the NodeSet pod cordon annotations.
+func setPodCordon(ctx context.Context, opts *Options, name string, cordon bool, reason string) error {
+	pod, err := getNodeSetPod(ctx, opts, name)
+	if err != nil {
+		return err
+	}
+
+	toUpdate := pod.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	if cordon {
+		toUpdate.Annotations[slinkyv1alpha1.AnnotationPodCordon] = "true"
+	} else {
+		delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodCordon)
+	}
+	if reason != "" {
+		toUpdate.Annotations[slinkyv1alpha1.AnnotationPodCordonReason] = reason
+	} else {
+		delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodCordonReason)
+	}
+	if err := opts.Client.Patch(ctx, toUpdate, client.MergeFrom(pod)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func runCordon(ctx context.Context, opts *Options, args []string) error {
+	if err := requireArgs(args, "POD"); err != nil {
+		return err
+	}
+	if err := setPodCordon(ctx, opts, args[0], true, ""); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Out, "pod/%s cordoned\n", args[0])
+	return nil
+}
+
+func runUncordon(ctx context.Context, opts *Options, args []string) error {
+	if err := requireArgs(args, "POD"); err != nil {
+		return err
+	}
+	if err := setPodCordon(ctx, opts, args[0], false, ""); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Out, "pod/%s uncordoned\n", args[0])
+	return nil
+}
+
+func runDrain(ctx context.Context, opts *Options, args []string, reason string) error {
+	if err := requireArgs(args, "POD"); err != nil {
+		return err
+	}
+	if reason == "" {
+		return errors.New("a drain reason is required (--reason)")
+	}
+	if err := setPodCordon(ctx, opts, args[0], true, reason); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Out, "pod/%s drained\n", args[0])
+	return nil
+}
+
+// runConfig prints the slurm.conf rendered by the operator for the Controller.
+func runConfig(ctx context.Context, opts *Options, args []string) error {
+	if err := requireArgs(args, "CONTROLLER"); err != nil {
+		return err
+	}
+
+	controller := &slinkyv1alpha1.Controller{}
+	key := types.NamespacedName{Namespace: opts.Namespace, Name: args[0]}
+	if err := opts.Client.Get(ctx, key, controller); err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := opts.Client.Get(ctx, controller.ConfigKey(), configMap); err != nil {
+		return err
+	}
+	slurmConf, ok := configMap.Data[slurmConfFile]
+	if !ok {
+		return fmt.Errorf("configmap (%s) has no %s", controller.ConfigKey(), slurmConfFile)
+	}
+
+	fmt.Fprint(opts.Out, slurmConf)
+	return nil
+}
+
+// runNodeName prints the Slurm node name of the NodeSet pod.
+func runNodeName(ctx context.Context, opts *Options, args []string) error {
+	if err := requireArgs(args, "POD"); err != nil {
+		return err
+	}
+
+	pod, err := getNodeSetPod(ctx, opts, args[0])
+	if err != nil {
+		return err
+	}
+
+	nodeName := pod.Labels[slinkyv1alpha1.LabelNodeSetPodHostname]
+	if nodeName == "" {
+		nodeName = nodesetutils.GetNodeName(pod)
+	}
+
+	fmt.Fprintln(opts.Out, nodeName)
+	return nil
+}
+
+// runToken prints a new JWT for the user, signed by the Controller's JWT key.
+func runToken(ctx context.Context, opts *Options, args []string, username, lifetime string) error {
+	if err := requireArgs(args, "CONTROLLER"); err != nil {
+		return err
+	}
+	if username == "" {
+		return errors.New("a username is required (--username)")
+	}
+	duration, err := time.ParseDuration(lifetime)
+	if err != nil {
+		return fmt.Errorf("failed to parse lifetime: %w", err)
+	}
+
+	controller := &slinkyv1alpha1.Controller{}
+	key := types.NamespacedName{Namespace: opts.Namespace, Name: args[0]}
+	if err := opts.Client.Get(ctx, key, controller); err != nil {
+		return err
+	}
+
+	signingRef := controller.AuthJwtSigningRef()
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: controller.Namespace, Name: signingRef.Name}
+	if err := opts.Client.Get(ctx, secretKey, secret); err != nil {
+		return err
+	}
+	signingKey, ok := secret.Data[signingRef.Key]
+	if !ok {
+		return fmt.Errorf("secret key '%s' not found", signingRef.Key)
+	}
+
+	newToken := slurmjwt.NewToken(signingKey).
+		WithUsername(username).
+		WithLifetime(duration)
+	token, err := newToken.NewSignedToken()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(opts.Out, token)
+	return nil
+}
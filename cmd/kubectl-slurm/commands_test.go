@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+)
+
+func newController() *slinkyv1alpha1.Controller {
+	return &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			JwtHs256KeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "slurm-jwt",
+				},
+				Key: "jwt_hs256.key",
+			},
+		},
+	}
+}
+
+func newNodeSetPod(name string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      name,
+			Labels: map[string]string{
+				slinkyv1alpha1.LabelNodeSetPodName:     name,
+				slinkyv1alpha1.LabelNodeSetPodHostname: "foo-0",
+			},
+			Annotations: annotations,
+		},
+	}
+}
+
+func newOptions(c client.Client) (*Options, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &Options{
+		Client:    c,
+		Namespace: "slurm",
+		Out:       out,
+	}, out
+}
+
+func Test_runClusters(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newController()).
+		WithObjects(&slinkyv1alpha1.NodeSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "slurm",
+				Name:      "slurm-foo",
+			},
+			Spec: slinkyv1alpha1.NodeSetSpec{
+				ControllerRef: slinkyv1alpha1.ObjectReference{
+					Namespace: "slurm",
+					Name:      "slurm",
+				},
+			},
+			Status: slinkyv1alpha1.NodeSetStatus{
+				Replicas:       3,
+				ReadyReplicas:  3,
+				SlurmIdle:      1,
+				SlurmAllocated: 2,
+			},
+		}).
+		Build()
+	opts, out := newOptions(c)
+	if err := runClusters(context.TODO(), opts, nil); err != nil {
+		t.Fatalf("runClusters() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("runClusters() = %v, want 2 lines", out.String())
+	}
+	if got, want := strings.Fields(lines[1]), []string{"slurm", "slurm_slurm", "1", "3", "3", "1", "2", "0", "0"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("runClusters() = %v, want %v", got, want)
+	}
+}
+
+func Test_setPodCordon(t *testing.T) {
+	type args struct {
+		name   string
+		cordon bool
+		reason string
+	}
+	tests := []struct {
+		name            string
+		pod             *corev1.Pod
+		args            args
+		wantAnnotations map[string]string
+		wantErr         bool
+	}{
+		{
+			name: "cordon",
+			pod:  newNodeSetPod("slurm-foo-0", nil),
+			args: args{
+				name:   "slurm-foo-0",
+				cordon: true,
+			},
+			wantAnnotations: map[string]string{
+				slinkyv1alpha1.AnnotationPodCordon: "true",
+			},
+		},
+		{
+			name: "drain",
+			pod:  newNodeSetPod("slurm-foo-0", nil),
+			args: args{
+				name:   "slurm-foo-0",
+				cordon: true,
+				reason: "maintenance",
+			},
+			wantAnnotations: map[string]string{
+				slinkyv1alpha1.AnnotationPodCordon:       "true",
+				slinkyv1alpha1.AnnotationPodCordonReason: "maintenance",
+			},
+		},
+		{
+			name: "uncordon",
+			pod: newNodeSetPod("slurm-foo-0", map[string]string{
+				slinkyv1alpha1.AnnotationPodCordon:       "true",
+				slinkyv1alpha1.AnnotationPodCordonReason: "maintenance",
+			}),
+			args: args{
+				name:   "slurm-foo-0",
+				cordon: false,
+			},
+			wantAnnotations: map[string]string{},
+		},
+		{
+			name: "not a NodeSet pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "slurm",
+					Name:      "foo",
+				},
+			},
+			args: args{
+				name:   "foo",
+				cordon: true,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.pod).Build()
+			opts, _ := newOptions(c)
+			err := setPodCordon(context.TODO(), opts, tt.args.name, tt.args.cordon, tt.args.reason)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setPodCordon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			pod := &corev1.Pod{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "slurm", Name: tt.args.name}, pod); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if len(pod.Annotations) != len(tt.wantAnnotations) {
+				t.Errorf("Annotations = %v, want %v", pod.Annotations, tt.wantAnnotations)
+			}
+			for key, val := range tt.wantAnnotations {
+				if pod.Annotations[key] != val {
+					t.Errorf("Annotations[%s] = %v, want %v", key, pod.Annotations[key], val)
+				}
+			}
+		})
+	}
+}
+
+func Test_runDrain(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newNodeSetPod("slurm-foo-0", nil)).Build()
+	opts, _ := newOptions(c)
+	if err := runDrain(context.TODO(), opts, []string{"slurm-foo-0"}, ""); err == nil {
+		t.Errorf("runDrain() error = %v, want non-nil", err)
+	}
+	if err := runDrain(context.TODO(), opts, []string{"slurm-foo-0"}, "maintenance"); err != nil {
+		t.Errorf("runDrain() error = %v", err)
+	}
+}
+
+func Test_runConfig(t *testing.T) {
+	controller := newController()
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(controller).
+		WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: controller.ConfigKey().Namespace,
+				Name:      controller.ConfigKey().Name,
+			},
+			Data: map[string]string{
+				slurmConfFile: "ClusterName=slurm_slurm\n",
+			},
+		}).
+		Build()
+	opts, out := newOptions(c)
+	if err := runConfig(context.TODO(), opts, []string{"slurm"}); err != nil {
+		t.Fatalf("runConfig() error = %v", err)
+	}
+	if got := out.String(); got != "ClusterName=slurm_slurm\n" {
+		t.Errorf("runConfig() = %v", got)
+	}
+}
+
+func Test_runNodeName(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newNodeSetPod("slurm-foo-0", nil)).Build()
+	opts, out := newOptions(c)
+	if err := runNodeName(context.TODO(), opts, []string{"slurm-foo-0"}); err != nil {
+		t.Fatalf("runNodeName() error = %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "foo-0" {
+		t.Errorf("runNodeName() = %v, want %v", got, "foo-0")
+	}
+}
+
+func Test_runToken(t *testing.T) {
+	signingKey := []byte("foo")
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newController()).
+		WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "slurm",
+				Name:      "slurm-jwt",
+			},
+			Data: map[string][]byte{
+				"jwt_hs256.key": signingKey,
+			},
+		}).
+		Build()
+	opts, out := newOptions(c)
+	if err := runToken(context.TODO(), opts, []string{"slurm"}, "", "1h"); err == nil {
+		t.Errorf("runToken() error = %v, want non-nil", err)
+	}
+	if err := runToken(context.TODO(), opts, []string{"slurm"}, "alice", "1h"); err != nil {
+		t.Fatalf("runToken() error = %v", err)
+	}
+	claims, err := slurmjwt.ParseTokenClaims(strings.TrimSpace(out.String()), signingKey)
+	if err != nil {
+		t.Fatalf("ParseTokenClaims() error = %v", err)
+	}
+	if claims["sun"] != "alice" {
+		t.Errorf("claims[sun] = %v, want %v", claims["sun"], "alice")
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to ensure that exec-entrypoint and run can make use of them.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+var (
+	scheme = runtime.NewScheme()
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(scheme))
+}
+
+// Input flags common to all commands
+type Flags struct {
+	kubeconfig    string
+	kubecontext   string
+	namespace     string
+	allNamespaces bool
+}
+
+func (f *Flags) bind(fs *flag.FlagSet) {
+	fs.StringVar(&f.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use.")
+	fs.StringVar(&f.kubecontext, "context", "", "The name of the kubeconfig context to use.")
+	fs.StringVar(&f.namespace, "namespace", "", "The namespace scope for this request.")
+	fs.StringVar(&f.namespace, "n", "", "The namespace scope for this request (shorthand).")
+	fs.BoolVar(&f.allNamespaces, "all-namespaces", false, "List the requested objects across all namespaces.")
+	fs.BoolVar(&f.allNamespaces, "A", false, "List the requested objects across all namespaces (shorthand).")
+}
+
+// Options are given to each command when run.
+type Options struct {
+	Client        client.Client
+	Namespace     string
+	AllNamespaces bool
+	Out           io.Writer
+}
+
+type command struct {
+	name  string
+	usage string
+	short string
+	// flags binds command specific flags, may be nil.
+	flags func(fs *flag.FlagSet)
+	run   func(ctx context.Context, opts *Options, args []string) error
+}
+
+func commands() []*command {
+	var reason string
+	var username string
+	var lifetime string
+	return []*command{
+		{
+			name:  "clusters",
+			usage: "clusters",
+			short: "List Slurm clusters with a summary of their Slurm node states.",
+			run:   runClusters,
+		},
+		{
+			name:  "cordon",
+			usage: "cordon POD",
+			short: "Cordon a NodeSet pod, such that its Slurm node is drained.",
+			run:   runCordon,
+		},
+		{
+			name:  "uncordon",
+			usage: "uncordon POD",
+			short: "Uncordon a NodeSet pod, such that its Slurm node is undrained.",
+			run:   runUncordon,
+		},
+		{
+			name:  "drain",
+			usage: "drain POD --reason REASON",
+			short: "Cordon a NodeSet pod and drain its Slurm node with the given reason.",
+			flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&reason, "reason", "", "The reason given to Slurm for the drain.")
+			},
+			run: func(ctx context.Context, opts *Options, args []string) error {
+				return runDrain(ctx, opts, args, reason)
+			},
+		},
+		{
+			name:  "config",
+			usage: "config CONTROLLER",
+			short: "Show the rendered slurm.conf of a Controller.",
+			run:   runConfig,
+		},
+		{
+			name:  "nodename",
+			usage: "nodename POD",
+			short: "Show the Slurm node name of a NodeSet pod.",
+			run:   runNodeName,
+		},
+		{
+			name:  "token",
+			usage: "token CONTROLLER --username USERNAME [--lifetime DURATION]",
+			short: "Mint a Slurm JWT for a user, signed by the Controller's JWT key.",
+			flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&username, "username", "", "The username whom the token is created for.")
+				fs.StringVar(&lifetime, "lifetime", "15m", "The lifetime of the JWT before it expires.")
+			},
+			run: func(ctx context.Context, opts *Options, args []string) error {
+				return runToken(ctx, opts, args, username, lifetime)
+			},
+		},
+	}
+}
+
+func usage(w io.Writer, cmds []*command) {
+	fmt.Fprintln(w, "Operate on Slurm clusters managed by slurm-operator.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  kubectl slurm COMMAND [ARGS] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, cmd := range cmds {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.usage, cmd.short)
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs := flag.NewFlagSet("kubectl-slurm", flag.ContinueOnError)
+	fs.SetOutput(w)
+	(&Flags{}).bind(fs)
+	fs.PrintDefaults()
+}
+
+// parseInterspersed parses flags that may be mixed with positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func newClient(flags *Flags) (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = flags.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: flags.kubecontext,
+	}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return c, namespace, nil
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cmds := commands()
+	if len(args) == 0 || slices.Contains([]string{"help", "-h", "--help"}, args[0]) {
+		usage(stdout, cmds)
+		return nil
+	}
+
+	idx := slices.IndexFunc(cmds, func(cmd *command) bool {
+		return cmd.name == args[0]
+	})
+	if idx < 0 {
+		usage(stderr, cmds)
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+	cmd := cmds[idx]
+
+	var flags Flags
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "%s\n\nUsage:\n  kubectl slurm %s [flags]\n\nFlags:\n", cmd.short, cmd.usage)
+		fs.PrintDefaults()
+	}
+	flags.bind(fs)
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	c, namespace, err := newClient(&flags)
+	if err != nil {
+		return err
+	}
+	if flags.namespace != "" {
+		namespace = flags.namespace
+	}
+
+	opts := &Options{
+		Client:        c,
+		Namespace:     namespace,
+		AllNamespaces: flags.allNamespaces,
+		Out:           stdout,
+	}
+	return cmd.run(ctx, opts, positional)
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", strings.TrimSpace(err.Error()))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+func Test_parseInterspersed(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       []string
+		wantReason string
+		wantNs     string
+		wantErr    bool
+	}{
+		{
+			name: "empty",
+			args: args{
+				args: []string{},
+			},
+			want: []string{},
+		},
+		{
+			name: "flags first",
+			args: args{
+				args: []string{"-n", "slurm", "--reason", "foo", "slurm-foo-0"},
+			},
+			want:       []string{"slurm-foo-0"},
+			wantReason: "foo",
+			wantNs:     "slurm",
+		},
+		{
+			name: "flags last",
+			args: args{
+				args: []string{"slurm-foo-0", "--reason=foo bar", "--namespace", "slurm"},
+			},
+			want:       []string{"slurm-foo-0"},
+			wantReason: "foo bar",
+			wantNs:     "slurm",
+		},
+		{
+			name: "unknown flag",
+			args: args{
+				args: []string{"slurm-foo-0", "--foo"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var flags Flags
+			var reason string
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&bytes.Buffer{})
+			flags.bind(fs)
+			fs.StringVar(&reason, "reason", "", "")
+			got, err := parseInterspersed(fs, tt.args.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseInterspersed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("parseInterspersed() = %v, want %v", got, tt.want)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %v, want %v", reason, tt.wantReason)
+			}
+			if flags.namespace != tt.wantNs {
+				t.Errorf("namespace = %v, want %v", flags.namespace, tt.wantNs)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStdout string
+		wantErr    bool
+	}{
+		{
+			name: "no args",
+			args: args{
+				args: []string{},
+			},
+			wantStdout: "Usage:",
+		},
+		{
+			name: "help",
+			args: args{
+				args: []string{"help"},
+			},
+			wantStdout: "Commands:",
+		},
+		{
+			name: "unknown command",
+			args: args{
+				args: []string{"foo"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := run(context.TODO(), tt.args.args, stdout, stderr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("run() stdout = %v, want %v", stdout.String(), tt.wantStdout)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"net/http"
 	"os"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -13,6 +14,8 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -26,14 +29,21 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/accounting"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/controller"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/federation"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/loginset"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/restapi"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmclient"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/token"
+	"github.com/SlinkyProject/slurm-operator/internal/exporter"
 	// +kubebuilder:scaffold:imports
 )
 
+const (
+	// slurmExporterPath is the metrics server path of the built-in Slurm exporter.
+	slurmExporterPath = "/slurm/metrics"
+)
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -53,6 +63,7 @@ type Flags struct {
 	metricsAddr          string
 	secureMetrics        bool
 	enableHTTP2          bool
+	enableSlurmExporter  bool
 }
 
 func parseFlags(flags *Flags) {
@@ -79,6 +90,8 @@ func parseFlags(flags *Flags) {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&flags.enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&flags.enableSlurmExporter, "enable-slurm-exporter", false,
+		"If set, Slurm metrics of each Controller will be served by the metrics server at "+slurmExporterPath)
 	flag.Parse()
 }
 
@@ -105,11 +118,21 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	clientMap := clientmap.NewClientMap()
+
+	extraHandlers := map[string]http.Handler{}
+	if flags.enableSlurmExporter {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.NewCollector(clientMap))
+		extraHandlers[slurmExporterPath] = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
-			TLSOpts:     tlsOpts,
-			BindAddress: flags.metricsAddr,
+			TLSOpts:       tlsOpts,
+			BindAddress:   flags.metricsAddr,
+			ExtraHandlers: extraHandlers,
 		},
 		HealthProbeBindAddress:        flags.probeAddr,
 		LeaderElection:                flags.enableLeaderElection,
@@ -121,7 +144,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	clientMap := clientmap.NewClientMap()
 	eventCh := make(chan event.GenericEvent, 100)
 	if err := controller.NewReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Controller")
@@ -139,7 +161,7 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeSet")
 		os.Exit(1)
 	}
-	if err := loginset.NewReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+	if err := loginset.NewReconciler(mgr.GetClient(), mgr.GetConfig()).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LoginSet")
 		os.Exit(1)
 	}
@@ -151,6 +173,10 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Token")
 		os.Exit(1)
 	}
+	if err := federation.NewReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Federation")
+		os.Exit(1)
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -131,7 +131,9 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Controller")
 		os.Exit(1)
 	}
-	if err := (&webhookv1alpha1.RestapiWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhookv1alpha1.RestapiWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Restapi")
 		os.Exit(1)
 	}
@@ -139,18 +141,30 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Accounting")
 		os.Exit(1)
 	}
-	if err := (&webhookv1alpha1.NodeSetWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhookv1alpha1.NodeSetWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "NodeSet")
 		os.Exit(1)
 	}
-	if err = (&webhookv1alpha1.LoginSetWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&webhookv1alpha1.LoginSetWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "LoginSet")
 		os.Exit(1)
 	}
-	if err = (&webhookv1alpha1.TokenWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&webhookv1alpha1.TokenWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Token")
 		os.Exit(1)
 	}
+	if err = (&webhookv1alpha1.FederationWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Federation")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
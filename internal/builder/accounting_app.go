@@ -76,9 +76,26 @@ func (b *Builder) accountingPodTemplate(accounting *slinkyv1alpha1.Accounting) (
 		return corev1.PodTemplateSpec{}, err
 	}
 
+	rotatingController, err := b.getSlurmKeyRotationController(ctx, accounting)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	jwtRotatingController, err := b.getJwtKeyRotationController(ctx, accounting)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	hasJwks, err := b.AccountingHasJwks(accounting)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	objectMeta := metadata.NewBuilder(key).
 		WithLabels(labels.NewBuilder().WithAccountingLabels(accounting).Build()).
 		WithAnnotations(hashMap).
+		WithAnnotations(SlurmKeyRotationAnnotations(rotatingController)).
+		WithAnnotations(JwtKeyRotationAnnotations(jwtRotatingController)).
 		WithAnnotations(map[string]string{
 			annotationDefaultContainer: labels.AccountingApp,
 		}).
@@ -101,7 +118,7 @@ func (b *Builder) accountingPodTemplate(accounting *slinkyv1alpha1.Accounting) (
 			InitContainers: []corev1.Container{
 				b.initconfContainer(spec.InitConf),
 			},
-			Volumes: accountingVolumes(accounting),
+			Volumes: accountingVolumes(accounting, rotatingController, jwtRotatingController, hasJwks),
 		},
 		merge: template.PodSpec,
 	}
@@ -109,7 +126,11 @@ func (b *Builder) accountingPodTemplate(accounting *slinkyv1alpha1.Accounting) (
 	return b.buildPodTemplate(opts), nil
 }
 
-func accountingVolumes(accounting *slinkyv1alpha1.Accounting) []corev1.Volume {
+func accountingVolumes(accounting *slinkyv1alpha1.Accounting, rotatingController, jwtRotatingController *slinkyv1alpha1.Controller, hasJwks bool) []corev1.Volume {
+	jwtHs256Ref := accounting.AuthJwtHs256Ref()
+	if jwtRotatingController.AuthJwtHs256NewRef() != nil {
+		jwtHs256Ref = jwtRotatingController.AuthJwtHs256VerifyingRef()
+	}
 	out := []corev1.Volume{
 		etcSlurmVolume(),
 		{
@@ -141,10 +162,10 @@ func accountingVolumes(accounting *slinkyv1alpha1.Accounting) []corev1.Volume {
 						{
 							Secret: &corev1.SecretProjection{
 								LocalObjectReference: corev1.LocalObjectReference{
-									Name: accounting.AuthJwtHs256Ref().Name,
+									Name: jwtHs256Ref.Name,
 								},
 								Items: []corev1.KeyToPath{
-									{Key: accounting.AuthJwtHs256Ref().Key, Path: JwtHs256KeyFile},
+									{Key: jwtHs256Ref.Key, Path: JwtHs256KeyFile},
 								},
 							},
 						},
@@ -154,6 +175,12 @@ func accountingVolumes(accounting *slinkyv1alpha1.Accounting) []corev1.Volume {
 		},
 		pidfileVolume(),
 	}
+	if rotatingController.HasSlurmJwks() {
+		out[1].Projected.Sources = append(out[1].Projected.Sources, slurmJwksVolumeProjection(rotatingController))
+	}
+	if hasJwks {
+		out[1].Projected.Sources = append(out[1].Projected.Sources, jwksVolumeProjection(accounting.JwksKey().Name))
+	}
 	return out
 }
 
@@ -195,6 +222,46 @@ const (
 	annotationSlurmdbdConfHash = slinkyv1alpha1.SlinkyPrefix + "slurmdbd-conf-hash"
 )
 
+// getSlurmKeyRotationController returns the Controller which is rotating the
+// Slurm key shared with the Accounting, otherwise an empty Controller.
+func (b *Builder) getSlurmKeyRotationController(ctx context.Context, accounting *slinkyv1alpha1.Accounting) (*slinkyv1alpha1.Controller, error) {
+	controllerList, err := b.refResolver.GetControllersForAccounting(ctx, accounting)
+	if err != nil {
+		return nil, err
+	}
+	for _, controller := range controllerList.Items {
+		if controller.Namespace == accounting.Namespace &&
+			controller.AuthSlurmKey() == accounting.AuthSlurmKey() &&
+			controller.AuthSlurmNewRef() != nil {
+			return &controller, nil
+		}
+	}
+	return &slinkyv1alpha1.Controller{}, nil
+}
+
+// getJwtKeyRotationController returns the Controller which is rotating the JWT
+// HS256 key shared with the Accounting to the same new key, otherwise an empty
+// Controller. Only the Controller reports the phase of the rotation.
+func (b *Builder) getJwtKeyRotationController(ctx context.Context, accounting *slinkyv1alpha1.Accounting) (*slinkyv1alpha1.Controller, error) {
+	newRef := accounting.AuthJwtHs256NewRef()
+	if newRef == nil {
+		return &slinkyv1alpha1.Controller{}, nil
+	}
+	controllerList, err := b.refResolver.GetControllersForAccounting(ctx, accounting)
+	if err != nil {
+		return nil, err
+	}
+	for _, controller := range controllerList.Items {
+		if controller.Namespace == accounting.Namespace &&
+			controller.AuthJwtHs256Key() == accounting.AuthJwtHs256Key() &&
+			controller.AuthJwtHs256NewRef() != nil &&
+			*controller.AuthJwtHs256NewRef() == *newRef {
+			return &controller, nil
+		}
+	}
+	return &slinkyv1alpha1.Controller{}, nil
+}
+
 func (b *Builder) getAccountingHashes(ctx context.Context, accounting *slinkyv1alpha1.Accounting) (map[string]string, error) {
 	hashMap, err := b.getAuthHashesFromAccounting(ctx, accounting)
 	if err != nil {
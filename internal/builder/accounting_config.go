@@ -15,7 +15,14 @@ import (
 )
 
 func (b *Builder) BuildAccountingConfig(accounting *slinkyv1alpha1.Accounting) (*corev1.Secret, error) {
-	storagePass, err := b.refResolver.GetSecretKeyRef(context.TODO(), accounting.AuthStorageRef(), accounting.Namespace)
+	ctx := context.TODO()
+
+	storagePass, err := b.refResolver.GetSecretKeyRef(ctx, accounting.AuthStorageRef(), accounting.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcs, err := b.getAccountingOidcs(ctx, accounting)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +31,7 @@ func (b *Builder) BuildAccountingConfig(accounting *slinkyv1alpha1.Accounting) (
 		Key:      accounting.ConfigKey(),
 		Metadata: accounting.Spec.Template.PodMetadata,
 		StringData: map[string]string{
-			slurmdbdConfFile: buildSlurmdbdConf(accounting, string(storagePass)),
+			slurmdbdConfFile: buildSlurmdbdConf(accounting, string(storagePass), oidcs),
 		},
 	}
 
@@ -34,7 +41,7 @@ func (b *Builder) BuildAccountingConfig(accounting *slinkyv1alpha1.Accounting) (
 }
 
 // https://slurm.schedmd.com/slurmdbd.conf.html
-func buildSlurmdbdConf(accounting *slinkyv1alpha1.Accounting, storagePass string) string {
+func buildSlurmdbdConf(accounting *slinkyv1alpha1.Accounting, storagePass string, oidcs []slinkyv1alpha1.RestApiOidc) string {
 	dbdHost := accounting.PrimaryName()
 	storageHost := accounting.Spec.StorageConfig.Host
 	storagePort := accounting.Spec.StorageConfig.Port
@@ -53,7 +60,7 @@ func buildSlurmdbdConf(accounting *slinkyv1alpha1.Accounting, storagePass string
 	conf.AddProperty(config.NewPropertyRaw("### PLUGINS & PARAMETERS ###"))
 	conf.AddProperty(config.NewProperty("AuthType", authType))
 	conf.AddProperty(config.NewProperty("AuthAltTypes", authAltTypes))
-	conf.AddProperty(config.NewProperty("AuthAltParameters", authAltParameters))
+	conf.AddProperty(config.NewProperty("AuthAltParameters", buildAuthAltParameters(accounting.HasJwks() || len(oidcs) > 0, oidcUsernameClaim(oidcs))))
 	conf.AddProperty(config.NewProperty("AuthInfo", authInfo))
 
 	conf.AddProperty(config.NewPropertyRaw("#"))
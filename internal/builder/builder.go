@@ -4,22 +4,29 @@
 package builder
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	annotationDefaultContainer = "kubectl.kubernetes.io/default-container"
+
+	httpClientTimeout = 10 * time.Second
 )
 
 type Builder struct {
 	client      client.Client
 	refResolver *refresolver.RefResolver
+	httpClient  *http.Client
 }
 
 func New(c client.Client) *Builder {
 	return &Builder{
 		client:      c,
 		refResolver: refresolver.New(c),
+		httpClient:  &http.Client{Timeout: httpClientTimeout},
 	}
 }
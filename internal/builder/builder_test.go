@@ -4,6 +4,7 @@
 package builder
 
 import (
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -37,6 +38,7 @@ func TestNew(t *testing.T) {
 			want: &Builder{
 				client:      nil,
 				refResolver: refresolver.New(nil),
+				httpClient:  &http.Client{Timeout: httpClientTimeout},
 			},
 		},
 		{
@@ -47,6 +49,7 @@ func TestNew(t *testing.T) {
 			want: &Builder{
 				client:      c,
 				refResolver: refresolver.New(c),
+				httpClient:  &http.Client{Timeout: httpClientTimeout},
 			},
 		},
 	}
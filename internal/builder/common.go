@@ -6,6 +6,8 @@ package builder
 import (
 	_ "embed"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -36,15 +38,18 @@ const (
 	slurmLogFileVolume = "slurm-logfile"
 	slurmLogFileDir    = "/var/log/slurm"
 
-	slurmKeyFile = "slurm.key"
-	authType     = "auth/slurm"
-	credType     = "cred/slurm" // #nosec G101
-	authInfo     = "use_client_ids"
+	slurmKeyFile  = "slurm.key"
+	SlurmJwksFile = "slurm.jwks"
+	authType      = "auth/slurm"
+	credType      = "cred/slurm" // #nosec G101
+	authInfo      = "use_client_ids"
 
 	authAltTypes      = "auth/jwt"
 	JwtHs256KeyFile   = "jwt_hs256.key"
 	jwtHs256KeyPath   = slurmEtcDir + "/" + JwtHs256KeyFile
 	authAltParameters = "jwt_key=" + jwtHs256KeyPath
+	JwksFile          = "jwks.json"
+	jwksPath          = slurmEtcDir + "/" + JwksFile
 
 	logTimeFormat = "iso8601,format_stderr"
 
@@ -54,16 +59,148 @@ const (
 const (
 	annotationAuthSlurmKeyHash    = slinkyv1alpha1.SlinkyPrefix + "slurm-key-hash"
 	annotationAuthJwtHs256KeyHash = slinkyv1alpha1.SlinkyPrefix + "jwt-hs256-key-hash"
+
+	// AnnotationSlurmKeyRotation identifies the phase of a Slurm key rotation a pod was created for.
+	AnnotationSlurmKeyRotation = slinkyv1alpha1.SlinkyPrefix + "slurm-key-rotation"
+	// AnnotationJwtHs256KeyRotation identifies the phase of a JWT HS256 key rotation a pod was created for.
+	AnnotationJwtHs256KeyRotation = slinkyv1alpha1.SlinkyPrefix + "jwt-hs256-key-rotation"
 )
 
+// JwtKeyRotationAnnotations returns the pod annotations which cause slurmctld,
+// slurmdbd, and slurmrestd to be restarted with the key which Slurm verifies
+// JWTs with during a JWT HS256 key rotation.
+func JwtKeyRotationAnnotations(controller *slinkyv1alpha1.Controller) map[string]string {
+	newRef := controller.AuthJwtHs256NewRef()
+	if newRef == nil {
+		return nil
+	}
+	verifyingRef := controller.AuthJwtHs256VerifyingRef()
+	return map[string]string{
+		AnnotationJwtHs256KeyRotation: fmt.Sprintf("%s/%s,verifying=%s/%s", newRef.Name, newRef.Key, verifyingRef.Name, verifyingRef.Key),
+	}
+}
+
+// SlurmKeyRotationAnnotations returns the pod annotations which cause Slurm
+// pods to be rolled out through each phase of a Slurm key rotation. Once rolled
+// back, the new key is omitted as pods no longer accept it.
+func SlurmKeyRotationAnnotations(controller *slinkyv1alpha1.Controller) map[string]string {
+	newRef := controller.AuthSlurmNewRef()
+	if newRef == nil {
+		return nil
+	}
+	signingRef := controller.AuthSlurmSigningRef()
+	if !controller.HasSlurmJwks() {
+		return map[string]string{
+			AnnotationSlurmKeyRotation: fmt.Sprintf("signing=%s/%s", signingRef.Name, signingRef.Key),
+		}
+	}
+	return map[string]string{
+		AnnotationSlurmKeyRotation: fmt.Sprintf("%s/%s,signing=%s/%s", newRef.Name, newRef.Key, signingRef.Name, signingRef.Key),
+	}
+}
+
+// slurmAuthVolumeProjections returns the projections of the Slurm key, and of
+// the Slurm JWKS during a Slurm key rotation, into the etc volume of a pod in
+// the namespace. In another namespace than the controller, both are copied into
+// the namespace by the controller.
+func slurmAuthVolumeProjections(controller *slinkyv1alpha1.Controller, namespace string) []corev1.VolumeProjection {
+	if namespace != controller.Namespace {
+		items := []corev1.KeyToPath{
+			{Key: slurmKeyFile, Path: slurmKeyFile},
+		}
+		if controller.HasSlurmJwks() {
+			items = append(items, corev1.KeyToPath{Key: SlurmJwksFile, Path: SlurmJwksFile})
+		}
+		return []corev1.VolumeProjection{
+			{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: controller.TenantAuthKey(namespace).Name,
+					},
+					Items: items,
+				},
+			},
+		}
+	}
+	out := []corev1.VolumeProjection{
+		{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: controller.AuthSlurmRef().Name,
+				},
+				Items: []corev1.KeyToPath{
+					{Key: controller.AuthSlurmRef().Key, Path: slurmKeyFile},
+				},
+			},
+		},
+	}
+	if controller.HasSlurmJwks() {
+		out = append(out, slurmJwksVolumeProjection(controller))
+	}
+	return out
+}
+
+// slurmJwksVolumeProjection returns the projection of the Slurm JWKS Secret into the etc volume.
+func slurmJwksVolumeProjection(controller *slinkyv1alpha1.Controller) corev1.VolumeProjection {
+	return corev1.VolumeProjection{
+		Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: controller.SlurmJwksKey().Name,
+			},
+			Items: []corev1.KeyToPath{
+				{Key: SlurmJwksFile, Path: SlurmJwksFile},
+			},
+		},
+	}
+}
+
+// buildAuthAltParameters returns the `auth/jwt` parameters. When an RS256 key
+// is used, or when JWTs of an OIDC issuer are accepted, the JWKS is also loaded
+// so JWTs signed by any of its RS256 keys are accepted. The usernameClaim, if
+// any, maps a JWT claim to the username.
+func buildAuthAltParameters(hasJwks bool, usernameClaim string) string {
+	out := authAltParameters
+	if hasJwks {
+		out += ",jwks=" + jwksPath
+	}
+	if usernameClaim != "" {
+		out += ",userclaimfield=" + usernameClaim
+	}
+	return out
+}
+
+// jwksVolumeProjection returns the projection of the JWKS Secret into the etc volume.
+func jwksVolumeProjection(name string) corev1.VolumeProjection {
+	return corev1.VolumeProjection{
+		Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: name,
+			},
+			Items: []corev1.KeyToPath{
+				{Key: JwksFile, Path: JwksFile},
+			},
+		},
+	}
+}
+
 func configlessArgs(controller *slinkyv1alpha1.Controller) []string {
 	args := []string{
 		"--conf-server",
-		fmt.Sprintf("%s:%d", controller.ServiceFQDNShort(), SlurmctldPort),
+		slurmctldAddress(controller),
 	}
 	return args
 }
 
+// slurmctldAddress returns the `host:port` of slurmctld, which is either
+// external to Kubernetes or the Service of the Controller.
+func slurmctldAddress(controller *slinkyv1alpha1.Controller) string {
+	if external := controller.Spec.External; external != nil {
+		port := defaultPort(external.Port, SlurmctldPort)
+		return net.JoinHostPort(external.Host, strconv.Itoa(int(port)))
+	}
+	return fmt.Sprintf("%s:%d", controller.ServiceFQDNShort(), SlurmctldPort)
+}
+
 //go:embed scripts/initconf.sh
 var initConfScript string
 
@@ -10,6 +10,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 )
 
 func Test_mergeEnvVar(t *testing.T) {
@@ -146,3 +149,118 @@ func Test_mergeEnvVar(t *testing.T) {
 		})
 	}
 }
+
+func Test_slurmctldAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		controller *slinkyv1alpha1.Controller
+		want       string
+	}{
+		{
+			name: "Service",
+			controller: &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{Name: "slurm", Namespace: "slurm"},
+			},
+			want: "slurm-controller.slurm:6817",
+		},
+		{
+			name: "External",
+			controller: &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{Name: "slurm", Namespace: "slurm"},
+				Spec: slinkyv1alpha1.ControllerSpec{
+					External: &slinkyv1alpha1.ExternalController{Host: "slurmctld.example.com", Port: 7817},
+				},
+			},
+			want: "slurmctld.example.com:7817",
+		},
+		{
+			name: "External, default port",
+			controller: &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{Name: "slurm", Namespace: "slurm"},
+				Spec: slinkyv1alpha1.ControllerSpec{
+					External: &slinkyv1alpha1.ExternalController{Host: "fd00::1"},
+				},
+			},
+			want: "[fd00::1]:6817",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slurmctldAddress(tt.controller); got != tt.want {
+				t.Errorf("slurmctldAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_slurmAuthVolumeProjections(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{Name: "slurm", Namespace: "slurm"},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			SlurmKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "slurm-auth-slurm"},
+				Key:                  "slurm.key",
+			},
+		},
+	}
+	rotatingController := controller.DeepCopy()
+	rotatingController.Spec.SlurmKeyRotation = &slinkyv1alpha1.SlurmKeyRotation{
+		NewKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "slurm-auth-slurm-new"},
+			Key:                  "slurm.key",
+		},
+	}
+	tests := []struct {
+		name       string
+		controller *slinkyv1alpha1.Controller
+		namespace  string
+		wantNames  []string
+		wantItems  int
+	}{
+		{
+			name:       "Same namespace",
+			controller: controller,
+			namespace:  "slurm",
+			wantNames:  []string{"slurm-auth-slurm"},
+			wantItems:  1,
+		},
+		{
+			name:       "Same namespace, rotating",
+			controller: rotatingController,
+			namespace:  "slurm",
+			wantNames:  []string{"slurm-auth-slurm", "slurm-slurm-jwks"},
+			wantItems:  2,
+		},
+		{
+			name:       "Other namespace",
+			controller: controller,
+			namespace:  "tenant",
+			wantNames:  []string{"slurm-slurm-auth-slurm"},
+			wantItems:  1,
+		},
+		{
+			name:       "Other namespace, rotating",
+			controller: rotatingController,
+			namespace:  "tenant",
+			wantNames:  []string{"slurm-slurm-auth-slurm"},
+			wantItems:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slurmAuthVolumeProjections(tt.controller, tt.namespace)
+			names := []string{}
+			items := 0
+			for _, projection := range got {
+				names = append(names, projection.Secret.Name)
+				items += len(projection.Secret.Items)
+			}
+			if !apiequality.Semantic.DeepEqual(names, tt.wantNames) {
+				t.Errorf("slurmAuthVolumeProjections() names = %v, want %v", names, tt.wantNames)
+			}
+			if items != tt.wantItems {
+				t.Errorf("slurmAuthVolumeProjections() items = %v, want %v", items, tt.wantItems)
+			}
+		})
+	}
+}
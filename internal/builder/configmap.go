@@ -36,8 +36,14 @@ func (b *Builder) BuildConfigMap(opts ConfigMapOpts, owner metav1.Object) (*core
 		Immutable:  ptr.To(opts.Immutable),
 	}
 
-	if err := controllerutil.SetControllerReference(owner, out, b.client.Scheme()); err != nil {
-		return nil, fmt.Errorf("failed to set owner controller: %w", err)
+	if owner == nil {
+		return nil, fmt.Errorf("failed to specify an owner")
+	}
+
+	if owner.GetNamespace() == out.GetNamespace() {
+		if err := controllerutil.SetControllerReference(owner, out, b.client.Scheme()); err != nil {
+			return nil, fmt.Errorf("failed to set owner controller: %w", err)
+		}
 	}
 
 	return out, nil
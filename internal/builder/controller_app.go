@@ -10,6 +10,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -34,6 +35,8 @@ const (
 	slurmctldStateSaveVolume = "statesave"
 
 	slurmctldSpoolDir = "/var/spool/slurmctld"
+
+	internalSlurmrestdEtcVolume = "slurmrestd-etc"
 )
 
 func (b *Builder) BuildController(controller *slinkyv1alpha1.Controller) (*appsv1.StatefulSet, error) {
@@ -108,27 +111,20 @@ func (b *Builder) BuildController(controller *slinkyv1alpha1.Controller) (*appsv
 func (b *Builder) controllerPodTemplate(controller *slinkyv1alpha1.Controller) (corev1.PodTemplateSpec, error) {
 	key := controller.Key()
 
-	size := len(controller.Spec.ConfigFileRefs) + len(controller.Spec.PrologScriptRefs) + len(controller.Spec.EpilogScriptRefs) + len(controller.Spec.PrologSlurmctldScriptRefs) + len(controller.Spec.EpilogSlurmctldScriptRefs)
-	extraConfigMapNames := make([]string, 0, size)
-	for _, ref := range controller.Spec.ConfigFileRefs {
-		extraConfigMapNames = append(extraConfigMapNames, ref.Name)
-	}
-	for _, ref := range controller.Spec.PrologScriptRefs {
-		extraConfigMapNames = append(extraConfigMapNames, ref.Name)
-	}
-	for _, ref := range controller.Spec.EpilogScriptRefs {
-		extraConfigMapNames = append(extraConfigMapNames, ref.Name)
-	}
-	for _, ref := range controller.Spec.PrologSlurmctldScriptRefs {
-		extraConfigMapNames = append(extraConfigMapNames, ref.Name)
-	}
-	for _, ref := range controller.Spec.EpilogSlurmctldScriptRefs {
-		extraConfigMapNames = append(extraConfigMapNames, ref.Name)
+	extraConfigMapNames := controllerExtraConfigMapNames(controller)
+
+	hasJwks, err := b.ControllerHasJwks(controller)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
 	}
 
+	hasAccounting := !apiequality.Semantic.DeepEqual(controller.Spec.AccountingRef, slinkyv1alpha1.ObjectReference{})
+
 	objectMeta := metadata.NewBuilder(key).
 		WithMetadata(controller.Spec.Template.PodMetadata).
 		WithLabels(labels.NewBuilder().WithControllerLabels(controller).Build()).
+		WithAnnotations(SlurmKeyRotationAnnotations(controller)).
+		WithAnnotations(JwtKeyRotationAnnotations(controller)).
 		WithAnnotations(map[string]string{
 			annotationDefaultContainer: labels.ControllerApp,
 		}).
@@ -158,15 +154,67 @@ func (b *Builder) controllerPodTemplate(controller *slinkyv1alpha1.Controller) (
 				RunAsGroup:   ptr.To(slurmUserGid),
 				FSGroup:      ptr.To(slurmUserGid),
 			},
-			Volumes: controllerVolumes(controller, extraConfigMapNames),
+			Volumes: controllerVolumes(controller, extraConfigMapNames, hasJwks),
 		},
 		merge: template.PodSpec,
 	}
 
+	if spec.InternalSlurmrestd != nil {
+		opts.base.Containers = append(opts.base.Containers,
+			b.internalSlurmrestdContainer(*spec.InternalSlurmrestd, hasAccounting))
+		opts.base.Volumes = append(opts.base.Volumes, internalSlurmrestdEtcVolumeFor(controller, hasJwks))
+	}
+
 	return b.buildPodTemplate(opts), nil
 }
 
-func controllerVolumes(controller *slinkyv1alpha1.Controller, extra []string) []corev1.Volume {
+// internalSlurmrestdContainer returns the slurmrestd sidecar of slurmctld.
+// slurmrestd refuses to run as the SlurmUser, so it runs as nobody in the slurm
+// group, with its own copy of the configuration.
+func (b *Builder) internalSlurmrestdContainer(container slinkyv1alpha1.ContainerMinimal, hasAccounting bool) corev1.Container {
+	merge := &corev1.Container{}
+	clientutils.RemarshalOrDie(container, merge)
+
+	out := b.slurmrestdContainer(*merge, hasAccounting)
+	out.SecurityContext.RunAsGroup = ptr.To(slurmUserGid)
+	out.VolumeMounts = []corev1.VolumeMount{
+		{Name: internalSlurmrestdEtcVolume, MountPath: slurmEtcDir, ReadOnly: true},
+	}
+
+	return out
+}
+
+func internalSlurmrestdEtcVolumeFor(controller *slinkyv1alpha1.Controller, hasJwks bool) corev1.Volume {
+	out := restapiVolumes(controller, hasJwks)[0]
+	out.Name = internalSlurmrestdEtcVolume
+	out.Projected.DefaultMode = ptr.To[int32](0o640)
+	return out
+}
+
+// controllerExtraConfigMapNames returns the ConfigMaps of the config files and
+// scripts of the Controller, which are mounted in `/etc/slurm`.
+func controllerExtraConfigMapNames(controller *slinkyv1alpha1.Controller) []string {
+	size := len(controller.Spec.ConfigFileRefs) + len(controller.Spec.PrologScriptRefs) + len(controller.Spec.EpilogScriptRefs) + len(controller.Spec.PrologSlurmctldScriptRefs) + len(controller.Spec.EpilogSlurmctldScriptRefs)
+	out := make([]string, 0, size)
+	for _, ref := range controller.Spec.ConfigFileRefs {
+		out = append(out, ref.Name)
+	}
+	for _, ref := range controller.Spec.PrologScriptRefs {
+		out = append(out, ref.Name)
+	}
+	for _, ref := range controller.Spec.EpilogScriptRefs {
+		out = append(out, ref.Name)
+	}
+	for _, ref := range controller.Spec.PrologSlurmctldScriptRefs {
+		out = append(out, ref.Name)
+	}
+	for _, ref := range controller.Spec.EpilogSlurmctldScriptRefs {
+		out = append(out, ref.Name)
+	}
+	return out
+}
+
+func controllerVolumes(controller *slinkyv1alpha1.Controller, extra []string, hasJwks bool) []corev1.Volume {
 	out := []corev1.Volume{
 		{
 			Name: slurmEtcVolume,
@@ -194,10 +242,10 @@ func controllerVolumes(controller *slinkyv1alpha1.Controller, extra []string) []
 						{
 							Secret: &corev1.SecretProjection{
 								LocalObjectReference: corev1.LocalObjectReference{
-									Name: controller.AuthJwtHs256Ref().Name,
+									Name: controller.AuthJwtHs256VerifyingRef().Name,
 								},
 								Items: []corev1.KeyToPath{
-									{Key: controller.AuthJwtHs256Ref().Key, Path: JwtHs256KeyFile},
+									{Key: controller.AuthJwtHs256VerifyingRef().Key, Path: JwtHs256KeyFile},
 								},
 							},
 						},
@@ -214,6 +262,12 @@ func controllerVolumes(controller *slinkyv1alpha1.Controller, extra []string) []
 			},
 		},
 	}
+	if controller.HasSlurmJwks() {
+		out[0].Projected.Sources = append(out[0].Projected.Sources, slurmJwksVolumeProjection(controller))
+	}
+	if hasJwks {
+		out[0].Projected.Sources = append(out[0].Projected.Sources, jwksVolumeProjection(controller.JwksKey().Name))
+	}
 	for _, name := range extra {
 		volumeProjection := corev1.VolumeProjection{
 			ConfigMap: &corev1.ConfigMapProjection{
@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
@@ -27,6 +28,37 @@ const (
 )
 
 func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (*corev1.ConfigMap, error) {
+	data, err := b.buildControllerConfigData(controller, true)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ConfigMapOpts{
+		Key:      controller.ConfigKey(),
+		Metadata: controller.Spec.Template.PodMetadata,
+		Data:     data,
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
+
+	return b.BuildConfigMap(opts, controller)
+}
+
+// BuildSlurmConf renders the `slurm.conf` of the controller, as it would be
+// found in the controller config. The ConfigMaps referenced by the controller
+// are not read (e.g. during admission, before they are created), so its prolog
+// and epilog scripts are omitted and cgroup is assumed to be enabled.
+func (b *Builder) BuildSlurmConf(controller *slinkyv1alpha1.Controller) (string, error) {
+	data, err := b.buildControllerConfigData(controller, false)
+	if err != nil {
+		return "", err
+	}
+	return data[slurmConfFile], nil
+}
+
+// buildControllerConfigData renders the config files of the controller. When
+// readConfigMaps is false, its referenced ConfigMaps are not read.
+func (b *Builder) buildControllerConfigData(controller *slinkyv1alpha1.Controller, readConfigMaps bool) (map[string]string, error) {
 	ctx := context.TODO()
 
 	accounting, err := b.refResolver.GetAccounting(ctx, controller.Spec.AccountingRef)
@@ -41,6 +73,24 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 		return nil, err
 	}
 
+	oidcs, err := b.getControllerOidcs(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+
+	federation, err := b.refResolver.GetFederationForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+
+	// getConfigMap leaves the ConfigMap empty when it is not read.
+	getConfigMap := func(key types.NamespacedName, cm *corev1.ConfigMap) error {
+		if !readConfigMaps {
+			return nil
+		}
+		return b.client.Get(ctx, key, cm)
+	}
+
 	configFilesList := &corev1.ConfigMapList{
 		Items: make([]corev1.ConfigMap, 0, len(controller.Spec.ConfigFileRefs)),
 	}
@@ -50,7 +100,7 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 			Namespace: controller.Namespace,
 			Name:      ref.Name,
 		}
-		if err := b.client.Get(ctx, key, cm); err != nil {
+		if err := getConfigMap(key, cm); err != nil {
 			return nil, err
 		}
 		configFilesList.Items = append(configFilesList.Items, *cm)
@@ -71,7 +121,7 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 			Namespace: controller.Namespace,
 			Name:      ref.Name,
 		}
-		if err := b.client.Get(ctx, key, cm); err != nil {
+		if err := getConfigMap(key, cm); err != nil {
 			return nil, err
 		}
 		filenames := structutils.Keys(cm.Data)
@@ -86,7 +136,7 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 			Namespace: controller.Namespace,
 			Name:      ref.Name,
 		}
-		if err := b.client.Get(ctx, key, cm); err != nil {
+		if err := getConfigMap(key, cm); err != nil {
 			return nil, err
 		}
 		filenames := structutils.Keys(cm.Data)
@@ -101,7 +151,7 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 			Namespace: controller.Namespace,
 			Name:      ref.Name,
 		}
-		if err := b.client.Get(ctx, key, cm); err != nil {
+		if err := getConfigMap(key, cm); err != nil {
 			return nil, err
 		}
 		filenames := structutils.Keys(cm.Data)
@@ -116,7 +166,7 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 			Namespace: controller.Namespace,
 			Name:      ref.Name,
 		}
-		if err := b.client.Get(ctx, key, cm); err != nil {
+		if err := getConfigMap(key, cm); err != nil {
 			return nil, err
 		}
 		filenames := structutils.Keys(cm.Data)
@@ -124,27 +174,23 @@ func (b *Builder) BuildControllerConfig(controller *slinkyv1alpha1.Controller) (
 		epilogSlurmctldScripts = filenames
 	}
 
-	opts := ConfigMapOpts{
-		Key:      controller.ConfigKey(),
-		Metadata: controller.Spec.Template.PodMetadata,
-		Data: map[string]string{
-			slurmConfFile: buildSlurmConf(controller, accounting, nodesetList, prologScripts, epilogScripts, prologSlurmctldScripts, epilogSlurmctldScripts, cgroupEnabled),
-		},
+	data := map[string]string{
+		slurmConfFile: buildSlurmConf(controller, accounting, federation, nodesetList, oidcs, prologScripts, epilogScripts, prologSlurmctldScripts, epilogSlurmctldScripts, cgroupEnabled),
 	}
 	if !hasCgroupConfFile {
-		opts.Data[cgroupConfFile] = buildCgroupConf()
+		data[cgroupConfFile] = buildCgroupConf()
 	}
 
-	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
-
-	return b.BuildConfigMap(opts, controller)
+	return data, nil
 }
 
 // https://slurm.schedmd.com/slurm.conf.html
 func buildSlurmConf(
 	controller *slinkyv1alpha1.Controller,
 	accounting *slinkyv1alpha1.Accounting,
+	federation *slinkyv1alpha1.Federation,
 	nodesetList *slinkyv1alpha1.NodeSetList,
+	oidcs []slinkyv1alpha1.RestApiOidc,
 	prologScripts, epilogScripts []string,
 	prologSlurmctldScripts, epilogSlurmctldScripts []string,
 	cgroupEnabled bool,
@@ -179,7 +225,7 @@ func buildSlurmConf(
 	conf.AddProperty(config.NewProperty("AuthType", authType))
 	conf.AddProperty(config.NewProperty("CredType", credType))
 	conf.AddProperty(config.NewProperty("AuthAltTypes", authAltTypes))
-	conf.AddProperty(config.NewProperty("AuthAltParameters", authAltParameters))
+	conf.AddProperty(config.NewProperty("AuthAltParameters", buildAuthAltParameters(controller.HasJwks() || len(oidcs) > 0, oidcUsernameClaim(oidcs))))
 	conf.AddProperty(config.NewProperty("AuthInfo", authInfo))
 	conf.AddProperty(config.NewProperty("CommunicationParameters", "block_null_hash"))
 	conf.AddProperty(config.NewProperty("SelectTypeParameters", "CR_Core_Memory"))
@@ -208,6 +254,12 @@ func buildSlurmConf(
 		conf.AddProperty(config.NewProperty("JobAcctGatherType", "jobacct_gather/none"))
 	}
 
+	if federation != nil && len(federation.Spec.Parameters) > 0 {
+		conf.AddProperty(config.NewPropertyRaw("#"))
+		conf.AddProperty(config.NewPropertyRaw("### FEDERATION ###"))
+		conf.AddProperty(config.NewProperty("FederationParameters", strings.Join(federation.Spec.Parameters, ",")))
+	}
+
 	if len(prologSlurmctldScripts) > 0 || len(epilogSlurmctldScripts) > 0 {
 		conf.AddProperty(config.NewPropertyRaw("#"))
 		conf.AddProperty(config.NewPropertyRaw("### SLURMCTLD PROLOG & EPILOG ###"))
@@ -237,10 +289,11 @@ func buildSlurmConf(
 		conf.AddProperty(config.NewPropertyRaw("### COMPUTE & PARTITION ###"))
 	}
 	for _, nodeset := range nodesetList.Items {
-		name := nodeset.Name
-		template := nodeset.Spec.Template.PodSpecWrapper
-		if template.Hostname != "" {
-			name = strings.Trim(template.Hostname, "-")
+		name := nodesetFeatureName(&nodeset)
+		if isStaticNodeSet(&nodeset) {
+			for _, nodeLine := range buildStaticNodeLines(&nodeset) {
+				conf.AddProperty(config.NewPropertyRaw(nodeLine))
+			}
 		}
 		nodesetLine := []string{
 			fmt.Sprintf("NodeSet=%v", name),
@@ -269,6 +322,26 @@ func buildSlurmConf(
 	return conf.Build()
 }
 
+// buildStaticNodeLines returns a `NodeName` line for each ordinal of the nodeset.
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_NODE-CONFIGURATION
+func buildStaticNodeLines(nodeset *slinkyv1alpha1.NodeSet) []string {
+	replicas := int(ptr.Deref(nodeset.Spec.Replicas, 0))
+	nodeConf := slurmdConf(nodeset)
+	service := slurmClusterWorkerService(nodeset.Spec.ControllerRef.Name, nodeset.Namespace)
+
+	lines := make([]string, 0, replicas)
+	for ordinal := range replicas {
+		hostname := workerHostname(nodeset, ordinal)
+		nodeLine := []string{
+			fmt.Sprintf("NodeName=%v", hostname),
+			fmt.Sprintf("NodeAddr=%v.%v", hostname, service),
+		}
+		nodeLine = append(nodeLine, nodeConf...)
+		lines = append(lines, strings.Join(nodeLine, " "))
+	}
+	return lines
+}
+
 // https://slurm.schedmd.com/cgroup.conf.html
 func buildCgroupConf() string {
 	conf := config.NewBuilder()
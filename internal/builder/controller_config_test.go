@@ -8,8 +8,11 @@ import (
 	"testing"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmconf"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -22,10 +25,11 @@ func TestBuilder_BuildControllerConfig(t *testing.T) {
 		controller *slinkyv1alpha1.Controller
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
+		name         string
+		fields       fields
+		args         args
+		wantContains []string
+		wantErr      bool
 	}{
 		{
 			name: "default",
@@ -144,6 +148,34 @@ func TestBuilder_BuildControllerConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with federation",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithObjects(&slinkyv1alpha1.Federation{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "fed",
+						},
+						Spec: slinkyv1alpha1.FederationSpec{
+							Clusters: []slinkyv1alpha1.FederationCluster{
+								{ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm"}},
+							},
+							Parameters: []string{"fed_display"},
+						},
+					}).
+					Build(),
+			},
+			args: args{
+				controller: &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "slurm",
+					},
+				},
+			},
+			wantContains: []string{
+				"FederationParameters=fed_display",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -160,10 +192,47 @@ func TestBuilder_BuildControllerConfig(t *testing.T) {
 			case got.Data[slurmConfFile] == "" && got.BinaryData[slurmConfFile] == nil:
 				t.Errorf("got.Data[%s] = %v", slurmConfFile, got.Data[slurmConfFile])
 			}
+			if warns, errs := slurmconf.Lint(got.Data[slurmConfFile]); len(warns) > 0 || len(errs) > 0 {
+				t.Errorf("slurmconf.Lint() warns = %v, errs = %v", warns, errs)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got.Data[slurmConfFile], want) {
+					t.Errorf("got.Data[%s] does not contain %q", slurmConfFile, want)
+				}
+			}
 		})
 	}
 }
 
+func TestBuilder_BuildSlurmConf(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			ExtraConf: "PartitionName=all Nodes=slinky",
+			ConfigFileRefs: []slinkyv1alpha1.ObjectReference{
+				{Name: "config"},
+			},
+			PrologScriptRefs: []slinkyv1alpha1.ObjectReference{
+				{Name: "prolog"},
+			},
+		},
+	}
+	// The referenced ConfigMaps, and NodeSets, do not exist yet.
+	b := New(fake.NewFakeClient())
+	got, err := b.BuildSlurmConf(controller)
+	if err != nil {
+		t.Fatalf("Builder.BuildSlurmConf() error = %v", err)
+	}
+	if !strings.Contains(got, "PartitionName=all Nodes=slinky") {
+		t.Errorf("Builder.BuildSlurmConf() = %v, want extraConf", got)
+	}
+	if _, errs := slurmconf.Lint(got); len(errs) > 0 {
+		t.Errorf("slurmconf.Lint() errs = %v", errs)
+	}
+}
+
 func Test_isCgroupEnabled(t *testing.T) {
 	type args struct {
 		cgroupConf string
@@ -214,3 +283,91 @@ ignoresystemd=yes`,
 		})
 	}
 }
+
+func Test_buildStaticNodeLines(t *testing.T) {
+	type args struct {
+		nodeset *slinkyv1alpha1.NodeSet
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "no replicas",
+			args: args{
+				nodeset: &slinkyv1alpha1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm-foo",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.NodeSetSpec{
+						ControllerRef: slinkyv1alpha1.ObjectReference{
+							Name: "slurm",
+						},
+						NodeRegistration: slinkyv1alpha1.StaticNodeSetNodeRegistrationType,
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "with hostname",
+			args: args{
+				nodeset: &slinkyv1alpha1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm-foo",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.NodeSetSpec{
+						ControllerRef: slinkyv1alpha1.ObjectReference{
+							Name: "slurm",
+						},
+						NodeRegistration: slinkyv1alpha1.StaticNodeSetNodeRegistrationType,
+						Replicas:         ptr.To[int32](2),
+						ExtraConf:        "features=bar weight=5",
+						Template: slinkyv1alpha1.PodTemplate{
+							PodSpecWrapper: slinkyv1alpha1.PodSpecWrapper{
+								PodSpec: corev1.PodSpec{
+									Hostname: "foo-",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				"NodeName=foo-0 NodeAddr=foo-0.slurm-workers-slurm.slurm.svc.cluster.local Features=foo,bar Weight=5",
+				"NodeName=foo-1 NodeAddr=foo-1.slurm-workers-slurm.slurm.svc.cluster.local Features=foo,bar Weight=5",
+			},
+		},
+		{
+			name: "without hostname",
+			args: args{
+				nodeset: &slinkyv1alpha1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm-foo",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.NodeSetSpec{
+						ControllerRef: slinkyv1alpha1.ObjectReference{
+							Name: "slurm",
+						},
+						NodeRegistration: slinkyv1alpha1.StaticNodeSetNodeRegistrationType,
+						Replicas:         ptr.To[int32](1),
+					},
+				},
+			},
+			want: []string{
+				"NodeName=slurm-foo-0 NodeAddr=slurm-foo-0.slurm-workers-slurm.slurm.svc.cluster.local Features=slurm-foo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildStaticNodeLines(tt.args.nodeset); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("buildStaticNodeLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package builder
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -35,3 +37,33 @@ func (b *Builder) BuildControllerService(controller *slinkyv1alpha1.Controller)
 
 	return b.BuildService(opts, controller)
 }
+
+// BuildControllerSlurmrestdService returns the Service of the internal slurmrestd
+// sidecar of the Controller.
+func (b *Builder) BuildControllerSlurmrestdService(controller *slinkyv1alpha1.Controller) (*corev1.Service, error) {
+	opts := ServiceOpts{
+		Key:      controller.InternalRestapiServiceKey(),
+		Metadata: controller.Spec.Template.PodMetadata,
+		Selector: labels.NewBuilder().
+			WithControllerSelectorLabels(controller).
+			Build(),
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
+
+	port := corev1.ServicePort{
+		Name:       labels.RestapiApp,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       SlurmrestdPort,
+		TargetPort: intstr.FromString(labels.RestapiApp),
+	}
+	opts.Ports = append(opts.Ports, port)
+
+	return b.BuildService(opts, controller)
+}
+
+// ControllerSlurmrestdURL returns the URL of the internal slurmrestd sidecar of
+// the Controller.
+func ControllerSlurmrestdURL(controller *slinkyv1alpha1.Controller) string {
+	return fmt.Sprintf("http://%s:%d", controller.InternalRestapiServiceFQDNShort(), SlurmrestdPort)
+}
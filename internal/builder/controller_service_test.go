@@ -72,3 +72,78 @@ func TestBuilder_BuildControllerService(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildControllerSlurmrestdService(t *testing.T) {
+	type fields struct {
+		client client.Client
+	}
+	type args struct {
+		controller *slinkyv1alpha1.Controller
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "default",
+			fields: fields{
+				client: fake.NewFakeClient(),
+			},
+			args: args{
+				controller: &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "slurm",
+					},
+					Spec: slinkyv1alpha1.ControllerSpec{
+						InternalSlurmrestd: &slinkyv1alpha1.ContainerMinimal{
+							Image: "slurmrestd",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.fields.client)
+			got, err := b.BuildControllerSlurmrestdService(tt.args.controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildControllerSlurmrestdService() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			got2, err := b.BuildController(tt.args.controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildController() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			containers := got2.Spec.Template.Spec.Containers
+			switch {
+			case got.Name != "slurm-controller-slurmrestd":
+				t.Errorf("Name = %v", got.Name)
+
+			case !set.KeySet(got2.Labels).HasAll(set.KeySet(got.Spec.Selector).UnsortedList()...):
+				t.Errorf("Labels = %v , Selector = %v", got.Labels, got.Spec.Selector)
+
+			case len(containers) != 2:
+				t.Errorf("len(Template.Spec.Containers) = %v", len(containers))
+
+			case got.Spec.Ports[0].TargetPort.String() != containers[1].Ports[0].Name:
+				t.Errorf("Ports[0].TargetPort = %v , Template.Spec.Containers[1].Ports[0].Name = %v",
+					got.Spec.Ports[0].TargetPort, containers[1].Ports[0].Name)
+
+			case containers[1].Image != "slurmrestd" ||
+				*containers[1].SecurityContext.RunAsUser != slurmrestdUserUid ||
+				*containers[1].SecurityContext.RunAsGroup != slurmUserGid:
+				t.Errorf("Template.Spec.Containers[1] = %v", containers[1])
+
+			case containers[1].VolumeMounts[0].Name != internalSlurmrestdEtcVolume:
+				t.Errorf("Template.Spec.Containers[1].VolumeMounts = %v", containers[1].VolumeMounts)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+)
+
+// BuildControllerTenantAuth returns the Secret of the Slurm key of the
+// controller, and of the Slurm JWKS during a Slurm key rotation, for the pods of
+// NodeSets and LoginSets in another namespace.
+func (b *Builder) BuildControllerTenantAuth(controller *slinkyv1alpha1.Controller, namespace string) (*corev1.Secret, error) {
+	ctx := context.TODO()
+
+	slurmKey, err := b.refResolver.GetSecretKeyRef(ctx, controller.AuthSlurmRef(), controller.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := SecretOpts{
+		Key: controller.TenantAuthKey(namespace),
+		Metadata: slinkyv1alpha1.Metadata{
+			Labels: labels.NewBuilder().WithControllerTenantLabels(controller).Build(),
+		},
+		Data: map[string][]byte{
+			slurmKeyFile: slurmKey,
+		},
+	}
+	if controller.AuthSlurmNewRef() != nil {
+		data, err := b.buildControllerSlurmJwks(ctx, controller)
+		if err != nil {
+			return nil, err
+		}
+		opts.Data[SlurmJwksFile] = []byte(data)
+	}
+
+	return b.BuildSecret(opts, controller)
+}
+
+// BuildControllerTenantUsers returns the ConfigMap of the users provisioned by
+// the LoginSets of the controller, for the pods of NodeSets and LoginSets in
+// another namespace.
+func (b *Builder) BuildControllerTenantUsers(controller *slinkyv1alpha1.Controller, namespace string) (*corev1.ConfigMap, error) {
+	users, err := b.getControllerUsers(context.TODO(), controller)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ConfigMapOpts{
+		Key: controller.TenantUsersKey(namespace),
+		Metadata: slinkyv1alpha1.Metadata{
+			Labels: labels.NewBuilder().WithControllerTenantLabels(controller).Build(),
+		},
+		Data: buildUsersData(users),
+	}
+
+	return b.BuildConfigMap(opts, controller)
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func TestBuilder_BuildControllerTenantAuth(t *testing.T) {
+	slurmKeyRef := testutils.NewSlurmKeyRef("slurm")
+	newSlurmKeyRef := testutils.NewSlurmKeyRef("slurm-new")
+	controller := testutils.NewController("slurm", slurmKeyRef, testutils.NewJwtHs256KeyRef("slurm"), nil)
+	rotatingController := controller.DeepCopy()
+	rotatingController.Spec.SlurmKeyRotation = &slinkyv1alpha1.SlurmKeyRotation{NewKeyRef: newSlurmKeyRef}
+	tests := []struct {
+		name       string
+		client     client.Client
+		controller *slinkyv1alpha1.Controller
+		wantKeys   []string
+		wantErr    bool
+	}{
+		{
+			name:       "Slurm key",
+			client:     fake.NewFakeClient(testutils.NewSlurmKeySecret(slurmKeyRef)),
+			controller: controller,
+			wantKeys:   []string{slurmKeyFile},
+		},
+		{
+			name: "Slurm key rotation",
+			client: fake.NewFakeClient(
+				testutils.NewSlurmKeySecret(slurmKeyRef),
+				testutils.NewSlurmKeySecret(newSlurmKeyRef),
+			),
+			controller: rotatingController,
+			wantKeys:   []string{slurmKeyFile, SlurmJwksFile},
+		},
+		{
+			name:       "Slurm key not found",
+			client:     fake.NewFakeClient(),
+			controller: controller,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.client)
+			got, err := b.BuildControllerTenantAuth(tt.controller, "tenant")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Builder.BuildControllerTenantAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != "default-slurm-auth-slurm" || got.Namespace != "tenant" {
+				t.Errorf("got = %s/%s", got.Namespace, got.Name)
+			}
+			if len(got.OwnerReferences) != 0 {
+				t.Errorf("got.OwnerReferences = %v, want none", got.OwnerReferences)
+			}
+			if got.Labels[slinkyv1alpha1.LabelControllerName] != "slurm" ||
+				got.Labels[slinkyv1alpha1.LabelControllerNamespace] != "default" {
+				t.Errorf("got.Labels = %v", got.Labels)
+			}
+			if len(got.Data) != len(tt.wantKeys) {
+				t.Errorf("got.Data has %d keys, want %v", len(got.Data), tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if len(got.Data[key]) == 0 {
+					t.Errorf("got.Data[%s] is empty", key)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildControllerTenantUsers(t *testing.T) {
+	controller := testutils.NewController("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), nil)
+	loginset := testutils.NewLoginset("login", controller, testutils.NewSssdConfRef("login"))
+	loginset.Spec.Users = []slinkyv1alpha1.LoginSetUser{
+		{Name: "alice", Uid: 1000, SshAuthorizedKeys: "ssh-ed25519 AAAA alice"},
+	}
+	b := New(fake.NewFakeClient(loginset))
+
+	got, err := b.BuildControllerTenantUsers(controller, "tenant")
+	if err != nil {
+		t.Fatalf("Builder.BuildControllerTenantUsers() error = %v", err)
+	}
+	want, err := b.BuildControllerUsers(controller)
+	if err != nil {
+		t.Fatalf("Builder.BuildControllerUsers() error = %v", err)
+	}
+	if got.Name != "default-slurm-users" || got.Namespace != "tenant" {
+		t.Errorf("got = %s/%s", got.Namespace, got.Name)
+	}
+	if len(got.OwnerReferences) != 0 {
+		t.Errorf("got.OwnerReferences = %v, want none", got.OwnerReferences)
+	}
+	if len(got.Data) != len(want.Data) {
+		t.Fatalf("got.Data = %v, want %v", got.Data, want.Data)
+	}
+	for key, val := range want.Data {
+		if got.Data[key] != val {
+			t.Errorf("got.Data[%s] = %q, want %q", key, got.Data[key], val)
+		}
+	}
+}
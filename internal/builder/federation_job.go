@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clientutils "github.com/SlinkyProject/slurm-client/pkg/utils"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/metadata"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+const (
+	// AnnotationFederationHash is the hash of the registration of the
+	// Federation. The registration Job is replaced when it changes.
+	AnnotationFederationHash = slinkyv1alpha1.SlinkyPrefix + "federation-hash"
+)
+
+//go:embed scripts/federation.sh
+var federationScript string
+
+//go:embed scripts/federation-remove.sh
+var federationRemoveScript string
+
+// ErrFederationNoMembers is returned when no member cluster of a Federation
+// exists, so `sacctmgr` has no configuration to reach slurmdbd with.
+var ErrFederationNoMembers = errors.New("federation has no existing member clusters")
+
+// BuildFederationJob returns the Job which registers the Federation, and its
+// member clusters, in slurmdbd. It runs `sacctmgr` with the configuration of
+// the first member cluster.
+func (b *Builder) BuildFederationJob(federation *slinkyv1alpha1.Federation) (*batchv1.Job, error) {
+	ctx := context.TODO()
+	key := federation.RegistrationKey()
+
+	if len(federation.Spec.Clusters) == 0 {
+		return nil, errors.New("federation has no member clusters")
+	}
+	controllers := make([]*slinkyv1alpha1.Controller, 0, len(federation.Spec.Clusters))
+	for _, cluster := range federation.Spec.Clusters {
+		controller, err := b.refResolver.GetController(ctx, cluster.ControllerRef)
+		if err != nil {
+			return nil, err
+		}
+		controllers = append(controllers, controller)
+	}
+	controller := controllers[0]
+
+	clusterNames := make([]string, 0, len(controllers))
+	clusterFeatures := make([]string, 0, len(controllers))
+	for i, cluster := range federation.Spec.Clusters {
+		clusterName := controllers[i].ClusterName()
+		clusterNames = append(clusterNames, clusterName)
+		clusterFeatures = append(clusterFeatures, fmt.Sprintf("%s=%s", clusterName, strings.Join(cluster.Features, ",")))
+	}
+	env := []corev1.EnvVar{
+		{Name: "FEDERATION", Value: federation.FederationName()},
+		{Name: "FEDERATION_CLUSTERS", Value: strings.Join(clusterNames, ",")},
+		{Name: "FEDERATION_FEATURES", Value: strings.Join(clusterFeatures, " ")},
+	}
+
+	container := b.federationContainer(federation.Spec.Registration, controller, federationScript, env)
+	containerJson, err := json.Marshal(container)
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{
+		AnnotationFederationHash: crypto.CheckSum(containerJson),
+	}
+
+	return b.buildFederationJob(federation, key, controller, container, annotations)
+}
+
+// BuildFederationRemovalJob returns the Job which removes the Federation from
+// slurmdbd. It runs `sacctmgr` with the configuration of the first existing
+// member cluster, or fails with ErrFederationNoMembers.
+func (b *Builder) BuildFederationRemovalJob(federation *slinkyv1alpha1.Federation) (*batchv1.Job, error) {
+	ctx := context.TODO()
+	key := federation.RemovalKey()
+
+	var controller *slinkyv1alpha1.Controller
+	for _, cluster := range federation.Spec.Clusters {
+		member, err := b.refResolver.GetController(ctx, cluster.ControllerRef)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		controller = member
+		break
+	}
+	if controller == nil {
+		return nil, ErrFederationNoMembers
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "FEDERATION", Value: federation.FederationName()},
+	}
+	container := b.federationContainer(federation.Spec.Registration, controller, federationRemoveScript, env)
+
+	o, err := b.buildFederationJob(federation, key, controller, container, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Do not hold the deletion of the Federation forever on an unreachable
+	// slurmdbd.
+	o.Spec.ActiveDeadlineSeconds = ptr.To[int64](600)
+	return o, nil
+}
+
+// buildFederationJob returns a Job which runs the `sacctmgr` container with
+// the configuration of the controller.
+func (b *Builder) buildFederationJob(
+	federation *slinkyv1alpha1.Federation,
+	key types.NamespacedName,
+	controller *slinkyv1alpha1.Controller,
+	container corev1.Container,
+	annotations map[string]string,
+) (*batchv1.Job, error) {
+	hasJwks, err := b.ControllerHasJwks(controller)
+	if err != nil {
+		return nil, err
+	}
+
+	objectMeta := metadata.NewBuilder(key).
+		WithAnnotations(annotations).
+		WithLabels(labels.NewBuilder().WithFederationLabels(federation).Build()).
+		Build()
+
+	opts := PodTemplateOpts{
+		Key: key,
+		Metadata: slinkyv1alpha1.Metadata{
+			Annotations: objectMeta.Annotations,
+			Labels:      objectMeta.Labels,
+		},
+		base: corev1.PodSpec{
+			AutomountServiceAccountToken: ptr.To(false),
+			Containers: []corev1.Container{
+				container,
+			},
+			RestartPolicy: corev1.RestartPolicyNever,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot: ptr.To(true),
+				RunAsUser:    ptr.To(slurmUserUid),
+				RunAsGroup:   ptr.To(slurmUserGid),
+				FSGroup:      ptr.To(slurmUserGid),
+			},
+			Volumes: []corev1.Volume{
+				controllerVolumes(controller, controllerExtraConfigMapNames(controller), hasJwks)[0],
+			},
+		},
+	}
+
+	o := &batchv1.Job{
+		ObjectMeta: objectMeta,
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](6),
+			Template:     b.buildPodTemplate(opts),
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(federation, o, b.client.Scheme()); err != nil {
+		return nil, fmt.Errorf("failed to set owner controller: %w", err)
+	}
+
+	return o, nil
+}
+
+func (b *Builder) federationContainer(container slinkyv1alpha1.ContainerMinimal, controller *slinkyv1alpha1.Controller, script string, env []corev1.EnvVar) corev1.Container {
+	merge := &corev1.Container{}
+	clientutils.RemarshalOrDie(container, merge)
+	if merge.Image == "" {
+		merge.Image = controller.Spec.Slurmctld.Image
+		merge.ImagePullPolicy = controller.Spec.Slurmctld.ImagePullPolicy
+	}
+
+	opts := ContainerOpts{
+		base: corev1.Container{
+			Name: labels.FederationApp,
+			Command: []string{
+				"bash",
+				"-c",
+				script,
+			},
+			Env: env,
+			SecurityContext: &corev1.SecurityContext{
+				RunAsNonRoot: ptr.To(true),
+				RunAsUser:    ptr.To(slurmUserUid),
+				RunAsGroup:   ptr.To(slurmUserGid),
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: slurmEtcVolume, MountPath: slurmEtcDir, ReadOnly: true},
+			},
+		},
+		merge: *merge,
+	}
+
+	return b.BuildContainer(opts)
+}
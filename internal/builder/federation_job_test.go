@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"errors"
+	"testing"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuilder_BuildFederationJob(t *testing.T) {
+	newController := func(name string) *slinkyv1alpha1.Controller {
+		controller := &slinkyv1alpha1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		controller.Spec.Slurmctld.Image = "slurmctld:" + name
+		return controller
+	}
+	federation := &slinkyv1alpha1.Federation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fed",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: slinkyv1alpha1.FederationSpec{
+			Clusters: []slinkyv1alpha1.FederationCluster{
+				{
+					ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm1", Namespace: metav1.NamespaceDefault},
+					Features:      []string{"gpu", "fast"},
+				},
+				{
+					ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm2", Namespace: metav1.NamespaceDefault},
+				},
+			},
+		},
+	}
+	type fields struct {
+		client client.Client
+	}
+	type args struct {
+		federation *slinkyv1alpha1.Federation
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantImage string
+		wantEnv   map[string]string
+		wantErr   bool
+	}{
+		{
+			name: "default",
+			fields: fields{
+				client: fake.NewFakeClient(newController("slurm1"), newController("slurm2")),
+			},
+			args: args{
+				federation: federation,
+			},
+			wantImage: "slurmctld:slurm1",
+			wantEnv: map[string]string{
+				"FEDERATION":          "default_fed",
+				"FEDERATION_CLUSTERS": "default_slurm1,default_slurm2",
+				"FEDERATION_FEATURES": "default_slurm1=gpu,fast default_slurm2=",
+			},
+		},
+		{
+			name: "registration image",
+			fields: fields{
+				client: fake.NewFakeClient(newController("slurm1"), newController("slurm2")),
+			},
+			args: args{
+				federation: func() *slinkyv1alpha1.Federation {
+					out := federation.DeepCopy()
+					out.Spec.Registration.Image = "sacctmgr"
+					return out
+				}(),
+			},
+			wantImage: "sacctmgr",
+			wantEnv: map[string]string{
+				"FEDERATION":          "default_fed",
+				"FEDERATION_CLUSTERS": "default_slurm1,default_slurm2",
+				"FEDERATION_FEATURES": "default_slurm1=gpu,fast default_slurm2=",
+			},
+		},
+		{
+			name: "missing member",
+			fields: fields{
+				client: fake.NewFakeClient(newController("slurm1")),
+			},
+			args: args{
+				federation: federation,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.fields.client)
+			got, err := b.BuildFederationJob(tt.args.federation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildFederationJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			switch {
+			case got.Name != tt.args.federation.RegistrationKey().Name:
+				t.Errorf("Name = %v , want = %v",
+					got.Name, tt.args.federation.RegistrationKey().Name)
+
+			case got.Annotations[AnnotationFederationHash] == "":
+				t.Errorf("Annotations[%s] is empty", AnnotationFederationHash)
+
+			case got.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever:
+				t.Errorf("RestartPolicy = %v , want = %v",
+					got.Spec.Template.Spec.RestartPolicy, corev1.RestartPolicyNever)
+
+			case len(got.Spec.Template.Spec.Containers) != 1:
+				t.Errorf("len(Containers) = %v , want = %v",
+					len(got.Spec.Template.Spec.Containers), 1)
+
+			case got.Spec.Template.Spec.Containers[0].Name != labels.FederationApp:
+				t.Errorf("Containers[0].Name = %v , want = %v",
+					got.Spec.Template.Spec.Containers[0].Name, labels.FederationApp)
+
+			case got.Spec.Template.Spec.Containers[0].Image != tt.wantImage:
+				t.Errorf("Containers[0].Image = %v , want = %v",
+					got.Spec.Template.Spec.Containers[0].Image, tt.wantImage)
+
+			case got.Spec.Template.Spec.Volumes[0].Projected.Sources[0].ConfigMap.Name != "slurm1-config":
+				t.Errorf("Volumes[0] = %v , want = %v",
+					got.Spec.Template.Spec.Volumes[0].Projected.Sources[0].ConfigMap.Name, "slurm1-config")
+			}
+
+			env := map[string]string{}
+			for _, envVar := range got.Spec.Template.Spec.Containers[0].Env {
+				env[envVar.Name] = envVar.Value
+			}
+			for name, want := range tt.wantEnv {
+				if env[name] != want {
+					t.Errorf("Env[%s] = %v , want = %v", name, env[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildFederationRemovalJob(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm2",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	federation := &slinkyv1alpha1.Federation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fed",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: slinkyv1alpha1.FederationSpec{
+			Clusters: []slinkyv1alpha1.FederationCluster{
+				{ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm1", Namespace: metav1.NamespaceDefault}},
+				{ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm2", Namespace: metav1.NamespaceDefault}},
+			},
+		},
+	}
+
+	b := New(fake.NewFakeClient(controller))
+	got, err := b.BuildFederationRemovalJob(federation)
+	if err != nil {
+		t.Fatalf("Builder.BuildFederationRemovalJob() error = %v", err)
+	}
+	if got.Name != federation.RemovalKey().Name {
+		t.Errorf("Name = %v , want = %v", got.Name, federation.RemovalKey().Name)
+	}
+	if got.Spec.ActiveDeadlineSeconds == nil {
+		t.Errorf("ActiveDeadlineSeconds is not set")
+	}
+	// The first member cluster does not exist, the second one is used.
+	if name := got.Spec.Template.Spec.Volumes[0].Projected.Sources[0].ConfigMap.Name; name != "slurm2-config" {
+		t.Errorf("Volumes[0] = %v , want = %v", name, "slurm2-config")
+	}
+
+	b = New(fake.NewFakeClient())
+	if _, err := b.BuildFederationRemovalJob(federation); !errors.Is(err, ErrFederationNoMembers) {
+		t.Errorf("Builder.BuildFederationRemovalJob() error = %v, want %v", err, ErrFederationNoMembers)
+	}
+}
@@ -0,0 +1,289 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
+)
+
+const (
+	jwtRs256KeyLength = 2048
+)
+
+// Ref: https://datatracker.ietf.org/doc/html/rfc7517
+type jwk struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Key       string `json:"k,omitempty"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// newSlurmJwk returns the `auth/slurm` JWK of a key.
+func newSlurmJwk(key []byte, use string) jwk {
+	return jwk{
+		KeyType:   "oct",
+		Use:       use,
+		Algorithm: "HS256",
+		KeyID:     slurmjwt.KeyID(key),
+		Key:       base64.RawURLEncoding.EncodeToString(key),
+	}
+}
+
+// newRs256Jwk returns the JWK of the public key of an RSA private key, in PEM format.
+func newRs256Jwk(privateKey []byte) (jwk, error) {
+	publicKey, err := slurmjwt.RS256PublicKey(privateKey)
+	if err != nil {
+		return jwk{}, err
+	}
+	return jwk{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: "RS256",
+		KeyID:     slurmjwt.KeyID(privateKey),
+		Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}, nil
+}
+
+func marshalJwks(keys ...jwk) (string, error) {
+	out := jwks{
+		Keys: keys,
+	}
+	if out.Keys == nil {
+		out.Keys = []jwk{}
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// buildJwks returns the `auth/jwt` JWKS of RSA (RS256) private keys, by only
+// their public key, identified by slurmjwt.KeyID. The otherKeys (e.g. of OIDC
+// issuers) are appended as is. Slurm only loads RS256 keys from the JWKS, so
+// HS256 keys are rejected.
+func buildJwks(rs256Keys [][]byte, otherKeys ...jwk) (string, error) {
+	keys := make([]jwk, 0, len(rs256Keys)+len(otherKeys))
+	for _, key := range rs256Keys {
+		if !crypto.IsPem(key) {
+			return "", errors.New("JWKS keys must be RS256 keys in PEM format")
+		}
+		rsaKey, err := newRs256Jwk(key)
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, rsaKey)
+	}
+	keys = append(keys, otherKeys...)
+	return marshalJwks(keys...)
+}
+
+// buildSlurmJwks returns the `auth/slurm` JWKS, where the key which daemons
+// sign with is marked as the default, and all other keys are only verified.
+// Ref: https://slurm.schedmd.com/authentication.html#slurm
+func buildSlurmJwks(signingKey []byte, otherKeys ...[]byte) (string, error) {
+	keys := []jwk{newSlurmJwk(signingKey, "default")}
+	for _, key := range otherKeys {
+		keys = append(keys, newSlurmJwk(key, ""))
+	}
+	return marshalJwks(keys...)
+}
+
+// getJwksKeys returns the keys of the JWKS, which is the RS256 key if any.
+func (b *Builder) getJwksKeys(ctx context.Context, namespace string, rs256Ref *corev1.SecretKeySelector) ([][]byte, error) {
+	keys := [][]byte{}
+	if rs256Ref == nil {
+		return keys, nil
+	}
+	key, err := b.refResolver.GetSecretKeyRef(ctx, rs256Ref, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return append(keys, key), nil
+}
+
+// buildJwksSecretData sets the JWKS of the RS256 keys and OIDC issuers on the
+// JWKS Secret, along with the cached JWKS of the issuers and the result of
+// their last fetch.
+func (b *Builder) buildJwksSecretData(ctx context.Context, opts *SecretOpts, rs256Keys [][]byte, oidcs []slinkyv1alpha1.RestApiOidc) error {
+	cache, err := b.getOidcJwksCache(ctx, opts.Key)
+	if err != nil {
+		return err
+	}
+	oidcKeys, err := b.getOidcJwks(ctx, oidcs, cache, time.Now())
+	if err != nil {
+		return err
+	}
+	data, err := buildJwks(rs256Keys, oidcKeys...)
+	if err != nil {
+		return fmt.Errorf("failed to build JWKS: %w", err)
+	}
+
+	opts.StringData = map[string]string{
+		JwksFile: data,
+	}
+	for key, jwks := range cache.jwks {
+		opts.StringData[key] = string(jwks)
+	}
+	if hasOidcJwksUrl(oidcs) {
+		opts.Metadata.Annotations = structutils.MergeMaps(opts.Metadata.Annotations, map[string]string{
+			AnnotationOidcJwksFetchTime:  cache.fetchTime.UTC().Format(time.RFC3339),
+			AnnotationOidcJwksFetchError: cache.fetchErr,
+		})
+	}
+	return nil
+}
+
+func (b *Builder) BuildControllerJwks(controller *slinkyv1alpha1.Controller) (*corev1.Secret, error) {
+	ctx := context.TODO()
+
+	oidcs, err := b.getControllerOidcs(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	if !controller.HasJwks() && len(oidcs) == 0 {
+		return nil, errors.New("JWKS is not used: no RS256 key is set, and no OIDC issuer is used")
+	}
+	keys, err := b.getJwksKeys(ctx, controller.Namespace, controller.AuthJwtRs256Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := SecretOpts{
+		Key:      controller.JwksKey(),
+		Metadata: controller.Spec.Template.PodMetadata,
+	}
+	if err := b.buildJwksSecretData(ctx, &opts, keys, oidcs); err != nil {
+		return nil, err
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
+
+	return b.BuildSecret(opts, controller)
+}
+
+func (b *Builder) BuildAccountingJwks(accounting *slinkyv1alpha1.Accounting) (*corev1.Secret, error) {
+	ctx := context.TODO()
+
+	oidcs, err := b.getAccountingOidcs(ctx, accounting)
+	if err != nil {
+		return nil, err
+	}
+	if !accounting.HasJwks() && len(oidcs) == 0 {
+		return nil, errors.New("JWKS is not used: no RS256 key is set, and no OIDC issuer is used")
+	}
+	keys, err := b.getJwksKeys(ctx, accounting.Namespace, accounting.AuthJwtRs256Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := SecretOpts{
+		Key:      accounting.JwksKey(),
+		Metadata: accounting.Spec.Template.PodMetadata,
+	}
+	if err := b.buildJwksSecretData(ctx, &opts, keys, oidcs); err != nil {
+		return nil, err
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithAccountingLabels(accounting).Build())
+
+	return b.BuildSecret(opts, accounting)
+}
+
+func (b *Builder) BuildControllerSlurmJwks(controller *slinkyv1alpha1.Controller) (*corev1.Secret, error) {
+	data, err := b.buildControllerSlurmJwks(context.TODO(), controller)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := SecretOpts{
+		Key:      controller.SlurmJwksKey(),
+		Metadata: controller.Spec.Template.PodMetadata,
+		StringData: map[string]string{
+			SlurmJwksFile: data,
+		},
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
+
+	return b.BuildSecret(opts, controller)
+}
+
+// buildControllerSlurmJwks returns the `auth/slurm` JWKS of the Slurm key
+// rotation of the controller.
+func (b *Builder) buildControllerSlurmJwks(ctx context.Context, controller *slinkyv1alpha1.Controller) (string, error) {
+	newRef := controller.AuthSlurmNewRef()
+	if newRef == nil {
+		return "", fmt.Errorf("no Slurm key rotation is in progress")
+	}
+	oldKey, err := b.refResolver.GetSecretKeyRef(ctx, controller.AuthSlurmRef(), controller.Namespace)
+	if err != nil {
+		return "", err
+	}
+	newKey, err := b.refResolver.GetSecretKeyRef(ctx, newRef, controller.Namespace)
+	if err != nil {
+		return "", err
+	}
+	signingKey, otherKey := oldKey, newKey
+	if *controller.AuthSlurmSigningRef() == *newRef {
+		signingKey, otherKey = newKey, oldKey
+	}
+	data, err := buildSlurmJwks(signingKey, otherKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to build JWKS: %w", err)
+	}
+	return data, nil
+}
+
+// BuildControllerJwtRs256Key returns a Secret containing a generated RSA key
+// pair, in PEM format, for the JWT RS256 key.
+func (b *Builder) BuildControllerJwtRs256Key(controller *slinkyv1alpha1.Controller) (*corev1.Secret, error) {
+	ref := controller.AuthJwtRs256Ref()
+	if ref == nil {
+		return nil, errors.New("no JWT RS256 key is set")
+	}
+	keyPair, err := crypto.NewKeyPair(crypto.WithType(crypto.KeyPairRsa), crypto.WithRsaLength(jwtRs256KeyLength))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RSA key pair: %w", err)
+	}
+
+	opts := SecretOpts{
+		Key: types.NamespacedName{
+			Name:      ref.Name,
+			Namespace: controller.Namespace,
+		},
+		Metadata: controller.Spec.Template.PodMetadata,
+		Data: map[string][]byte{
+			ref.Key: keyPair.PrivateKey(),
+		},
+		Immutable: true,
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
+
+	return b.BuildSecret(opts, controller)
+}
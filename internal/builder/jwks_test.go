@@ -0,0 +1,467 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// assertSlurmJwks checks that every key of the `auth/jwt` JWKS is one which
+// Slurm loads: an RS256 public key, identified by its `kid`.
+// Ref: https://slurm.schedmd.com/jwt.html
+func assertSlurmJwks(t *testing.T, data string, wantKeyIDs []string) {
+	t.Helper()
+	out := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(out.Keys) != len(wantKeyIDs) {
+		t.Fatalf("JWKS = %v, want %v keys", data, len(wantKeyIDs))
+	}
+	for i, key := range out.Keys {
+		if key["kid"] != wantKeyIDs[i] {
+			t.Errorf("JWKS key[%d] = %v, want kid %v", i, key, wantKeyIDs[i])
+		}
+		if key["kty"] != "RSA" || key["alg"] != "RS256" || key["n"] == "" || key["e"] != "AQAB" {
+			t.Errorf("JWKS key[%d] = %v, want RS256 public key", i, key)
+		}
+		for _, private := range []string{"k", "d", "p", "q"} {
+			if _, ok := key[private]; ok {
+				t.Errorf("JWKS key[%d] = %v, has secret %q", i, key, private)
+			}
+		}
+	}
+}
+
+func Test_buildJwks(t *testing.T) {
+	keyPair, err := crypto.NewKeyPair(crypto.WithType(crypto.KeyPairRsa), crypto.WithRsaLength(2048))
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	rs256Key := keyPair.PrivateKey()
+	type args struct {
+		rs256Keys [][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			args: args{},
+			want: []string{},
+		},
+		{
+			name: "RS256",
+			args: args{
+				rs256Keys: [][]byte{rs256Key},
+			},
+			want: []string{slurmjwt.KeyID(rs256Key)},
+		},
+		{
+			name: "HS256",
+			args: args{
+				rs256Keys: [][]byte{[]byte("old"), []byte("new")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildJwks(tt.args.rs256Keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildJwks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			assertSlurmJwks(t, got, tt.want)
+		})
+	}
+}
+
+func TestBuilder_BuildControllerJwks(t *testing.T) {
+	jwtSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Data: map[string][]byte{
+				"jwt_hs256.key": []byte(name),
+			},
+		}
+	}
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			JwtHs256KeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "jwt-old",
+				},
+				Key: "jwt_hs256.key",
+			},
+		},
+	}
+	rotating := controller.DeepCopy()
+	rotating.Spec.JwtHs256KeyRotation = &slinkyv1alpha1.JwtKeyRotation{
+		NewKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "jwt-new",
+			},
+			Key: "jwt_hs256.key",
+		},
+	}
+	rs256 := controller.DeepCopy()
+	rs256.Spec.JwtRs256KeyRef = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "jwt-rs256",
+		},
+		Key: "jwt_rs256.key",
+	}
+	rs256Rotating := rs256.DeepCopy()
+	rs256Rotating.Spec.JwtHs256KeyRotation = rotating.Spec.JwtHs256KeyRotation
+	rs256Secret := func() *corev1.Secret {
+		keyPair, err := crypto.NewKeyPair(crypto.WithType(crypto.KeyPairRsa), crypto.WithRsaLength(2048))
+		if err != nil {
+			t.Fatalf("NewKeyPair() error = %v", err)
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "jwt-rs256",
+			},
+			Data: map[string][]byte{
+				"jwt_rs256.key": keyPair.PrivateKey(),
+			},
+		}
+	}
+	type fields struct {
+		client client.Client
+	}
+	type args struct {
+		controller *slinkyv1alpha1.Controller
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "RS256",
+			fields: fields{
+				client: fake.NewFakeClient(jwtSecret("jwt-old"), rs256Secret()),
+			},
+			args: args{
+				controller: rs256,
+			},
+		},
+		{
+			name: "Not rotating",
+			fields: fields{
+				client: fake.NewFakeClient(jwtSecret("jwt-old")),
+			},
+			args: args{
+				controller: controller,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing new key",
+			fields: fields{
+				client: fake.NewFakeClient(jwtSecret("jwt-old")),
+			},
+			args: args{
+				controller: rotating,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Rotating",
+			fields: fields{
+				client: fake.NewFakeClient(jwtSecret("jwt-old"), jwtSecret("jwt-new")),
+			},
+			args: args{
+				controller: rotating,
+			},
+			wantErr: true,
+		},
+		{
+			name: "RS256 while rotating",
+			fields: fields{
+				client: fake.NewFakeClient(jwtSecret("jwt-old"), jwtSecret("jwt-new"), rs256Secret()),
+			},
+			args: args{
+				controller: rs256Rotating,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.fields.client)
+			got, err := b.BuildControllerJwks(tt.args.controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildControllerJwks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != tt.args.controller.JwksKey().Name {
+				t.Errorf("got.Name = %v, want %v", got.Name, tt.args.controller.JwksKey().Name)
+			}
+			// Only the RS256 key is published, never the HS256 keys.
+			secret := &corev1.Secret{}
+			if err := tt.fields.client.Get(context.TODO(), client.ObjectKey{Name: "jwt-rs256"}, secret); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			assertSlurmJwks(t, got.StringData[JwksFile], []string{slurmjwt.KeyID(secret.Data["jwt_rs256.key"])})
+		})
+	}
+}
+
+func Test_buildAuthAltParameters(t *testing.T) {
+	tests := []struct {
+		name          string
+		hasJwks       bool
+		usernameClaim string
+		want          string
+	}{
+		{
+			name: "Default",
+			want: "jwt_key=/etc/slurm/jwt_hs256.key",
+		},
+		{
+			name:    "JWKS",
+			hasJwks: true,
+			want:    "jwt_key=/etc/slurm/jwt_hs256.key,jwks=/etc/slurm/jwks.json",
+		},
+		{
+			name:          "Username claim",
+			hasJwks:       true,
+			usernameClaim: "preferred_username",
+			want:          "jwt_key=/etc/slurm/jwt_hs256.key,jwks=/etc/slurm/jwks.json,userclaimfield=preferred_username",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildAuthAltParameters(tt.hasJwks, tt.usernameClaim); got != tt.want {
+				t.Errorf("buildAuthAltParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildSlurmJwks(t *testing.T) {
+	got, err := buildSlurmJwks([]byte("new"), []byte("old"))
+	if err != nil {
+		t.Fatalf("buildSlurmJwks() error = %v", err)
+	}
+	out := jwks{}
+	if err := json.Unmarshal([]byte(got), &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(out.Keys) != 2 {
+		t.Fatalf("buildSlurmJwks() = %v, want 2 keys", got)
+	}
+	if out.Keys[0].KeyID != slurmjwt.KeyID([]byte("new")) || out.Keys[0].Use != "default" {
+		t.Errorf("buildSlurmJwks() key[0] = %v, want default signing key", out.Keys[0])
+	}
+	if out.Keys[1].KeyID != slurmjwt.KeyID([]byte("old")) || out.Keys[1].Use != "" {
+		t.Errorf("buildSlurmJwks() key[1] = %v, want verify-only key", out.Keys[1])
+	}
+}
+
+func TestSlurmKeyRotationAnnotations(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		Spec: slinkyv1alpha1.ControllerSpec{
+			SlurmKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "slurm-old",
+				},
+				Key: "slurm.key",
+			},
+		},
+	}
+	rotating := controller.DeepCopy()
+	rotating.Spec.SlurmKeyRotation = &slinkyv1alpha1.SlurmKeyRotation{
+		NewKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "slurm-new",
+			},
+			Key: "slurm.key",
+		},
+	}
+	switching := rotating.DeepCopy()
+	switching.Status.Conditions = []metav1.Condition{
+		{
+			Type:   slinkyv1alpha1.ControllerConditionSlurmKeyRotation,
+			Reason: slinkyv1alpha1.SlurmKeyRotationReasonSwitching,
+		},
+	}
+	rollingBack := rotating.DeepCopy()
+	rollingBack.Status.Conditions = []metav1.Condition{
+		{
+			Type:   slinkyv1alpha1.ControllerConditionSlurmKeyRotation,
+			Reason: slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+		},
+	}
+	tests := []struct {
+		name       string
+		controller *slinkyv1alpha1.Controller
+		want       string
+	}{
+		{
+			name:       "Not rotating",
+			controller: controller,
+			want:       "",
+		},
+		{
+			name:       "Distributing",
+			controller: rotating,
+			want:       "slurm-new/slurm.key,signing=slurm-old/slurm.key",
+		},
+		{
+			name:       "Switching",
+			controller: switching,
+			want:       "slurm-new/slurm.key,signing=slurm-new/slurm.key",
+		},
+		{
+			name:       "Rolling back",
+			controller: rollingBack,
+			want:       "signing=slurm-old/slurm.key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SlurmKeyRotationAnnotations(tt.controller)[AnnotationSlurmKeyRotation]; got != tt.want {
+				t.Errorf("SlurmKeyRotationAnnotations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJwtKeyRotationAnnotations(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		Spec: slinkyv1alpha1.ControllerSpec{
+			JwtHs256KeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "jwt-old",
+				},
+				Key: "jwt_hs256.key",
+			},
+		},
+	}
+	rotating := controller.DeepCopy()
+	rotating.Spec.JwtHs256KeyRotation = &slinkyv1alpha1.JwtKeyRotation{
+		NewKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "jwt-new",
+			},
+			Key: "jwt_hs256.key",
+		},
+	}
+	withReason := func(reason string) *slinkyv1alpha1.Controller {
+		out := rotating.DeepCopy()
+		out.Status.Conditions = []metav1.Condition{
+			{
+				Type:   slinkyv1alpha1.ControllerConditionJwtHs256KeyRotation,
+				Reason: reason,
+			},
+		}
+		return out
+	}
+	tests := []struct {
+		name       string
+		controller *slinkyv1alpha1.Controller
+		want       string
+	}{
+		{
+			name:       "Not rotating",
+			controller: controller,
+			want:       "",
+		},
+		{
+			name:       "Reissuing tokens",
+			controller: withReason(slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens),
+			want:       "jwt-new/jwt_hs256.key,verifying=jwt-old/jwt_hs256.key",
+		},
+		{
+			name:       "Rolling out",
+			controller: withReason(slinkyv1alpha1.JwtKeyRotationReasonRollingOut),
+			want:       "jwt-new/jwt_hs256.key,verifying=jwt-new/jwt_hs256.key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JwtKeyRotationAnnotations(tt.controller)[AnnotationJwtHs256KeyRotation]; got != tt.want {
+				t.Errorf("JwtKeyRotationAnnotations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildControllerJwtRs256Key(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	rs256 := controller.DeepCopy()
+	rs256.Spec.JwtRs256KeyRef = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "jwt-rs256",
+		},
+		Key: "jwt_rs256.key",
+	}
+	tests := []struct {
+		name       string
+		controller *slinkyv1alpha1.Controller
+		wantErr    bool
+	}{
+		{
+			name:       "Not set",
+			controller: controller,
+			wantErr:    true,
+		},
+		{
+			name:       "RS256",
+			controller: rs256,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(fake.NewFakeClient())
+			got, err := b.BuildControllerJwtRs256Key(tt.controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildControllerJwtRs256Key() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != "jwt-rs256" || got.Immutable == nil || !*got.Immutable {
+				t.Errorf("Builder.BuildControllerJwtRs256Key() = %v", got)
+			}
+			if _, err := crypto.ParseRsaPrivateKey(got.Data["jwt_rs256.key"]); err != nil {
+				t.Errorf("ParseRsaPrivateKey() error = %v", err)
+			}
+		})
+	}
+}
@@ -82,6 +82,9 @@ const (
 
 	LoginApp  = "login"
 	LoginComp = "login"
+
+	FederationApp  = "sacctmgr"
+	FederationComp = "federation"
 )
 
 func (b *Builder) WithControllerSelectorLabels(obj *slinkyv1alpha1.Controller) *Builder {
@@ -145,6 +148,25 @@ func (b *Builder) WithLoginLabels(obj *slinkyv1alpha1.LoginSet) *Builder {
 		WithComponent(LoginComp)
 }
 
+func (b *Builder) WithFederationLabels(obj *slinkyv1alpha1.Federation) *Builder {
+	return b.
+		WithApp(FederationApp).
+		WithInstance(obj.Name).
+		WithComponent(FederationComp)
+}
+
+func (b *Builder) WithTokenTargetLabels(obj *slinkyv1alpha1.Token) *Builder {
+	b.labels[slinkyv1alpha1.LabelTokenName] = obj.Name
+	b.labels[slinkyv1alpha1.LabelTokenNamespace] = obj.Namespace
+	return b
+}
+
+func (b *Builder) WithControllerTenantLabels(obj *slinkyv1alpha1.Controller) *Builder {
+	b.labels[slinkyv1alpha1.LabelControllerName] = obj.Name
+	b.labels[slinkyv1alpha1.LabelControllerNamespace] = obj.Namespace
+	return b
+}
+
 func (b *Builder) WithPodProtect() *Builder {
 	b.labels[slinkyv1alpha1.LabelNodeSetPodProtect] = "true"
 	return b
@@ -6,7 +6,9 @@ package builder
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -55,6 +57,18 @@ const (
 	sshHostEcdsaPubKeyFile     = sshHostEcdsaKeyFile + ".pub"
 	sshHostEcdsaPubKeyFilePath = sshDir + "/" + sshHostEcdsaPubKeyFile
 
+	sshHostCertsVolume = "ssh-host-certs"
+
+	sshHostRsaCertFile         = sshHostRsaKeyFile + "-cert.pub"
+	sshHostRsaCertFilePath     = sshDir + "/" + sshHostRsaCertFile
+	sshHostEd25519CertFile     = sshHostEd25519KeyFile + "-cert.pub"
+	sshHostEd25519CertFilePath = sshDir + "/" + sshHostEd25519CertFile
+	sshHostEcdsaCertFile       = sshHostEcdsaKeyFile + "-cert.pub"
+	sshHostEcdsaCertFilePath   = sshDir + "/" + sshHostEcdsaCertFile
+
+	trustedUserCaKeysFile     = "trusted_user_ca_keys.pub"
+	trustedUserCaKeysFilePath = sshDir + "/" + trustedUserCaKeysFile
+
 	sssdConfVolume   = "sssd-conf"
 	sssdConfFile     = "sssd.conf"
 	sssdConfDir      = "/etc/sssd"
@@ -66,6 +80,13 @@ const (
 	rootAuthorizedKeysFilePath = "/root/.ssh/" + authorizedKeysFile
 )
 
+// sshHostCertFiles maps the public SSH host keys to their host certificates.
+var sshHostCertFiles = map[string]string{
+	sshHostRsaPubKeyFile:     sshHostRsaCertFile,
+	sshHostEd25519PubKeyFile: sshHostEd25519CertFile,
+	sshHostEcdsaPubKeyFile:   sshHostEcdsaCertFile,
+}
+
 func (b *Builder) BuildLogin(loginset *slinkyv1alpha1.LoginSet) (*appsv1.Deployment, error) {
 	key := loginset.Key()
 
@@ -81,6 +102,15 @@ func (b *Builder) BuildLogin(loginset *slinkyv1alpha1.LoginSet) (*appsv1.Deploym
 	if err != nil {
 		return nil, fmt.Errorf("failed to build pod template: %w", err)
 	}
+	if loginset.HasSessionDrain() {
+		// Old pods are found by their hash, as their pod template cannot be
+		// compared with the defaulted one of the Deployment.
+		templateHash, err := loginTemplateHash(podTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash pod template: %w", err)
+		}
+		podTemplate.Annotations[AnnotationLoginTemplateHash] = templateHash
+	}
 
 	o := &appsv1.Deployment{
 		ObjectMeta: objectMeta,
@@ -105,7 +135,7 @@ func (b *Builder) loginPodTemplate(loginset *slinkyv1alpha1.LoginSet) (corev1.Po
 	ctx := context.TODO()
 	key := loginset.Key()
 
-	controller, err := b.refResolver.GetController(ctx, loginset.Spec.ControllerRef)
+	controller, err := b.refResolver.GetAllowedController(ctx, loginset.Spec.ControllerRef, loginset.Namespace)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
@@ -115,10 +145,17 @@ func (b *Builder) loginPodTemplate(loginset *slinkyv1alpha1.LoginSet) (corev1.Po
 		return corev1.PodTemplateSpec{}, err
 	}
 
+	usersAnnotations, err := b.UsersAnnotations(ctx, controller)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	objectMeta := metadata.NewBuilder(key).
 		WithMetadata(loginset.Spec.Template.PodMetadata).
 		WithLabels(labels.NewBuilder().WithLoginLabels(loginset).Build()).
 		WithAnnotations(hashMap).
+		WithAnnotations(usersAnnotations).
+		WithAnnotations(SlurmKeyRotationAnnotations(controller)).
 		WithAnnotations(map[string]string{
 			annotationDefaultContainer: labels.LoginApp,
 		}).
@@ -126,6 +163,7 @@ func (b *Builder) loginPodTemplate(loginset *slinkyv1alpha1.LoginSet) (corev1.Po
 
 	spec := loginset.Spec
 	template := spec.Template.PodSpecWrapper
+	withUsers := len(usersAnnotations) > 0
 
 	opts := PodTemplateOpts{
 		Key: key,
@@ -137,22 +175,25 @@ func (b *Builder) loginPodTemplate(loginset *slinkyv1alpha1.LoginSet) (corev1.Po
 			AutomountServiceAccountToken: ptr.To(false),
 			EnableServiceLinks:           ptr.To(false),
 			Containers: []corev1.Container{
-				b.loginContainer(spec.Login.Container, controller),
+				b.loginContainer(spec.Login.Container, loginset, controller, withUsers),
 			},
 			DNSConfig: &corev1.PodDNSConfig{
 				Searches: []string{
 					slurmClusterWorkerService(spec.ControllerRef.Name, loginset.Namespace),
 				},
 			},
-			Volumes: loginVolumes(loginset, controller),
+			Volumes: loginVolumes(loginset, controller, withUsers),
 		},
 		merge: template.PodSpec,
 	}
+	if withUsers {
+		opts.base.InitContainers = append(opts.base.InitContainers, b.initusersContainer(spec.Login.Container))
+	}
 
 	return b.buildPodTemplate(opts), nil
 }
 
-func loginVolumes(loginset *slinkyv1alpha1.LoginSet, controller *slinkyv1alpha1.Controller) []corev1.Volume {
+func loginVolumes(loginset *slinkyv1alpha1.LoginSet, controller *slinkyv1alpha1.Controller, hasUsers bool) []corev1.Volume {
 	out := []corev1.Volume{
 		{
 			Name: sackdVolume,
@@ -167,18 +208,7 @@ func loginVolumes(loginset *slinkyv1alpha1.LoginSet, controller *slinkyv1alpha1.
 			VolumeSource: corev1.VolumeSource{
 				Projected: &corev1.ProjectedVolumeSource{
 					DefaultMode: ptr.To[int32](0o600),
-					Sources: []corev1.VolumeProjection{
-						{
-							Secret: &corev1.SecretProjection{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: controller.AuthSlurmRef().Name,
-								},
-								Items: []corev1.KeyToPath{
-									{Key: controller.AuthSlurmRef().Key, Path: slurmKeyFile},
-								},
-							},
-						},
-					},
+					Sources:     slurmAuthVolumeProjections(controller, loginset.Namespace),
 				},
 			},
 		},
@@ -228,7 +258,9 @@ func loginVolumes(loginset *slinkyv1alpha1.LoginSet, controller *slinkyv1alpha1.
 				},
 			},
 		},
-		{
+	}
+	if loginset.HasSssd() {
+		out = append(out, corev1.Volume{
 			Name: sssdConfVolume,
 			VolumeSource: corev1.VolumeSource{
 				Projected: &corev1.ProjectedVolumeSource{
@@ -247,12 +279,44 @@ func loginVolumes(loginset *slinkyv1alpha1.LoginSet, controller *slinkyv1alpha1.
 					},
 				},
 			},
-		},
+		})
+	}
+	if hasUsers {
+		out = append(out, usersVolumes(controller, loginset.Namespace)...)
+	}
+	out = append(out, sharedVolumes(controller, loginset.Namespace, slinkyv1alpha1.SharedVolumeComponentLogin)...)
+	if loginset.HasSshCa() {
+		sshConfig := out[3].Projected.Sources[0].ConfigMap
+		sshConfig.Items = append(sshConfig.Items, corev1.KeyToPath{Key: trustedUserCaKeysFile, Path: trustedUserCaKeysFile, Mode: ptr.To[int32](0o644)})
+	}
+	if loginset.HasSshHostCertificates() {
+		out = append(out, corev1.Volume{
+			Name: sshHostCertsVolume,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					DefaultMode: ptr.To[int32](0o644),
+					Sources: []corev1.VolumeProjection{
+						{
+							Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: loginset.SshHostCertificatesKey().Name,
+								},
+								Items: []corev1.KeyToPath{
+									{Key: sshHostRsaCertFile, Path: sshHostRsaCertFile},
+									{Key: sshHostEd25519CertFile, Path: sshHostEd25519CertFile},
+									{Key: sshHostEcdsaCertFile, Path: sshHostEcdsaCertFile},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
 	}
 	return out
 }
 
-func (b *Builder) loginContainer(merge corev1.Container, controller *slinkyv1alpha1.Controller) corev1.Container {
+func (b *Builder) loginContainer(merge corev1.Container, loginset *slinkyv1alpha1.LoginSet, controller *slinkyv1alpha1.Controller, hasUsers bool) corev1.Container {
 	opts := ContainerOpts{
 		base: corev1.Container{
 			Name: labels.LoginApp,
@@ -286,11 +350,34 @@ func (b *Builder) loginContainer(merge corev1.Container, controller *slinkyv1alp
 				{Name: sshHostKeysVolume, MountPath: sshHostEcdsaPubKeyFilePath, SubPath: sshHostEcdsaPubKeyFile, ReadOnly: true},
 				{Name: sshConfigVolume, MountPath: sshdConfigFilePath, SubPath: sshdConfigFile, ReadOnly: true},
 				{Name: sshConfigVolume, MountPath: rootAuthorizedKeysFilePath, SubPath: authorizedKeysFile, ReadOnly: true},
-				{Name: sssdConfVolume, MountPath: sssdConfFilePath, SubPath: sssdConfFile, ReadOnly: true},
 			},
 		},
 		merge: merge,
 	}
+	if loginset.HasSssd() {
+		opts.base.VolumeMounts = append(opts.base.VolumeMounts,
+			corev1.VolumeMount{Name: sssdConfVolume, MountPath: sssdConfFilePath, SubPath: sssdConfFile, ReadOnly: true},
+		)
+	}
+	if hasUsers {
+		opts.base.VolumeMounts = append(opts.base.VolumeMounts, usersVolumeMounts()...)
+		opts.base.VolumeMounts = append(opts.base.VolumeMounts,
+			corev1.VolumeMount{Name: usersVolume, MountPath: usersAuthorizedKeysDir, ReadOnly: true},
+		)
+	}
+	if loginset.HasSshCa() {
+		opts.base.VolumeMounts = append(opts.base.VolumeMounts,
+			corev1.VolumeMount{Name: sshConfigVolume, MountPath: trustedUserCaKeysFilePath, SubPath: trustedUserCaKeysFile, ReadOnly: true},
+		)
+	}
+	if loginset.HasSshHostCertificates() {
+		opts.base.VolumeMounts = append(opts.base.VolumeMounts,
+			corev1.VolumeMount{Name: sshHostCertsVolume, MountPath: sshHostRsaCertFilePath, SubPath: sshHostRsaCertFile, ReadOnly: true},
+			corev1.VolumeMount{Name: sshHostCertsVolume, MountPath: sshHostEd25519CertFilePath, SubPath: sshHostEd25519CertFile, ReadOnly: true},
+			corev1.VolumeMount{Name: sshHostCertsVolume, MountPath: sshHostEcdsaCertFilePath, SubPath: sshHostEcdsaCertFile, ReadOnly: true},
+		)
+	}
+	opts.base.VolumeMounts = append(opts.base.VolumeMounts, sharedVolumeMounts(controller, slinkyv1alpha1.SharedVolumeComponentLogin)...)
 
 	return b.BuildContainer(opts)
 }
@@ -309,8 +396,21 @@ const (
 	annotationSshdConfHash    = slinkyv1alpha1.LoginSetPrefix + "sshd-conf-hash"
 	annotationSssdConfHash    = slinkyv1alpha1.LoginSetPrefix + "sssd-conf-hash"
 	annotationSshHostKeysHash = slinkyv1alpha1.LoginSetPrefix + "ssh-host-keys-hash"
+	annotationSshCaHash       = slinkyv1alpha1.LoginSetPrefix + "ssh-ca-hash"
+
+	// AnnotationLoginTemplateHash is the hash of the pod template of a login
+	// pod, with the SessionDrain update strategy.
+	AnnotationLoginTemplateHash = slinkyv1alpha1.LoginSetPrefix + "pod-template-hash"
 )
 
+func loginTemplateHash(template corev1.PodTemplateSpec) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	return crypto.CheckSum(data), nil
+}
+
 func (b *Builder) getLoginHashes(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) (map[string]string, error) {
 	sshConfig := &corev1.ConfigMap{}
 	sshConfigKey := loginset.SshConfigKey()
@@ -330,20 +430,38 @@ func (b *Builder) getLoginHashes(ctx context.Context, loginset *slinkyv1alpha1.L
 	}
 	sshHostKeysHash := crypto.CheckSumFromMap(sshHostKeys.Data)
 
-	sssdSecret := &corev1.Secret{}
-	sssdSecretKey := loginset.SssdSecretKey()
-	if err := b.client.Get(ctx, sssdSecretKey, sssdSecret); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(sssdSecret), err)
-		}
-	}
-	sssdConfRefKey := loginset.SssdSecretRef().Key
-	SssdConfHash := crypto.CheckSum([]byte(sssdSecret.StringData[sssdConfRefKey]))
-
 	hashMap := map[string]string{
 		annotationSshHostKeysHash: sshHostKeysHash,
 		annotationSshdConfHash:    sshdConfigHash,
-		annotationSssdConfHash:    SssdConfHash,
+	}
+
+	if loginset.HasSssd() {
+		sssdSecret := &corev1.Secret{}
+		sssdSecretKey := loginset.SssdSecretKey()
+		if err := b.client.Get(ctx, sssdSecretKey, sssdSecret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(sssdSecret), err)
+			}
+		}
+		sssdConfRefKey := loginset.SssdSecretRef().Key
+		hashMap[annotationSssdConfHash] = crypto.CheckSum([]byte(sssdSecret.StringData[sssdConfRefKey]))
+	}
+
+	if loginset.HasSshCa() {
+		sshCaData := map[string][]byte{
+			trustedUserCaKeysFile: []byte(sshConfig.Data[trustedUserCaKeysFile]),
+		}
+		if loginset.HasSshHostCertificates() {
+			sshHostCerts := &corev1.Secret{}
+			sshHostCertsKey := loginset.SshHostCertificatesKey()
+			if err := b.client.Get(ctx, sshHostCertsKey, sshHostCerts); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(sshHostCerts), err)
+				}
+			}
+			maps.Copy(sshCaData, sshHostCerts.Data)
+		}
+		hashMap[annotationSshCaHash] = crypto.CheckSumFromMap(sshCaData)
 	}
 
 	return hashMap, nil
@@ -4,6 +4,8 @@
 package builder
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
@@ -13,15 +15,35 @@ import (
 )
 
 func (b *Builder) BuildLoginSshConfig(loginset *slinkyv1alpha1.LoginSet) (*corev1.ConfigMap, error) {
+	ctx := context.TODO()
 	spec := loginset.Spec
+
+	trustedUserCaKeys, err := b.getSshUserCaPublicKey(ctx, loginset)
+	if err != nil {
+		return nil, err
+	}
+
+	controller, err := b.refResolver.GetAllowedController(ctx, loginset.Spec.ControllerRef, loginset.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	usersAnnotations, err := b.UsersAnnotations(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	hasUsers := len(usersAnnotations) > 0
+
 	opts := ConfigMapOpts{
 		Key:      loginset.SshConfigKey(),
 		Metadata: loginset.Spec.Template.PodMetadata,
 		Data: map[string]string{
 			authorizedKeysFile: buildAuthorizedKeys(spec.RootSshAuthorizedKeys),
-			sshdConfigFile:     buildSshdConfig(spec.ExtraSshdConfig),
+			sshdConfigFile:     buildSshdConfig(spec.ExtraSshdConfig, loginset.HasSshCa(), loginset.HasSshHostCertificates(), hasUsers),
 		},
 	}
+	if loginset.HasSshCa() {
+		opts.Data[trustedUserCaKeysFile] = trustedUserCaKeys
+	}
 
 	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithLoginLabels(loginset).Build())
 
@@ -38,7 +60,7 @@ func buildAuthorizedKeys(authorizedKeys string) string {
 	return conf.Build()
 }
 
-func buildSshdConfig(extraConf string) string {
+func buildSshdConfig(extraConf string, hasUserCa, hasHostCerts, hasUsers bool) string {
 	conf := config.NewBuilder().WithSeperator(" ")
 
 	conf.AddProperty(config.NewPropertyRaw("#"))
@@ -47,6 +69,25 @@ func buildSshdConfig(extraConf string) string {
 	conf.AddProperty(config.NewProperty("UsePAM", "yes"))
 	conf.AddProperty(config.NewProperty("X11Forwarding", "yes"))
 	conf.AddProperty(config.NewProperty("Subsystem", "sftp internal-sftp"))
+	if hasUsers {
+		conf.AddProperty(config.NewProperty("AuthorizedKeysFile", ".ssh/authorized_keys "+usersAuthorizedKeysDir+"/%u"+usersAuthorizedKeysSuffix))
+	}
+
+	if hasUserCa || hasHostCerts {
+		conf.AddProperty(config.NewPropertyRaw("#"))
+		conf.AddProperty(config.NewPropertyRaw("### CERTIFICATE AUTHORITY ###"))
+	}
+	if hasUserCa {
+		conf.AddProperty(config.NewProperty("TrustedUserCAKeys", trustedUserCaKeysFilePath))
+	}
+	if hasHostCerts {
+		conf.AddProperty(config.NewProperty("HostKey", sshHostRsaKeyFilePath))
+		conf.AddProperty(config.NewProperty("HostKey", sshHostEd25519KeyFilePath))
+		conf.AddProperty(config.NewProperty("HostKey", sshHostEcdsaKeyFilePath))
+		conf.AddProperty(config.NewProperty("HostCertificate", sshHostRsaCertFilePath))
+		conf.AddProperty(config.NewProperty("HostCertificate", sshHostEd25519CertFilePath))
+		conf.AddProperty(config.NewProperty("HostCertificate", sshHostEcdsaCertFilePath))
+	}
 
 	conf.AddProperty(config.NewPropertyRaw("#"))
 	conf.AddProperty(config.NewPropertyRaw("### EXTRA CONFIG ###"))
@@ -8,12 +8,64 @@ import (
 	"testing"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestBuilder_BuildLoginSshConfig(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	controllerRef := slinkyv1alpha1.ObjectReference{
+		Name: controller.Name,
+	}
+	users := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: controller.UsersKey().Name,
+		},
+		Data: map[string]string{
+			passwdFile: "alice:x:1000:1000::/home/alice:/bin/bash\n",
+			groupFile:  "alice:x:1000:\n",
+		},
+	}
+	sshCaLoginSet := &slinkyv1alpha1.LoginSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.LoginSetSpec{
+			ControllerRef: controllerRef,
+			SshCertificateAuthority: &slinkyv1alpha1.LoginSetSshCertificateAuthority{
+				SignHostKeys: true,
+			},
+		},
+	}
+	sshCa, err := New(fake.NewFakeClient()).BuildLoginSshCa(sshCaLoginSet)
+	if err != nil {
+		t.Fatalf("Builder.BuildLoginSshCa() error = %v", err)
+	}
+	userCaLoginSet := sshCaLoginSet.DeepCopy()
+	userCaLoginSet.Spec.SshCertificateAuthority = &slinkyv1alpha1.LoginSetSshCertificateAuthority{
+		UserCaPublicKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "ssh-user-ca",
+			},
+			Key: "ssh_user_ca_key.pub",
+		},
+	}
+	userCa := func(key []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ssh-user-ca",
+			},
+			Data: map[string][]byte{
+				"ssh_user_ca_key.pub": key,
+			},
+		}
+	}
 	type fields struct {
 		client client.Client
 	}
@@ -21,15 +73,16 @@ func TestBuilder_BuildLoginSshConfig(t *testing.T) {
 		loginset *slinkyv1alpha1.LoginSet
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
+		name      string
+		fields    fields
+		args      args
+		wantUsers bool
+		wantErr   bool
 	}{
 		{
 			name: "default",
 			fields: fields{
-				client: fake.NewFakeClient(),
+				client: fake.NewFakeClient(controller.DeepCopy()),
 			},
 			args: args{
 				loginset: &slinkyv1alpha1.LoginSet{
@@ -37,6 +90,7 @@ func TestBuilder_BuildLoginSshConfig(t *testing.T) {
 						Name: "slurm",
 					},
 					Spec: slinkyv1alpha1.LoginSetSpec{
+						ControllerRef: controllerRef,
 						RootSshAuthorizedKeys: strings.Join([]string{
 							"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx user@example.com",
 						}, "\n"),
@@ -45,6 +99,61 @@ func TestBuilder_BuildLoginSshConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "users",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), users.DeepCopy()),
+			},
+			args: args{
+				loginset: &slinkyv1alpha1.LoginSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "slurm",
+					},
+					Spec: slinkyv1alpha1.LoginSetSpec{
+						ControllerRef: controllerRef,
+					},
+				},
+			},
+			wantUsers: true,
+		},
+		{
+			name: "SSH CA",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), sshCa),
+			},
+			args: args{
+				loginset: sshCaLoginSet,
+			},
+		},
+		{
+			name: "SSH user CA public key",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), userCa(sshCa.Data[slinkyv1alpha1.SshUserCaPublicKeyFile])),
+			},
+			args: args{
+				loginset: userCaLoginSet,
+			},
+		},
+		{
+			name: "SSH user CA private key",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), userCa(sshCa.Data[slinkyv1alpha1.SshUserCaKeyFile])),
+			},
+			args: args{
+				loginset: userCaLoginSet,
+			},
+			wantErr: true,
+		},
+		{
+			name: "SSH CA, missing",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy()),
+			},
+			args: args{
+				loginset: sshCaLoginSet,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,6 +167,18 @@ func TestBuilder_BuildLoginSshConfig(t *testing.T) {
 			case err != nil:
 				return
 
+			case strings.Contains(got.Data[sshdConfigFile], "AuthorizedKeysFile") != tt.wantUsers:
+				t.Errorf("got.Data[%s] = %v", sshdConfigFile, got.Data[sshdConfigFile])
+
+			case strings.Contains(got.Data[sshdConfigFile], "TrustedUserCAKeys") != tt.args.loginset.HasSshCa():
+				t.Errorf("got.Data[%s] = %v", sshdConfigFile, got.Data[sshdConfigFile])
+
+			case strings.Contains(got.Data[sshdConfigFile], "HostCertificate") != tt.args.loginset.HasSshHostCertificates():
+				t.Errorf("got.Data[%s] = %v", sshdConfigFile, got.Data[sshdConfigFile])
+
+			case tt.args.loginset.HasSshCa() && !strings.HasPrefix(got.Data[trustedUserCaKeysFile], "ssh-ed25519 "):
+				t.Errorf("got.Data[%s] = %v", trustedUserCaKeysFile, got.Data[trustedUserCaKeysFile])
+
 			case got.Data[authorizedKeysFile] == "" && got.BinaryData[authorizedKeysFile] == nil:
 				t.Errorf("got.Data[%s] = %v", authorizedKeysFile, got.Data[authorizedKeysFile])
 
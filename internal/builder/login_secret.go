@@ -4,9 +4,12 @@
 package builder
 
 import (
+	"context"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
@@ -46,3 +49,102 @@ func (b *Builder) BuildLoginSshHostKeys(loginset *slinkyv1alpha1.LoginSet) (*cor
 
 	return b.BuildSecret(opts, loginset)
 }
+
+// BuildLoginSshCa returns the secret of the SSH CAs which are generated by the
+// operator, because they are not referenced.
+func (b *Builder) BuildLoginSshCa(loginset *slinkyv1alpha1.LoginSet) (*corev1.Secret, error) {
+	keyPairUserCa, err := crypto.NewKeyPair(crypto.WithType(crypto.KeyPairEd25519))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user CA key pair: %w", err)
+	}
+	keyPairHostCa, err := crypto.NewKeyPair(crypto.WithType(crypto.KeyPairEd25519))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create host CA key pair: %w", err)
+	}
+
+	opts := SecretOpts{
+		Key:      loginset.SshCaKey(),
+		Metadata: loginset.Spec.Template.PodMetadata,
+		Data: map[string][]byte{
+			slinkyv1alpha1.SshUserCaKeyFile:          keyPairUserCa.PrivateKey(),
+			slinkyv1alpha1.SshUserCaPublicKeyFile:    keyPairUserCa.PublicKey(),
+			slinkyv1alpha1.SshHostCaKeyFile:          keyPairHostCa.PrivateKey(),
+			slinkyv1alpha1.SshHostCaKeyFile + ".pub": keyPairHostCa.PublicKey(),
+		},
+		Immutable: true,
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithLoginLabels(loginset).Build())
+
+	return b.BuildSecret(opts, loginset)
+}
+
+// BuildLoginSshHostCertificates returns the secret of the host certificates of
+// the SSH host keys, signed by the host CA. Certificates which are still valid
+// are kept, so pods are only restarted when a host key or the host CA changes.
+func (b *Builder) BuildLoginSshHostCertificates(loginset *slinkyv1alpha1.LoginSet) (*corev1.Secret, error) {
+	ctx := context.TODO()
+
+	caKey, err := b.refResolver.GetSecretKeyRef(ctx, loginset.SshHostCaRef(), loginset.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	sshHostKeys := &corev1.Secret{}
+	sshHostKeysKey := loginset.SshHostKeys()
+	if err := b.client.Get(ctx, sshHostKeysKey, sshHostKeys); err != nil {
+		return nil, fmt.Errorf("failed to get object (%s): %w", sshHostKeysKey, err)
+	}
+
+	sshHostCerts := &corev1.Secret{}
+	sshHostCertsKey := loginset.SshHostCertificatesKey()
+	if err := b.client.Get(ctx, sshHostCertsKey, sshHostCerts); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(sshHostCerts), err)
+		}
+	}
+
+	keyId := sshHostCertsKey.String()
+	principals := loginset.SshHostCertificatePrincipals()
+	data := make(map[string][]byte, len(sshHostCertFiles))
+	for pubKeyFile, certFile := range sshHostCertFiles {
+		pubKey := sshHostKeys.Data[pubKeyFile]
+		if cert := sshHostCerts.Data[certFile]; crypto.IsSshHostCertificateValid(cert, caKey, pubKey, principals) {
+			data[certFile] = cert
+			continue
+		}
+		cert, err := crypto.SignSshHostCertificate(caKey, pubKey, keyId, principals)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign host key (%s): %w", pubKeyFile, err)
+		}
+		data[certFile] = cert
+	}
+
+	opts := SecretOpts{
+		Key:      sshHostCertsKey,
+		Metadata: loginset.Spec.Template.PodMetadata,
+		Data:     data,
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithLoginLabels(loginset).Build())
+
+	return b.BuildSecret(opts, loginset)
+}
+
+// getSshUserCaPublicKey returns the public key of the user CA, in
+// `authorized_keys` format, if any. Only the public key is read, never the
+// private key of the user CA.
+func (b *Builder) getSshUserCaPublicKey(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) (string, error) {
+	if !loginset.HasSshCa() {
+		return "", nil
+	}
+	caPubKey, err := b.refResolver.GetSecretKeyRef(ctx, loginset.SshUserCaPublicKeyRef(), loginset.Namespace)
+	if err != nil {
+		return "", err
+	}
+	pubKey, err := crypto.SshAuthorizedKey(caPubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user CA public key: %w", err)
+	}
+	return string(pubKey), nil
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -69,3 +71,55 @@ func TestBuilder_BuildLoginSshHostKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildLoginSshHostCertificates(t *testing.T) {
+	loginset := &slinkyv1alpha1.LoginSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: "slurm",
+		},
+		Spec: slinkyv1alpha1.LoginSetSpec{
+			SshCertificateAuthority: &slinkyv1alpha1.LoginSetSshCertificateAuthority{
+				SignHostKeys: true,
+			},
+		},
+	}
+	b := New(fake.NewFakeClient())
+	sshCa, err := b.BuildLoginSshCa(loginset)
+	if err != nil {
+		t.Fatalf("Builder.BuildLoginSshCa() error = %v", err)
+	}
+	sshHostKeys, err := b.BuildLoginSshHostKeys(loginset)
+	if err != nil {
+		t.Fatalf("Builder.BuildLoginSshHostKeys() error = %v", err)
+	}
+	caKey := sshCa.Data[slinkyv1alpha1.SshHostCaKeyFile]
+	principals := loginset.SshHostCertificatePrincipals()
+
+	b = New(fake.NewFakeClient(sshCa, sshHostKeys))
+	got, err := b.BuildLoginSshHostCertificates(loginset)
+	if err != nil {
+		t.Fatalf("Builder.BuildLoginSshHostCertificates() error = %v", err)
+	}
+	for pubKeyFile, certFile := range sshHostCertFiles {
+		if !crypto.IsSshHostCertificateValid(got.Data[certFile], caKey, sshHostKeys.Data[pubKeyFile], principals) {
+			t.Errorf("got.Data[%s] = %s, not a valid host certificate", certFile, got.Data[certFile])
+		}
+	}
+
+	// Valid certificates are kept.
+	b = New(fake.NewFakeClient(sshCa, sshHostKeys, got))
+	again, err := b.BuildLoginSshHostCertificates(loginset)
+	if err != nil {
+		t.Fatalf("Builder.BuildLoginSshHostCertificates() error = %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(again.Data, got.Data) {
+		t.Errorf("Builder.BuildLoginSshHostCertificates() = %v, want %v", again.Data, got.Data)
+	}
+
+	// Host keys are required.
+	b = New(fake.NewFakeClient(sshCa))
+	if _, err := b.BuildLoginSshHostCertificates(loginset); err == nil {
+		t.Errorf("Builder.BuildLoginSshHostCertificates() error = nil, want error without host keys")
+	}
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+const (
+	// OidcJwksRefreshInterval is how often the JWKS of an OIDC issuer is
+	// fetched again, as issuers rotate their keys.
+	OidcJwksRefreshInterval = 1 * time.Hour
+	// OidcJwksRetryInterval is how soon the JWKS of an OIDC issuer is fetched
+	// again after a fetch failed.
+	OidcJwksRetryInterval = 1 * time.Minute
+
+	// AnnotationOidcJwksFetchTime is the time the JWKS of the OIDC issuers was
+	// last fetched, as recorded on the JWKS Secret.
+	AnnotationOidcJwksFetchTime = slinkyv1alpha1.SlinkyPrefix + "oidc-jwks-fetch-time"
+	// AnnotationOidcJwksFetchError is the error of the last fetch of the JWKS
+	// of the OIDC issuers, if any, as recorded on the JWKS Secret.
+	AnnotationOidcJwksFetchError = slinkyv1alpha1.SlinkyPrefix + "oidc-jwks-fetch-error"
+
+	// oidcJwksCachePrefix prefixes the JWKS Secret keys of the cached JWKS of
+	// the OIDC issuers.
+	oidcJwksCachePrefix = "oidc-"
+
+	// oidcJwksMaxSize is the maximum size of a JWKS fetched from an OIDC issuer.
+	oidcJwksMaxSize = 1 << 20
+)
+
+// getControllerOidcs returns the OIDC issuers of the RestApis of the
+// controller, ordered by RestApi name.
+func (b *Builder) getControllerOidcs(ctx context.Context, controller *slinkyv1alpha1.Controller) ([]slinkyv1alpha1.RestApiOidc, error) {
+	restapiList, err := b.refResolver.GetRestapisForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(restapiList.Items, func(i, j int) bool {
+		return restapiList.Items[i].Name < restapiList.Items[j].Name
+	})
+	oidcs := []slinkyv1alpha1.RestApiOidc{}
+	for _, restapi := range restapiList.Items {
+		if restapi.HasOidc() {
+			oidcs = append(oidcs, *restapi.Spec.Oidc)
+		}
+	}
+	return oidcs, nil
+}
+
+// getAccountingOidcs returns the OIDC issuers of the RestApis of the
+// controllers of the accounting, as slurmdbd also verifies their JWTs.
+func (b *Builder) getAccountingOidcs(ctx context.Context, accounting *slinkyv1alpha1.Accounting) ([]slinkyv1alpha1.RestApiOidc, error) {
+	controllerList, err := b.refResolver.GetControllersForAccounting(ctx, accounting)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(controllerList.Items, func(i, j int) bool {
+		return controllerList.Items[i].Name < controllerList.Items[j].Name
+	})
+	oidcs := []slinkyv1alpha1.RestApiOidc{}
+	for _, controller := range controllerList.Items {
+		controllerOidcs, err := b.getControllerOidcs(ctx, &controller)
+		if err != nil {
+			return nil, err
+		}
+		oidcs = append(oidcs, controllerOidcs...)
+	}
+	return oidcs, nil
+}
+
+// ControllerHasJwks returns true if slurmctld verifies JWTs with a JWKS, which
+// is also the case when any of its RestApis accepts JWTs of an OIDC issuer.
+func (b *Builder) ControllerHasJwks(controller *slinkyv1alpha1.Controller) (bool, error) {
+	if controller.HasJwks() {
+		return true, nil
+	}
+	oidcs, err := b.getControllerOidcs(context.TODO(), controller)
+	if err != nil {
+		return false, err
+	}
+	return len(oidcs) > 0, nil
+}
+
+// AccountingHasJwks returns true if slurmdbd verifies JWTs with a JWKS, which
+// is also the case when any RestApi of its controllers accepts JWTs of an OIDC
+// issuer.
+func (b *Builder) AccountingHasJwks(accounting *slinkyv1alpha1.Accounting) (bool, error) {
+	if accounting.HasJwks() {
+		return true, nil
+	}
+	oidcs, err := b.getAccountingOidcs(context.TODO(), accounting)
+	if err != nil {
+		return false, err
+	}
+	return len(oidcs) > 0, nil
+}
+
+// ControllerFetchesOidcJwks returns true if the JWKS of any OIDC issuer of the
+// controller is fetched from its URL.
+func (b *Builder) ControllerFetchesOidcJwks(controller *slinkyv1alpha1.Controller) (bool, error) {
+	oidcs, err := b.getControllerOidcs(context.TODO(), controller)
+	if err != nil {
+		return false, err
+	}
+	return hasOidcJwksUrl(oidcs), nil
+}
+
+// AccountingFetchesOidcJwks returns true if the JWKS of any OIDC issuer of the
+// accounting is fetched from its URL.
+func (b *Builder) AccountingFetchesOidcJwks(accounting *slinkyv1alpha1.Accounting) (bool, error) {
+	oidcs, err := b.getAccountingOidcs(context.TODO(), accounting)
+	if err != nil {
+		return false, err
+	}
+	return hasOidcJwksUrl(oidcs), nil
+}
+
+func hasOidcJwksUrl(oidcs []slinkyv1alpha1.RestApiOidc) bool {
+	for _, oidc := range oidcs {
+		if oidc.JwksUrl != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// oidcUsernameClaim returns the claim which Slurm maps to the username, if
+// any OIDC issuer sets one. Slurm only supports a single claim per cluster.
+func oidcUsernameClaim(oidcs []slinkyv1alpha1.RestApiOidc) string {
+	for _, oidc := range oidcs {
+		if oidc.UsernameClaim != "" {
+			return oidc.UsernameClaim
+		}
+	}
+	return ""
+}
+
+// oidcJwksCache is the JWKS of each OIDC issuer as last fetched, which is kept
+// in the JWKS Secret, so that the keys of an unreachable issuer are not dropped.
+type oidcJwksCache struct {
+	jwks      map[string][]byte
+	fetchTime time.Time
+	fetchErr  string
+}
+
+// oidcJwksCacheKey returns the JWKS Secret key of the cached JWKS of an issuer.
+func oidcJwksCacheKey(url string) string {
+	return oidcJwksCachePrefix + crypto.CheckSum([]byte(url))[:16] + ".json"
+}
+
+// getOidcJwksCache returns the JWKS of the OIDC issuers cached in the JWKS
+// Secret, if it exists.
+func (b *Builder) getOidcJwksCache(ctx context.Context, key types.NamespacedName) (*oidcJwksCache, error) {
+	cache := &oidcJwksCache{
+		jwks: map[string][]byte{},
+	}
+	secret := &corev1.Secret{}
+	if err := b.client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cache, nil
+		}
+		return nil, err
+	}
+	for k, v := range secret.Data {
+		if strings.HasPrefix(k, oidcJwksCachePrefix) {
+			cache.jwks[k] = v
+		}
+	}
+	for k, v := range secret.StringData {
+		if strings.HasPrefix(k, oidcJwksCachePrefix) {
+			cache.jwks[k] = []byte(v)
+		}
+	}
+	cache.fetchTime, cache.fetchErr = OidcJwksFetchResult(secret)
+	return cache, nil
+}
+
+// OidcJwksFetchResult returns the time and error of the last fetch of the
+// JWKS of the OIDC issuers, as recorded on the JWKS Secret.
+func OidcJwksFetchResult(secret *corev1.Secret) (time.Time, string) {
+	annotations := secret.GetAnnotations()
+	fetchTime, err := time.Parse(time.RFC3339, annotations[AnnotationOidcJwksFetchTime])
+	if err != nil {
+		return time.Time{}, annotations[AnnotationOidcJwksFetchError]
+	}
+	return fetchTime, annotations[AnnotationOidcJwksFetchError]
+}
+
+// OidcJwksNextFetch returns how long until the JWKS of the OIDC issuers is
+// fetched again, which is sooner after a fetch failed.
+func OidcJwksNextFetch(fetchTime time.Time, fetchErr string, now time.Time) time.Duration {
+	interval := OidcJwksRefreshInterval
+	if fetchErr != "" {
+		interval = OidcJwksRetryInterval
+	}
+	return max(fetchTime.Add(interval).Sub(now), 0)
+}
+
+// getOidcJwks returns the keys of the JWKS of the OIDC issuers. The JWKS of an
+// issuer is only fetched from its URL when the cache is due a refresh, or does
+// not have it yet. When a fetch fails, the cached JWKS is kept, and the error
+// is recorded in the cache instead, so an unreachable issuer never fails the
+// sync.
+func (b *Builder) getOidcJwks(ctx context.Context, oidcs []slinkyv1alpha1.RestApiOidc, cache *oidcJwksCache, now time.Time) ([]jwk, error) {
+	due := OidcJwksNextFetch(cache.fetchTime, cache.fetchErr, now) == 0
+	fetched := false
+	errs := []string{}
+	cached := map[string][]byte{}
+	keys := []jwk{}
+	for _, oidc := range oidcs {
+		data := []byte(oidc.Jwks)
+		if oidc.JwksUrl != "" {
+			cacheKey := oidcJwksCacheKey(oidc.JwksUrl)
+			var ok bool
+			data, ok = cache.jwks[cacheKey]
+			if due || (!ok && cache.fetchErr == "") {
+				fetched = true
+				newData, err := b.fetchOidcJwks(ctx, oidc.JwksUrl)
+				if err == nil {
+					_, err = parseOidcJwks(newData)
+				}
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("failed to fetch JWKS (%s): %v", oidc.JwksUrl, err))
+				} else {
+					data, ok = newData, true
+				}
+			}
+			if !ok {
+				continue
+			}
+			cached[cacheKey] = data
+		}
+		oidcKeys, err := parseOidcJwks(data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, oidcKeys...)
+	}
+	cache.jwks = cached
+	if fetched {
+		cache.fetchTime = now
+		cache.fetchErr = strings.Join(errs, "; ")
+	}
+	return keys, nil
+}
+
+func (b *Builder) fetchOidcJwks(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, oidcJwksMaxSize))
+}
+
+// parseOidcJwks returns the RS256 keys of the JWKS of an OIDC issuer, which
+// are the only keys Slurm verifies JWTs of an issuer with.
+func parseOidcJwks(data []byte) ([]jwk, error) {
+	in := jwks{}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	keys := []jwk{}
+	for _, key := range in.Keys {
+		if key.KeyType != "RSA" || key.Modulus == "" || key.Exponent == "" {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != "RS256" {
+			continue
+		}
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		key.Algorithm = "RS256"
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no RS256 keys")
+	}
+	return keys, nil
+}
+
+// ValidateOidcJwks returns an error if the JWKS of an OIDC issuer has no keys
+// which Slurm can verify JWTs with.
+func ValidateOidcJwks(data string) error {
+	_, err := parseOidcJwks([]byte(data))
+	return err
+}
@@ -0,0 +1,304 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newIssuerJwks returns the JWKS of a stand-in OIDC issuer, with an RS256 key
+// and a key which Slurm cannot verify JWTs with.
+func newIssuerJwks(t *testing.T) (string, string) {
+	keyPair, err := crypto.NewKeyPair(crypto.WithType(crypto.KeyPairRsa), crypto.WithRsaLength(2048))
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	rsaKey, err := newRs256Jwk(keyPair.PrivateKey())
+	if err != nil {
+		t.Fatalf("newRs256Jwk() error = %v", err)
+	}
+	rsaKey.KeyID = "issuer-rs256"
+	ecKey := jwk{KeyType: "EC", Use: "sig", Algorithm: "ES256", KeyID: "issuer-es256"}
+	data, err := marshalJwks(rsaKey, ecKey)
+	if err != nil {
+		t.Fatalf("marshalJwks() error = %v", err)
+	}
+	return data, rsaKey.KeyID
+}
+
+func Test_parseOidcJwks(t *testing.T) {
+	issuerJwks, kid := newIssuerJwks(t)
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "RS256 keys only",
+			data: issuerJwks,
+			want: []string{kid},
+		},
+		{
+			name:    "No RS256 keys",
+			data:    `{"keys":[{"kty":"EC","alg":"ES256","kid":"foo"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Encryption key",
+			data:    `{"keys":[{"kty":"RSA","use":"enc","kid":"foo","n":"AQAB","e":"AQAB"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid",
+			data:    `keys`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOidcJwks([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOidcJwks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseOidcJwks() = %v, want %v keys", got, len(tt.want))
+			}
+			for i, key := range got {
+				if key.KeyID != tt.want[i] || key.Algorithm != "RS256" {
+					t.Errorf("parseOidcJwks() key[%d] = %v, want RS256 kid %v", i, key, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_Oidc(t *testing.T) {
+	issuerJwks, kid := newIssuerJwks(t)
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/certs" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(issuerJwks))
+	}))
+	defer issuer.Close()
+
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			JwtHs256KeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "jwt",
+				},
+				Key: "jwt_hs256.key",
+			},
+		},
+	}
+	jwtSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "jwt",
+		},
+		Data: map[string][]byte{
+			"jwt_hs256.key": []byte("jwt"),
+		},
+	}
+	restapi := func(oidc *slinkyv1alpha1.RestApiOidc) *slinkyv1alpha1.RestApi {
+		return &slinkyv1alpha1.RestApi{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "slurm",
+			},
+			Spec: slinkyv1alpha1.RestApiSpec{
+				ControllerRef: slinkyv1alpha1.ObjectReference{
+					Name: controller.Name,
+				},
+				Oidc: oidc,
+			},
+		}
+	}
+	// jwksSecret returns the JWKS Secret, caching the JWKS of the issuer at
+	// url as fetched at fetchTime.
+	jwksSecret := func(url string, fetchTime time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: controller.JwksKey().Name,
+				Annotations: map[string]string{
+					AnnotationOidcJwksFetchTime: fetchTime.UTC().Format(time.RFC3339),
+				},
+			},
+			Data: map[string][]byte{
+				oidcJwksCacheKey(url): []byte(issuerJwks),
+			},
+		}
+	}
+	type fields struct {
+		client client.Client
+	}
+	tests := []struct {
+		name              string
+		fields            fields
+		wantHasJwks       bool
+		wantKeyIDs        []string
+		wantAuthAltParams string
+		wantFetchErr      bool
+	}{
+		{
+			name: "No OIDC",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), jwtSecret.DeepCopy(), restapi(nil)),
+			},
+			wantAuthAltParams: "AuthAltParameters=jwt_key=/etc/slurm/jwt_hs256.key\n",
+		},
+		{
+			name: "JWKS URL",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), jwtSecret.DeepCopy(), restapi(&slinkyv1alpha1.RestApiOidc{
+					JwksUrl:       issuer.URL + "/certs",
+					UsernameClaim: "preferred_username",
+				})),
+			},
+			wantHasJwks:       true,
+			wantKeyIDs:        []string{kid},
+			wantAuthAltParams: "AuthAltParameters=jwt_key=/etc/slurm/jwt_hs256.key,jwks=/etc/slurm/jwks.json,userclaimfield=preferred_username\n",
+		},
+		{
+			name: "Inline JWKS",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), jwtSecret.DeepCopy(), restapi(&slinkyv1alpha1.RestApiOidc{
+					Jwks: issuerJwks,
+				})),
+			},
+			wantHasJwks:       true,
+			wantKeyIDs:        []string{kid},
+			wantAuthAltParams: "AuthAltParameters=jwt_key=/etc/slurm/jwt_hs256.key,jwks=/etc/slurm/jwks.json\n",
+		},
+		{
+			name: "Issuer unavailable",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), jwtSecret.DeepCopy(), restapi(&slinkyv1alpha1.RestApiOidc{
+					JwksUrl: issuer.URL + "/missing",
+				})),
+			},
+			wantHasJwks:  true,
+			wantKeyIDs:   []string{},
+			wantFetchErr: true,
+		},
+		{
+			name: "Issuer unavailable, cached",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), jwtSecret.DeepCopy(), restapi(&slinkyv1alpha1.RestApiOidc{
+					JwksUrl: issuer.URL + "/missing",
+				}), jwksSecret(issuer.URL+"/missing", time.Now().Add(-OidcJwksRefreshInterval))),
+			},
+			wantHasJwks:  true,
+			wantKeyIDs:   []string{kid},
+			wantFetchErr: true,
+		},
+		{
+			name: "Not due a refresh",
+			fields: fields{
+				client: fake.NewFakeClient(controller.DeepCopy(), jwtSecret.DeepCopy(), restapi(&slinkyv1alpha1.RestApiOidc{
+					JwksUrl: issuer.URL + "/missing",
+				}), jwksSecret(issuer.URL+"/missing", time.Now())),
+			},
+			wantHasJwks: true,
+			wantKeyIDs:  []string{kid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.fields.client)
+			hasJwks, err := b.ControllerHasJwks(controller)
+			if err != nil {
+				t.Fatalf("Builder.ControllerHasJwks() error = %v", err)
+			}
+			if hasJwks != tt.wantHasJwks {
+				t.Errorf("Builder.ControllerHasJwks() = %v, want %v", hasJwks, tt.wantHasJwks)
+			}
+
+			slurmConf, err := b.BuildSlurmConf(controller)
+			if err != nil {
+				t.Fatalf("Builder.BuildSlurmConf() error = %v", err)
+			}
+			if !strings.Contains(slurmConf, tt.wantAuthAltParams) {
+				t.Errorf("Builder.BuildSlurmConf() = %v, want %v", slurmConf, tt.wantAuthAltParams)
+			}
+
+			if !hasJwks {
+				return
+			}
+			got, err := b.BuildControllerJwks(controller)
+			if err != nil {
+				t.Fatalf("Builder.BuildControllerJwks() error = %v", err)
+			}
+			if fetchErr := got.Annotations[AnnotationOidcJwksFetchError]; (fetchErr != "") != tt.wantFetchErr {
+				t.Errorf("Builder.BuildControllerJwks() fetch error = %v, wantFetchErr %v", fetchErr, tt.wantFetchErr)
+			}
+			out := jwks{}
+			if err := json.Unmarshal([]byte(got.StringData[JwksFile]), &out); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			keyIDs := []string{}
+			for _, key := range out.Keys {
+				keyIDs = append(keyIDs, key.KeyID)
+			}
+			if strings.Join(keyIDs, ",") != strings.Join(tt.wantKeyIDs, ",") {
+				t.Errorf("Builder.BuildControllerJwks() keys = %v, want %v", keyIDs, tt.wantKeyIDs)
+			}
+		})
+	}
+}
+
+func TestOidcJwksNextFetch(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		fetchTime time.Time
+		fetchErr  string
+		want      time.Duration
+	}{
+		{
+			name: "Never fetched",
+			want: 0,
+		},
+		{
+			name:      "Fetched",
+			fetchTime: now.Add(-10 * time.Minute),
+			want:      OidcJwksRefreshInterval - 10*time.Minute,
+		},
+		{
+			name:      "Failed",
+			fetchTime: now.Add(-10 * time.Second),
+			fetchErr:  "failed to fetch JWKS",
+			want:      OidcJwksRetryInterval - 10*time.Second,
+		},
+		{
+			name:      "Overdue",
+			fetchTime: now.Add(-2 * OidcJwksRefreshInterval),
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OidcJwksNextFetch(tt.fetchTime, tt.fetchErr, now); got != tt.want {
+				t.Errorf("OidcJwksNextFetch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -76,9 +76,22 @@ func (b *Builder) restapiPodTemplate(restapi *slinkyv1alpha1.RestApi) (corev1.Po
 
 	hasAccounting := !apiequality.Semantic.DeepEqual(controller.Spec.AccountingRef, slinkyv1alpha1.ObjectReference{})
 
+	hasJwks, err := b.ControllerHasJwks(controller)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	tlsAnnotations, err := b.RestapiTlsAnnotations(ctx, restapi)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	objectMeta := metadata.NewBuilder(key).
 		WithMetadata(restapi.Spec.Template.PodMetadata).
 		WithLabels(labels.NewBuilder().WithRestapiLabels(restapi).Build()).
+		WithAnnotations(SlurmKeyRotationAnnotations(controller)).
+		WithAnnotations(JwtKeyRotationAnnotations(controller)).
+		WithAnnotations(tlsAnnotations).
 		WithAnnotations(map[string]string{
 			annotationDefaultContainer: labels.RestapiApp,
 		}).
@@ -104,15 +117,23 @@ func (b *Builder) restapiPodTemplate(restapi *slinkyv1alpha1.RestApi) (corev1.Po
 				RunAsGroup:   ptr.To(slurmrestdUserGid),
 				FSGroup:      ptr.To(slurmrestdUserGid),
 			},
-			Volumes: restapiVolumes(controller),
+			Volumes: restapiVolumes(controller, hasJwks),
 		},
 		merge: template.PodSpec,
 	}
 
+	if restapi.HasTls() {
+		opts.base.Containers = []corev1.Container{
+			withLocalListener(opts.base.Containers[0]),
+			b.restapiTlsProxyContainer(spec.Tls.Proxy),
+		}
+		opts.base.Volumes = append(opts.base.Volumes, restapiTlsVolumeFor(restapi))
+	}
+
 	return b.buildPodTemplate(opts), nil
 }
 
-func restapiVolumes(controller *slinkyv1alpha1.Controller) []corev1.Volume {
+func restapiVolumes(controller *slinkyv1alpha1.Controller, hasJwks bool) []corev1.Volume {
 	out := []corev1.Volume{
 		{
 			Name: slurmEtcVolume,
@@ -145,6 +166,12 @@ func restapiVolumes(controller *slinkyv1alpha1.Controller) []corev1.Volume {
 			},
 		},
 	}
+	if controller.HasSlurmJwks() {
+		out[0].Projected.Sources = append(out[0].Projected.Sources, slurmJwksVolumeProjection(controller))
+	}
+	if hasJwks {
+		out[0].Projected.Sources = append(out[0].Projected.Sources, jwksVolumeProjection(controller.JwksKey().Name))
+	}
 	return out
 }
 
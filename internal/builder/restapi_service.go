@@ -4,6 +4,8 @@
 package builder
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -35,3 +37,17 @@ func (b *Builder) BuildRestapiService(restapi *slinkyv1alpha1.RestApi) (*corev1.
 
 	return b.BuildService(opts, restapi)
 }
+
+// RestapiURL returns the URL of the slurmrestd service, reachable from any namespace.
+func RestapiURL(restapi *slinkyv1alpha1.RestApi) string {
+	port := defaultPort(int32(restapi.Spec.Service.Port), SlurmrestdPort)
+	return fmt.Sprintf("%s://%s:%d", RestapiScheme(restapi), restapi.ServiceFQDN(), port)
+}
+
+// RestapiScheme returns the URL scheme of the slurmrestd service.
+func RestapiScheme(restapi *slinkyv1alpha1.RestApi) string {
+	if restapi.HasTls() {
+		return "https"
+	}
+	return "http"
+}
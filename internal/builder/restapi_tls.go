@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	clientutils "github.com/SlinkyProject/slurm-client/pkg/utils"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
+)
+
+const (
+	// slurmrestdLocalPort is the port slurmrestd listens on, on localhost,
+	// when the TLS proxy listens on SlurmrestdPort.
+	slurmrestdLocalPort = 6821
+
+	restapiTlsProxyName = "tls-proxy"
+	restapiTlsVolume    = "slurmrestd-tls"
+	restapiTlsDir       = "/etc/slurmrestd/tls"
+
+	restapiTlsValidity = 10 * 365 * 24 * time.Hour
+
+	// tlsCaCertKey is the key of the CA certificate in a `kubernetes.io/tls`
+	// Secret, as written by cert-manager.
+	tlsCaCertKey = "ca.crt"
+
+	// AnnotationTlsHash is the hash of the TLS certificate of slurmrestd.
+	AnnotationTlsHash = slinkyv1alpha1.SlinkyPrefix + "tls-hash"
+)
+
+// BuildRestapiTlsSecret returns a `kubernetes.io/tls` Secret containing a
+// generated self-signed certificate of the slurmrestd service. It is only
+// generated into the default Secret, never into a Secret named by secretName.
+func (b *Builder) BuildRestapiTlsSecret(restapi *slinkyv1alpha1.RestApi) (*corev1.Secret, error) {
+	if !restapi.GeneratesTlsSecret() {
+		return nil, errors.New("TLS Secret is not generated: secretName is set, or TLS is not used")
+	}
+	serviceKey := restapi.ServiceKey()
+	dnsNames := []string{
+		serviceKey.Name,
+		restapi.ServiceFQDNShort(),
+		restapi.ServiceFQDN(),
+	}
+	certificate, privateKey, err := crypto.NewTlsCertificate(dnsNames, restapiTlsValidity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS certificate: %w", err)
+	}
+
+	opts := SecretOpts{
+		Key:      restapi.TlsSecretKey(),
+		Metadata: restapi.Spec.Template.PodMetadata,
+		Type:     corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certificate,
+			corev1.TLSPrivateKeyKey: privateKey,
+			tlsCaCertKey:            certificate,
+		},
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithRestapiLabels(restapi).Build())
+
+	return b.BuildSecret(opts, restapi)
+}
+
+// RestapiTlsAnnotations returns the pod annotations which cause slurmrestd pods
+// to be rolled out when the TLS certificate is renewed.
+func (b *Builder) RestapiTlsAnnotations(ctx context.Context, restapi *slinkyv1alpha1.RestApi) (map[string]string, error) {
+	if !restapi.HasTls() {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	secretKey := restapi.TlsSecretKey()
+	if err := b.client.Get(ctx, secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(secret), err)
+	}
+	return map[string]string{
+		AnnotationTlsHash: crypto.CheckSum(secret.Data[corev1.TLSCertKey]),
+	}, nil
+}
+
+// RestapiCaCertificate returns the CA certificate, in PEM format, which
+// verifies the TLS certificate of slurmrestd. Without a `ca.crt` key, the
+// certificate is expected to be self-signed.
+func RestapiCaCertificate(secret *corev1.Secret) []byte {
+	if ca := secret.Data[tlsCaCertKey]; len(ca) > 0 {
+		return ca
+	}
+	return secret.Data[corev1.TLSCertKey]
+}
+
+// restapiTlsProxyContainer returns the sidecar which terminates TLS on the
+// slurmrestd port, and forwards requests to slurmrestd on localhost.
+func (b *Builder) restapiTlsProxyContainer(container slinkyv1alpha1.ContainerMinimal) corev1.Container {
+	merge := &corev1.Container{}
+	clientutils.RemarshalOrDie(container, merge)
+
+	opts := ContainerOpts{
+		base: corev1.Container{
+			Name:    restapiTlsProxyName,
+			Command: []string{"socat"},
+			Args: []string{
+				fmt.Sprintf("OPENSSL-LISTEN:%d,reuseaddr,fork,verify=0,cert=%s,key=%s", SlurmrestdPort,
+					path.Join(restapiTlsDir, corev1.TLSCertKey), path.Join(restapiTlsDir, corev1.TLSPrivateKeyKey)),
+				fmt.Sprintf("TCP:127.0.0.1:%d", slurmrestdLocalPort),
+			},
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          labels.RestapiApp,
+					ContainerPort: SlurmrestdPort,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+			ReadinessProbe: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					TCPSocket: &corev1.TCPSocketAction{
+						Port: intstr.FromInt(SlurmrestdPort),
+					},
+				},
+			},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsNonRoot: ptr.To(true),
+				RunAsUser:    ptr.To(slurmrestdUserUid),
+				RunAsGroup:   ptr.To(slurmrestdUserGid),
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: restapiTlsVolume, MountPath: restapiTlsDir, ReadOnly: true},
+			},
+		},
+		merge: *merge,
+	}
+
+	return b.BuildContainer(opts)
+}
+
+// withLocalListener makes slurmrestd listen on localhost only, behind the TLS
+// proxy. The probes are left to the TLS proxy, as the kubelet cannot reach
+// localhost.
+func withLocalListener(container corev1.Container) corev1.Container {
+	container.Args[len(container.Args)-1] = fmt.Sprintf("127.0.0.1:%d", slurmrestdLocalPort)
+	container.Ports = nil
+	container.StartupProbe = nil
+	container.ReadinessProbe = nil
+	return container
+}
+
+func restapiTlsVolumeFor(restapi *slinkyv1alpha1.RestApi) corev1.Volume {
+	return corev1.Volume{
+		Name: restapiTlsVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  restapi.TlsSecretKey().Name,
+				DefaultMode: ptr.To[int32](0o440),
+				Items: []corev1.KeyToPath{
+					{Key: corev1.TLSCertKey, Path: corev1.TLSCertKey},
+					{Key: corev1.TLSPrivateKeyKey, Path: corev1.TLSPrivateKeyKey},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+func newTlsRestapi() *slinkyv1alpha1.RestApi {
+	return &slinkyv1alpha1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+		Spec: slinkyv1alpha1.RestApiSpec{
+			ControllerRef: slinkyv1alpha1.ObjectReference{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "slurm",
+			},
+			Tls: &slinkyv1alpha1.RestApiTls{
+				Proxy: slinkyv1alpha1.ContainerMinimal{
+					Image: "socat",
+				},
+			},
+		},
+	}
+}
+
+func TestBuilder_BuildRestapiTlsSecret(t *testing.T) {
+	restapi := newTlsRestapi()
+	b := New(fake.NewFakeClient())
+	got, err := b.BuildRestapiTlsSecret(restapi)
+	if err != nil {
+		t.Fatalf("Builder.BuildRestapiTlsSecret() error = %v", err)
+	}
+	if got.Name != "slurm-restapi-tls" || got.Type != corev1.SecretTypeTLS {
+		t.Errorf("Name = %v, Type = %v", got.Name, got.Type)
+	}
+	if got.Immutable != nil && *got.Immutable {
+		t.Errorf("Immutable = %v, want mutable", *got.Immutable)
+	}
+
+	block, _ := pem.Decode(got.Data[corev1.TLSCertKey])
+	if block == nil {
+		t.Fatalf("tls.crt is not PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(RestapiCaCertificate(got))
+	for _, dnsName := range []string{restapi.ServiceFQDNShort(), restapi.ServiceFQDN()} {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: dnsName, Roots: roots}); err != nil {
+			t.Errorf("Verify(%s) error = %v", dnsName, err)
+		}
+	}
+}
+
+func TestBuilder_BuildRestapiTlsSecret_SecretName(t *testing.T) {
+	restapi := newTlsRestapi()
+	restapi.Spec.Tls.SecretName = "slurm-restapi-cert"
+	b := New(fake.NewFakeClient())
+	if _, err := b.BuildRestapiTlsSecret(restapi); err == nil {
+		t.Errorf("Builder.BuildRestapiTlsSecret() error = nil, want error for a named Secret")
+	}
+}
+
+func TestBuilder_BuildRestapi_Tls(t *testing.T) {
+	restapi := newTlsRestapi()
+	certificate, privateKey, err := crypto.NewTlsCertificate([]string{restapi.ServiceFQDN()}, restapiTlsValidity)
+	if err != nil {
+		t.Fatalf("NewTlsCertificate() error = %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: restapi.TlsSecretKey().Namespace,
+			Name:      restapi.TlsSecretKey().Name,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certificate,
+			corev1.TLSPrivateKeyKey: privateKey,
+		},
+	}
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	b := New(fake.NewClientBuilder().WithObjects(controller, secret).Build())
+	got, err := b.BuildRestapi(restapi)
+	if err != nil {
+		t.Fatalf("Builder.BuildRestapi() error = %v", err)
+	}
+
+	podSpec := got.Spec.Template.Spec
+	if len(podSpec.Containers) != 2 {
+		t.Fatalf("len(Containers) = %v", len(podSpec.Containers))
+	}
+	slurmrestd, proxy := podSpec.Containers[0], podSpec.Containers[1]
+	if want := fmt.Sprintf("127.0.0.1:%d", slurmrestdLocalPort); slurmrestd.Args[len(slurmrestd.Args)-1] != want {
+		t.Errorf("slurmrestd Args = %v, want listener %v", slurmrestd.Args, want)
+	}
+	if len(slurmrestd.Ports) != 0 {
+		t.Errorf("slurmrestd Ports = %v, want none", slurmrestd.Ports)
+	}
+	if proxy.Name != restapiTlsProxyName || proxy.Image != "socat" || proxy.Ports[0].Name != labels.RestapiApp {
+		t.Errorf("proxy = %v", proxy)
+	}
+	if got.Spec.Template.Annotations[AnnotationTlsHash] != crypto.CheckSum(certificate) {
+		t.Errorf("Annotations = %v", got.Spec.Template.Annotations)
+	}
+	if want := fmt.Sprintf("https://%s:%d", restapi.ServiceFQDN(), SlurmrestdPort); RestapiURL(restapi) != want {
+		t.Errorf("RestapiURL() = %v, want %v", RestapiURL(restapi), want)
+	}
+}
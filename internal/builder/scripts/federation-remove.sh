@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+# SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+# SPDX-License-Identifier: Apache-2.0
+
+set -euo pipefail
+
+# FEDERATION is the name of the federation.
+
+function main() {
+	echo "[$(date)] Waiting for slurmdbd..."
+	until sacctmgr --noheader --parsable2 show federation >/dev/null; do
+		sleep 2
+	done
+
+	if [ -n "$(sacctmgr --noheader --parsable2 show federation "$FEDERATION" format=federation)" ]; then
+		echo "[$(date)] Removing federation '$FEDERATION'..."
+		sacctmgr --immediate remove federation "$FEDERATION"
+	fi
+
+	echo "[$(date)] SUCCESS"
+}
+main
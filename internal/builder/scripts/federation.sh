@@ -0,0 +1,46 @@
+#!/usr/bin/env bash
+# SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+# SPDX-License-Identifier: Apache-2.0
+
+set -euo pipefail
+
+# FEDERATION is the name of the federation.
+# FEDERATION_CLUSTERS is the comma separated list of member clusters.
+# FEDERATION_FEATURES is the space separated list of `<cluster>=<features>`.
+
+function modify() {
+	local out=""
+	if ! out="$(sacctmgr --immediate modify "$@" 2>&1)"; then
+		# Not an error, the federation is already up to date.
+		if ! grep -q "Nothing modified" <<<"$out"; then
+			echo "$out" >&2
+			return 1
+		fi
+	fi
+	echo "$out"
+}
+
+function main() {
+	echo "[$(date)] Waiting for slurmdbd..."
+	until sacctmgr --noheader --parsable2 show federation >/dev/null; do
+		sleep 2
+	done
+
+	if [ -z "$(sacctmgr --noheader --parsable2 show federation "$FEDERATION" format=federation)" ]; then
+		echo "[$(date)] Adding federation '$FEDERATION'..."
+		sacctmgr --immediate add federation "$FEDERATION"
+	fi
+
+	echo "[$(date)] Setting clusters of federation '$FEDERATION': $FEDERATION_CLUSTERS"
+	modify federation "$FEDERATION" set clusters="$FEDERATION_CLUSTERS"
+
+	for item in $FEDERATION_FEATURES; do
+		local cluster="${item%%=*}"
+		local features="${item#*=}"
+		echo "[$(date)] Setting features of cluster '$cluster': $features"
+		modify cluster "$cluster" set features="$features"
+	done
+
+	echo "[$(date)] SUCCESS"
+}
+main
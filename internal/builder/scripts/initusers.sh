@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+# SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+# SPDX-License-Identifier: Apache-2.0
+
+set -euo pipefail
+
+USERS_DIR=/mnt/users
+ETC_DIR=/mnt/etc
+
+# Append the entries of the extra file which conflict with no entry of the base
+# file, by name or by ID.
+function merge() {
+	local base="$1"
+	local extra="$2"
+	local out="$3"
+
+	awk -F: '
+		NR == FNR { names[$1]; ids[$3]; print; next }
+		($1 in names) || ($3 in ids) { print "skipping conflicting entry: " $1 > "/dev/stderr"; next }
+		{ print }
+	' "$base" "$extra" >"$out"
+}
+
+function main() {
+	# Keep the users and groups of the image, and add the provisioned ones.
+	merge /etc/passwd "${USERS_DIR}/passwd" "${ETC_DIR}/passwd"
+	merge /etc/group "${USERS_DIR}/group" "${ETC_DIR}/group"
+	chmod -v 644 "${ETC_DIR}/passwd" "${ETC_DIR}/group"
+}
+main
@@ -21,6 +21,7 @@ type SecretOpts struct {
 	Metadata   slinkyv1alpha1.Metadata
 	Data       map[string][]byte
 	StringData map[string]string
+	Type       corev1.SecretType
 	Immutable  bool
 }
 
@@ -33,6 +34,7 @@ func (b *Builder) BuildSecret(opts SecretOpts, owner metav1.Object) (*corev1.Sec
 		ObjectMeta: objectMeta,
 		Data:       opts.Data,
 		StringData: opts.StringData,
+		Type:       opts.Type,
 		Immutable:  ptr.To(opts.Immutable),
 	}
 
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/metadata"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+const (
+	sharedVolumePrefix = "shared-"
+
+	// AnnotationSharedVolumesHash is the hash of the shared volumes mounted on a pod.
+	AnnotationSharedVolumesHash = slinkyv1alpha1.SlinkyPrefix + "shared-volumes-hash"
+)
+
+// BuildSharedVolumeClaim returns the PersistentVolumeClaim of a shared volume
+// with a claim template, in the namespace. It is not owned by the controller,
+// so the data is retained when the controller is deleted.
+func (b *Builder) BuildSharedVolumeClaim(controller *slinkyv1alpha1.Controller, volume *slinkyv1alpha1.SharedVolume, namespace string) *corev1.PersistentVolumeClaim {
+	key := controller.SharedVolumeClaimKey(volume, namespace)
+	objectMeta := metadata.NewBuilder(key).
+		WithLabels(labels.NewBuilder().WithControllerLabels(controller).Build()).
+		Build()
+
+	o := &corev1.PersistentVolumeClaim{
+		ObjectMeta: objectMeta,
+	}
+	if volume.ClaimTemplate != nil {
+		o.Spec = *volume.ClaimTemplate.DeepCopy()
+	}
+
+	return o
+}
+
+// sharedVolumes returns the shared volumes of the component, for a pod in the
+// namespace.
+func sharedVolumes(controller *slinkyv1alpha1.Controller, namespace string, component slinkyv1alpha1.SharedVolumeComponent) []corev1.Volume {
+	out := []corev1.Volume{}
+	for _, volume := range controller.SharedVolumesFor(component) {
+		out = append(out, corev1.Volume{
+			Name: sharedVolumePrefix + volume.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: controller.SharedVolumeClaimKey(&volume, namespace).Name,
+					ReadOnly:  volume.ReadOnly,
+				},
+			},
+		})
+	}
+	return out
+}
+
+func sharedVolumeMounts(controller *slinkyv1alpha1.Controller, component slinkyv1alpha1.SharedVolumeComponent) []corev1.VolumeMount {
+	out := []corev1.VolumeMount{}
+	for _, volume := range controller.SharedVolumesFor(component) {
+		out = append(out, corev1.VolumeMount{
+			Name:      sharedVolumePrefix + volume.Name,
+			MountPath: volume.MountPath,
+			ReadOnly:  volume.ReadOnly,
+		})
+	}
+	return out
+}
+
+// SharedVolumesAnnotations returns the pod annotations which cause pods in the
+// namespace to be rolled out when the shared volumes of the component change.
+func SharedVolumesAnnotations(controller *slinkyv1alpha1.Controller, namespace string, component slinkyv1alpha1.SharedVolumeComponent) map[string]string {
+	mounts := sharedVolumeMounts(controller, component)
+	if len(mounts) == 0 {
+		return nil
+	}
+	bytes, _ := json.Marshal(struct {
+		Volumes      []corev1.Volume
+		VolumeMounts []corev1.VolumeMount
+	}{
+		Volumes:      sharedVolumes(controller, namespace, component),
+		VolumeMounts: mounts,
+	})
+	return map[string]string{
+		AnnotationSharedVolumesHash: crypto.CheckSum(bytes),
+	}
+}
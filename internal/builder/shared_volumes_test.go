@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+)
+
+func newSharedVolumesController() *slinkyv1alpha1.Controller {
+	return &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			SharedVolumes: []slinkyv1alpha1.SharedVolume{
+				{
+					Name:          "home",
+					MountPath:     "/home",
+					ExistingClaim: "home",
+				},
+				{
+					Name:      "scratch",
+					MountPath: "/scratch",
+					ClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("1Ti"),
+							},
+						},
+					},
+					Components: []slinkyv1alpha1.SharedVolumeComponent{slinkyv1alpha1.SharedVolumeComponentWorker},
+				},
+				{
+					Name:          "software",
+					MountPath:     "/opt/software",
+					ExistingClaim: "software",
+					ReadOnly:      true,
+					Components:    []slinkyv1alpha1.SharedVolumeComponent{slinkyv1alpha1.SharedVolumeComponentLogin},
+				},
+			},
+		},
+	}
+}
+
+func TestBuilder_BuildSharedVolumeClaim(t *testing.T) {
+	controller := newSharedVolumesController()
+	b := New(fake.NewFakeClient())
+	volume := &controller.Spec.SharedVolumes[1]
+	got := b.BuildSharedVolumeClaim(controller, volume, controller.Namespace)
+
+	if got.Name != "slurm-scratch" || got.Namespace != controller.Namespace {
+		t.Errorf("BuildSharedVolumeClaim() key = %s/%s", got.Namespace, got.Name)
+	}
+	if tenant := b.BuildSharedVolumeClaim(controller, volume, "tenant"); tenant.Name != "slurm-scratch" || tenant.Namespace != "tenant" {
+		t.Errorf("BuildSharedVolumeClaim() key = %s/%s, want tenant/slurm-scratch", tenant.Namespace, tenant.Name)
+	}
+	if len(got.OwnerReferences) != 0 {
+		t.Errorf("BuildSharedVolumeClaim() ownerReferences = %v, want none", got.OwnerReferences)
+	}
+	for key, value := range labels.NewBuilder().WithControllerLabels(controller).Build() {
+		if got.Labels[key] != value {
+			t.Errorf("BuildSharedVolumeClaim() label %s = %v, want %v", key, got.Labels[key], value)
+		}
+	}
+	if diff := cmp.Diff(*volume.ClaimTemplate, got.Spec); diff != "" {
+		t.Errorf("BuildSharedVolumeClaim() spec (-want,+got):\n%s", diff)
+	}
+}
+
+func Test_sharedVolumes(t *testing.T) {
+	controller := newSharedVolumesController()
+	tests := []struct {
+		name       string
+		component  slinkyv1alpha1.SharedVolumeComponent
+		want       []corev1.Volume
+		wantMounts []corev1.VolumeMount
+	}{
+		{
+			name:      "Login",
+			component: slinkyv1alpha1.SharedVolumeComponentLogin,
+			want: []corev1.Volume{
+				{
+					Name: "shared-home",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "home"},
+					},
+				},
+				{
+					Name: "shared-software",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "software", ReadOnly: true},
+					},
+				},
+			},
+			wantMounts: []corev1.VolumeMount{
+				{Name: "shared-home", MountPath: "/home"},
+				{Name: "shared-software", MountPath: "/opt/software", ReadOnly: true},
+			},
+		},
+		{
+			name:      "Worker",
+			component: slinkyv1alpha1.SharedVolumeComponentWorker,
+			want: []corev1.Volume{
+				{
+					Name: "shared-home",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "home"},
+					},
+				},
+				{
+					Name: "shared-scratch",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "slurm-scratch"},
+					},
+				},
+			},
+			wantMounts: []corev1.VolumeMount{
+				{Name: "shared-home", MountPath: "/home"},
+				{Name: "shared-scratch", MountPath: "/scratch"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, sharedVolumes(controller, controller.Namespace, tt.component)); diff != "" {
+				t.Errorf("sharedVolumes() (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMounts, sharedVolumeMounts(controller, tt.component)); diff != "" {
+				t.Errorf("sharedVolumeMounts() (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSharedVolumesAnnotations(t *testing.T) {
+	controller := newSharedVolumesController()
+	worker := SharedVolumesAnnotations(controller, controller.Namespace, slinkyv1alpha1.SharedVolumeComponentWorker)
+	if worker[AnnotationSharedVolumesHash] == "" {
+		t.Fatalf("SharedVolumesAnnotations() = %v, want hash", worker)
+	}
+
+	// Changing a login volume does not change the worker hash.
+	changed := controller.DeepCopy()
+	changed.Spec.SharedVolumes[2].MountPath = "/opt/apps"
+	if got := SharedVolumesAnnotations(changed, changed.Namespace, slinkyv1alpha1.SharedVolumeComponentWorker); !cmp.Equal(got, worker) {
+		t.Errorf("SharedVolumesAnnotations() = %v, want %v", got, worker)
+	}
+
+	changed.Spec.SharedVolumes[0].MountPath = "/users"
+	if got := SharedVolumesAnnotations(changed, changed.Namespace, slinkyv1alpha1.SharedVolumeComponentWorker); cmp.Equal(got, worker) {
+		t.Errorf("SharedVolumesAnnotations() = %v, want changed hash", got)
+	}
+
+	if got := SharedVolumesAnnotations(&slinkyv1alpha1.Controller{}, corev1.NamespaceDefault, slinkyv1alpha1.SharedVolumeComponentWorker); got != nil {
+		t.Errorf("SharedVolumesAnnotations() = %v, want nil", got)
+	}
+}
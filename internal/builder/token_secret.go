@@ -5,27 +5,34 @@ package builder
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
 )
 
 func (b *Builder) BuildTokenSecret(token *slinkyv1alpha1.Token) (*corev1.Secret, error) {
 	ctx := context.TODO()
 
-	jwtHs256Ref := token.JwtHs256Ref()
-	signingKey, err := b.refResolver.GetSecretKeyRef(ctx, &jwtHs256Ref.SecretKeySelector, jwtHs256Ref.Namespace)
+	signingRef, err := b.refResolver.GetTokenSigningKeyRef(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	signingKey, err := b.refResolver.GetSecretKeyRef(ctx, &signingRef.SecretKeySelector, signingRef.Namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	authToken, err := slurmjwt.NewToken(signingKey).
+	newToken := slurmjwt.NewToken(signingKey).
 		WithUsername(token.Username()).
 		WithLifetime(token.Lifetime()).
-		NewSignedToken()
+		WithGroups(token.Spec.Groups...).
+		WithAudience(token.Spec.Audience...)
+	authToken, err := newToken.NewSignedToken()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Slurm auth token: %w", err)
 	}
@@ -38,15 +45,70 @@ func (b *Builder) BuildTokenSecret(token *slinkyv1alpha1.Token) (*corev1.Secret,
 		Immutable: !token.Spec.Refresh,
 	}
 
-	jwtHs256Secret := &corev1.Secret{}
-	if err := b.client.Get(ctx, token.JwtHs256Key(), jwtHs256Secret); err != nil {
+	jwtSecret := &corev1.Secret{}
+	if err := b.client.Get(ctx, token.JwtKey(), jwtSecret); err != nil {
 		return nil, err
 	}
 
-	o, err := b.BuildSecret(opts, jwtHs256Secret)
+	o, err := b.BuildSecret(opts, jwtSecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build token secret: %w", err)
 	}
 
 	return o, nil
 }
+
+// tokenJson is the Json format of a JWT delivered to a target Secret.
+type tokenJson struct {
+	Server   string `json:"server,omitempty"`
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// BuildTokenTargetSecret returns a target Secret of the Token, containing the
+// JWT in the format of the target.
+func (b *Builder) BuildTokenTargetSecret(token *slinkyv1alpha1.Token, target slinkyv1alpha1.TokenTarget, authToken string) (*corev1.Secret, error) {
+	ctx := context.TODO()
+
+	server := ""
+	if target.RestapiRef != nil {
+		restapi := &slinkyv1alpha1.RestApi{}
+		if err := b.client.Get(ctx, token.TargetRestapiKey(target), restapi); err != nil {
+			return nil, err
+		}
+		server = RestapiURL(restapi)
+	}
+
+	var data string
+	switch target.Format {
+	case slinkyv1alpha1.TokenFormatEnv:
+		data = fmt.Sprintf("SLURM_JWT=%s\n", authToken)
+		if server != "" {
+			data += fmt.Sprintf("SLURMRESTD_URL=%s\n", server)
+		}
+	case slinkyv1alpha1.TokenFormatJson:
+		out, err := json.MarshalIndent(tokenJson{
+			Server:   server,
+			Username: token.Username(),
+			Token:    authToken,
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal token: %w", err)
+		}
+		data = string(out)
+	default:
+		data = authToken
+	}
+
+	opts := SecretOpts{
+		Key: token.TargetKey(target),
+		Metadata: slinkyv1alpha1.Metadata{
+			Labels: labels.NewBuilder().WithTokenTargetLabels(token).Build(),
+		},
+		StringData: map[string]string{
+			token.TargetDataKey(target): data,
+		},
+	}
+
+	return b.BuildSecret(opts, token)
+}
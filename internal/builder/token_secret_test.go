@@ -107,3 +107,75 @@ func TestBuilder_BuildTokenSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildTokenTargetSecret(t *testing.T) {
+	token := &slinkyv1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "foo",
+		},
+		Spec: slinkyv1alpha1.TokenSpec{
+			Username: "alice",
+		},
+	}
+	restapi := &slinkyv1alpha1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "slurm",
+		},
+	}
+	restapiRef := &slinkyv1alpha1.ObjectReference{Name: "slurm"}
+	tests := []struct {
+		name    string
+		target  slinkyv1alpha1.TokenTarget
+		wantKey string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Raw",
+			target:  slinkyv1alpha1.TokenTarget{Name: "jwt", Namespace: "apps"},
+			wantKey: "SLURM_JWT",
+			want:    "jwt",
+		},
+		{
+			name:    "Env",
+			target:  slinkyv1alpha1.TokenTarget{Name: "jwt", Format: slinkyv1alpha1.TokenFormatEnv, RestapiRef: restapiRef},
+			wantKey: "slurm.env",
+			want:    "SLURM_JWT=jwt\nSLURMRESTD_URL=http://slurm-restapi.slurm.svc.cluster.local:6820\n",
+		},
+		{
+			name:    "Json",
+			target:  slinkyv1alpha1.TokenTarget{Name: "jwt", Key: "config.json", Format: slinkyv1alpha1.TokenFormatJson, RestapiRef: restapiRef},
+			wantKey: "config.json",
+			want:    "{\n  \"server\": \"http://slurm-restapi.slurm.svc.cluster.local:6820\",\n  \"username\": \"alice\",\n  \"token\": \"jwt\"\n}",
+		},
+		{
+			name:    "RestApi not found",
+			target:  slinkyv1alpha1.TokenTarget{Name: "jwt", Format: slinkyv1alpha1.TokenFormatJson, RestapiRef: &slinkyv1alpha1.ObjectReference{Name: "other"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(fake.NewFakeClient(restapi))
+			got, err := b.BuildTokenTargetSecret(token, tt.target, "jwt")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildTokenTargetSecret() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Namespace != token.TargetKey(tt.target).Namespace {
+				t.Errorf("got.Namespace = %v, want %v", got.Namespace, token.TargetKey(tt.target).Namespace)
+			}
+			if got.StringData[tt.wantKey] != tt.want {
+				t.Errorf("got.StringData[%s] = %q, want %q", tt.wantKey, got.StringData[tt.wantKey], tt.want)
+			}
+			if got.Labels[slinkyv1alpha1.LabelTokenName] != token.Name {
+				t.Errorf("got.Labels = %v", got.Labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
+)
+
+const (
+	usersVolume   = "users"
+	usersMountDir = "/mnt/users"
+
+	usersEtcVolume   = "users-etc"
+	usersEtcMountDir = "/mnt/etc"
+
+	passwdFile     = "passwd"
+	passwdFilePath = "/etc/" + passwdFile
+	groupFile      = "group"
+	groupFilePath  = "/etc/" + groupFile
+
+	// usersAuthorizedKeysSuffix is the suffix of the `authorized_keys` of a
+	// user, in the users ConfigMap.
+	usersAuthorizedKeysSuffix = ".keys"
+	usersAuthorizedKeysDir    = sshDir + "/authorized_keys.d"
+
+	// AnnotationUsersHash is the hash of the users provisioned on a pod.
+	AnnotationUsersHash = slinkyv1alpha1.SlinkyPrefix + "users-hash"
+)
+
+// getControllerUsers returns the users of the LoginSets of the controller,
+// ordered by name. The same user may be defined by several LoginSets, but
+// conflicting definitions are an error, as IDs must match across all pods.
+func (b *Builder) getControllerUsers(ctx context.Context, controller *slinkyv1alpha1.Controller) ([]slinkyv1alpha1.LoginSetUser, error) {
+	loginsetList, err := b.refResolver.GetLoginSetsForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(loginsetList.Items, func(i, j int) bool {
+		return loginsetList.Items[i].Name < loginsetList.Items[j].Name
+	})
+	users := []slinkyv1alpha1.LoginSetUser{}
+	for _, loginset := range loginsetList.Items {
+		users = append(users, loginset.Spec.Users...)
+	}
+	return MergeUsers(users)
+}
+
+// MergeUsers returns the distinct users, ordered by name, or an error if any
+// users or groups conflict.
+func MergeUsers(users []slinkyv1alpha1.LoginSetUser) ([]slinkyv1alpha1.LoginSetUser, error) {
+	byName := map[string]slinkyv1alpha1.LoginSetUser{}
+	uidNames := map[int64]string{}
+	groupGids := map[string]int64{}
+	out := []slinkyv1alpha1.LoginSetUser{}
+	for _, user := range users {
+		if other, ok := byName[user.Name]; ok {
+			if !userEqual(user, other) {
+				return nil, fmt.Errorf("user %q is defined several times, differently", user.Name)
+			}
+			continue
+		}
+		if name, ok := uidNames[user.Uid]; ok {
+			return nil, fmt.Errorf("users %q and %q have the same uid (%d)", name, user.Name, user.Uid)
+		}
+		if gid, ok := groupGids[user.UserGroup()]; ok && gid != user.UserGid() {
+			return nil, fmt.Errorf("group %q has several gids (%d, %d)", user.UserGroup(), gid, user.UserGid())
+		}
+		byName[user.Name] = user
+		uidNames[user.Uid] = user.Name
+		groupGids[user.UserGroup()] = user.UserGid()
+		out = append(out, user)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+func userEqual(a, b slinkyv1alpha1.LoginSetUser) bool {
+	return a.Name == b.Name &&
+		a.Uid == b.Uid &&
+		a.UserGid() == b.UserGid() &&
+		a.UserGroup() == b.UserGroup() &&
+		a.UserHome() == b.UserHome() &&
+		a.UserShell() == b.UserShell() &&
+		a.SshAuthorizedKeys == b.SshAuthorizedKeys
+}
+
+// ControllerHasUsers returns true if any LoginSet of the controller provisions
+// users.
+func (b *Builder) ControllerHasUsers(controller *slinkyv1alpha1.Controller) (bool, error) {
+	users, err := b.getControllerUsers(context.TODO(), controller)
+	if err != nil {
+		return false, err
+	}
+	return len(users) > 0, nil
+}
+
+// BuildControllerUsers returns the ConfigMap of the users provisioned by the
+// LoginSets of the controller, in `passwd` and `group` format, with the
+// `authorized_keys` of each user.
+func (b *Builder) BuildControllerUsers(controller *slinkyv1alpha1.Controller) (*corev1.ConfigMap, error) {
+	users, err := b.getControllerUsers(context.TODO(), controller)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ConfigMapOpts{
+		Key:      controller.UsersKey(),
+		Metadata: controller.Spec.Template.PodMetadata,
+		Data:     buildUsersData(users),
+	}
+
+	opts.Metadata.Labels = structutils.MergeMaps(opts.Metadata.Labels, labels.NewBuilder().WithControllerLabels(controller).Build())
+
+	return b.BuildConfigMap(opts, controller)
+}
+
+// buildUsersData returns the `passwd` and `group` of the users, with the
+// `authorized_keys` of each user.
+func buildUsersData(users []slinkyv1alpha1.LoginSetUser) map[string]string {
+	data := map[string]string{
+		passwdFile: buildPasswd(users),
+		groupFile:  buildGroup(users),
+	}
+	for _, user := range users {
+		if user.SshAuthorizedKeys == "" {
+			continue
+		}
+		data[user.Name+usersAuthorizedKeysSuffix] = buildAuthorizedKeys(user.SshAuthorizedKeys)
+	}
+	return data
+}
+
+func buildPasswd(users []slinkyv1alpha1.LoginSetUser) string {
+	lines := []string{}
+	for _, user := range users {
+		lines = append(lines, fmt.Sprintf("%s:x:%d:%d::%s:%s", user.Name, user.Uid, user.UserGid(), user.UserHome(), user.UserShell()))
+	}
+	return strings.Join(append(lines, ""), "\n")
+}
+
+func buildGroup(users []slinkyv1alpha1.LoginSetUser) string {
+	lines := []string{}
+	groups := map[string]bool{}
+	for _, user := range users {
+		group := user.UserGroup()
+		if groups[group] {
+			continue
+		}
+		groups[group] = true
+		lines = append(lines, fmt.Sprintf("%s:x:%d:", group, user.UserGid()))
+	}
+	sort.Strings(lines)
+	return strings.Join(append(lines, ""), "\n")
+}
+
+// UsersAnnotations returns the pod annotations which cause pods to be rolled
+// out when the users provisioned by the LoginSets of the controller change.
+func (b *Builder) UsersAnnotations(ctx context.Context, controller *slinkyv1alpha1.Controller) (map[string]string, error) {
+	users := &corev1.ConfigMap{}
+	usersKey := controller.UsersKey()
+	if err := b.client.Get(ctx, usersKey, users); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(users), err)
+	}
+	return map[string]string{
+		AnnotationUsersHash: crypto.CheckSum([]byte(users.Data[passwdFile] + users.Data[groupFile])),
+	}, nil
+}
+
+// workerHasUsers returns true if the users annotation was applied to the pod
+// template of the nodeset.
+func workerHasUsers(nodeset *slinkyv1alpha1.NodeSet) bool {
+	return nodeset.Spec.Template.PodMetadata.Annotations[AnnotationUsersHash] != ""
+}
+
+// usersVolumes returns the volumes of the users of the controller, for a pod
+// in the namespace. In another namespace than the controller, the users are
+// copied into the namespace by the controller.
+func usersVolumes(controller *slinkyv1alpha1.Controller, namespace string) []corev1.Volume {
+	usersKey := controller.UsersKey()
+	if namespace != controller.Namespace {
+		usersKey = controller.TenantUsersKey(namespace)
+	}
+	return []corev1.Volume{
+		{
+			Name: usersVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: usersKey.Name,
+					},
+					DefaultMode: ptr.To[int32](0o644),
+				},
+			},
+		},
+		{
+			Name: usersEtcVolume,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium: corev1.StorageMediumMemory,
+				},
+			},
+		},
+	}
+}
+
+func usersVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{Name: usersEtcVolume, MountPath: passwdFilePath, SubPath: passwdFile, ReadOnly: true},
+		{Name: usersEtcVolume, MountPath: groupFilePath, SubPath: groupFile, ReadOnly: true},
+	}
+}
+
+//go:embed scripts/initusers.sh
+var initUsersScript string
+
+// initusersContainer returns the container which adds the users to the
+// `passwd` and `group` of the image of the container, so users of the image
+// are kept.
+func (b *Builder) initusersContainer(container corev1.Container) corev1.Container {
+	opts := ContainerOpts{
+		base: corev1.Container{
+			Name:            "initusers",
+			Image:           container.Image,
+			ImagePullPolicy: container.ImagePullPolicy,
+			Command: []string{
+				"bash",
+				"-c",
+				initUsersScript,
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: usersVolume, MountPath: usersMountDir, ReadOnly: true},
+				{Name: usersEtcVolume, MountPath: usersEtcMountDir},
+			},
+		},
+	}
+
+	return b.BuildContainer(opts)
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMergeUsers(t *testing.T) {
+	alice := slinkyv1alpha1.LoginSetUser{Name: "alice", Uid: 1000}
+	bob := slinkyv1alpha1.LoginSetUser{Name: "bob", Uid: 1001, Gid: ptr.To[int64](100), Group: "users"}
+	tests := []struct {
+		name    string
+		users   []slinkyv1alpha1.LoginSetUser
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Sorted",
+			users: []slinkyv1alpha1.LoginSetUser{bob, alice},
+			want:  []string{"alice", "bob"},
+		},
+		{
+			name:  "Same user",
+			users: []slinkyv1alpha1.LoginSetUser{alice, alice},
+			want:  []string{"alice"},
+		},
+		{
+			name:    "Different user, same name",
+			users:   []slinkyv1alpha1.LoginSetUser{alice, {Name: "alice", Uid: 2000}},
+			wantErr: true,
+		},
+		{
+			name:    "Same uid",
+			users:   []slinkyv1alpha1.LoginSetUser{alice, {Name: "carol", Uid: 1000}},
+			wantErr: true,
+		},
+		{
+			name:    "Same group, different gid",
+			users:   []slinkyv1alpha1.LoginSetUser{bob, {Name: "carol", Uid: 1002, Group: "users"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeUsers(tt.users)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeUsers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("MergeUsers() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Name != tt.want[i] {
+					t.Errorf("MergeUsers()[%d] = %v, want %v", i, got[i].Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildControllerUsers(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	loginset := func(name string, users ...slinkyv1alpha1.LoginSetUser) *slinkyv1alpha1.LoginSet {
+		return &slinkyv1alpha1.LoginSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: slinkyv1alpha1.LoginSetSpec{
+				ControllerRef: slinkyv1alpha1.ObjectReference{
+					Name: controller.Name,
+				},
+				Users: users,
+			},
+		}
+	}
+	alice := slinkyv1alpha1.LoginSetUser{
+		Name:              "alice",
+		Uid:               1000,
+		SshAuthorizedKeys: "ssh-ed25519 AAAA alice@example.com",
+	}
+	bob := slinkyv1alpha1.LoginSetUser{
+		Name:  "bob",
+		Uid:   1001,
+		Gid:   ptr.To[int64](100),
+		Group: "users",
+		Home:  "/shared/bob",
+		Shell: "/bin/zsh",
+	}
+	type fields struct {
+		client client.Client
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		wantPasswd string
+		wantGroup  string
+		wantKeys   []string
+		wantErr    bool
+	}{
+		{
+			name: "Across LoginSets",
+			fields: fields{
+				client: fake.NewFakeClient(loginset("a", alice), loginset("b", bob, alice)),
+			},
+			wantPasswd: "alice:x:1000:1000::/home/alice:/bin/bash\nbob:x:1001:100::/shared/bob:/bin/zsh\n",
+			wantGroup:  "alice:x:1000:\nusers:x:100:\n",
+			wantKeys:   []string{"alice.keys"},
+		},
+		{
+			name: "Conflict",
+			fields: fields{
+				client: fake.NewFakeClient(loginset("a", alice), loginset("b", slinkyv1alpha1.LoginSetUser{Name: "alice", Uid: 2000})),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.fields.client)
+			got, err := b.BuildControllerUsers(controller)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Builder.BuildControllerUsers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Data[passwdFile] != tt.wantPasswd {
+				t.Errorf("got.Data[%s] = %q, want %q", passwdFile, got.Data[passwdFile], tt.wantPasswd)
+			}
+			if got.Data[groupFile] != tt.wantGroup {
+				t.Errorf("got.Data[%s] = %q, want %q", groupFile, got.Data[groupFile], tt.wantGroup)
+			}
+			if len(got.Data) != 2+len(tt.wantKeys) {
+				t.Errorf("got.Data = %v, want keys %v", got.Data, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if got.Data[key] == "" {
+					t.Errorf("got.Data[%s] is empty", key)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_UsersAnnotations(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	users := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: controller.UsersKey().Name,
+		},
+		Data: map[string]string{
+			passwdFile: "alice:x:1000:1000::/home/alice:/bin/bash\n",
+		},
+	}
+	b := New(fake.NewFakeClient())
+	got, err := b.UsersAnnotations(context.TODO(), controller)
+	if err != nil || len(got) != 0 {
+		t.Errorf("Builder.UsersAnnotations() = %v, %v, want none", got, err)
+	}
+
+	b = New(fake.NewFakeClient(users))
+	got, err = b.UsersAnnotations(context.TODO(), controller)
+	if err != nil || got[AnnotationUsersHash] == "" {
+		t.Errorf("Builder.UsersAnnotations() = %v, %v, want %s", got, err, AnnotationUsersHash)
+	}
+
+	nodeset := &slinkyv1alpha1.NodeSet{}
+	nodeset.Spec.Template.PodMetadata.Annotations = got
+	template := b.BuildWorkerPodTemplate(nodeset, controller)
+	initContainers := template.Spec.InitContainers
+	if len(initContainers) == 0 || initContainers[len(initContainers)-1].Name != "initusers" {
+		t.Errorf("Builder.BuildWorkerPodTemplate() InitContainers = %v, want initusers", initContainers)
+	}
+}
@@ -36,6 +36,7 @@ func (b *Builder) BuildWorkerPodTemplate(nodeset *slinkyv1alpha1.NodeSet, contro
 	objectMeta := metadata.NewBuilder(key).
 		WithMetadata(nodeset.Spec.Template.PodMetadata).
 		WithLabels(labels.NewBuilder().WithWorkerLabels(nodeset).Build()).
+		WithAnnotations(SlurmKeyRotationAnnotations(controller)).
 		WithAnnotations(map[string]string{
 			annotationDefaultContainer: labels.WorkerApp,
 		}).
@@ -65,33 +66,27 @@ func (b *Builder) BuildWorkerPodTemplate(nodeset *slinkyv1alpha1.NodeSet, contro
 			InitContainers: []corev1.Container{
 				b.logfileContainer(spec.LogFile, slurmdLogFilePath),
 			},
-			Volumes: nodesetVolumes(controller),
+			Volumes: nodesetVolumes(nodeset, controller),
 		},
 		merge: template.PodSpec,
 	}
+	if workerHasUsers(nodeset) {
+		opts.base.InitContainers = append(opts.base.InitContainers, b.initusersContainer(spec.Slurmd.Container))
+		opts.base.Volumes = append(opts.base.Volumes, usersVolumes(controller, nodeset.Namespace)...)
+	}
+	opts.base.Volumes = append(opts.base.Volumes, sharedVolumes(controller, nodeset.Namespace, slinkyv1alpha1.SharedVolumeComponentWorker)...)
 
 	return b.buildPodTemplate(opts)
 }
 
-func nodesetVolumes(controller *slinkyv1alpha1.Controller) []corev1.Volume {
+func nodesetVolumes(nodeset *slinkyv1alpha1.NodeSet, controller *slinkyv1alpha1.Controller) []corev1.Volume {
 	out := []corev1.Volume{
 		{
 			Name: slurmEtcVolume,
 			VolumeSource: corev1.VolumeSource{
 				Projected: &corev1.ProjectedVolumeSource{
 					DefaultMode: ptr.To[int32](0o600),
-					Sources: []corev1.VolumeProjection{
-						{
-							Secret: &corev1.SecretProjection{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: controller.AuthSlurmRef().Name,
-								},
-								Items: []corev1.KeyToPath{
-									{Key: controller.AuthSlurmRef().Key, Path: slurmKeyFile},
-								},
-							},
-						},
-					},
+					Sources:     slurmAuthVolumeProjections(controller, nodeset.Namespace),
 				},
 			},
 		},
@@ -155,7 +150,7 @@ func (b *Builder) slurmdContainer(nodeset *slinkyv1alpha1.NodeSet, controller *s
 						Command: []string{
 							"/usr/bin/sh",
 							"-c",
-							"scontrol update nodename=$(hostname) state=down reason=preStop && scontrol delete nodename=$(hostname);",
+							slurmdPreStopScript(nodeset),
 						},
 					},
 				},
@@ -167,11 +162,27 @@ func (b *Builder) slurmdContainer(nodeset *slinkyv1alpha1.NodeSet, controller *s
 		},
 		merge: merge,
 	}
+	if workerHasUsers(nodeset) {
+		opts.base.VolumeMounts = append(opts.base.VolumeMounts, usersVolumeMounts()...)
+	}
+	opts.base.VolumeMounts = append(opts.base.VolumeMounts, sharedVolumeMounts(controller, slinkyv1alpha1.SharedVolumeComponentWorker)...)
 
 	return b.BuildContainer(opts)
 }
 
+func slurmdPreStopScript(nodeset *slinkyv1alpha1.NodeSet) string {
+	if isStaticNodeSet(nodeset) {
+		// Static nodes are defined in `slurm.conf` and cannot be deleted.
+		return "scontrol update nodename=$(hostname) state=down reason=preStop;"
+	}
+	return "scontrol update nodename=$(hostname) state=down reason=preStop && scontrol delete nodename=$(hostname);"
+}
+
 func slurmdArgs(nodeset *slinkyv1alpha1.NodeSet, controller *slinkyv1alpha1.Controller) []string {
+	if isStaticNodeSet(nodeset) {
+		// Static nodes get their configuration from the `NodeName` line.
+		return configlessArgs(controller)
+	}
 	args := []string{"-Z"}
 	args = append(args, configlessArgs(controller)...)
 	args = append(args, slurmdConfArgs(nodeset)...)
@@ -179,16 +190,23 @@ func slurmdArgs(nodeset *slinkyv1alpha1.NodeSet, controller *slinkyv1alpha1.Cont
 }
 
 func slurmdConfArgs(nodeset *slinkyv1alpha1.NodeSet) []string {
+	args := []string{
+		"--conf",
+		fmt.Sprintf("'%s'", strings.Join(slurmdConf(nodeset), " ")),
+	}
+
+	return args
+}
+
+// slurmdConf returns the sorted list of node parameters for the nodeset.
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_NODE-CONFIGURATION
+func slurmdConf(nodeset *slinkyv1alpha1.NodeSet) []string {
 	extraConf := []string{}
 	if nodeset.Spec.ExtraConf != "" {
 		extraConf = strings.Split(nodeset.Spec.ExtraConf, " ")
 	}
 
-	name := nodeset.Name
-	template := nodeset.Spec.Template.PodSpecWrapper
-	if template.Hostname != "" {
-		name = strings.Trim(template.Hostname, "-")
-	}
+	name := nodesetFeatureName(nodeset)
 
 	confMap := map[string]string{
 		"Features": name,
@@ -219,10 +237,32 @@ func slurmdConfArgs(nodeset *slinkyv1alpha1.NodeSet) []string {
 	}
 	sort.Strings(confList)
 
-	args := []string{
-		"--conf",
-		fmt.Sprintf("'%s'", strings.Join(confList, " ")),
+	return confList
+}
+
+// nodesetFeatureName returns the name used for the nodeset's Slurm Feature,
+// NodeSet, and Partition.
+func nodesetFeatureName(nodeset *slinkyv1alpha1.NodeSet) string {
+	name := nodeset.Name
+	template := nodeset.Spec.Template.PodSpecWrapper
+	if template.Hostname != "" {
+		name = strings.Trim(template.Hostname, "-")
 	}
+	return name
+}
 
-	return args
+// isStaticNodeSet returns true if the nodeset pods are defined as static Slurm nodes.
+func isStaticNodeSet(nodeset *slinkyv1alpha1.NodeSet) bool {
+	return nodeset.Spec.NodeRegistration == slinkyv1alpha1.StaticNodeSetNodeRegistrationType
+}
+
+// workerHostname returns the hostname, used as the Slurm NodeName, of the
+// nodeset pod with the given ordinal.
+// NOTE: this must be kept consistent with the NodeSet controller pod identity.
+func workerHostname(nodeset *slinkyv1alpha1.NodeSet, ordinal int) string {
+	template := nodeset.Spec.Template.PodSpecWrapper
+	if template.Hostname != "" {
+		return fmt.Sprintf("%s%d", template.Hostname, ordinal)
+	}
+	return fmt.Sprintf("%s-%d", nodeset.Name, ordinal)
 }
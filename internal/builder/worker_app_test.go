@@ -11,6 +11,7 @@ import (
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/set"
@@ -105,3 +106,62 @@ func TestBuilder_BuildWorkerPodTemplate(t *testing.T) {
 		})
 	}
 }
+
+func Test_slurmdArgs(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: "slurm",
+		},
+	}
+	type args struct {
+		nodeset    *slinkyv1alpha1.NodeSet
+		controller *slinkyv1alpha1.Controller
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "dynamic",
+			args: args{
+				nodeset: &slinkyv1alpha1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "slurm-foo",
+					},
+				},
+				controller: controller,
+			},
+			want: []string{
+				"-Z",
+				"--conf-server", "slurm-controller.slurm:6817",
+				"--conf", "'Features=slurm-foo'",
+			},
+		},
+		{
+			name: "static",
+			args: args{
+				nodeset: &slinkyv1alpha1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "slurm-foo",
+					},
+					Spec: slinkyv1alpha1.NodeSetSpec{
+						NodeRegistration: slinkyv1alpha1.StaticNodeSetNodeRegistrationType,
+					},
+				},
+				controller: controller,
+			},
+			want: []string{
+				"--conf-server", "slurm-controller.slurm:6817",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slurmdArgs(tt.args.nodeset, tt.args.controller); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("slurmdArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
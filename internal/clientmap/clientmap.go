@@ -5,11 +5,14 @@ package clientmap
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-operator/internal/metrics"
 )
 
 type ClientMap struct {
@@ -44,11 +47,29 @@ func (c *ClientMap) Has(names ...types.NamespacedName) bool {
 	return false
 }
 
+// Keys returns the names of all clients, sorted.
+func (c *ClientMap) Keys() []types.NamespacedName {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	keys := make([]string, 0, len(c.clients))
+	for key := range c.clients {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	names := make([]types.NamespacedName, 0, len(keys))
+	for _, key := range keys {
+		namespace, name, _ := strings.Cut(key, string(types.Separator))
+		names = append(names, types.NamespacedName{Namespace: namespace, Name: name})
+	}
+	return names
+}
+
 func (c *ClientMap) add(name types.NamespacedName, client client.Client) bool {
 	if _, ok := c.clients[name.String()]; !ok {
 		ctx := context.TODO()
 		go client.Start(ctx)
 		c.clients[name.String()] = client
+		metrics.SetClientMapClient(name, true)
 		return true
 	}
 	return false
@@ -65,6 +86,7 @@ func (c *ClientMap) remove(name types.NamespacedName) bool {
 	if client, ok := c.clients[name.String()]; ok {
 		client.Stop()
 		delete(c.clients, name.String())
+		metrics.SetClientMapClient(name, false)
 		return true
 	}
 	return false
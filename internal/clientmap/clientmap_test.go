@@ -323,3 +323,47 @@ func TestClientMap_Remove(t *testing.T) {
 		})
 	}
 }
+
+func TestClientMap_Keys(t *testing.T) {
+	type fields struct {
+		clients map[string]client.Client
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []types.NamespacedName
+	}{
+		{
+			name: "empty",
+			fields: fields{
+				clients: map[string]client.Client{},
+			},
+			want: []types.NamespacedName{},
+		},
+		{
+			name: "sorted",
+			fields: fields{
+				clients: map[string]client.Client{
+					"slurm/foo":   fake.NewFakeClient(),
+					"default/foo": fake.NewFakeClient(),
+					"default/bar": fake.NewFakeClient(),
+				},
+			},
+			want: []types.NamespacedName{
+				{Namespace: "default", Name: "bar"},
+				{Namespace: "default", Name: "foo"},
+				{Namespace: "slurm", Name: "foo"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ClientMap{
+				clients: tt.fields.clients,
+			}
+			if got := c.Keys(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClientMap.Keys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
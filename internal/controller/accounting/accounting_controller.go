@@ -62,6 +62,7 @@ type AccountingReconciler struct {
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings/finalizers,verbs=update
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=restapis,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
@@ -115,6 +116,13 @@ func (r *AccountingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			Reader:      r.Client,
 			refResolver: r.refResolver,
 		}).
+		Watches(&slinkyv1alpha1.Controller{}, &controllerEventHandler{
+			Reader: r.Client,
+		}).
+		Watches(&slinkyv1alpha1.RestApi{}, &restapiEventHandler{
+			Reader:      r.Client,
+			refResolver: r.refResolver,
+		}).
 		Watches(&corev1.Secret{}, &secretEventHandler{
 			Reader: r.Client,
 		}).
@@ -81,6 +81,133 @@ func (e *accountingEventHandler) enqueueRequest(
 	}
 }
 
+var _ handler.EventHandler = &controllerEventHandler{}
+
+type controllerEventHandler struct {
+	client.Reader
+}
+
+func (e *controllerEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *controllerEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *controllerEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *controllerEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *controllerEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	controller, ok := obj.(*slinkyv1alpha1.Controller)
+	if !ok {
+		return
+	}
+
+	// The Accounting follows the phases of a JWT HS256 key rotation of its Controller.
+	if controller.AuthJwtHs256NewRef() == nil || controller.Spec.AccountingRef.Name == "" {
+		return
+	}
+	accounting := &slinkyv1alpha1.Accounting{}
+	if err := e.Get(ctx, controller.Spec.AccountingRef.NamespacedName(), accounting); err != nil {
+		return
+	}
+	objectutils.EnqueueRequest(q, accounting)
+}
+
+var _ handler.EventHandler = &restapiEventHandler{}
+
+type restapiEventHandler struct {
+	client.Reader
+	refResolver *refresolver.RefResolver
+}
+
+func (e *restapiEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *restapiEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectOld, q)
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *restapiEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *restapiEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *restapiEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	restapi, ok := obj.(*slinkyv1alpha1.RestApi)
+	if !ok {
+		return
+	}
+
+	// The Accounting also verifies JWTs of the OIDC issuers of the RestApis of its Controllers.
+	if !restapi.HasOidc() {
+		return
+	}
+
+	controller, err := e.refResolver.GetController(ctx, restapi.Spec.ControllerRef)
+	if err != nil {
+		return
+	}
+	if controller.Spec.AccountingRef.Name == "" {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: controller.Spec.AccountingRef.NamespacedName(),
+	})
+}
+
 var _ handler.EventHandler = &secretEventHandler{}
 
 type secretEventHandler struct {
@@ -140,11 +267,30 @@ func (e *secretEventHandler) enqueueRequest(
 	for _, accounting := range accountingList.Items {
 		slurmKeyKey := accounting.AuthSlurmKey()
 		jwtHs256KeyKey := accounting.AuthJwtHs256Key()
+		jwtRs256Ref := accounting.AuthJwtRs256Ref()
 		if secretKey.String() != slurmKeyKey.String() &&
-			secretKey.String() != jwtHs256KeyKey.String() {
+			secretKey.String() != jwtHs256KeyKey.String() &&
+			(jwtRs256Ref == nil || secretKey.Namespace != accounting.Namespace || secretKey.Name != jwtRs256Ref.Name) {
 			continue
 		}
 
 		objectutils.EnqueueRequest(q, &accounting)
 	}
+
+	// The Accounting follows a Slurm key rotation of its Controllers.
+	controllerList := &slinkyv1alpha1.ControllerList{}
+	if err := e.List(ctx, controllerList); err != nil {
+		logger.Error(err, "failed to list controller CRs")
+	}
+
+	for _, controller := range controllerList.Items {
+		if secretKey.String() != controller.SlurmJwksKey().String() {
+			continue
+		}
+		accounting := &slinkyv1alpha1.Accounting{}
+		if err := e.Get(ctx, controller.Spec.AccountingRef.NamespacedName(), accounting); err != nil {
+			continue
+		}
+		objectutils.EnqueueRequest(q, accounting)
+	}
 }
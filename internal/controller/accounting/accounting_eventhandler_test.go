@@ -9,6 +9,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -275,3 +276,68 @@ func Test_controllerEventHandler_Generic(t *testing.T) {
 		})
 	}
 }
+
+func Test_controllerEventHandler_enqueueRequest(t *testing.T) {
+	accounting := &slinkyv1alpha1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			AccountingRef: slinkyv1alpha1.ObjectReference{
+				Name:      "slurm",
+				Namespace: metav1.NamespaceDefault,
+			},
+		},
+	}
+	rotating := controller.DeepCopy()
+	rotating.Spec.JwtHs256KeyRotation = &slinkyv1alpha1.JwtKeyRotation{
+		NewKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "jwt-new",
+			},
+			Key: "jwt_hs256.key",
+		},
+	}
+	tests := []struct {
+		name       string
+		client     client.Client
+		controller *slinkyv1alpha1.Controller
+		want       int
+	}{
+		{
+			name:       "Not rotating",
+			client:     fake.NewFakeClient(accounting.DeepCopy()),
+			controller: controller,
+			want:       0,
+		},
+		{
+			name:       "Rotating",
+			client:     fake.NewFakeClient(accounting.DeepCopy()),
+			controller: rotating,
+			want:       1,
+		},
+		{
+			name:       "Accounting not found",
+			client:     fake.NewFakeClient(),
+			controller: rotating,
+			want:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &controllerEventHandler{Reader: tt.client}
+			q := newQueue()
+			e.Update(context.TODO(), event.UpdateEvent{ObjectOld: tt.controller, ObjectNew: tt.controller}, q)
+			if got := q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
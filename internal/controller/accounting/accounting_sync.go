@@ -7,7 +7,9 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -50,6 +52,35 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 				return nil
 			},
 		},
+		{
+			Name: "Jwks",
+			Sync: func(ctx context.Context, accounting *slinkyv1alpha1.Accounting) error {
+				hasJwks, err := r.builder.AccountingHasJwks(accounting)
+				if err != nil {
+					return err
+				}
+				if !hasJwks {
+					object := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      accounting.JwksKey().Name,
+							Namespace: accounting.JwksKey().Namespace,
+						},
+					}
+					if err := objectutils.DeleteObject(r.Client, ctx, object); err != nil {
+						return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(object), err)
+					}
+					return nil
+				}
+				object, err := r.builder.BuildAccountingJwks(accounting)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
 		{
 			Name: "Config",
 			Sync: func(ctx context.Context, accounting *slinkyv1alpha1.Accounting) error {
@@ -90,5 +121,9 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 		}
 	}
 
-	return r.syncStatus(ctx, cluster)
+	if err := r.syncStatus(ctx, cluster); err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -6,9 +6,12 @@ package accounting
 import (
 	"context"
 	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -16,6 +19,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 )
 
 // syncStatus handles determining and updating the status.
@@ -30,6 +35,21 @@ func (r *AccountingReconciler) syncStatus(
 	}
 	newStatus.Conditions = append(newStatus.Conditions, accounting.Status.Conditions...)
 
+	oidcJwksCond, refreshAfter, err := r.oidcJwksCondition(ctx, accounting)
+	if err != nil {
+		return fmt.Errorf("failed to determine OIDC JWKS status: %w", err)
+	}
+	if oidcJwksCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *oidcJwksCond)
+		// Resync the Accounting to fetch the JWKS of its OIDC issuers again,
+		// unless it is already requeued sooner.
+		if key := objectutils.KeyFunc(accounting); durationStore.Peek(key) == 0 {
+			durationStore.Push(key, refreshAfter)
+		}
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1alpha1.AccountingConditionOidcJwksReady)
+	}
+
 	if apiequality.Semantic.DeepEqual(accounting.Status, newStatus) {
 		logger.V(2).Info("Accounting Status has not changed, skipping status update",
 			"accounting", klog.KObj(accounting), "status", accounting.Status)
@@ -44,6 +64,45 @@ func (r *AccountingReconciler) syncStatus(
 	return nil
 }
 
+// oidcJwksCondition returns the OidcJwksReady condition of the Accounting, and
+// how long until the JWKS of its OIDC issuers is fetched again, as recorded on
+// the JWKS Secret. It is nil if no JWKS is fetched from an issuer.
+func (r *AccountingReconciler) oidcJwksCondition(
+	ctx context.Context,
+	accounting *slinkyv1alpha1.Accounting,
+) (*metav1.Condition, time.Duration, error) {
+	fetches, err := r.builder.AccountingFetchesOidcJwks(accounting)
+	if err != nil || !fetches {
+		return nil, 0, err
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, accounting.JwksKey(), secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, builder.OidcJwksRetryInterval, nil
+		}
+		return nil, 0, err
+	}
+	fetchTime, fetchErr := builder.OidcJwksFetchResult(secret)
+	refreshAfter := builder.OidcJwksNextFetch(fetchTime, fetchErr, time.Now())
+	if refreshAfter == 0 {
+		refreshAfter = builder.OidcJwksRetryInterval
+	}
+	if fetchErr != "" {
+		return &metav1.Condition{
+			Type:    slinkyv1alpha1.AccountingConditionOidcJwksReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  slinkyv1alpha1.OidcJwksReasonFetchFailed,
+			Message: fetchErr,
+		}, refreshAfter, nil
+	}
+	return &metav1.Condition{
+		Type:    slinkyv1alpha1.AccountingConditionOidcJwksReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  slinkyv1alpha1.OidcJwksReasonFetched,
+		Message: "The JWKS of all OIDC issuers were fetched",
+	}, refreshAfter, nil
+}
+
 func (r *AccountingReconciler) updateStatus(
 	ctx context.Context,
 	cluster *slinkyv1alpha1.Accounting,
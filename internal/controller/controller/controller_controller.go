@@ -47,6 +47,8 @@ var (
 
 	onceBackoffGC     sync.Once
 	failedPodsBackoff = flowcontrol.NewBackOff(1*time.Second, 15*time.Minute)
+	// slurmClientBackoff paces the resyncs of Controllers without a Slurm client.
+	slurmClientBackoff = flowcontrol.NewBackOff(5*time.Second, 5*time.Minute)
 )
 
 // ControllerReconciler reconciles a Controller object
@@ -65,10 +67,18 @@ type ControllerReconciler struct {
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=loginsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=restapis,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=tokens,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=federations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -78,6 +88,7 @@ func (r *ControllerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	onceBackoffGC.Do(func() {
 		go wait.Until(failedPodsBackoff.GC, BackoffGCInterval, ctx.Done())
+		go wait.Until(slurmClientBackoff.GC, BackoffGCInterval, ctx.Done())
 	})
 
 	startTime := time.Now()
@@ -115,9 +126,23 @@ func (r *ControllerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			Reader:      r.Client,
 			refResolver: r.refResolver,
 		}).
+		Watches(&slinkyv1alpha1.NodeSet{}, &nodesetEventHandler{
+			Reader: r.Client,
+		}).
+		Watches(&slinkyv1alpha1.RestApi{}, &restapiEventHandler{
+			Reader: r.Client,
+		}).
+		Watches(&slinkyv1alpha1.LoginSet{}, &loginsetEventHandler{
+			Reader: r.Client,
+		}).
+		Watches(&slinkyv1alpha1.Federation{}, &federationEventHandler{
+			Reader: r.Client,
+		}).
 		Watches(&corev1.Secret{}, &secretEventHandler{
 			Reader: r.Client,
 		}).
+		Watches(&corev1.Secret{}, &tenantEventHandler{}).
+		Watches(&corev1.ConfigMap{}, &tenantEventHandler{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -82,6 +83,178 @@ func (e *accountingEventHandler) enqueueRequest(
 	}
 }
 
+var _ handler.EventHandler = &nodesetEventHandler{}
+
+type nodesetEventHandler struct {
+	client.Reader
+}
+
+func (e *nodesetEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *nodesetEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectOld, q)
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *nodesetEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *nodesetEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *nodesetEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	nodeset, ok := obj.(*slinkyv1alpha1.NodeSet)
+	if !ok {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: nodeset.Spec.ControllerRef.NamespacedName(),
+	})
+}
+
+var _ handler.EventHandler = &restapiEventHandler{}
+
+type restapiEventHandler struct {
+	client.Reader
+}
+
+func (e *restapiEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *restapiEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectOld, q)
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *restapiEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *restapiEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *restapiEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	restapi, ok := obj.(*slinkyv1alpha1.RestApi)
+	if !ok {
+		return
+	}
+
+	// Only the OIDC issuers of a RestApi are rendered by the Controller.
+	if !restapi.HasOidc() {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: restapi.Spec.ControllerRef.NamespacedName(),
+	})
+}
+
+var _ handler.EventHandler = &loginsetEventHandler{}
+
+type loginsetEventHandler struct {
+	client.Reader
+}
+
+func (e *loginsetEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *loginsetEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectOld, q)
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *loginsetEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *loginsetEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *loginsetEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	loginset, ok := obj.(*slinkyv1alpha1.LoginSet)
+	if !ok {
+		return
+	}
+
+	// Only the users of a LoginSet are rendered by the Controller.
+	if len(loginset.Spec.Users) == 0 {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: loginset.Spec.ControllerRef.NamespacedName(),
+	})
+}
+
 var _ handler.EventHandler = &secretEventHandler{}
 
 type secretEventHandler struct {
@@ -142,10 +315,148 @@ func (e *secretEventHandler) enqueueRequest(
 		slurmKeyKey := controller.AuthSlurmKey()
 		jwtHs256KeyKey := controller.AuthJwtHs256Key()
 		if secretKey.String() != slurmKeyKey.String() &&
-			secretKey.String() != jwtHs256KeyKey.String() {
+			secretKey.String() != jwtHs256KeyKey.String() &&
+			!isRotationKey(secretKey, &controller) &&
+			!isJwtRs256Key(secretKey, controller.Namespace, controller.AuthJwtRs256Ref()) {
 			continue
 		}
 
 		objectutils.EnqueueRequest(q, &controller)
 	}
 }
+
+// isJwtRs256Key returns true if the Secret holds the JWT RS256 key.
+func isJwtRs256Key(secretKey types.NamespacedName, namespace string, ref *corev1.SecretKeySelector) bool {
+	return ref != nil && secretKey.Namespace == namespace && secretKey.Name == ref.Name
+}
+
+// isRotationKey returns true if the Secret holds a key which the Controller is rotating to.
+func isRotationKey(secretKey types.NamespacedName, controller *slinkyv1alpha1.Controller) bool {
+	if secretKey.Namespace != controller.Namespace {
+		return false
+	}
+	if ref := controller.AuthSlurmNewRef(); ref != nil && ref.Name == secretKey.Name {
+		return true
+	}
+	if ref := controller.AuthJwtHs256NewRef(); ref != nil && ref.Name == secretKey.Name {
+		return true
+	}
+	return false
+}
+
+var _ handler.EventHandler = &federationEventHandler{}
+
+type federationEventHandler struct {
+	client.Reader
+}
+
+func (e *federationEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *federationEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectOld, q)
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *federationEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *federationEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *federationEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	federation, ok := obj.(*slinkyv1alpha1.Federation)
+	if !ok {
+		return
+	}
+
+	for _, cluster := range federation.Spec.Clusters {
+		q.Add(reconcile.Request{
+			NamespacedName: cluster.ControllerRef.NamespacedName(),
+		})
+	}
+}
+
+var _ handler.EventHandler = &tenantEventHandler{}
+
+// tenantEventHandler enqueues the Controller which copied the Secret or
+// ConfigMap into another namespace, so that it is restored when changed or
+// deleted.
+type tenantEventHandler struct{}
+
+func (e *tenantEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *tenantEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *tenantEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *tenantEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *tenantEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	if obj == nil {
+		return
+	}
+	name := obj.GetLabels()[slinkyv1alpha1.LabelControllerName]
+	namespace := obj.GetLabels()[slinkyv1alpha1.LabelControllerNamespace]
+	if name == "" || namespace == "" {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -275,3 +276,324 @@ func Test_controllerEventHandler_Generic(t *testing.T) {
 		})
 	}
 }
+
+func Test_nodesetEventHandler_Update(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "non-empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: &slinkyv1alpha1.NodeSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "slurm-foo",
+						},
+						Spec: slinkyv1alpha1.NodeSetSpec{
+							ControllerRef: slinkyv1alpha1.ObjectReference{
+								Name: "slurm",
+							},
+						},
+					},
+					ObjectOld: &slinkyv1alpha1.NodeSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "slurm-foo",
+						},
+						Spec: slinkyv1alpha1.NodeSetSpec{
+							ControllerRef: slinkyv1alpha1.ObjectReference{
+								Name: "slurm",
+							},
+						},
+					},
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &nodesetEventHandler{
+				Reader: fake.NewFakeClient(),
+			}
+			e.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_restapiEventHandler_Update(t *testing.T) {
+	restapi := &slinkyv1alpha1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.RestApiSpec{
+			ControllerRef: slinkyv1alpha1.ObjectReference{
+				Name: "slurm",
+			},
+		},
+	}
+	oidc := restapi.DeepCopy()
+	oidc.Spec.Oidc = &slinkyv1alpha1.RestApiOidc{
+		JwksUrl: "https://idp.example.com/certs",
+	}
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "without OIDC",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: restapi,
+					ObjectOld: restapi,
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "OIDC added",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: oidc,
+					ObjectOld: restapi,
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &restapiEventHandler{
+				Reader: fake.NewFakeClient(),
+			}
+			e.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loginsetEventHandler_Update(t *testing.T) {
+	loginset := &slinkyv1alpha1.LoginSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.LoginSetSpec{
+			ControllerRef: slinkyv1alpha1.ObjectReference{
+				Name: "slurm",
+			},
+		},
+	}
+	users := loginset.DeepCopy()
+	users.Spec.Users = []slinkyv1alpha1.LoginSetUser{
+		{Name: "alice", Uid: 1000},
+	}
+	tests := []struct {
+		name string
+		evt  event.UpdateEvent
+		want int
+	}{
+		{
+			name: "empty",
+			evt:  event.UpdateEvent{},
+			want: 0,
+		},
+		{
+			name: "without users",
+			evt: event.UpdateEvent{
+				ObjectNew: loginset,
+				ObjectOld: loginset,
+			},
+			want: 0,
+		},
+		{
+			name: "users removed",
+			evt: event.UpdateEvent{
+				ObjectNew: loginset,
+				ObjectOld: users,
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &loginsetEventHandler{
+				Reader: fake.NewFakeClient(),
+			}
+			q := newQueue()
+			e.Update(context.TODO(), tt.evt, q)
+			if got := q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_federationEventHandler_Update(t *testing.T) {
+	newFederation := func(controllers ...string) *slinkyv1alpha1.Federation {
+		federation := &slinkyv1alpha1.Federation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "fed",
+			},
+		}
+		for _, controller := range controllers {
+			federation.Spec.Clusters = append(federation.Spec.Clusters, slinkyv1alpha1.FederationCluster{
+				ControllerRef: slinkyv1alpha1.ObjectReference{
+					Name: controller,
+				},
+			})
+		}
+		return federation
+	}
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "removed member",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: newFederation("slurm1", "slurm2"),
+					ObjectOld: newFederation("slurm1", "slurm2", "slurm3"),
+				},
+				q: newQueue(),
+			},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &federationEventHandler{
+				Reader: fake.NewFakeClient(),
+			}
+			e.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tenantEventHandler_Update(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "not a copy",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "foo",
+							Namespace: "tenant",
+						},
+					},
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "copy",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "slurm-slurm-users",
+							Namespace: "tenant",
+							Labels: map[string]string{
+								slinkyv1alpha1.LabelControllerName:      "slurm",
+								slinkyv1alpha1.LabelControllerNamespace: "slurm",
+							},
+						},
+					},
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &tenantEventHandler{}
+			e.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+)
+
+// tokenReissueStatus counts the Tokens signed by the key being rotated.
+type tokenReissueStatus struct {
+	// Refreshable is the number of Tokens which will be reissued with the RS256 key.
+	Refreshable int
+	// Reissued is the number of refreshable Tokens reissued with the RS256 key.
+	Reissued int
+	// Static is the number of Tokens which are never reissued.
+	Static int
+}
+
+// jwtKeyRotationCondition returns the JWT HS256 key rotation condition, or nil
+// if no rotation is in progress. Slurm only loads a single HS256 key, but it
+// verifies RS256 JWTs with the JWKS along with either HS256 key. JWTs are
+// reissued with the RS256 key before slurmctld, slurmdbd, and slurmrestd are
+// restarted to verify with the new key, then signed with the new key, so Slurm
+// never rejects them. The phases only ever advance:
+// ReissuingTokens -> RollingOut -> ReadyToRetire.
+func (r *ControllerReconciler) jwtKeyRotationCondition(
+	ctx context.Context,
+	controller *slinkyv1alpha1.Controller,
+) (*metav1.Condition, error) {
+	if controller.AuthJwtHs256NewRef() == nil {
+		return nil, nil
+	}
+
+	reason := slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens
+	if cond := meta.FindStatusCondition(controller.Status.Conditions, slinkyv1alpha1.ControllerConditionJwtHs256KeyRotation); cond != nil {
+		reason = cond.Reason
+	}
+
+	// Without an RS256 key, JWTs can only be reissued with the new key.
+	bridgeRef := controller.AuthJwtRs256Ref()
+	if bridgeRef == nil {
+		bridgeRef = controller.AuthJwtHs256NewRef()
+	}
+	bridgeKey, err := r.refResolver.GetSecretKeyRef(ctx, bridgeRef, controller.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	status, err := r.getTokenReissueStatus(ctx, controller, slurmjwt.KeyFingerprint(bridgeKey))
+	if err != nil {
+		return nil, err
+	}
+
+	rollout := jwtKeyRolloutStatus{}
+	if reason != slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire {
+		rollout.Done, rollout.Message, err = r.isJwtKeyRolledOut(ctx, controller)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reason, msg := jwtKeyRotationPhase(reason, status, rollout)
+	return newJwtKeyRotationCondition(controller, reason, msg), nil
+}
+
+// jwtKeyRolloutStatus reports whether Slurm verifies JWTs with the new key.
+type jwtKeyRolloutStatus struct {
+	// Done is true once slurmctld, slurmdbd, and slurmrestd were restarted with the new key.
+	Done bool
+	// Message describes what the rollout is waiting for.
+	Message string
+}
+
+// jwtKeyRotationPhase determines the next phase of the rotation.
+func jwtKeyRotationPhase(reason string, status tokenReissueStatus, rollout jwtKeyRolloutStatus) (string, string) {
+	warning := ""
+	if status.Static > 0 {
+		warning = fmt.Sprintf(" %d Token(s) without refresh must be recreated with the new key.", status.Static)
+	}
+
+	switch {
+	case reason == slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire:
+	case reason != slinkyv1alpha1.JwtKeyRotationReasonRollingOut && status.Reissued < status.Refreshable:
+		msg := fmt.Sprintf("Reissued %d/%d Token(s) with the RS256 key. Slurm verifies with the current key until all are reissued.%s",
+			status.Reissued, status.Refreshable, warning)
+		return slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens, msg
+	case !rollout.Done:
+		return slinkyv1alpha1.JwtKeyRotationReasonRollingOut, rollout.Message + warning
+	}
+
+	msg := "The current key is no longer used. Set jwtHs256KeyRef of the Controller, " +
+		"its Accounting, and Tokens to the new key, then remove jwtHs256KeyRotation." + warning
+	return slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire, msg
+}
+
+func newJwtKeyRotationCondition(controller *slinkyv1alpha1.Controller, reason, msg string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               slinkyv1alpha1.ControllerConditionJwtHs256KeyRotation,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: controller.Generation,
+		Reason:             reason,
+		Message:            msg,
+	}
+}
+
+// isJwtKeyRolledOut returns true once slurmctld, slurmdbd, and slurmrestd are
+// all running with the new key as their `jwt_key`.
+func (r *ControllerReconciler) isJwtKeyRolledOut(
+	ctx context.Context,
+	controller *slinkyv1alpha1.Controller,
+) (bool, string, error) {
+	var err error
+
+	newRef := controller.AuthJwtHs256NewRef()
+	rollingOut := controller.DeepCopy()
+	meta.SetStatusCondition(&rollingOut.Status.Conditions, *newJwtKeyRotationCondition(rollingOut, slinkyv1alpha1.JwtKeyRotationReasonRollingOut, ""))
+	want := builder.JwtKeyRotationAnnotations(rollingOut)[builder.AnnotationJwtHs256KeyRotation]
+
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, controller.Key(), sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "Waiting for the slurmctld StatefulSet.", nil
+		}
+		return false, "", err
+	}
+	if !hasJwtKeyRotationAnnotation(sts.Spec.Template, want) || !isStatefulSetRolledOut(sts) {
+		return false, "Restarting slurmctld with the new key.", nil
+	}
+
+	var accounting *slinkyv1alpha1.Accounting
+	if controller.Spec.AccountingRef.Name != "" {
+		accounting, err = r.refResolver.GetAccounting(ctx, controller.Spec.AccountingRef)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, "", err
+		}
+	}
+	if accounting != nil && accounting.AuthJwtHs256Key() == controller.AuthJwtHs256Key() {
+		accountingNewRef := accounting.AuthJwtHs256NewRef()
+		if accountingNewRef == nil || *accountingNewRef != *newRef {
+			msg := fmt.Sprintf("Accounting %s must also set jwtHs256KeyRotation to the new key.", accounting.Name)
+			return false, msg, nil
+		}
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, accounting.Key(), sts); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, "Waiting for the slurmdbd StatefulSet.", nil
+			}
+			return false, "", err
+		}
+		if !hasJwtKeyRotationAnnotation(sts.Spec.Template, want) || !isStatefulSetRolledOut(sts) {
+			return false, "Restarting slurmdbd with the new key.", nil
+		}
+	}
+
+	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
+	if err != nil {
+		return false, "", err
+	}
+	for _, restapi := range restapiList.Items {
+		deploy := &appsv1.Deployment{}
+		if err := r.Get(ctx, restapi.Key(), deploy); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, "Waiting for the slurmrestd Deployment.", nil
+			}
+			return false, "", err
+		}
+		if !hasJwtKeyRotationAnnotation(deploy.Spec.Template, want) || !isDeploymentRolledOut(deploy) {
+			return false, "Restarting slurmrestd with the new key.", nil
+		}
+	}
+
+	return true, "", nil
+}
+
+func (r *ControllerReconciler) getTokenReissueStatus(
+	ctx context.Context,
+	controller *slinkyv1alpha1.Controller,
+	bridgeKeyFingerprint string,
+) (tokenReissueStatus, error) {
+	status := tokenReissueStatus{}
+
+	tokenList := &slinkyv1alpha1.TokenList{}
+	if err := r.List(ctx, tokenList); err != nil {
+		return status, err
+	}
+	for _, token := range tokenList.Items {
+		if token.JwtKey() != controller.AuthJwtHs256Key() {
+			continue
+		}
+		if !token.Spec.Refresh {
+			status.Static++
+			continue
+		}
+		status.Refreshable++
+		if token.Status.KeyFingerprint == bridgeKeyFingerprint {
+			status.Reissued++
+		}
+	}
+
+	return status, nil
+}
+
+// hasJwtKeyRotationAnnotation returns true if the pod template was rendered for
+// the phase of the JWT HS256 key rotation.
+func hasJwtKeyRotationAnnotation(template corev1.PodTemplateSpec, want string) bool {
+	return template.Annotations[builder.AnnotationJwtHs256KeyRotation] == want
+}
+
+func isStatefulSetRolledOut(sts *appsv1.StatefulSet) bool {
+	replicas := ptr.Deref(sts.Spec.Replicas, 1)
+	return sts.Status.ObservedGeneration >= sts.Generation &&
+		sts.Status.UpdateRevision == sts.Status.CurrentRevision &&
+		sts.Status.UpdatedReplicas == replicas &&
+		sts.Status.ReadyReplicas == replicas
+}
+
+func isDeploymentRolledOut(deploy *appsv1.Deployment) bool {
+	replicas := ptr.Deref(deploy.Spec.Replicas, 1)
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.Replicas == replicas &&
+		deploy.Status.AvailableReplicas == replicas
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+)
+
+func Test_jwtKeyRotationPhase(t *testing.T) {
+	type args struct {
+		reason  string
+		status  tokenReissueStatus
+		rollout jwtKeyRolloutStatus
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "No tokens",
+			args: args{
+				reason:  slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens,
+				rollout: jwtKeyRolloutStatus{Message: "Restarting slurmctld with the new key."},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonRollingOut,
+		},
+		{
+			name: "Tokens pending",
+			args: args{
+				reason: slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens,
+				status: tokenReissueStatus{Refreshable: 2, Reissued: 1},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens,
+		},
+		{
+			name: "Tokens pending, not restarted before reissued",
+			args: args{
+				reason:  slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens,
+				status:  tokenReissueStatus{Refreshable: 2, Reissued: 1},
+				rollout: jwtKeyRolloutStatus{Done: true},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens,
+		},
+		{
+			name: "Tokens reissued",
+			args: args{
+				reason:  slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens,
+				status:  tokenReissueStatus{Refreshable: 2, Reissued: 2, Static: 1},
+				rollout: jwtKeyRolloutStatus{Message: "Restarting slurmctld with the new key."},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonRollingOut,
+		},
+		{
+			name: "Rolling out",
+			args: args{
+				reason:  slinkyv1alpha1.JwtKeyRotationReasonRollingOut,
+				status:  tokenReissueStatus{Refreshable: 3, Reissued: 2},
+				rollout: jwtKeyRolloutStatus{Message: "Restarting slurmctld with the new key."},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonRollingOut,
+		},
+		{
+			name: "Rolled out",
+			args: args{
+				reason:  slinkyv1alpha1.JwtKeyRotationReasonRollingOut,
+				status:  tokenReissueStatus{Refreshable: 2, Reissued: 2},
+				rollout: jwtKeyRolloutStatus{Done: true},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire,
+		},
+		{
+			name: "Never regresses",
+			args: args{
+				reason: slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire,
+				status: tokenReissueStatus{Refreshable: 3, Reissued: 2},
+			},
+			want: slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, msg := jwtKeyRotationPhase(tt.args.reason, tt.args.status, tt.args.rollout)
+			if got != tt.want {
+				t.Errorf("jwtKeyRotationPhase() = %v, want %v", got, tt.want)
+			}
+			if msg == "" {
+				t.Errorf("jwtKeyRotationPhase() message is empty")
+			}
+		})
+	}
+}
+
+func Test_hasJwtKeyRotationAnnotation(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				builder.AnnotationJwtHs256KeyRotation: "jwt-new/jwt_hs256.key,verifying=jwt-new/jwt_hs256.key",
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		template corev1.PodTemplateSpec
+		want     bool
+	}{
+		{
+			name: "Empty",
+			want: false,
+		},
+		{
+			name:     "Found",
+			template: template,
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasJwtKeyRotationAnnotation(tt.template, "jwt-new/jwt_hs256.key,verifying=jwt-new/jwt_hs256.key"); got != tt.want {
+				t.Errorf("hasJwtKeyRotationAnnotation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isStatefulSetRolledOut(t *testing.T) {
+	tests := []struct {
+		name string
+		sts  *appsv1.StatefulSet
+		want bool
+	}{
+		{
+			name: "Rolled out",
+			sts: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					CurrentRevision:    "b",
+					UpdateRevision:     "b",
+					UpdatedReplicas:    1,
+					ReadyReplicas:      1,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Stale generation",
+			sts: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					CurrentRevision:    "b",
+					UpdateRevision:     "b",
+					UpdatedReplicas:    1,
+					ReadyReplicas:      1,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Updating",
+			sts: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					CurrentRevision:    "a",
+					UpdateRevision:     "b",
+					ReadyReplicas:      1,
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStatefulSetRolledOut(tt.sts); got != tt.want {
+				t.Errorf("isStatefulSetRolledOut() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
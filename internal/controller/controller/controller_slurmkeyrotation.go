@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/podutils"
+)
+
+// slurmKeyRotationCondition returns the Slurm key rotation condition, or nil
+// if no rotation is in progress.
+//
+// The new key is distributed to every Slurm pod before any of them sign with
+// it, so daemons always accept the key of their peers:
+// Distributing -> Switching -> ReadyToRetire.
+// If a phase misses its deadline, every Slurm pod is rolled out to sign with
+// the current key again, then without the new key:
+// [SwitchingBack ->] RollingBack -> RolledBack.
+func (r *ControllerReconciler) slurmKeyRotationCondition(
+	ctx context.Context,
+	controller *slinkyv1alpha1.Controller,
+	now time.Time,
+) (*metav1.Condition, error) {
+	if controller.AuthSlurmNewRef() == nil {
+		return nil, nil
+	}
+
+	reason := slinkyv1alpha1.SlurmKeyRotationReasonDistributing
+	since := now
+	cond := meta.FindStatusCondition(controller.Status.Conditions, slinkyv1alpha1.ControllerConditionSlurmKeyRotation)
+	if cond != nil {
+		reason = cond.Reason
+		since = cond.LastTransitionTime.Time
+	}
+
+	switch reason {
+	case slinkyv1alpha1.SlurmKeyRotationReasonReadyToRetire, slinkyv1alpha1.SlurmKeyRotationReasonRolledBack:
+		return cond, nil
+	}
+
+	pending, err := r.getSlurmKeyRolloutPending(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Duration(controller.Spec.SlurmKeyRotation.ProgressDeadlineSeconds) * time.Second
+	expired := deadline > 0 && now.Sub(since) > deadline
+
+	reason, msg := slurmKeyRotationPhase(reason, pending, expired)
+	newCond := &metav1.Condition{
+		Type:               slinkyv1alpha1.ControllerConditionSlurmKeyRotation,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: controller.Generation,
+		LastTransitionTime: metav1.NewTime(since),
+		Reason:             reason,
+		Message:            msg,
+	}
+	if reason == slinkyv1alpha1.SlurmKeyRotationReasonRolledBack {
+		newCond.Status = metav1.ConditionFalse
+	}
+	// Each phase has its own deadline.
+	if cond == nil || cond.Reason != newCond.Reason {
+		newCond.LastTransitionTime = metav1.NewTime(now)
+	}
+	return newCond, nil
+}
+
+// slurmKeyRotationPhase determines the next phase, given the number of Slurm
+// pods not yet rolled out for the current phase.
+func slurmKeyRotationPhase(reason string, pending int, expired bool) (string, string) {
+	switch reason {
+	case slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack:
+		if pending > 0 {
+			return reason, fmt.Sprintf("Rolling out %d Slurm pod(s) to sign with the current key.", pending)
+		}
+		return slinkyv1alpha1.SlurmKeyRotationReasonRollingBack, "All Slurm pods sign with the current key, removing the new key."
+
+	case slinkyv1alpha1.SlurmKeyRotationReasonRollingBack:
+		if pending > 0 {
+			return reason, fmt.Sprintf("Rolling out %d Slurm pod(s) without the new key.", pending)
+		}
+		return slinkyv1alpha1.SlurmKeyRotationReasonRolledBack,
+			"The rotation was rolled back. Remove slurmKeyRotation, then investigate the failed rollout before retrying."
+
+	case slinkyv1alpha1.SlurmKeyRotationReasonSwitching:
+		if pending == 0 {
+			return slinkyv1alpha1.SlurmKeyRotationReasonReadyToRetire,
+				"The current key is no longer used for signing. Set slurmKeyRef of the Controller and its Accounting to the new key, then remove slurmKeyRotation."
+		}
+		if expired {
+			return slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack,
+				fmt.Sprintf("Deadline exceeded with %d Slurm pod(s) not signing with the new key, rolling back.", pending)
+		}
+		return reason, fmt.Sprintf("Rolling out %d Slurm pod(s) to sign with the new key.", pending)
+
+	default:
+		if pending == 0 {
+			return slinkyv1alpha1.SlurmKeyRotationReasonSwitching, "All Slurm pods accept the new key, switching to sign with it."
+		}
+		if expired {
+			return slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+				fmt.Sprintf("Deadline exceeded with %d Slurm pod(s) not accepting the new key, rolling back.", pending)
+		}
+		return slinkyv1alpha1.SlurmKeyRotationReasonDistributing,
+			fmt.Sprintf("Rolling out %d Slurm pod(s) to accept the new key.", pending)
+	}
+}
+
+type podSelector struct {
+	namespace string
+	labels    map[string]string
+}
+
+// getSlurmKeyRolloutPending returns the number of Slurm pods which are not yet
+// running and ready for the current phase of the Slurm key rotation.
+func (r *ControllerReconciler) getSlurmKeyRolloutPending(
+	ctx context.Context,
+	controller *slinkyv1alpha1.Controller,
+) (int, error) {
+	want := builder.SlurmKeyRotationAnnotations(controller)[builder.AnnotationSlurmKeyRotation]
+
+	selectors := []podSelector{
+		{controller.Namespace, labels.NewBuilder().WithControllerSelectorLabels(controller).Build()},
+	}
+
+	if controller.Spec.AccountingRef.Name != "" {
+		accounting, err := r.refResolver.GetAccounting(ctx, controller.Spec.AccountingRef)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		if accounting != nil && accounting.Namespace == controller.Namespace && accounting.AuthSlurmKey() == controller.AuthSlurmKey() {
+			selectors = append(selectors, podSelector{accounting.Namespace, labels.NewBuilder().WithAccountingSelectorLabels(accounting).Build()})
+		}
+	}
+
+	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
+	if err != nil {
+		return 0, err
+	}
+	for _, restapi := range restapiList.Items {
+		selectors = append(selectors, podSelector{restapi.Namespace, labels.NewBuilder().WithRestapiSelectorLabels(&restapi).Build()})
+	}
+
+	loginsetList, err := r.refResolver.GetLoginSetsForController(ctx, controller)
+	if err != nil {
+		return 0, err
+	}
+	for _, loginset := range loginsetList.Items {
+		selectors = append(selectors, podSelector{loginset.Namespace, labels.NewBuilder().WithLoginSelectorLabels(&loginset).Build()})
+	}
+
+	nodesetList, err := r.refResolver.GetNodeSetsForController(ctx, controller)
+	if err != nil {
+		return 0, err
+	}
+	for _, nodeset := range nodesetList.Items {
+		selectors = append(selectors, podSelector{nodeset.Namespace, labels.NewBuilder().WithWorkerSelectorLabels(&nodeset).Build()})
+	}
+
+	pending := 0
+	for _, selector := range selectors {
+		podList := &corev1.PodList{}
+		opts := []client.ListOption{
+			client.InNamespace(selector.namespace),
+			client.MatchingLabelsSelector{Selector: k8slabels.SelectorFromSet(selector.labels)},
+		}
+		if err := r.List(ctx, podList, opts...); err != nil {
+			return 0, err
+		}
+		pending += countSlurmKeyRolloutPending(podList.Items, want)
+	}
+
+	return pending, nil
+}
+
+func countSlurmKeyRolloutPending(pods []corev1.Pod, want string) int {
+	pending := 0
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Annotations[builder.AnnotationSlurmKeyRotation] != want || !podutils.IsRunningAndReady(&pod) {
+			pending++
+		}
+	}
+	return pending
+}
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func Test_slurmKeyRotationPhase(t *testing.T) {
+	type args struct {
+		reason  string
+		pending int
+		expired bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Distributing",
+			args: args{
+				reason:  slinkyv1alpha1.SlurmKeyRotationReasonDistributing,
+				pending: 2,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonDistributing,
+		},
+		{
+			name: "Distributed",
+			args: args{
+				reason: slinkyv1alpha1.SlurmKeyRotationReasonDistributing,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonSwitching,
+		},
+		{
+			name: "Distributing expired",
+			args: args{
+				reason:  slinkyv1alpha1.SlurmKeyRotationReasonDistributing,
+				pending: 1,
+				expired: true,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+		},
+		{
+			name: "Switched",
+			args: args{
+				reason: slinkyv1alpha1.SlurmKeyRotationReasonSwitching,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonReadyToRetire,
+		},
+		{
+			name: "Switching expired",
+			args: args{
+				reason:  slinkyv1alpha1.SlurmKeyRotationReasonSwitching,
+				pending: 1,
+				expired: true,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack,
+		},
+		{
+			name: "Switching back",
+			args: args{
+				reason:  slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack,
+				pending: 1,
+				expired: true,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack,
+		},
+		{
+			name: "Switched back",
+			args: args{
+				reason: slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+		},
+		{
+			name: "Rolling back ignores deadline",
+			args: args{
+				reason:  slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+				pending: 1,
+				expired: true,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+		},
+		{
+			name: "Rolled back",
+			args: args{
+				reason: slinkyv1alpha1.SlurmKeyRotationReasonRollingBack,
+			},
+			want: slinkyv1alpha1.SlurmKeyRotationReasonRolledBack,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, msg := slurmKeyRotationPhase(tt.args.reason, tt.args.pending, tt.args.expired)
+			if got != tt.want {
+				t.Errorf("slurmKeyRotationPhase() = %v, want %v", got, tt.want)
+			}
+			if msg == "" {
+				t.Errorf("slurmKeyRotationPhase() message is empty")
+			}
+		})
+	}
+}
+
+func Test_countSlurmKeyRolloutPending(t *testing.T) {
+	newPod := func(annotation string, ready bool) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					builder.AnnotationSlurmKeyRotation: annotation,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+				},
+			},
+		}
+		if ready {
+			pod.Status.Conditions[0].Status = corev1.ConditionTrue
+		}
+		return pod
+	}
+	terminating := newPod("old", false)
+	terminating.DeletionTimestamp = &metav1.Time{}
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want int
+	}{
+		{
+			name: "Empty",
+			want: 0,
+		},
+		{
+			name: "Rolled out",
+			pods: []corev1.Pod{newPod("new", true), newPod("new", true)},
+			want: 0,
+		},
+		{
+			name: "Stale and unready",
+			pods: []corev1.Pod{newPod("old", true), newPod("new", false), newPod("new", true)},
+			want: 2,
+		},
+		{
+			name: "Terminating",
+			pods: []corev1.Pod{terminating, newPod("new", true)},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countSlurmKeyRolloutPending(tt.pods, "new"); got != tt.want {
+				t.Errorf("countSlurmKeyRolloutPending() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerReconciler_slurmKeyRotationCondition_RollBack(t *testing.T) {
+	ctx := context.TODO()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(scheme))
+
+	controller := testutils.NewController("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), nil)
+	controller.Spec.SlurmKeyRotation = &slinkyv1alpha1.SlurmKeyRotation{
+		NewKeyRef:               testutils.NewSlurmKeyRef("slurm-new"),
+		ProgressDeadlineSeconds: 60,
+	}
+	now := time.Now()
+	controller.Status.Conditions = []metav1.Condition{
+		{
+			Type:               slinkyv1alpha1.ControllerConditionSlurmKeyRotation,
+			Status:             metav1.ConditionTrue,
+			Reason:             slinkyv1alpha1.SlurmKeyRotationReasonDistributing,
+			LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+		},
+	}
+	distributing := builder.SlurmKeyRotationAnnotations(controller)[builder.AnnotationSlurmKeyRotation]
+	newPod := func(name, annotation string, ready bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   controller.Namespace,
+				Labels:      labels.NewBuilder().WithControllerSelectorLabels(controller).Build(),
+				Annotations: map[string]string{builder.AnnotationSlurmKeyRotation: annotation},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			},
+		}
+		if ready {
+			pod.Status.Conditions[0].Status = corev1.ConditionTrue
+		}
+		return pod
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newPod("slurm-0", distributing, true), newPod("slurm-1", distributing, false)).
+		Build()
+	r := NewReconciler(c, clientmap.NewClientMap())
+
+	// Distributing misses its deadline.
+	cond, err := r.slurmKeyRotationCondition(ctx, controller, now)
+	if err != nil {
+		t.Fatalf("slurmKeyRotationCondition() error = %v", err)
+	}
+	if cond.Reason != slinkyv1alpha1.SlurmKeyRotationReasonRollingBack {
+		t.Fatalf("slurmKeyRotationCondition() = %v, want %v", cond.Reason, slinkyv1alpha1.SlurmKeyRotationReasonRollingBack)
+	}
+	meta.SetStatusCondition(&controller.Status.Conditions, *cond)
+
+	// Pods are rolled out without the new key.
+	if controller.HasSlurmJwks() {
+		t.Errorf("HasSlurmJwks() = true while rolling back")
+	}
+	rollingBack := builder.SlurmKeyRotationAnnotations(controller)[builder.AnnotationSlurmKeyRotation]
+	if rollingBack == distributing || strings.Contains(rollingBack, "slurm-new") {
+		t.Errorf("SlurmKeyRotationAnnotations() = %v, want without the new key", rollingBack)
+	}
+	cond, err = r.slurmKeyRotationCondition(ctx, controller, now)
+	if err != nil {
+		t.Fatalf("slurmKeyRotationCondition() error = %v", err)
+	}
+	if cond.Reason != slinkyv1alpha1.SlurmKeyRotationReasonRollingBack {
+		t.Fatalf("slurmKeyRotationCondition() = %v, want %v", cond.Reason, slinkyv1alpha1.SlurmKeyRotationReasonRollingBack)
+	}
+	meta.SetStatusCondition(&controller.Status.Conditions, *cond)
+
+	for _, pod := range []*corev1.Pod{newPod("slurm-0", rollingBack, true), newPod("slurm-1", rollingBack, true)} {
+		if err := c.Delete(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Create(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cond, err = r.slurmKeyRotationCondition(ctx, controller, now)
+	if err != nil {
+		t.Fatalf("slurmKeyRotationCondition() error = %v", err)
+	}
+	if cond.Reason != slinkyv1alpha1.SlurmKeyRotationReasonRolledBack {
+		t.Fatalf("slurmKeyRotationCondition() = %v, want %v", cond.Reason, slinkyv1alpha1.SlurmKeyRotationReasonRolledBack)
+	}
+	meta.SetStatusCondition(&controller.Status.Conditions, *cond)
+	if controller.HasSlurmJwks() || builder.SlurmKeyRotationAnnotations(controller)[builder.AnnotationSlurmKeyRotation] != rollingBack {
+		t.Errorf("RolledBack pods must keep only the current key")
+	}
+}
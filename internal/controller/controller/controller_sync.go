@@ -7,7 +7,10 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -30,7 +33,7 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 	if err := r.Get(ctx, req.NamespacedName, controller); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("Controller has been deleted", "request", req)
-			return nil
+			return r.deleteTenants(ctx, req.NamespacedName, nil)
 		}
 		return err
 	}
@@ -39,6 +42,9 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 		{
 			Name: "Service",
 			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				if controller.IsExternal() {
+					return nil
+				}
 				object, err := r.builder.BuildControllerService(controller)
 				if err != nil {
 					return fmt.Errorf("failed to build: %w", err)
@@ -49,9 +55,164 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 				return nil
 			},
 		},
+		{
+			Name: "SlurmrestdService",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				if controller.Spec.InternalSlurmrestd == nil {
+					object := &corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      controller.InternalRestapiServiceKey().Name,
+							Namespace: controller.InternalRestapiServiceKey().Namespace,
+						},
+					}
+					if err := objectutils.DeleteObject(r.Client, ctx, object); err != nil {
+						return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(object), err)
+					}
+					return nil
+				}
+				object, err := r.builder.BuildControllerSlurmrestdService(controller)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "SlurmJwks",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				if controller.AuthSlurmNewRef() == nil {
+					object := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      controller.SlurmJwksKey().Name,
+							Namespace: controller.SlurmJwksKey().Namespace,
+						},
+					}
+					if err := objectutils.DeleteObject(r.Client, ctx, object); err != nil {
+						return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(object), err)
+					}
+					return nil
+				}
+				object, err := r.builder.BuildControllerSlurmJwks(controller)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "JwtRs256Key",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				ref := controller.AuthJwtRs256Ref()
+				if ref == nil {
+					return nil
+				}
+				// Only generate the key pair, never replace an existing key.
+				key := types.NamespacedName{Name: ref.Name, Namespace: controller.Namespace}
+				if err := r.Get(ctx, key, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+					return err
+				}
+				object, err := r.builder.BuildControllerJwtRs256Key(controller)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "Jwks",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				hasJwks, err := r.builder.ControllerHasJwks(controller)
+				if err != nil {
+					return err
+				}
+				if !hasJwks {
+					object := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      controller.JwksKey().Name,
+							Namespace: controller.JwksKey().Namespace,
+						},
+					}
+					if err := objectutils.DeleteObject(r.Client, ctx, object); err != nil {
+						return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(object), err)
+					}
+					return nil
+				}
+				object, err := r.builder.BuildControllerJwks(controller)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "Users",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				hasUsers, err := r.builder.ControllerHasUsers(controller)
+				if err != nil {
+					return err
+				}
+				if !hasUsers {
+					object := &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      controller.UsersKey().Name,
+							Namespace: controller.UsersKey().Namespace,
+						},
+					}
+					if err := objectutils.DeleteObject(r.Client, ctx, object); err != nil {
+						return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(object), err)
+					}
+					return nil
+				}
+				object, err := r.builder.BuildControllerUsers(controller)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "SharedVolumes",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				for _, volume := range controller.Spec.SharedVolumes {
+					if volume.ClaimTemplate == nil {
+						continue
+					}
+					object := r.builder.BuildSharedVolumeClaim(controller, &volume, controller.Namespace)
+					if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+						return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name: "Tenants",
+			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				return r.syncTenants(ctx, controller)
+			},
+		},
 		{
 			Name: "Config",
 			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				// The external slurmctld serves its own `slurm.conf`.
+				if controller.IsExternal() {
+					return nil
+				}
 				object, err := r.builder.BuildControllerConfig(controller)
 				if err != nil {
 					return fmt.Errorf("failed to build: %w", err)
@@ -65,6 +226,9 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 		{
 			Name: "StatefulSet",
 			Sync: func(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+				if controller.IsExternal() {
+					return nil
+				}
 				object, err := r.builder.BuildController(controller)
 				if err != nil {
 					return fmt.Errorf("failed to build: %w", err)
@@ -89,5 +253,9 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 		}
 	}
 
-	return r.syncStatus(ctx, controller)
+	if err := r.syncStatus(ctx, controller); err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -6,9 +6,12 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -16,6 +19,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmversion"
 )
 
 // syncStatus handles determining and updating the status.
@@ -30,6 +36,65 @@ func (r *ControllerReconciler) syncStatus(
 	}
 	newStatus.Conditions = append(newStatus.Conditions, controller.Status.Conditions...)
 
+	federation, err := r.refResolver.GetFederationForController(ctx, controller)
+	if err != nil {
+		return fmt.Errorf("failed to get Federation: %w", err)
+	}
+	if federation != nil {
+		newStatus.Federation = &slinkyv1alpha1.ObjectReference{
+			Name:      federation.Name,
+			Namespace: federation.Namespace,
+		}
+	}
+
+	rotationCond, err := r.jwtKeyRotationCondition(ctx, controller)
+	if err != nil {
+		return fmt.Errorf("failed to determine JWT key rotation progress: %w", err)
+	}
+	if rotationCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *rotationCond)
+		durationStore.Push(objectutils.KeyFunc(controller), 30*time.Second)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1alpha1.ControllerConditionJwtHs256KeyRotation)
+	}
+
+	slurmKeyCond, err := r.slurmKeyRotationCondition(ctx, controller, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to determine Slurm key rotation progress: %w", err)
+	}
+	// Replace the condition, as each phase tracks its own transition time.
+	meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1alpha1.ControllerConditionSlurmKeyRotation)
+	if slurmKeyCond != nil {
+		newStatus.Conditions = append(newStatus.Conditions, *slurmKeyCond)
+		durationStore.Push(objectutils.KeyFunc(controller), 30*time.Second)
+	}
+
+	clientCond := r.slurmClientCondition(controller)
+	meta.SetStatusCondition(&newStatus.Conditions, clientCond)
+	key := objectutils.KeyFunc(controller)
+	if clientCond.Status != metav1.ConditionTrue {
+		// Resync the Controller, with backoff, until the Slurm client is available.
+		slurmClientBackoff.Next(key, time.Now())
+		durationStore.Push(key, slurmClientBackoff.Get(key))
+	} else {
+		slurmClientBackoff.Reset(key)
+	}
+
+	oidcJwksCond, refreshAfter, err := r.oidcJwksCondition(ctx, controller)
+	if err != nil {
+		return fmt.Errorf("failed to determine OIDC JWKS status: %w", err)
+	}
+	if oidcJwksCond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *oidcJwksCond)
+		// Resync the Controller to fetch the JWKS of its OIDC issuers again,
+		// unless it is already requeued sooner.
+		if key := objectutils.KeyFunc(controller); durationStore.Peek(key) == 0 {
+			durationStore.Push(key, refreshAfter)
+		}
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1alpha1.ControllerConditionOidcJwksReady)
+	}
+
 	if apiequality.Semantic.DeepEqual(controller.Status, newStatus) {
 		logger.V(2).Info("Controller Status has not changed, skipping status update",
 			"controller", klog.KObj(controller), "status", controller.Status)
@@ -44,6 +109,64 @@ func (r *ControllerReconciler) syncStatus(
 	return nil
 }
 
+// slurmClientCondition returns the SlurmClientReady condition of the Controller.
+func (r *ControllerReconciler) slurmClientCondition(controller *slinkyv1alpha1.Controller) metav1.Condition {
+	slurmClient := r.ClientMap.Get(objectutils.NamespacedName(controller))
+	if slurmClient == nil {
+		return metav1.Condition{
+			Type:    slinkyv1alpha1.ControllerConditionSlurmClientReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  slinkyv1alpha1.SlurmClientReasonUnavailable,
+			Message: "Slurm client unavailable: no ready RestApi, nor an internal slurmrestd",
+		}
+	}
+	return metav1.Condition{
+		Type:    slinkyv1alpha1.ControllerConditionSlurmClientReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  slinkyv1alpha1.SlurmClientReasonAvailable,
+		Message: "Slurm REST API " + string(slurmversion.VersionOf(slurmClient)),
+	}
+}
+
+// oidcJwksCondition returns the OidcJwksReady condition of the Controller, and
+// how long until the JWKS of its OIDC issuers is fetched again, as recorded on
+// the JWKS Secret. It is nil if no JWKS is fetched from an issuer.
+func (r *ControllerReconciler) oidcJwksCondition(
+	ctx context.Context,
+	controller *slinkyv1alpha1.Controller,
+) (*metav1.Condition, time.Duration, error) {
+	fetches, err := r.builder.ControllerFetchesOidcJwks(controller)
+	if err != nil || !fetches {
+		return nil, 0, err
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, controller.JwksKey(), secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, builder.OidcJwksRetryInterval, nil
+		}
+		return nil, 0, err
+	}
+	fetchTime, fetchErr := builder.OidcJwksFetchResult(secret)
+	refreshAfter := builder.OidcJwksNextFetch(fetchTime, fetchErr, time.Now())
+	if refreshAfter == 0 {
+		refreshAfter = builder.OidcJwksRetryInterval
+	}
+	if fetchErr != "" {
+		return &metav1.Condition{
+			Type:    slinkyv1alpha1.ControllerConditionOidcJwksReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  slinkyv1alpha1.OidcJwksReasonFetchFailed,
+			Message: fetchErr,
+		}, refreshAfter, nil
+	}
+	return &metav1.Condition{
+		Type:    slinkyv1alpha1.ControllerConditionOidcJwksReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  slinkyv1alpha1.OidcJwksReasonFetched,
+		Message: "The JWKS of all OIDC issuers were fetched",
+	}, refreshAfter, nil
+}
+
 func (r *ControllerReconciler) updateStatus(
 	ctx context.Context,
 	controller *slinkyv1alpha1.Controller,
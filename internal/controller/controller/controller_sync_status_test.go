@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func TestControllerReconciler_oidcJwksCondition(t *testing.T) {
+	controller := testutils.NewController("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), nil)
+	restapi := testutils.NewRestapi("slurm", controller)
+	restapi.Spec.Oidc = &slinkyv1alpha1.RestApiOidc{
+		JwksUrl: "https://idp.example.com/certs",
+	}
+	jwksSecret := func(fetchErr string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controller.JwksKey().Name,
+				Namespace: controller.JwksKey().Namespace,
+				Annotations: map[string]string{
+					builder.AnnotationOidcJwksFetchTime:  time.Now().UTC().Format(time.RFC3339),
+					builder.AnnotationOidcJwksFetchError: fetchErr,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		client     client.Client
+		wantCond   bool
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:   "No OIDC issuer",
+			client: fake.NewFakeClient(testutils.NewRestapi("slurm", controller)),
+		},
+		{
+			name:   "Not fetched yet",
+			client: fake.NewFakeClient(restapi.DeepCopy()),
+		},
+		{
+			name:       "Fetched",
+			client:     fake.NewFakeClient(restapi.DeepCopy(), jwksSecret("")),
+			wantCond:   true,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: slinkyv1alpha1.OidcJwksReasonFetched,
+		},
+		{
+			name:       "Fetch failed",
+			client:     fake.NewFakeClient(restapi.DeepCopy(), jwksSecret("failed to fetch JWKS")),
+			wantCond:   true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: slinkyv1alpha1.OidcJwksReasonFetchFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReconciler(tt.client, clientmap.NewClientMap())
+			cond, refreshAfter, err := r.oidcJwksCondition(context.TODO(), controller)
+			if err != nil {
+				t.Fatalf("oidcJwksCondition() error = %v", err)
+			}
+			if (cond != nil) != tt.wantCond {
+				t.Fatalf("oidcJwksCondition() = %v, wantCond %v", cond, tt.wantCond)
+			}
+			if cond == nil {
+				return
+			}
+			if cond.Status != tt.wantStatus || cond.Reason != tt.wantReason {
+				t.Errorf("oidcJwksCondition() = %v/%v, want %v/%v", cond.Status, cond.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if refreshAfter <= 0 || refreshAfter > builder.OidcJwksRefreshInterval {
+				t.Errorf("oidcJwksCondition() refreshAfter = %v", refreshAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+// getTenantNamespaces returns the other namespaces of the NodeSets and
+// LoginSets of the Controller, which it allows.
+func (r *ControllerReconciler) getTenantNamespaces(ctx context.Context, controller *slinkyv1alpha1.Controller) (set.Set[string], error) {
+	namespaces := set.New[string]()
+
+	nodesetList, err := r.refResolver.GetNodeSetsForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	for _, nodeset := range nodesetList.Items {
+		namespaces.Insert(nodeset.Namespace)
+	}
+
+	loginsetList, err := r.refResolver.GetLoginSetsForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	for _, loginset := range loginsetList.Items {
+		namespaces.Insert(loginset.Namespace)
+	}
+
+	namespaces.Delete(controller.Namespace)
+	return namespaces, nil
+}
+
+// syncTenants copies the Slurm key, and the users, of the Controller into the
+// other namespaces of its NodeSets and LoginSets, and deletes the copies which
+// are no longer allowed or desired. It also creates the claims of the shared
+// volumes with a claim template there, which are never deleted, like in the
+// namespace of the Controller.
+func (r *ControllerReconciler) syncTenants(ctx context.Context, controller *slinkyv1alpha1.Controller) error {
+	namespaces, err := r.getTenantNamespaces(ctx, controller)
+	if err != nil {
+		return err
+	}
+
+	desired := map[types.NamespacedName]bool{}
+	if namespaces.Len() > 0 {
+		hasUsers, err := r.builder.ControllerHasUsers(controller)
+		if err != nil {
+			return err
+		}
+		for _, namespace := range namespaces.SortedList() {
+			secret, err := r.builder.BuildControllerTenantAuth(controller, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to build: %w", err)
+			}
+			if err := objectutils.SyncObject(r.Client, ctx, secret, true); err != nil {
+				return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(secret), err)
+			}
+			desired[client.ObjectKeyFromObject(secret)] = true
+
+			for _, volume := range controller.Spec.SharedVolumes {
+				if volume.ClaimTemplate == nil {
+					continue
+				}
+				claim := r.builder.BuildSharedVolumeClaim(controller, &volume, namespace)
+				if err := objectutils.SyncObject(r.Client, ctx, claim, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(claim), err)
+				}
+			}
+
+			if !hasUsers {
+				continue
+			}
+			users, err := r.builder.BuildControllerTenantUsers(controller, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to build: %w", err)
+			}
+			if err := objectutils.SyncObject(r.Client, ctx, users, true); err != nil {
+				return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(users), err)
+			}
+			desired[client.ObjectKeyFromObject(users)] = true
+		}
+	}
+
+	return r.deleteTenants(ctx, client.ObjectKeyFromObject(controller), desired)
+}
+
+// deleteTenants deletes the copies of the Slurm key, and the users, of the
+// Controller which are not desired.
+func (r *ControllerReconciler) deleteTenants(ctx context.Context, controllerKey types.NamespacedName, desired map[types.NamespacedName]bool) error {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerKey.Name,
+			Namespace: controllerKey.Namespace,
+		},
+	}
+	opts := []client.ListOption{
+		client.MatchingLabels(labels.NewBuilder().WithControllerTenantLabels(controller).Build()),
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := r.List(ctx, secretList, opts...); err != nil {
+		return err
+	}
+	for _, secret := range secretList.Items {
+		if desired[client.ObjectKeyFromObject(&secret)] {
+			continue
+		}
+		if err := objectutils.DeleteObject(r.Client, ctx, &secret); err != nil {
+			return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(&secret), err)
+		}
+	}
+
+	configMapList := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMapList, opts...); err != nil {
+		return err
+	}
+	for _, configMap := range configMapList.Items {
+		if desired[client.ObjectKeyFromObject(&configMap)] {
+			continue
+		}
+		if err := objectutils.DeleteObject(r.Client, ctx, &configMap); err != nil {
+			return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(&configMap), err)
+		}
+	}
+
+	return nil
+}
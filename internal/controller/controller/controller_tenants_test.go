@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func TestControllerReconciler_syncTenants(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(scheme))
+
+	slurmKeyRef := testutils.NewSlurmKeyRef("slurm")
+	controller := testutils.NewController("slurm", slurmKeyRef, testutils.NewJwtHs256KeyRef("slurm"), nil)
+	controller.Spec.AllowedNamespaces = []string{"tenant"}
+	newNodeSet := func(name, namespace string) *slinkyv1alpha1.NodeSet {
+		nodeset := testutils.NewNodeset(name, controller, 1)
+		nodeset.Namespace = namespace
+		return nodeset
+	}
+	newLoginSet := func(name, namespace string) *slinkyv1alpha1.LoginSet {
+		loginset := testutils.NewLoginset(name, controller, testutils.NewSssdConfRef(name))
+		loginset.Namespace = namespace
+		loginset.Spec.Users = []slinkyv1alpha1.LoginSetUser{{Name: "alice", Uid: 1000}}
+		return loginset
+	}
+	staleSecret := &corev1.Secret{}
+	staleSecret.Name = controller.TenantAuthKey("stale").Name
+	staleSecret.Namespace = "stale"
+	staleSecret.Labels = labels.NewBuilder().WithControllerTenantLabels(controller).Build()
+
+	withClaimTemplate := controller.DeepCopy()
+	withClaimTemplate.Spec.SharedVolumes = []slinkyv1alpha1.SharedVolume{
+		{
+			Name:      "scratch",
+			MountPath: "/scratch",
+			ClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			},
+		},
+		{Name: "home", MountPath: "/home", ExistingClaim: "home"},
+	}
+
+	tests := []struct {
+		name        string
+		controller  *slinkyv1alpha1.Controller
+		objs        []client.Object
+		wantSecrets []string
+		wantUsers   []string
+		wantClaims  []string
+	}{
+		{
+			name: "Same namespace",
+			objs: []client.Object{newNodeSet("foo", controller.Namespace)},
+		},
+		{
+			name:        "Allowed namespace",
+			objs:        []client.Object{newNodeSet("foo", "tenant")},
+			wantSecrets: []string{"tenant"},
+		},
+		{
+			name:        "Allowed namespace, with users",
+			objs:        []client.Object{newNodeSet("foo", "tenant"), newLoginSet("login", "tenant")},
+			wantSecrets: []string{"tenant"},
+			wantUsers:   []string{"tenant"},
+		},
+		{
+			name: "Namespace not allowed",
+			objs: []client.Object{newNodeSet("foo", "other"), newLoginSet("login", "other")},
+		},
+		{
+			name:        "Allowed namespace, with shared volumes",
+			controller:  withClaimTemplate,
+			objs:        []client.Object{newNodeSet("foo", "tenant"), newNodeSet("bar", controller.Namespace)},
+			wantSecrets: []string{"tenant"},
+			wantClaims:  []string{"tenant"},
+		},
+		{
+			name:        "Stale copy",
+			objs:        []client.Object{newNodeSet("foo", "tenant"), staleSecret.DeepCopy()},
+			wantSecrets: []string{"tenant"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			controller := controller
+			if tt.controller != nil {
+				controller = tt.controller
+			}
+			objs := append([]client.Object{controller.DeepCopy(), testutils.NewSlurmKeySecret(slurmKeyRef)}, tt.objs...)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			r := NewReconciler(c, clientmap.NewClientMap())
+
+			if err := r.syncTenants(ctx, controller); err != nil {
+				t.Fatalf("ControllerReconciler.syncTenants() error = %v", err)
+			}
+
+			opts := client.MatchingLabels(labels.NewBuilder().WithControllerTenantLabels(controller).Build())
+			secretList := &corev1.SecretList{}
+			if err := c.List(ctx, secretList, opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(secretList.Items); got != len(tt.wantSecrets) {
+				t.Errorf("Secrets = %v, want %v", got, len(tt.wantSecrets))
+			}
+			for _, namespace := range tt.wantSecrets {
+				if err := c.Get(ctx, controller.TenantAuthKey(namespace), &corev1.Secret{}); err != nil {
+					t.Errorf("Get(Secret) error = %v", err)
+				}
+			}
+			configMapList := &corev1.ConfigMapList{}
+			if err := c.List(ctx, configMapList, opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(configMapList.Items); got != len(tt.wantUsers) {
+				t.Errorf("ConfigMaps = %v, want %v", got, len(tt.wantUsers))
+			}
+			for _, namespace := range tt.wantUsers {
+				if err := c.Get(ctx, controller.TenantUsersKey(namespace), &corev1.ConfigMap{}); err != nil {
+					t.Errorf("Get(ConfigMap) error = %v", err)
+				}
+			}
+
+			// Only the claims of claim templates are created, in the tenant namespaces.
+			claimList := &corev1.PersistentVolumeClaimList{}
+			if err := c.List(ctx, claimList); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(claimList.Items); got != len(tt.wantClaims) {
+				t.Errorf("PersistentVolumeClaims = %v, want %v", got, len(tt.wantClaims))
+			}
+			for _, namespace := range tt.wantClaims {
+				key := controller.SharedVolumeClaimKey(&controller.Spec.SharedVolumes[0], namespace)
+				if err := c.Get(ctx, key, &corev1.PersistentVolumeClaim{}); err != nil {
+					t.Errorf("Get(PersistentVolumeClaim) error = %v", err)
+				}
+			}
+
+			// The copies are deleted with the Controller, the claims are retained.
+			if err := r.deleteTenants(ctx, client.ObjectKeyFromObject(controller), nil); err != nil {
+				t.Fatalf("ControllerReconciler.deleteTenants() error = %v", err)
+			}
+			for _, namespace := range tt.wantSecrets {
+				if err := c.Get(ctx, controller.TenantAuthKey(namespace), &corev1.Secret{}); !apierrors.IsNotFound(err) {
+					t.Errorf("Get(Secret) error = %v, want NotFound", err)
+				}
+			}
+			for _, namespace := range tt.wantClaims {
+				key := controller.SharedVolumeClaimKey(&controller.Spec.SharedVolumes[0], namespace)
+				if err := c.Get(ctx, key, &corev1.PersistentVolumeClaim{}); err != nil {
+					t.Errorf("Get(PersistentVolumeClaim) error = %v", err)
+				}
+			}
+		})
+	}
+}
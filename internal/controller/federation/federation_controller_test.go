@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+var _ = Describe("Federation Controller", func() {
+	Context("When reconciling a Federation", func() {
+		var name = testutils.GenerateResourceName(5)
+		var federation *slinkyv1alpha1.Federation
+
+		BeforeEach(func() {
+			slurmKeyRef := testutils.NewSlurmKeyRef(name)
+			jwtHs256KeyRef := testutils.NewJwtHs256KeyRef(name)
+			controller := testutils.NewController(name, slurmKeyRef, jwtHs256KeyRef, nil)
+			federation = testutils.NewFederation(name, nil, controller)
+			Expect(k8sClient.Create(ctx, federation.DeepCopy())).To(Succeed())
+		})
+
+		AfterEach(func() {
+			_ = k8sClient.Delete(ctx, federation)
+		})
+
+		It("Should successfully create a federation", func(ctx SpecContext) {
+			By("Creating Federation CR")
+			createdFederation := &slinkyv1alpha1.Federation{}
+			federationKey := client.ObjectKeyFromObject(federation)
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, federationKey, createdFederation)).To(Succeed())
+			}).Should(Succeed())
+		}, SpecTimeout(testutils.Timeout))
+	})
+})
@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+// errInvalidMember is returned when a member cluster of a Federation cannot be
+// registered. It is reported in the status of the Federation.
+var errInvalidMember = errors.New("invalid member cluster")
+
+const (
+	// FederationRemovalFailedReason is the reason of the events recorded when
+	// the federation could not be removed from slurmdbd.
+	FederationRemovalFailedReason = "RemovalFailed"
+)
+
+type SyncStep struct {
+	Name string
+	Sync func(ctx context.Context, federation *slinkyv1alpha1.Federation) error
+}
+
+// Sync implements control logic for synchronizing a Federation.
+func (r *FederationReconciler) Sync(ctx context.Context, req reconcile.Request) error {
+	logger := log.FromContext(ctx)
+
+	federation := &slinkyv1alpha1.Federation{}
+	if err := r.Get(ctx, req.NamespacedName, federation); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Federation has been deleted", "request", req)
+			return nil
+		}
+		return err
+	}
+
+	if !federation.DeletionTimestamp.IsZero() {
+		return r.syncDelete(ctx, federation)
+	}
+	if controllerutil.AddFinalizer(federation, slinkyv1alpha1.FederationFinalizer) {
+		if err := r.Update(ctx, federation); err != nil {
+			return fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	syncSteps := []SyncStep{
+		{
+			Name: "Job",
+			Sync: func(ctx context.Context, federation *slinkyv1alpha1.Federation) error {
+				if _, err := r.getMembers(ctx, federation); err != nil {
+					if errors.Is(err, errInvalidMember) {
+						logger.Info("Skipping registration of Federation", "reason", err.Error())
+						return nil
+					}
+					return err
+				}
+				object, err := r.builder.BuildFederationJob(federation)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				// The pod template of a Job is immutable, replace the Job when
+				// the registration changes.
+				job := &batchv1.Job{}
+				if err := r.Get(ctx, client.ObjectKeyFromObject(object), job); err != nil {
+					if !apierrors.IsNotFound(err) {
+						return err
+					}
+				} else if job.Annotations[builder.AnnotationFederationHash] != object.Annotations[builder.AnnotationFederationHash] {
+					logger.Info("Federation registration changed, replacing Job", "job", klog.KObj(job))
+					if err := objectutils.DeleteObject(r.Client, ctx, job); err != nil {
+						return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(job), err)
+					}
+					durationStore.Push(objectutils.KeyFunc(federation), 5*time.Second)
+					return nil
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, s := range syncSteps {
+		if err := s.Sync(ctx, federation); err != nil {
+			e := fmt.Errorf("[%s]: %w", s.Name, err)
+			errors := []error{e}
+			if err := r.syncStatus(ctx, federation); err != nil {
+				e := fmt.Errorf("[%s]: %w", s.Name, err)
+				errors = append(errors, e)
+			}
+			return utilerrors.NewAggregate(errors)
+		}
+	}
+
+	return r.syncStatus(ctx, federation)
+}
+
+// getMembers returns the Controllers of the member clusters of the Federation.
+// A member cluster must exist, use the Accounting of the Federation, and be a
+// member of no other Federation.
+func (r *FederationReconciler) getMembers(ctx context.Context, federation *slinkyv1alpha1.Federation) ([]*slinkyv1alpha1.Controller, error) {
+	out := make([]*slinkyv1alpha1.Controller, 0, len(federation.Spec.Clusters))
+	for _, cluster := range federation.Spec.Clusters {
+		controller, err := r.refResolver.GetController(ctx, cluster.ControllerRef)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("%w: Controller %s not found", errInvalidMember, cluster.ControllerRef.NamespacedName())
+			}
+			return nil, err
+		}
+		if !controller.Spec.AccountingRef.IsMatch(federation.Spec.AccountingRef.NamespacedName()) {
+			return nil, fmt.Errorf("%w: Controller %s does not use Accounting %s",
+				errInvalidMember, klog.KObj(controller), federation.Spec.AccountingRef.NamespacedName())
+		}
+		other, err := r.refResolver.GetFederationForController(ctx, controller)
+		if err != nil {
+			return nil, err
+		}
+		if other != nil && objectutils.NamespacedName(other) != objectutils.NamespacedName(federation) {
+			return nil, fmt.Errorf("%w: Controller %s is a member of Federation %s",
+				errInvalidMember, klog.KObj(controller), klog.KObj(other))
+		}
+		out = append(out, controller)
+	}
+	return out, nil
+}
+
+// syncDelete removes the Federation from slurmdbd, with the removal Job, then
+// releases the deletion of the Federation. A failed removal is reported as an
+// event, and does not hold the deletion.
+func (r *FederationReconciler) syncDelete(ctx context.Context, federation *slinkyv1alpha1.Federation) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(federation, slinkyv1alpha1.FederationFinalizer) {
+		return nil
+	}
+
+	// Do not register the federation again while it is removed.
+	registration := &batchv1.Job{}
+	if err := r.Get(ctx, federation.RegistrationKey(), registration); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if registration.DeletionTimestamp.IsZero() {
+		if err := objectutils.DeleteObject(r.Client, ctx, registration); err != nil {
+			return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(registration), err)
+		}
+	}
+
+	object, err := r.builder.BuildFederationRemovalJob(federation)
+	if err != nil {
+		if !errors.Is(err, builder.ErrFederationNoMembers) {
+			return fmt.Errorf("failed to build: %w", err)
+		}
+		r.eventRecorder.Eventf(federation, corev1.EventTypeWarning, FederationRemovalFailedReason,
+			"Cannot remove federation %s from slurmdbd: %v", federation.FederationName(), err)
+		return r.removeFinalizer(ctx, federation)
+	}
+
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(object), job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Removing Federation from slurmdbd", "job", klog.KObj(object))
+		if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+			return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+		}
+		durationStore.Push(objectutils.KeyFunc(federation), 5*time.Second)
+		return nil
+	}
+
+	for _, jobCond := range job.Status.Conditions {
+		if jobCond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch jobCond.Type {
+		case batchv1.JobComplete:
+			logger.Info("Removed Federation from slurmdbd", "job", klog.KObj(job))
+			return r.removeFinalizer(ctx, federation)
+		case batchv1.JobFailed:
+			r.eventRecorder.Eventf(federation, corev1.EventTypeWarning, FederationRemovalFailedReason,
+				"Failed to remove federation %s from slurmdbd, Job %s failed: %s",
+				federation.FederationName(), klog.KObj(job), jobCond.Message)
+			return r.removeFinalizer(ctx, federation)
+		}
+	}
+
+	// Wait for the removal Job to finish.
+	durationStore.Push(objectutils.KeyFunc(federation), 5*time.Second)
+	return nil
+}
+
+// removeFinalizer releases the deletion of the Federation.
+func (r *FederationReconciler) removeFinalizer(ctx context.Context, federation *slinkyv1alpha1.Federation) error {
+	if !controllerutil.RemoveFinalizer(federation, slinkyv1alpha1.FederationFinalizer) {
+		return nil
+	}
+	if err := r.Update(ctx, federation); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+)
+
+// syncStatus handles determining and updating the status.
+func (r *FederationReconciler) syncStatus(
+	ctx context.Context,
+	federation *slinkyv1alpha1.Federation,
+) error {
+	logger := log.FromContext(ctx)
+
+	newStatus := &slinkyv1alpha1.FederationStatus{
+		Conditions: []metav1.Condition{},
+	}
+	newStatus.Conditions = append(newStatus.Conditions, federation.Status.Conditions...)
+
+	registeredCond, clusters, err := r.registeredCondition(ctx, federation)
+	if err != nil {
+		return fmt.Errorf("failed to determine Federation registration: %w", err)
+	}
+	registeredCond.ObservedGeneration = federation.Generation
+	meta.SetStatusCondition(&newStatus.Conditions, registeredCond)
+	if registeredCond.Status == metav1.ConditionTrue {
+		newStatus.Clusters = clusters
+	}
+
+	if apiequality.Semantic.DeepEqual(federation.Status, *newStatus) {
+		logger.V(2).Info("Federation Status has not changed, skipping status update",
+			"federation", klog.KObj(federation), "status", federation.Status)
+		return nil
+	}
+
+	if err := r.updateStatus(ctx, federation, newStatus); err != nil {
+		return fmt.Errorf("error updating Federation(%s) status: %w",
+			klog.KObj(federation), err)
+	}
+
+	return nil
+}
+
+// registeredCondition returns the Registered condition of the Federation, and
+// the names of its member clusters.
+func (r *FederationReconciler) registeredCondition(
+	ctx context.Context,
+	federation *slinkyv1alpha1.Federation,
+) (metav1.Condition, []string, error) {
+	cond := metav1.Condition{
+		Type: slinkyv1alpha1.FederationConditionRegistered,
+	}
+
+	members, err := r.getMembers(ctx, federation)
+	if err != nil {
+		if !errors.Is(err, errInvalidMember) {
+			return cond, nil, err
+		}
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = slinkyv1alpha1.FederationReasonInvalidMember
+		cond.Message = err.Error()
+		return cond, nil, nil
+	}
+	clusters := make([]string, 0, len(members))
+	for _, member := range members {
+		clusters = append(clusters, member.ClusterName())
+	}
+
+	cond.Status = metav1.ConditionFalse
+	cond.Reason = slinkyv1alpha1.FederationReasonPending
+	cond.Message = fmt.Sprintf("Registering clusters %s in slurmdbd", strings.Join(clusters, ","))
+
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, federation.RegistrationKey(), job); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cond, clusters, nil
+		}
+		return cond, nil, err
+	}
+	// The Job of a previous registration is being replaced.
+	expected, err := r.builder.BuildFederationJob(federation)
+	if err != nil {
+		return cond, nil, err
+	}
+	if job.Annotations[builder.AnnotationFederationHash] != expected.Annotations[builder.AnnotationFederationHash] {
+		return cond, clusters, nil
+	}
+
+	for _, jobCond := range job.Status.Conditions {
+		if jobCond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch jobCond.Type {
+		case batchv1.JobComplete:
+			cond.Status = metav1.ConditionTrue
+			cond.Reason = slinkyv1alpha1.FederationReasonRegistered
+			cond.Message = fmt.Sprintf("Registered clusters %s in slurmdbd", strings.Join(clusters, ","))
+		case batchv1.JobFailed:
+			cond.Reason = slinkyv1alpha1.FederationReasonFailed
+			cond.Message = fmt.Sprintf("Job %s failed: %s", klog.KObj(job), jobCond.Message)
+		}
+	}
+
+	return cond, clusters, nil
+}
+
+func (r *FederationReconciler) updateStatus(
+	ctx context.Context,
+	federation *slinkyv1alpha1.Federation,
+	newStatus *slinkyv1alpha1.FederationStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	namespacedName := types.NamespacedName{
+		Namespace: federation.GetNamespace(),
+		Name:      federation.GetName(),
+	}
+
+	logger.V(1).Info("Pending Federation Status update",
+		"federation", klog.KObj(federation), "newStatus", newStatus)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		toUpdate := &slinkyv1alpha1.Federation{}
+		if err := r.Get(ctx, namespacedName, toUpdate); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		toUpdate.Status = *newStatus
+		return r.Status().Update(ctx, toUpdate)
+	})
+}
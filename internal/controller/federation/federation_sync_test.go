@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package federation
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func TestFederationReconciler_Sync(t *testing.T) {
+	accounting := testutils.NewAccounting("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), testutils.NewPasswordRef("slurm"))
+	newController := func(name string, accounting *slinkyv1alpha1.Accounting) *slinkyv1alpha1.Controller {
+		return testutils.NewController(name, testutils.NewSlurmKeyRef(name), testutils.NewJwtHs256KeyRef(name), accounting)
+	}
+	slurm1 := newController("slurm1", accounting)
+	slurm2 := newController("slurm2", accounting)
+	federation := testutils.NewFederation("fed", accounting, slurm1, slurm2)
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&slinkyv1alpha1.Federation{}).
+			Build()
+	}
+	newJob := func(federation *slinkyv1alpha1.Federation, objs ...client.Object) *batchv1.Job {
+		job, err := builder.New(newClient(objs...)).BuildFederationJob(federation)
+		if err != nil {
+			panic(err)
+		}
+		return job
+	}
+	completeJob := newJob(federation, slurm1, slurm2)
+	completeJob.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+	}
+	staleJob := newJob(federation, slurm1, slurm2)
+	staleJob.Annotations[builder.AnnotationFederationHash] = "stale"
+	type fields struct {
+		Client client.Client
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		wantJob      bool
+		wantReason   string
+		wantClusters []string
+		wantErr      bool
+	}{
+		{
+			name: "register",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1, slurm2),
+			},
+			wantJob:    true,
+			wantReason: slinkyv1alpha1.FederationReasonPending,
+		},
+		{
+			name: "registered",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1, slurm2, completeJob),
+			},
+			wantJob:      true,
+			wantReason:   slinkyv1alpha1.FederationReasonRegistered,
+			wantClusters: []string{"default_slurm1", "default_slurm2"},
+		},
+		{
+			name: "replace stale job",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1, slurm2, staleJob),
+			},
+			wantJob:    false,
+			wantReason: slinkyv1alpha1.FederationReasonPending,
+		},
+		{
+			name: "missing member",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1),
+			},
+			wantJob:    false,
+			wantReason: slinkyv1alpha1.FederationReasonInvalidMember,
+		},
+		{
+			name: "member without accounting",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1, newController("slurm2", nil)),
+			},
+			wantJob:    false,
+			wantReason: slinkyv1alpha1.FederationReasonInvalidMember,
+		},
+		{
+			name: "member of another federation",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1, slurm2,
+					testutils.NewFederation("another", accounting, slurm2)),
+			},
+			wantJob:    false,
+			wantReason: slinkyv1alpha1.FederationReasonInvalidMember,
+		},
+		{
+			name: "member of a federation of the same name in another namespace",
+			fields: fields{
+				Client: newClient(federation.DeepCopy(), slurm1, slurm2,
+					func() *slinkyv1alpha1.Federation {
+						// Ordered before the Federation, so slurm2 is its member.
+						other := testutils.NewFederation("fed", accounting, slurm2)
+						other.Namespace = "a"
+						return other
+					}()),
+			},
+			wantJob:    false,
+			wantReason: slinkyv1alpha1.FederationReasonInvalidMember,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			r := NewReconciler(tt.fields.Client)
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(federation)}
+			if err := r.Sync(ctx, req); (err != nil) != tt.wantErr {
+				t.Errorf("FederationReconciler.Sync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			job := &batchv1.Job{}
+			err := r.Get(ctx, federation.RegistrationKey(), job)
+			if gotJob := err == nil; gotJob != tt.wantJob {
+				t.Errorf("Job exists = %v, want %v (%v)", gotJob, tt.wantJob, err)
+			} else if err != nil && !apierrors.IsNotFound(err) {
+				t.Errorf("Get(Job) error = %v", err)
+			}
+
+			got := &slinkyv1alpha1.Federation{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(federation), got); err != nil {
+				t.Fatalf("Get(Federation) error = %v", err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, slinkyv1alpha1.FederationConditionRegistered)
+			if cond == nil {
+				t.Fatalf("Condition %s not found", slinkyv1alpha1.FederationConditionRegistered)
+			}
+			if cond.Reason != tt.wantReason {
+				t.Errorf("Condition.Reason = %v, want %v (%s)", cond.Reason, tt.wantReason, cond.Message)
+			}
+			if wantStatus := tt.wantReason == slinkyv1alpha1.FederationReasonRegistered; (cond.Status == metav1.ConditionTrue) != wantStatus {
+				t.Errorf("Condition.Status = %v, want %v", cond.Status, wantStatus)
+			}
+			if len(got.Status.Clusters) != len(tt.wantClusters) {
+				t.Errorf("Status.Clusters = %v, want %v", got.Status.Clusters, tt.wantClusters)
+			}
+			if !controllerutil.ContainsFinalizer(got, slinkyv1alpha1.FederationFinalizer) {
+				t.Errorf("Finalizers = %v, want %v", got.Finalizers, slinkyv1alpha1.FederationFinalizer)
+			}
+		})
+	}
+}
+
+func TestFederationReconciler_syncDelete(t *testing.T) {
+	accounting := testutils.NewAccounting("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), testutils.NewPasswordRef("slurm"))
+	slurm1 := testutils.NewController("slurm1", testutils.NewSlurmKeyRef("slurm1"), testutils.NewJwtHs256KeyRef("slurm1"), accounting)
+	federation := testutils.NewFederation("fed", accounting, slurm1)
+	federation.Finalizers = []string{slinkyv1alpha1.FederationFinalizer}
+	federation.DeletionTimestamp = ptr.To(metav1.Now())
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&slinkyv1alpha1.Federation{}).
+			Build()
+	}
+	newRemovalJob := func(jobCond batchv1.JobConditionType) *batchv1.Job {
+		job, err := builder.New(newClient(slurm1)).BuildFederationRemovalJob(federation)
+		if err != nil {
+			panic(err)
+		}
+		if jobCond != "" {
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: jobCond, Status: corev1.ConditionTrue},
+			}
+		}
+		return job
+	}
+	registrationJob, err := builder.New(newClient(slurm1)).BuildFederationJob(federation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name           string
+		client         client.Client
+		wantRemovalJob bool
+		wantDeleted    bool
+		wantEvent      bool
+	}{
+		{
+			name:           "start removal",
+			client:         newClient(federation.DeepCopy(), slurm1, registrationJob),
+			wantRemovalJob: true,
+		},
+		{
+			name:           "removal running",
+			client:         newClient(federation.DeepCopy(), slurm1, newRemovalJob("")),
+			wantRemovalJob: true,
+		},
+		{
+			name:        "removal complete",
+			client:      newClient(federation.DeepCopy(), slurm1, newRemovalJob(batchv1.JobComplete)),
+			wantDeleted: true,
+		},
+		{
+			name:        "removal failed",
+			client:      newClient(federation.DeepCopy(), slurm1, newRemovalJob(batchv1.JobFailed)),
+			wantDeleted: true,
+			wantEvent:   true,
+		},
+		{
+			name:        "no member cluster",
+			client:      newClient(federation.DeepCopy()),
+			wantDeleted: true,
+			wantEvent:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			r := NewReconciler(tt.client)
+			recorder := record.NewFakeRecorder(10)
+			r.eventRecorder = recorder
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(federation)}
+			if err := r.Sync(ctx, req); err != nil {
+				t.Fatalf("FederationReconciler.Sync() error = %v", err)
+			}
+
+			err := r.Get(ctx, federation.RemovalKey(), &batchv1.Job{})
+			if gotJob := err == nil; gotJob != tt.wantRemovalJob && !tt.wantDeleted {
+				t.Errorf("removal Job exists = %v, want %v (%v)", gotJob, tt.wantRemovalJob, err)
+			}
+			if err := r.Get(ctx, federation.RegistrationKey(), &batchv1.Job{}); !apierrors.IsNotFound(err) {
+				t.Errorf("Get(registration Job) error = %v, want NotFound", err)
+			}
+			err = r.Get(ctx, client.ObjectKeyFromObject(federation), &slinkyv1alpha1.Federation{})
+			if gotDeleted := apierrors.IsNotFound(err); gotDeleted != tt.wantDeleted {
+				t.Errorf("Federation deleted = %v, want %v (%v)", gotDeleted, tt.wantDeleted, err)
+			}
+			if gotEvent := len(recorder.Events) > 0; gotEvent != tt.wantEvent {
+				t.Errorf("event recorded = %v, want %v", gotEvent, tt.wantEvent)
+			}
+		})
+	}
+}
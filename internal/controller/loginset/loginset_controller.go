@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/flowcontrol"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -23,6 +24,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/loginset/sessioncontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/durationstore"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 )
@@ -53,9 +55,15 @@ type LoginSetReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 
-	builder       *builder.Builder
-	refResolver   *refresolver.RefResolver
-	eventRecorder record.EventRecorderLogger
+	builder        *builder.Builder
+	refResolver    *refresolver.RefResolver
+	sessionControl sessioncontrol.SessionControlInterface
+	eventRecorder  record.EventRecorderLogger
+
+	// scaleDownTimes is the time since which autoscaling wants to scale down,
+	// by LoginSet.
+	scaleDownTimes map[string]time.Time
+	scaleDownMu    sync.Mutex
 }
 
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=loginsets,verbs=get;list;watch;create;update;patch;delete
@@ -63,9 +71,12 @@ type LoginSetReconciler struct {
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=loginsets/finalizers,verbs=update
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -112,6 +123,10 @@ func (r *LoginSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			Reader:      r.Client,
 			refResolver: r.refResolver,
 		}).
+		Watches(&corev1.ConfigMap{}, &configMapEventHandler{
+			Reader:      r.Client,
+			refResolver: r.refResolver,
+		}).
 		Watches(&corev1.Secret{}, &secretEventHandler{
 			Reader:      r.Client,
 			refResolver: r.refResolver,
@@ -122,15 +137,16 @@ func (r *LoginSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
-func NewReconciler(c client.Client) *LoginSetReconciler {
+func NewReconciler(c client.Client, config *rest.Config) *LoginSetReconciler {
 	s := c.Scheme()
 	es := corev1.EventSource{Component: ControllerName}
 	return &LoginSetReconciler{
 		Client: c,
 		Scheme: s,
 
-		builder:       builder.New(c),
-		refResolver:   refresolver.New(c),
-		eventRecorder: record.NewBroadcaster().NewRecorder(s, es),
+		builder:        builder.New(c),
+		refResolver:    refresolver.New(c),
+		sessionControl: sessioncontrol.NewSessionControl(config),
+		eventRecorder:  record.NewBroadcaster().NewRecorder(s, es),
 	}
 }
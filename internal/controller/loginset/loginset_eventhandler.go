@@ -133,6 +133,17 @@ func (e *secretEventHandler) enqueueRequest(
 	}
 	secretKey := client.ObjectKeyFromObject(secret)
 
+	loginsetList := &slinkyv1alpha1.LoginSetList{}
+	if err := e.List(ctx, loginsetList, client.InNamespace(secret.Namespace)); err != nil {
+		logger.Error(err, "failed to list LoginSet CRs")
+	}
+
+	for _, loginset := range loginsetList.Items {
+		if isSshCaSecret(&loginset, secret.Name) {
+			objectutils.EnqueueRequest(q, &loginset)
+		}
+	}
+
 	controllerList := &slinkyv1alpha1.ControllerList{}
 	if err := e.List(ctx, controllerList); err != nil {
 		logger.Error(err, "failed to list controller CRs")
@@ -140,8 +151,10 @@ func (e *secretEventHandler) enqueueRequest(
 
 	for _, controller := range controllerList.Items {
 		slurmKeyKey := controller.AuthSlurmKey()
+		slurmJwksKey := controller.SlurmJwksKey()
 		jwtHs256KeyKey := controller.AuthJwtHs256Key()
 		if secretKey.String() != slurmKeyKey.String() &&
+			secretKey.String() != slurmJwksKey.String() &&
 			secretKey.String() != jwtHs256KeyKey.String() {
 			continue
 		}
@@ -159,3 +172,88 @@ func (e *secretEventHandler) enqueueRequest(
 		}
 	}
 }
+
+// isSshCaSecret returns true if the secret holds an SSH CA of the loginset.
+func isSshCaSecret(loginset *slinkyv1alpha1.LoginSet, name string) bool {
+	for _, ref := range []*corev1.SecretKeySelector{loginset.SshUserCaPublicKeyRef(), loginset.SshHostCaRef()} {
+		if ref != nil && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var _ handler.EventHandler = &configMapEventHandler{}
+
+type configMapEventHandler struct {
+	client.Reader
+	refResolver *refresolver.RefResolver
+}
+
+func (e *configMapEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *configMapEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *configMapEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *configMapEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *configMapEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	logger := log.FromContext(ctx)
+
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	configMapKey := client.ObjectKeyFromObject(configMap)
+
+	controllerList := &slinkyv1alpha1.ControllerList{}
+	if err := e.List(ctx, controllerList, client.InNamespace(configMap.Namespace)); err != nil {
+		logger.Error(err, "failed to list controller CRs")
+	}
+
+	for _, controller := range controllerList.Items {
+		// Only the users of the controller are provisioned on LoginSet pods.
+		if configMapKey.String() != controller.UsersKey().String() {
+			continue
+		}
+
+		list, err := e.refResolver.GetLoginSetsForController(ctx, &controller)
+		if err != nil {
+			logger.Error(err, "failed to list LoginSet CRs")
+			continue
+		}
+
+		for _, item := range list.Items {
+			objectutils.EnqueueRequest(q, &item)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -275,3 +276,61 @@ func Test_controllerEventHandler_Generic(t *testing.T) {
 		})
 	}
 }
+
+func Test_secretEventHandler_Update(t *testing.T) {
+	loginset := &slinkyv1alpha1.LoginSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1alpha1.LoginSetSpec{
+			ControllerRef: slinkyv1alpha1.ObjectReference{
+				Name: "slurm",
+			},
+			SshCertificateAuthority: &slinkyv1alpha1.LoginSetSshCertificateAuthority{
+				UserCaPublicKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "ssh-user-ca",
+					},
+					Key: "ca",
+				},
+			},
+		},
+	}
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+		want   int
+	}{
+		{
+			name:   "User CA",
+			secret: secret("ssh-user-ca"),
+			want:   1,
+		},
+		{
+			name:   "Other",
+			secret: secret("foo"),
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient(loginset.DeepCopy())
+			e := &secretEventHandler{
+				Reader:      c,
+				refResolver: refresolver.New(c),
+			}
+			q := newQueue()
+			e.Update(context.TODO(), event.UpdateEvent{ObjectOld: tt.secret, ObjectNew: tt.secret}, q)
+			if got := q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -36,12 +36,13 @@ func (r *LoginSetReconciler) Sync(ctx context.Context, req reconcile.Request) er
 		return err
 	}
 
-	controller := &slinkyv1alpha1.Controller{}
 	controllerKey := client.ObjectKey(loginset.Spec.ControllerRef.NamespacedName())
-	if err := r.Get(ctx, controllerKey, controller); err != nil {
+	if _, err := r.refResolver.GetAllowedController(ctx, loginset.Spec.ControllerRef, loginset.Namespace); err != nil {
 		return fmt.Errorf("failed to get controller (%s): %w", controllerKey, err)
 	}
 
+	var podSessions []slinkyv1alpha1.LoginSetPodSessions
+
 	syncSteps := []SyncStep{
 		{
 			Name: "SSH Host Keys",
@@ -56,6 +57,38 @@ func (r *LoginSetReconciler) Sync(ctx context.Context, req reconcile.Request) er
 				return nil
 			},
 		},
+		{
+			Name: "SSH CA",
+			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
+				if !loginset.GeneratesSshCa() {
+					return nil
+				}
+				object, err := r.builder.BuildLoginSshCa(loginset)
+				if err != nil {
+					return fmt.Errorf("failed to build object: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "SSH Host Certificates",
+			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
+				if !loginset.HasSshHostCertificates() {
+					return nil
+				}
+				object, err := r.builder.BuildLoginSshHostCertificates(loginset)
+				if err != nil {
+					return fmt.Errorf("failed to build object: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
 		{
 			Name: "SSH Config",
 			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
@@ -82,6 +115,20 @@ func (r *LoginSetReconciler) Sync(ctx context.Context, req reconcile.Request) er
 				return nil
 			},
 		},
+		{
+			Name: "Sessions",
+			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
+				var err error
+				podSessions, err = r.syncSessions(ctx, loginset)
+				return err
+			},
+		},
+		{
+			Name: "Autoscaling",
+			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
+				return r.syncAutoscaling(ctx, loginset, podSessions)
+			},
+		},
 		{
 			Name: "Deployment",
 			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
@@ -89,10 +136,13 @@ func (r *LoginSetReconciler) Sync(ctx context.Context, req reconcile.Request) er
 				if err != nil {
 					return fmt.Errorf("failed to build: %w", err)
 				}
-				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
-					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
-				}
-				return nil
+				return r.syncDeployment(ctx, loginset, object, podSessions)
+			},
+		},
+		{
+			Name: "Draining Pods",
+			Sync: func(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) error {
+				return r.syncDrainingPods(ctx, loginset, podSessions)
 			},
 		},
 	}
@@ -101,7 +151,7 @@ func (r *LoginSetReconciler) Sync(ctx context.Context, req reconcile.Request) er
 		if err := s.Sync(ctx, loginset); err != nil {
 			e := fmt.Errorf("[%s]: %w", s.Name, err)
 			errors := []error{e}
-			if err := r.syncStatus(ctx, loginset); err != nil {
+			if err := r.syncStatus(ctx, loginset, podSessions); err != nil {
 				e := fmt.Errorf("[%s]: %w", s.Name, err)
 				errors = append(errors, e)
 			}
@@ -109,5 +159,5 @@ func (r *LoginSetReconciler) Sync(ctx context.Context, req reconcile.Request) er
 		}
 	}
 
-	return r.syncStatus(ctx, loginset)
+	return r.syncStatus(ctx, loginset, podSessions)
 }
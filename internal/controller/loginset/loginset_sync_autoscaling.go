@@ -0,0 +1,293 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package loginset
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+)
+
+// podMetricsListGVK is the list of pod metrics served by the Metrics Server.
+var podMetricsListGVK = schema.GroupVersionKind{
+	Group:   "metrics.k8s.io",
+	Version: "v1beta1",
+	Kind:    "PodMetricsList",
+}
+
+// loginSetLoad is the load of the login pods which receive new connections.
+// Unknown metrics are nil.
+type loginSetLoad struct {
+	// Sessions is the total number of active SSH sessions.
+	Sessions *int32
+	// MissingSessions is the number of login pods whose SSH sessions are unknown.
+	MissingSessions int32
+	// CPUUtilization is the sum of the CPU utilization of the login
+	// containers, as percentages of their requested CPU.
+	CPUUtilization *int64
+	// MissingCPUUtilization is the number of login pods whose CPU utilization
+	// is unknown.
+	MissingCPUUtilization int32
+}
+
+// syncAutoscaling scales the replicas of the LoginSet on the load of its login
+// pods. Scaling up is immediate, while scaling down waits for the scale down
+// delay since the last scaling, or since it was first wanted if never scaled.
+func (r *LoginSetReconciler) syncAutoscaling(
+	ctx context.Context,
+	loginset *slinkyv1alpha1.LoginSet,
+	podSessions []slinkyv1alpha1.LoginSetPodSessions,
+) error {
+	logger := log.FromContext(ctx)
+
+	if !loginset.HasAutoscaling() {
+		return nil
+	}
+	autoscaling := loginset.Spec.Autoscaling
+	key := loginset.Key().String()
+	durationStore.Push(key, SessionsRefreshInterval)
+
+	pods, err := r.getLoginPods(ctx, loginset)
+	if err != nil {
+		return err
+	}
+
+	load := loginSetLoad{}
+	if autoscaling.TargetSessionsPerPod != nil {
+		load.Sessions, load.MissingSessions = sumSessions(pods, podSessions)
+	}
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		cpuUtilization, missing, err := r.getCPUUtilization(ctx, loginset, pods)
+		if err != nil {
+			logger.V(1).Info("failed to get CPU utilization, skipping...", "error", err)
+		}
+		load.CPUUtilization, load.MissingCPUUtilization = cpuUtilization, missing
+	}
+
+	now := time.Now()
+	currentReplicas := ptr.Deref(loginset.Spec.Replicas, 1)
+	desiredReplicas := calculateDesiredReplicas(autoscaling, currentReplicas, load)
+	scaleDownSince := r.observeScaleDown(key, desiredReplicas < currentReplicas, now)
+	if desiredReplicas == currentReplicas {
+		return nil
+	}
+
+	if desiredReplicas < currentReplicas {
+		if loginset.Status.LastScaleTime != nil {
+			scaleDownSince = loginset.Status.LastScaleTime.Time
+		}
+		scaleDownTime := scaleDownSince.Add(autoscaling.ScaleDownDelayOrDefault())
+		if now.Before(scaleDownTime) {
+			logger.V(1).Info("Delaying scale down of LoginSet",
+				"replicas", currentReplicas, "desiredReplicas", desiredReplicas, "scaleDownTime", scaleDownTime)
+			durationStore.Push(key, scaleDownTime.Sub(now))
+			return nil
+		}
+	}
+
+	logger.Info("Scaling LoginSet", "replicas", currentReplicas, "desiredReplicas", desiredReplicas,
+		"sessions", ptr.Deref(load.Sessions, -1), "cpuUtilization", ptr.Deref(load.CPUUtilization, -1))
+	toUpdate := loginset.DeepCopy()
+	toUpdate.Spec.Replicas = ptr.To(desiredReplicas)
+	if err := r.Patch(ctx, toUpdate, client.MergeFrom(loginset)); err != nil {
+		return fmt.Errorf("failed to patch LoginSet (%s): %w", klog.KObj(loginset), err)
+	}
+	toUpdate.Status.LastScaleTime = ptr.To(metav1.NewTime(now))
+	*loginset = *toUpdate
+	r.observeScaleDown(key, false, now)
+	r.eventRecorder.Eventf(loginset, corev1.EventTypeNormal, "Scaled",
+		"Scaled from %d to %d replicas", currentReplicas, desiredReplicas)
+
+	return nil
+}
+
+// observeScaleDown returns the time since which the LoginSet was observed to
+// scale down, or forgets it if it does not.
+func (r *LoginSetReconciler) observeScaleDown(key string, scaleDown bool, now time.Time) time.Time {
+	r.scaleDownMu.Lock()
+	defer r.scaleDownMu.Unlock()
+	if !scaleDown {
+		delete(r.scaleDownTimes, key)
+		return now
+	}
+	if r.scaleDownTimes == nil {
+		r.scaleDownTimes = make(map[string]time.Time)
+	}
+	since, ok := r.scaleDownTimes[key]
+	if !ok {
+		since = now
+		r.scaleDownTimes[key] = since
+	}
+	return since
+}
+
+// sumSessions returns the total SSH sessions of the login pods which receive
+// new connections, or nil if no sessions were counted, and the number of login
+// pods whose SSH sessions were not counted.
+func sumSessions(pods []corev1.Pod, podSessions []slinkyv1alpha1.LoginSetPodSessions) (*int32, int32) {
+	var total *int32
+	counted := make(map[string]bool, len(podSessions))
+	for _, s := range podSessions {
+		if s.Draining {
+			continue
+		}
+		counted[s.PodName] = true
+		total = ptr.To(ptr.Deref(total, 0) + s.Sessions)
+	}
+	missing := int32(0)
+	for _, pod := range pods {
+		if !counted[pod.Name] {
+			missing++
+		}
+	}
+	return total, missing
+}
+
+// getCPUUtilization returns the sum of the CPU utilization of the login
+// containers from the Metrics Server, or nil if no pod reported metrics, and
+// the number of login pods without it.
+func (r *LoginSetReconciler) getCPUUtilization(
+	ctx context.Context,
+	loginset *slinkyv1alpha1.LoginSet,
+	pods []corev1.Pod,
+) (*int64, int32, error) {
+	podMetricsList := &unstructured.UnstructuredList{}
+	podMetricsList.SetGroupVersionKind(podMetricsListGVK)
+	selectorLabels := labels.NewBuilder().WithLoginSelectorLabels(loginset).Build()
+	opts := []client.ListOption{
+		client.InNamespace(loginset.Namespace),
+		client.MatchingLabels(selectorLabels),
+	}
+	if err := r.List(ctx, podMetricsList, opts...); err != nil {
+		return nil, int32(len(pods)), err
+	}
+
+	cpuUsage := make(map[string]resource.Quantity, len(podMetricsList.Items))
+	for _, item := range podMetricsList.Items {
+		usage, err := containerCPUUsage(item, labels.LoginApp)
+		if err != nil {
+			return nil, int32(len(pods)), fmt.Errorf("failed to parse metrics of pod (%s): %w", klog.KObj(&item), err)
+		}
+		if usage != nil {
+			cpuUsage[item.GetName()] = *usage
+		}
+	}
+
+	total, missing := sumCPUUtilization(pods, cpuUsage)
+	return total, missing, nil
+}
+
+// containerCPUUsage returns the CPU usage of the container from the pod
+// metrics, or nil if the container is not reported.
+func containerCPUUsage(podMetrics unstructured.Unstructured, containerName string) (*resource.Quantity, error) {
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		if name != containerName {
+			continue
+		}
+		cpu, _, err := unstructured.NestedString(container, "usage", "cpu")
+		if err != nil {
+			return nil, err
+		}
+		usage, err := resource.ParseQuantity(cpu)
+		if err != nil {
+			return nil, err
+		}
+		return &usage, nil
+	}
+	return nil, nil
+}
+
+// sumCPUUtilization returns the sum of the CPU utilization of the login
+// containers, as percentages of their requested CPU, and the number of pods
+// which are omitted as they have no metrics or CPU requests.
+func sumCPUUtilization(pods []corev1.Pod, cpuUsage map[string]resource.Quantity) (*int64, int32) {
+	var total *int64
+	missing := int32(0)
+	for _, pod := range pods {
+		utilization, ok := podCPUUtilization(pod, cpuUsage)
+		if !ok {
+			missing++
+			continue
+		}
+		total = ptr.To(ptr.Deref(total, 0) + utilization)
+	}
+	return total, missing
+}
+
+// podCPUUtilization returns the CPU utilization of the login container of the
+// pod, as a percentage of its requested CPU.
+func podCPUUtilization(pod corev1.Pod, cpuUsage map[string]resource.Quantity) (int64, bool) {
+	usage, ok := cpuUsage[pod.Name]
+	if !ok {
+		return 0, false
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != labels.LoginApp {
+			continue
+		}
+		request := container.Resources.Requests.Cpu()
+		if request.IsZero() {
+			return 0, false
+		}
+		return usage.MilliValue() * 100 / request.MilliValue(), true
+	}
+	return 0, false
+}
+
+// calculateDesiredReplicas returns the replicas for the load, within the
+// limits. With several targets, the largest number of replicas is used. When
+// no load is known, the current replicas are kept.
+func calculateDesiredReplicas(
+	autoscaling *slinkyv1alpha1.LoginSetAutoscaling,
+	currentReplicas int32,
+	load loginSetLoad,
+) int32 {
+	desiredReplicas := int32(-1)
+	if target := autoscaling.TargetSessionsPerPod; target != nil && load.Sessions != nil {
+		replicas := metricReplicas(float64(*load.Sessions), load.MissingSessions, float64(*target), currentReplicas)
+		desiredReplicas = max(desiredReplicas, replicas)
+	}
+	if target := autoscaling.TargetCPUUtilizationPercentage; target != nil && load.CPUUtilization != nil {
+		replicas := metricReplicas(float64(*load.CPUUtilization), load.MissingCPUUtilization, float64(*target), currentReplicas)
+		desiredReplicas = max(desiredReplicas, replicas)
+	}
+	if desiredReplicas < 0 {
+		desiredReplicas = currentReplicas
+	}
+	return min(max(desiredReplicas, autoscaling.MinReplicasOrDefault()), autoscaling.MaxReplicas)
+}
+
+// metricReplicas returns the replicas for the total of a metric. Like the
+// HorizontalPodAutoscaler, pods without the metric count as zero when scaling
+// up, and as at the target when scaling down, but never cause scaling up.
+func metricReplicas(total float64, missing int32, target float64, currentReplicas int32) int32 {
+	replicas := int32(math.Ceil(total / target))
+	if replicas < currentReplicas && missing > 0 {
+		replicas = min(replicas+missing, currentReplicas)
+	}
+	return replicas
+}
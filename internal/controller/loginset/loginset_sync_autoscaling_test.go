@@ -0,0 +1,401 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package loginset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+)
+
+func Test_calculateDesiredReplicas(t *testing.T) {
+	autoscaling := &slinkyv1alpha1.LoginSetAutoscaling{
+		MinReplicas:                    ptr.To[int32](2),
+		MaxReplicas:                    6,
+		TargetSessionsPerPod:           ptr.To[int32](10),
+		TargetCPUUtilizationPercentage: ptr.To[int32](80),
+	}
+	tests := []struct {
+		name            string
+		autoscaling     *slinkyv1alpha1.LoginSetAutoscaling
+		currentReplicas int32
+		load            loginSetLoad
+		want            int32
+	}{
+		{
+			name:            "Unknown load",
+			autoscaling:     autoscaling,
+			currentReplicas: 3,
+			want:            3,
+		},
+		{
+			name:            "Unknown load, below minimum",
+			autoscaling:     autoscaling,
+			currentReplicas: 1,
+			want:            2,
+		},
+		{
+			name:            "Sessions",
+			autoscaling:     autoscaling,
+			currentReplicas: 2,
+			load:            loginSetLoad{Sessions: ptr.To[int32](31)},
+			want:            4,
+		},
+		{
+			name:            "No sessions",
+			autoscaling:     autoscaling,
+			currentReplicas: 4,
+			load:            loginSetLoad{Sessions: ptr.To[int32](0)},
+			want:            2,
+		},
+		{
+			name:            "CPU",
+			autoscaling:     autoscaling,
+			currentReplicas: 2,
+			load:            loginSetLoad{CPUUtilization: ptr.To[int64](250)},
+			want:            4,
+		},
+		{
+			name:            "Largest of targets",
+			autoscaling:     autoscaling,
+			currentReplicas: 2,
+			load: loginSetLoad{
+				Sessions:       ptr.To[int32](45),
+				CPUUtilization: ptr.To[int64](100),
+			},
+			want: 5,
+		},
+		{
+			name:            "Missing sessions, scale down",
+			autoscaling:     autoscaling,
+			currentReplicas: 4,
+			load:            loginSetLoad{Sessions: ptr.To[int32](1), MissingSessions: 2},
+			want:            3,
+		},
+		{
+			name:            "Missing sessions, no scale up",
+			autoscaling:     autoscaling,
+			currentReplicas: 4,
+			load:            loginSetLoad{Sessions: ptr.To[int32](25), MissingSessions: 3},
+			want:            4,
+		},
+		{
+			name:            "Missing sessions, scale up",
+			autoscaling:     autoscaling,
+			currentReplicas: 2,
+			load:            loginSetLoad{Sessions: ptr.To[int32](41), MissingSessions: 1},
+			want:            5,
+		},
+		{
+			name:            "Missing CPU, scale down",
+			autoscaling:     autoscaling,
+			currentReplicas: 5,
+			load:            loginSetLoad{CPUUtilization: ptr.To[int64](10), MissingCPUUtilization: 1},
+			want:            2,
+		},
+		{
+			name:            "Above maximum",
+			autoscaling:     autoscaling,
+			currentReplicas: 6,
+			load:            loginSetLoad{Sessions: ptr.To[int32](1000)},
+			want:            6,
+		},
+		{
+			name: "Default minimum",
+			autoscaling: &slinkyv1alpha1.LoginSetAutoscaling{
+				MaxReplicas:          4,
+				TargetSessionsPerPod: ptr.To[int32](10),
+			},
+			currentReplicas: 3,
+			load:            loginSetLoad{Sessions: ptr.To[int32](0)},
+			want:            1,
+		},
+		{
+			name: "No target",
+			autoscaling: &slinkyv1alpha1.LoginSetAutoscaling{
+				MaxReplicas: 4,
+			},
+			currentReplicas: 8,
+			load:            loginSetLoad{Sessions: ptr.To[int32](100)},
+			want:            4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateDesiredReplicas(tt.autoscaling, tt.currentReplicas, tt.load); got != tt.want {
+				t.Errorf("calculateDesiredReplicas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sumSessions(t *testing.T) {
+	newPods := func(names ...string) []corev1.Pod {
+		pods := make([]corev1.Pod, 0, len(names))
+		for _, name := range names {
+			pods = append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		return pods
+	}
+	tests := []struct {
+		name        string
+		pods        []corev1.Pod
+		podSessions []slinkyv1alpha1.LoginSetPodSessions
+		want        *int32
+		wantMissing int32
+	}{
+		{
+			name: "Empty",
+			want: nil,
+		},
+		{
+			name: "Without draining",
+			pods: newPods("a", "b"),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 2},
+				{PodName: "b", Sessions: 0},
+				{PodName: "c", Sessions: 5, Draining: true},
+			},
+			want: ptr.To[int32](2),
+		},
+		{
+			name: "Only draining",
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "c", Sessions: 5, Draining: true},
+			},
+			want: nil,
+		},
+		{
+			name: "Not counted",
+			pods: newPods("a", "b", "d"),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 2},
+			},
+			want:        ptr.To[int32](2),
+			wantMissing: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMissing := sumSessions(tt.pods, tt.podSessions)
+			if ptr.Deref(got, -1) != ptr.Deref(tt.want, -1) {
+				t.Errorf("sumSessions() = %v, want %v", ptr.Deref(got, -1), ptr.Deref(tt.want, -1))
+			}
+			if gotMissing != tt.wantMissing {
+				t.Errorf("sumSessions() missing = %v, want %v", gotMissing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func Test_sumCPUUtilization(t *testing.T) {
+	newPod := func(name, request string) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: labels.LoginApp},
+				},
+			},
+		}
+		if request != "" {
+			pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(request),
+			}
+		}
+		return pod
+	}
+	newPodMetrics := func(name, container, cpu string) unstructured.Unstructured {
+		return unstructured.Unstructured{
+			Object: map[string]any{
+				"metadata": map[string]any{"name": name},
+				"containers": []any{
+					map[string]any{
+						"name":  container,
+						"usage": map[string]any{"cpu": cpu, "memory": "10Mi"},
+					},
+				},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		newPod("a", "500m"),
+		newPod("b", "1"),
+		newPod("no-request", ""),
+		newPod("no-metrics", "1"),
+	}
+	podMetrics := []unstructured.Unstructured{
+		newPodMetrics("a", labels.LoginApp, "250000000n"),
+		newPodMetrics("b", labels.LoginApp, "900m"),
+		newPodMetrics("no-request", labels.LoginApp, "1"),
+		newPodMetrics("other", "sidecar", "1"),
+	}
+
+	cpuUsage := map[string]resource.Quantity{}
+	for _, item := range podMetrics {
+		usage, err := containerCPUUsage(item, labels.LoginApp)
+		if err != nil {
+			t.Fatalf("containerCPUUsage() error = %v", err)
+		}
+		if usage != nil {
+			cpuUsage[item.GetName()] = *usage
+		}
+	}
+	if _, ok := cpuUsage["other"]; ok {
+		t.Errorf("containerCPUUsage() reported another container")
+	}
+
+	got, missing := sumCPUUtilization(pods, cpuUsage)
+	if want := int64(50 + 90); ptr.Deref(got, -1) != want {
+		t.Errorf("sumCPUUtilization() = %v, want %v", ptr.Deref(got, -1), want)
+	}
+	if missing != 2 {
+		t.Errorf("sumCPUUtilization() missing = %v, want 2", missing)
+	}
+	if got, _ := sumCPUUtilization(pods[2:], cpuUsage); got != nil {
+		t.Errorf("sumCPUUtilization() = %v, want nil", *got)
+	}
+}
+
+func TestLoginSetReconciler_syncAutoscaling(t *testing.T) {
+	newLoginSet := func(replicas int32, lastScaleTime *metav1.Time) *slinkyv1alpha1.LoginSet {
+		return &slinkyv1alpha1.LoginSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "login",
+			},
+			Spec: slinkyv1alpha1.LoginSetSpec{
+				Replicas: ptr.To(replicas),
+				Autoscaling: &slinkyv1alpha1.LoginSetAutoscaling{
+					MaxReplicas:          4,
+					TargetSessionsPerPod: ptr.To[int32](10),
+					ScaleDownDelay:       &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			Status: slinkyv1alpha1.LoginSetStatus{
+				LastScaleTime: lastScaleTime,
+			},
+		}
+	}
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      name,
+				Labels:    labels.NewBuilder().WithLoginSelectorLabels(newLoginSet(1, nil)).Build(),
+			},
+		}
+	}
+	recently := ptr.To(metav1.NewTime(time.Now().Add(-time.Minute)))
+	longAgo := ptr.To(metav1.NewTime(time.Now().Add(-time.Hour)))
+	tests := []struct {
+		name           string
+		loginset       *slinkyv1alpha1.LoginSet
+		pods           []runtime.Object
+		podSessions    []slinkyv1alpha1.LoginSetPodSessions
+		scaleDownSince *time.Time
+		wantReplicas   int32
+		wantScaleTime  bool
+	}{
+		{
+			name:     "Scale up",
+			loginset: newLoginSet(1, recently),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 25},
+			},
+			wantReplicas:  3,
+			wantScaleTime: true,
+		},
+		{
+			name:     "Scale down, delayed",
+			loginset: newLoginSet(3, recently),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 1},
+			},
+			wantReplicas: 3,
+		},
+		{
+			name:     "Scale down",
+			loginset: newLoginSet(3, longAgo),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 1},
+			},
+			wantReplicas:  1,
+			wantScaleTime: true,
+		},
+		{
+			name:     "Scale down, never scaled",
+			loginset: newLoginSet(3, nil),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 1},
+			},
+			wantReplicas: 3,
+		},
+		{
+			name:     "Scale down, never scaled, delay elapsed",
+			loginset: newLoginSet(3, nil),
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 1},
+			},
+			scaleDownSince: ptr.To(longAgo.Time),
+			wantReplicas:   1,
+			wantScaleTime:  true,
+		},
+		{
+			name:     "Scale down, sessions not counted",
+			loginset: newLoginSet(3, longAgo),
+			pods:     []runtime.Object{newPod("a"), newPod("b"), newPod("c")},
+			podSessions: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "a", Sessions: 1},
+			},
+			wantReplicas: 3,
+		},
+		{
+			name:         "Unknown load",
+			loginset:     newLoginSet(2, nil),
+			wantReplicas: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient(append(tt.pods, tt.loginset.DeepCopy())...)
+			r := newLoginSetController(c, nil)
+			if tt.scaleDownSince != nil {
+				r.scaleDownTimes = map[string]time.Time{tt.loginset.Key().String(): *tt.scaleDownSince}
+			}
+			loginset := tt.loginset.DeepCopy()
+			if err := r.syncAutoscaling(context.Background(), loginset, tt.podSessions); err != nil {
+				t.Fatalf("syncAutoscaling() error = %v", err)
+			}
+			_ = durationStore.Pop(loginset.Key().String())
+
+			stored := &slinkyv1alpha1.LoginSet{}
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(loginset), stored); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := ptr.Deref(stored.Spec.Replicas, 0); got != tt.wantReplicas {
+				t.Errorf("stored replicas = %v, want %v", got, tt.wantReplicas)
+			}
+			if got := ptr.Deref(loginset.Spec.Replicas, 0); got != tt.wantReplicas {
+				t.Errorf("replicas = %v, want %v", got, tt.wantReplicas)
+			}
+			scaled := !loginset.Status.LastScaleTime.Equal(tt.loginset.Status.LastScaleTime)
+			if scaled != tt.wantScaleTime {
+				t.Errorf("LastScaleTime = %v, want updated %v", loginset.Status.LastScaleTime, tt.wantScaleTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package loginset
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+const (
+	// SessionsRefreshInterval is the time between counting the SSH sessions of
+	// the login pods.
+	SessionsRefreshInterval = 30 * time.Second
+)
+
+// getLoginPods returns the login pods which are targeted by the selector.
+func (r *LoginSetReconciler) getLoginPods(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) ([]corev1.Pod, error) {
+	selectorLabels := labels.NewBuilder().WithLoginSelectorLabels(loginset).Build()
+	return r.listPods(ctx, loginset, selectorLabels)
+}
+
+// getDrainingPods returns the old login pods which wait for their SSH sessions
+// to end.
+func (r *LoginSetReconciler) getDrainingPods(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) ([]corev1.Pod, error) {
+	drainingLabels := map[string]string{
+		slinkyv1alpha1.LabelLoginSetPodDraining: loginset.Name,
+	}
+	return r.listPods(ctx, loginset, drainingLabels)
+}
+
+func (r *LoginSetReconciler) listPods(ctx context.Context, loginset *slinkyv1alpha1.LoginSet, matchLabels map[string]string) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	opts := []client.ListOption{
+		client.InNamespace(loginset.Namespace),
+		client.MatchingLabels(matchLabels),
+	}
+	if err := r.List(ctx, podList, opts...); err != nil {
+		return nil, err
+	}
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	slices.SortFunc(pods, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return pods, nil
+}
+
+// syncSessions counts the SSH sessions of the draining login pods, and of all
+// login pods with the SessionDrain update strategy or autoscaling on sessions.
+// Pods whose sessions could not be counted are omitted.
+func (r *LoginSetReconciler) syncSessions(ctx context.Context, loginset *slinkyv1alpha1.LoginSet) ([]slinkyv1alpha1.LoginSetPodSessions, error) {
+	logger := log.FromContext(ctx)
+
+	pods, err := r.getDrainingPods(ctx, loginset)
+	if err != nil {
+		return nil, err
+	}
+	if loginset.TracksSessions() {
+		loginPods, err := r.getLoginPods(ctx, loginset)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, loginPods...)
+		durationStore.Push(loginset.Key().String(), SessionsRefreshInterval)
+	}
+
+	podSessions := make([]slinkyv1alpha1.LoginSetPodSessions, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		sessions, err := r.sessionControl.CountSessions(ctx, pod)
+		if err != nil {
+			logger.V(1).Info("failed to count SSH sessions, skipping...",
+				"pod", klog.KObj(pod), "error", err)
+			continue
+		}
+		draining := isPodDraining(pod)
+		podSessions = append(podSessions, slinkyv1alpha1.LoginSetPodSessions{
+			PodName:  pod.Name,
+			Sessions: sessions,
+			Draining: draining,
+		})
+		if draining {
+			continue
+		}
+		// Prefer deleting login pods with fewer SSH sessions when scaling in.
+		if err := r.syncPodDeletionCost(ctx, pod, sessions); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(podSessions, func(a, b slinkyv1alpha1.LoginSetPodSessions) int {
+		return strings.Compare(a.PodName, b.PodName)
+	})
+
+	return podSessions, nil
+}
+
+func (r *LoginSetReconciler) syncPodDeletionCost(ctx context.Context, pod *corev1.Pod, sessions int32) error {
+	cost := strconv.Itoa(int(sessions))
+	if pod.Annotations[corev1.PodDeletionCost] == cost {
+		return nil
+	}
+	toUpdate := pod.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[corev1.PodDeletionCost] = cost
+	if err := r.Patch(ctx, toUpdate, client.MergeFrom(pod)); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to patch pod (%s): %w", klog.KObj(pod), err)
+	}
+	return nil
+}
+
+// syncDeployment drains the old login pods, then updates the Deployment. Pods
+// are drained first, so the rolling update never deletes an old pod with SSH
+// sessions. The drained pods are reattached if the update fails, so they are
+// never left detached from an unchanged Deployment.
+func (r *LoginSetReconciler) syncDeployment(
+	ctx context.Context,
+	loginset *slinkyv1alpha1.LoginSet,
+	deployment *appsv1.Deployment,
+	podSessions []slinkyv1alpha1.LoginSetPodSessions,
+) error {
+	drained, drainErr := r.drainOldPods(ctx, loginset, deployment, podSessions)
+	if drainErr == nil {
+		if err := objectutils.SyncObject(r.Client, ctx, deployment, true); err != nil {
+			return utilerrors.NewAggregate([]error{
+				fmt.Errorf("failed to sync object (%s): %w", klog.KObj(deployment), err),
+				r.reattachPods(ctx, loginset, drained),
+			})
+		}
+		return nil
+	}
+	return utilerrors.NewAggregate([]error{
+		fmt.Errorf("failed to drain old pods: %w", drainErr),
+		r.reattachPods(ctx, loginset, drained),
+	})
+}
+
+// drainOldPods detaches the old login pods with active SSH sessions, whose
+// template hash differs from the one of the updated Deployment, from the
+// Deployment and Service. They no longer receive new connections, and are
+// deleted once their SSH sessions have ended. Pods whose SSH sessions could not
+// be counted are assumed to have some. It returns the detached pods.
+func (r *LoginSetReconciler) drainOldPods(
+	ctx context.Context,
+	loginset *slinkyv1alpha1.LoginSet,
+	deployment *appsv1.Deployment,
+	podSessions []slinkyv1alpha1.LoginSetPodSessions,
+) ([]*corev1.Pod, error) {
+	logger := log.FromContext(ctx)
+
+	if !loginset.HasSessionDrain() {
+		return nil, nil
+	}
+
+	templateHash := deployment.Spec.Template.Annotations[builder.AnnotationLoginTemplateHash]
+	sessionsByPod := make(map[string]int32, len(podSessions))
+	for _, s := range podSessions {
+		sessionsByPod[s.PodName] = s.Sessions
+	}
+
+	pods, err := r.getLoginPods(ctx, loginset)
+	if err != nil {
+		return nil, err
+	}
+	drained := []*corev1.Pod{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Annotations[builder.AnnotationLoginTemplateHash] == templateHash {
+			continue
+		}
+		sessions, counted := sessionsByPod[pod.Name]
+		if pod.Status.Phase != corev1.PodRunning || (counted && sessions == 0) {
+			// The Deployment replaces it.
+			continue
+		}
+		logger.Info("Draining old login pod with active SSH sessions",
+			"pod", klog.KObj(pod), "sessions", sessions, "counted", counted)
+		detached, err := r.detachPod(ctx, loginset, pod)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return drained, err
+		}
+		drained = append(drained, detached)
+		if counted {
+			r.eventRecorder.Eventf(loginset, corev1.EventTypeNormal, "DrainingPod",
+				"Draining pod %s with %d active SSH sessions", pod.Name, sessions)
+		} else {
+			r.eventRecorder.Eventf(loginset, corev1.EventTypeNormal, "DrainingPod",
+				"Draining pod %s whose SSH sessions could not be counted", pod.Name)
+		}
+	}
+
+	return drained, nil
+}
+
+// detachPod removes the selector labels of the pod, so it is released by the
+// ReplicaSet and removed from the Service endpoints. The LoginSet becomes an
+// owner of the pod, so it is garbage collected with the LoginSet. It returns the
+// detached pod.
+func (r *LoginSetReconciler) detachPod(ctx context.Context, loginset *slinkyv1alpha1.LoginSet, pod *corev1.Pod) (*corev1.Pod, error) {
+	selectorLabels := labels.NewBuilder().WithLoginSelectorLabels(loginset).Build()
+
+	toUpdate := pod.DeepCopy()
+	for key := range selectorLabels {
+		delete(toUpdate.Labels, key)
+	}
+	toUpdate.Labels[slinkyv1alpha1.LabelLoginSetPodDraining] = loginset.Name
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[slinkyv1alpha1.AnnotationLoginSetPodDrainStart] = time.Now().Format(time.RFC3339)
+	delete(toUpdate.Annotations, corev1.PodDeletionCost)
+	if err := controllerutil.SetOwnerReference(loginset, toUpdate, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner: %w", err)
+	}
+
+	if err := r.Patch(ctx, toUpdate, client.MergeFrom(pod)); err != nil {
+		return nil, fmt.Errorf("failed to patch pod (%s): %w", klog.KObj(pod), err)
+	}
+	return toUpdate, nil
+}
+
+// reattachPods reverts detachPod on the pods, so they are adopted by the
+// ReplicaSet again.
+func (r *LoginSetReconciler) reattachPods(ctx context.Context, loginset *slinkyv1alpha1.LoginSet, pods []*corev1.Pod) error {
+	logger := log.FromContext(ctx)
+	selectorLabels := labels.NewBuilder().WithLoginSelectorLabels(loginset).Build()
+
+	errs := []error{}
+	for _, pod := range pods {
+		logger.Info("Reattaching draining login pod", "pod", klog.KObj(pod))
+		toUpdate := pod.DeepCopy()
+		for key, value := range selectorLabels {
+			toUpdate.Labels[key] = value
+		}
+		delete(toUpdate.Labels, slinkyv1alpha1.LabelLoginSetPodDraining)
+		delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationLoginSetPodDrainStart)
+		if err := controllerutil.RemoveOwnerReference(loginset, toUpdate, r.Scheme); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove owner: %w", err))
+			continue
+		}
+		if err := r.Patch(ctx, toUpdate, client.MergeFrom(pod)); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to patch pod (%s): %w", klog.KObj(pod), err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncDrainingPods deletes the draining login pods once their SSH sessions have
+// ended, or their drain timeout expired.
+func (r *LoginSetReconciler) syncDrainingPods(
+	ctx context.Context,
+	loginset *slinkyv1alpha1.LoginSet,
+	podSessions []slinkyv1alpha1.LoginSetPodSessions,
+) error {
+	logger := log.FromContext(ctx)
+
+	sessionsByPod := make(map[string]int32, len(podSessions))
+	for _, s := range podSessions {
+		sessionsByPod[s.PodName] = s.Sessions
+	}
+
+	pods, err := r.getDrainingPods(ctx, loginset)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range pods {
+		pod := &pods[i]
+		deadline := drainDeadline(loginset, pod)
+		sessions, counted := sessionsByPod[pod.Name]
+		switch {
+		case pod.Status.Phase != corev1.PodRunning:
+			logger.Info("Deleting draining login pod which is not running", "pod", klog.KObj(pod))
+		case counted && sessions == 0:
+			logger.Info("Deleting draining login pod without SSH sessions", "pod", klog.KObj(pod))
+		case !loginset.HasSessionDrain():
+			logger.Info("Deleting draining login pod, the update strategy is no longer SessionDrain",
+				"pod", klog.KObj(pod), "sessions", sessions)
+		case !now.Before(deadline):
+			logger.Info("Deleting draining login pod, the drain timeout expired",
+				"pod", klog.KObj(pod), "sessions", sessions)
+			r.eventRecorder.Eventf(loginset, corev1.EventTypeWarning, "DrainTimeout",
+				"Deleting pod %s with %d active SSH sessions, the drain timeout expired", pod.Name, sessions)
+		default:
+			durationStore.Push(loginset.Key().String(), min(deadline.Sub(now), SessionsRefreshInterval))
+			continue
+		}
+		if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod (%s): %w", klog.KObj(pod), err)
+		}
+	}
+
+	return nil
+}
+
+// drainDeadline returns the time after which the draining pod is deleted,
+// regardless of its SSH sessions.
+func drainDeadline(loginset *slinkyv1alpha1.LoginSet, pod *corev1.Pod) time.Time {
+	drainStart, err := time.Parse(time.RFC3339, pod.Annotations[slinkyv1alpha1.AnnotationLoginSetPodDrainStart])
+	if err != nil {
+		drainStart = pod.CreationTimestamp.Time
+	}
+	return drainStart.Add(loginset.DrainTimeout())
+}
+
+func isPodDraining(pod *corev1.Pod) bool {
+	_, ok := pod.Labels[slinkyv1alpha1.LabelLoginSetPodDraining]
+	return ok
+}
@@ -0,0 +1,393 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package loginset
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/loginset/sessioncontrol"
+)
+
+// fakeSessionControl returns the SSH sessions of pods by name.
+type fakeSessionControl map[string]int32
+
+func (f fakeSessionControl) CountSessions(ctx context.Context, pod *corev1.Pod) (int32, error) {
+	sessions, ok := f[pod.Name]
+	if !ok {
+		return 0, errors.New("exec failed")
+	}
+	return sessions, nil
+}
+
+var _ sessioncontrol.SessionControlInterface = fakeSessionControl{}
+
+func newLoginSetController(c client.Client, sessions fakeSessionControl) *LoginSetReconciler {
+	return &LoginSetReconciler{
+		Client:         c,
+		Scheme:         c.Scheme(),
+		builder:        builder.New(c),
+		sessionControl: sessions,
+		eventRecorder:  record.NewFakeRecorder(10),
+	}
+}
+
+func newSessionDrainLoginSet(name string) *slinkyv1alpha1.LoginSet {
+	return &slinkyv1alpha1.LoginSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+			UID:       "loginset-uid",
+		},
+		Spec: slinkyv1alpha1.LoginSetSpec{
+			UpdateStrategy: slinkyv1alpha1.LoginSetUpdateStrategy{
+				Type: slinkyv1alpha1.SessionDrainLoginSetStrategyType,
+				SessionDrain: &slinkyv1alpha1.SessionDrainLoginSetStrategy{
+					DrainTimeout: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+		},
+	}
+}
+
+func newLoginPod(loginset *slinkyv1alpha1.LoginSet, name, templateHash string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: loginset.Namespace,
+			Name:      name,
+			Labels:    labels.NewBuilder().WithLoginLabels(loginset).Build(),
+			Annotations: map[string]string{
+				builder.AnnotationLoginTemplateHash: templateHash,
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func newDrainingPod(loginset *slinkyv1alpha1.LoginSet, name string, drainStart time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: loginset.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				slinkyv1alpha1.LabelLoginSetPodDraining: loginset.Name,
+			},
+			Annotations: map[string]string{
+				slinkyv1alpha1.AnnotationLoginSetPodDrainStart: drainStart.Format(time.RFC3339),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func TestLoginSetReconciler_syncSessions(t *testing.T) {
+	loginset := newSessionDrainLoginSet("login")
+	rollingUpdate := loginset.DeepCopy()
+	rollingUpdate.Spec.UpdateStrategy = slinkyv1alpha1.LoginSetUpdateStrategy{}
+	pending := newLoginPod(loginset, "login-pending", "new")
+	pending.Status.Phase = corev1.PodPending
+	objects := []runtime.Object{
+		newLoginPod(loginset, "login-a", "new"),
+		newLoginPod(loginset, "login-b", "new"),
+		newLoginPod(loginset, "login-unknown", "new"),
+		pending,
+		newDrainingPod(loginset, "login-old", time.Now()),
+	}
+	sessions := fakeSessionControl{
+		"login-a":   3,
+		"login-b":   0,
+		"login-old": 1,
+	}
+	tests := []struct {
+		name     string
+		loginset *slinkyv1alpha1.LoginSet
+		want     []slinkyv1alpha1.LoginSetPodSessions
+		wantCost map[string]string
+	}{
+		{
+			name:     "SessionDrain",
+			loginset: loginset,
+			want: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "login-a", Sessions: 3},
+				{PodName: "login-b", Sessions: 0},
+				{PodName: "login-old", Sessions: 1, Draining: true},
+			},
+			wantCost: map[string]string{
+				"login-a":   "3",
+				"login-b":   "0",
+				"login-old": "",
+			},
+		},
+		{
+			name:     "RollingUpdate",
+			loginset: rollingUpdate,
+			want: []slinkyv1alpha1.LoginSetPodSessions{
+				{PodName: "login-old", Sessions: 1, Draining: true},
+			},
+			wantCost: map[string]string{
+				"login-a":   "",
+				"login-old": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient(objects...)
+			r := newLoginSetController(c, sessions)
+			got, err := r.syncSessions(context.Background(), tt.loginset)
+			if err != nil {
+				t.Fatalf("syncSessions() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("syncSessions() (-want,+got):\n%s", diff)
+			}
+			for name, want := range tt.wantCost {
+				pod := &corev1.Pod{}
+				key := client.ObjectKey{Namespace: loginset.Namespace, Name: name}
+				if err := c.Get(context.Background(), key, pod); err != nil {
+					t.Fatalf("Get(%s) error = %v", key, err)
+				}
+				if got := pod.Annotations[corev1.PodDeletionCost]; got != want {
+					t.Errorf("pod %s deletion cost = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginSetReconciler_drainOldPods(t *testing.T) {
+	loginset := newSessionDrainLoginSet("login")
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						builder.AnnotationLoginTemplateHash: "new",
+					},
+				},
+			},
+		},
+	}
+	objects := []runtime.Object{
+		newLoginPod(loginset, "old-active", "old"),
+		newLoginPod(loginset, "old-idle", "old"),
+		newLoginPod(loginset, "old-unknown", "old"),
+		newLoginPod(loginset, "new-active", "new"),
+	}
+	podSessions := []slinkyv1alpha1.LoginSetPodSessions{
+		{PodName: "old-active", Sessions: 2},
+		{PodName: "old-idle", Sessions: 0},
+		{PodName: "new-active", Sessions: 5},
+	}
+	c := fake.NewFakeClient(objects...)
+	r := newLoginSetController(c, nil)
+	drained, err := r.drainOldPods(context.Background(), loginset, deployment, podSessions)
+	if err != nil {
+		t.Fatalf("drainOldPods() error = %v", err)
+	}
+	if len(drained) != 2 {
+		t.Errorf("drainOldPods() drained %d pods, want 2", len(drained))
+	}
+
+	// The SSH sessions of old-unknown could not be counted.
+	wantDraining := map[string]bool{
+		"old-active":  true,
+		"old-idle":    false,
+		"old-unknown": true,
+		"new-active":  false,
+	}
+	for name, want := range wantDraining {
+		pod := &corev1.Pod{}
+		key := client.ObjectKey{Namespace: loginset.Namespace, Name: name}
+		if err := c.Get(context.Background(), key, pod); err != nil {
+			t.Fatalf("Get(%s) error = %v", key, err)
+		}
+		if got := isPodDraining(pod); got != want {
+			t.Errorf("pod %s draining = %v, want %v", name, got, want)
+		}
+		if !want {
+			continue
+		}
+		for key := range labels.NewBuilder().WithLoginSelectorLabels(loginset).Build() {
+			if _, ok := pod.Labels[key]; ok {
+				t.Errorf("pod %s has selector label %s", name, key)
+			}
+		}
+		if _, err := time.Parse(time.RFC3339, pod.Annotations[slinkyv1alpha1.AnnotationLoginSetPodDrainStart]); err != nil {
+			t.Errorf("pod %s drain start error = %v", name, err)
+		}
+		if len(pod.OwnerReferences) != 1 || pod.OwnerReferences[0].UID != loginset.UID {
+			t.Errorf("pod %s ownerReferences = %v", name, pod.OwnerReferences)
+		}
+	}
+}
+
+func TestLoginSetReconciler_syncDeployment(t *testing.T) {
+	loginset := newSessionDrainLoginSet("login")
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: loginset.Namespace,
+			Name:      loginset.Name,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						builder.AnnotationLoginTemplateHash: "new",
+					},
+				},
+			},
+		},
+	}
+	podSessions := []slinkyv1alpha1.LoginSetPodSessions{
+		{PodName: "old-active", Sessions: 2},
+		{PodName: "new-active", Sessions: 5},
+	}
+	tests := []struct {
+		name          string
+		deploymentErr error
+		want          []string
+		wantDraining  bool
+		wantErr       bool
+	}{
+		{
+			name:         "Deployment is updated after draining",
+			want:         []string{"Pod/old-active", "Deployment/login"},
+			wantDraining: true,
+		},
+		{
+			name:          "Deployment update fails",
+			deploymentErr: errors.New("update failed"),
+			want:          []string{"Pod/old-active", "Pod/old-active"},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Record the order in which objects are written.
+			got := []string{}
+			record := func(obj client.Object) error {
+				switch obj.(type) {
+				case *appsv1.Deployment:
+					if tt.deploymentErr != nil {
+						return tt.deploymentErr
+					}
+					got = append(got, "Deployment/"+obj.GetName())
+				case *corev1.Pod:
+					got = append(got, "Pod/"+obj.GetName())
+				}
+				return nil
+			}
+			c := fake.NewClientBuilder().
+				WithRuntimeObjects(
+					newLoginPod(loginset, "old-active", "old"),
+					newLoginPod(loginset, "new-active", "new"),
+				).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if err := record(obj); err != nil {
+							return err
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if err := record(obj); err != nil {
+							return err
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+			r := newLoginSetController(c, nil)
+			err := r.syncDeployment(context.Background(), loginset, deployment.DeepCopy(), podSessions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("syncDeployment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("syncDeployment() writes (-want,+got):\n%s", diff)
+			}
+			pod := &corev1.Pod{}
+			key := client.ObjectKey{Namespace: loginset.Namespace, Name: "old-active"}
+			if err := c.Get(context.Background(), key, pod); err != nil {
+				t.Fatalf("Get(%s) error = %v", key, err)
+			}
+			if got := isPodDraining(pod); got != tt.wantDraining {
+				t.Errorf("pod old-active draining = %v, want %v", got, tt.wantDraining)
+			}
+			if !tt.wantDraining {
+				for key, value := range labels.NewBuilder().WithLoginSelectorLabels(loginset).Build() {
+					if pod.Labels[key] != value {
+						t.Errorf("pod old-active label %s = %q, want %q", key, pod.Labels[key], value)
+					}
+				}
+				if len(pod.OwnerReferences) != 0 {
+					t.Errorf("pod old-active ownerReferences = %v, want none", pod.OwnerReferences)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginSetReconciler_syncDrainingPods(t *testing.T) {
+	loginset := newSessionDrainLoginSet("login")
+	now := time.Now()
+	notRunning := newDrainingPod(loginset, "not-running", now)
+	notRunning.Status.Phase = corev1.PodFailed
+	objects := []runtime.Object{
+		newDrainingPod(loginset, "idle", now),
+		newDrainingPod(loginset, "active", now),
+		newDrainingPod(loginset, "unknown", now),
+		newDrainingPod(loginset, "expired", now.Add(-2*time.Hour)),
+		notRunning,
+	}
+	podSessions := []slinkyv1alpha1.LoginSetPodSessions{
+		{PodName: "idle", Sessions: 0, Draining: true},
+		{PodName: "active", Sessions: 1, Draining: true},
+		{PodName: "expired", Sessions: 1, Draining: true},
+	}
+	c := fake.NewFakeClient(objects...)
+	r := newLoginSetController(c, nil)
+	if err := r.syncDrainingPods(context.Background(), loginset, podSessions); err != nil {
+		t.Fatalf("syncDrainingPods() error = %v", err)
+	}
+	if got := durationStore.Pop(loginset.Key().String()); got <= 0 || got > SessionsRefreshInterval {
+		t.Errorf("requeue after = %v, want (0, %v]", got, SessionsRefreshInterval)
+	}
+
+	wantDeleted := map[string]bool{
+		"idle":        true,
+		"active":      false,
+		"unknown":     false,
+		"expired":     true,
+		"not-running": true,
+	}
+	for name, want := range wantDeleted {
+		pod := &corev1.Pod{}
+		key := client.ObjectKey{Namespace: loginset.Namespace, Name: name}
+		err := c.Get(context.Background(), key, pod)
+		if got := apierrors.IsNotFound(err); got != want {
+			t.Errorf("pod %s deleted = %v, want %v (error = %v)", name, got, want, err)
+		}
+	}
+}
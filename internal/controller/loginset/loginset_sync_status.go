@@ -25,6 +25,7 @@ import (
 func (r *LoginSetReconciler) syncStatus(
 	ctx context.Context,
 	loginset *slinkyv1alpha1.LoginSet,
+	podSessions []slinkyv1alpha1.LoginSetPodSessions,
 ) error {
 	logger := log.FromContext(ctx)
 
@@ -37,9 +38,17 @@ func (r *LoginSetReconciler) syncStatus(
 	}
 
 	newStatus := &slinkyv1alpha1.LoginSetStatus{
-		Replicas:   replicaStatus.Replicas,
-		Selector:   selector.String(),
-		Conditions: []metav1.Condition{},
+		Replicas:         replicaStatus.Replicas,
+		DrainingReplicas: replicaStatus.DrainingReplicas,
+		Selector:         selector.String(),
+		LastScaleTime:    loginset.Status.LastScaleTime,
+		Conditions:       []metav1.Condition{},
+	}
+	if loginset.TracksSessions() {
+		newStatus.PodSessions = podSessions
+		for _, s := range podSessions {
+			newStatus.Sessions += s.Sessions
+		}
 	}
 	newStatus.Conditions = append(newStatus.Conditions, loginset.Status.Conditions...)
 
@@ -58,7 +67,8 @@ func (r *LoginSetReconciler) syncStatus(
 }
 
 type replicaStatus struct {
-	Replicas int32
+	Replicas         int32
+	DrainingReplicas int32
 }
 
 // calculateReplicaStatus will calculate the status of the given pods.
@@ -72,8 +82,14 @@ func (r *LoginSetReconciler) calculateReplicaStatus(
 		return replicaStatus{}, err
 	}
 
+	drainingPods, err := r.getDrainingPods(ctx, loginset)
+	if err != nil {
+		return replicaStatus{}, err
+	}
+
 	status := replicaStatus{
-		Replicas: deployment.Status.Replicas,
+		Replicas:         deployment.Status.Replicas,
+		DrainingReplicas: int32(len(drainingPods)),
 	}
 
 	return status, nil
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package sessioncontrol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+)
+
+type SessionControlInterface interface {
+	// CountSessions returns the number of active SSH sessions of the login pod.
+	CountSessions(ctx context.Context, pod *corev1.Pod) (int32, error)
+}
+
+// realSessionControl is the default implementation of SessionControlInterface.
+type realSessionControl struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// CountSessions implements SessionControlInterface.
+func (r *realSessionControl) CountSessions(ctx context.Context, pod *corev1.Pod) (int32, error) {
+	req := r.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: labels.LoginApp,
+			Command:   countSessionsCommand(builder.LoginPort),
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create executor for pod (%s): %w", klog.KObj(pod), err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to count sessions of pod (%s): %w: %s",
+			klog.KObj(pod), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseSessions(stdout.String())
+}
+
+// countSessionsCommand returns a command which counts the established TCP
+// connections to the SSH port, from the kernel tables of the pod network.
+func countSessionsCommand(port int) []string {
+	script := fmt.Sprintf(`cat /proc/net/tcp /proc/net/tcp6 2>/dev/null | `+
+		`awk '$2 ~ /:%04X$/ && $4 == "01" { n++ } END { print n+0 }'`, port)
+	return []string{"sh", "-c", script}
+}
+
+// parseSessions parses the output of countSessionsCommand.
+func parseSessions(out string) (int32, error) {
+	sessions, err := strconv.ParseInt(strings.TrimSpace(out), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse sessions: %w", err)
+	}
+	return int32(sessions), nil
+}
+
+var _ SessionControlInterface = &realSessionControl{}
+
+func NewSessionControl(config *rest.Config) SessionControlInterface {
+	return &realSessionControl{
+		config:    config,
+		clientset: kubernetes.NewForConfigOrDie(config),
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package sessioncontrol
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func Test_countSessionsCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		port int
+		want string
+	}{
+		{
+			name: "SSH",
+			port: 22,
+			want: ":0016$",
+		},
+		{
+			name: "High port",
+			port: 2222,
+			want: ":08AE$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countSessionsCommand(tt.port)
+			if len(got) != 3 || got[0] != "sh" || got[1] != "-c" {
+				t.Fatalf("countSessionsCommand() = %v", got)
+			}
+			if !strings.Contains(got[2], tt.want) {
+				t.Errorf("countSessionsCommand() = %v, want %v", got[2], tt.want)
+			}
+			if _, err := exec.LookPath("awk"); err != nil {
+				return
+			}
+			out, err := exec.Command(got[0], got[1:]...).Output()
+			if err != nil {
+				t.Fatalf("exec countSessionsCommand() error = %v", err)
+			}
+			if _, err := parseSessions(string(out)); err != nil {
+				t.Errorf("parseSessions() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_parseSessions(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    int32
+		wantErr bool
+	}{
+		{
+			name: "None",
+			out:  "0\n",
+			want: 0,
+		},
+		{
+			name: "Some",
+			out:  "12\n",
+			want: 12,
+		},
+		{
+			name:    "Empty",
+			out:     "",
+			wantErr: true,
+		},
+		{
+			name:    "Error",
+			out:     "sh: awk: not found\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSessions(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSessions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseSessions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
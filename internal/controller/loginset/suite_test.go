@@ -81,7 +81,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
-	err = NewReconciler(k8sManager.GetClient()).SetupWithManager(k8sManager)
+	err = NewReconciler(k8sManager.GetClient(), k8sManager.GetConfig()).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	go func() {
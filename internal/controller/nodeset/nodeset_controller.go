@@ -62,6 +62,8 @@ var (
 
 	onceBackoffGC     sync.Once
 	failedPodsBackoff = flowcontrol.NewBackOff(1*time.Second, 15*time.Minute)
+	// slurmClientBackoff paces the resyncs of NodeSets without a Slurm client.
+	slurmClientBackoff = flowcontrol.NewBackOff(5*time.Second, 5*time.Minute)
 )
 
 // NodeSetReconciler reconciles a NodeSet object
@@ -85,6 +87,7 @@ type NodeSetReconciler struct {
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=nodesets/finalizers,verbs=update
 //+kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
@@ -103,6 +106,7 @@ func (r *NodeSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 
 	onceBackoffGC.Do(func() {
 		go wait.Until(failedPodsBackoff.GC, BackoffGCInterval, ctx.Done())
+		go wait.Until(slurmClientBackoff.GC, BackoffGCInterval, ctx.Done())
 	})
 
 	startTime := time.Now()
@@ -137,7 +141,7 @@ func (r *NodeSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.refResolver = refresolver.New(r.Client)
 	r.historyControl = historycontrol.NewHistoryControl(r.Client)
 	r.podControl = podcontrol.NewPodControl(r.Client, r.eventRecorder)
-	r.slurmControl = slurmcontrol.NewSlurmControl(r.ClientMap)
+	r.slurmControl = slurmcontrol.NewSlurmControl(r.ClientMap, r.eventRecorder)
 	r.expectations = kubecontroller.NewUIDTrackingControllerExpectations(kubecontroller.NewControllerExpectations())
 	podEventHandler := &podEventHandler{
 		Reader:       mgr.GetCache(),
@@ -161,6 +165,10 @@ func (r *NodeSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			Reader:      r.Client,
 			refResolver: r.refResolver,
 		}).
+		Watches(&corev1.ConfigMap{}, &configMapEventHandler{
+			Reader:      r.Client,
+			refResolver: r.refResolver,
+		}).
 		Watches(&corev1.Secret{}, &secretEventHandler{
 			Reader:      r.Client,
 			refResolver: r.refResolver,
@@ -192,7 +200,7 @@ func NewReconciler(c client.Client, cm *clientmap.ClientMap, ec chan event.Gener
 		refResolver:    refresolver.New(c),
 		historyControl: historycontrol.NewHistoryControl(c),
 		podControl:     podcontrol.NewPodControl(c, er),
-		slurmControl:   slurmcontrol.NewSlurmControl(cm),
+		slurmControl:   slurmcontrol.NewSlurmControl(cm, er),
 		eventRecorder:  er,
 		expectations:   kubecontroller.NewUIDTrackingControllerExpectations(kubecontroller.NewControllerExpectations()),
 	}
@@ -36,6 +36,7 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/podinfo"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmversion"
 )
 
 var _ handler.EventHandler = &podEventHandler{}
@@ -387,12 +388,13 @@ func (e *controllerEventHandler) enqueueRequest(
 }
 
 // SetEventHandler is a helper function to make slurm node updates propagate to
-// the nodeset controller via configured event channel.
+// the nodeset controller via configured event channel. The nodes may be of any
+// negotiated Slurm REST API version.
 func SetEventHandler(client slurmclient.Client, eventCh chan event.GenericEvent) {
 	informer := client.GetInformer(slurmtypes.ObjectTypeV0043Node)
 	informer.SetEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			node, ok := obj.(*slurmtypes.V0043Node)
+			node, ok := slurmversion.DefaultNode(obj)
 			if !ok {
 				return
 			}
@@ -401,11 +403,11 @@ func SetEventHandler(client slurmclient.Client, eventCh chan event.GenericEvent)
 			eventCh <- podEvent(podInfo)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			oldNode, ok := oldObj.(*slurmtypes.V0043Node)
+			oldNode, ok := slurmversion.DefaultNode(oldObj)
 			if !ok {
 				return
 			}
-			newNode, ok := newObj.(*slurmtypes.V0043Node)
+			newNode, ok := slurmversion.DefaultNode(newObj)
 			if !ok {
 				return
 			}
@@ -417,7 +419,7 @@ func SetEventHandler(client slurmclient.Client, eventCh chan event.GenericEvent)
 			eventCh <- podEvent(podInfo)
 		},
 		DeleteFunc: func(obj interface{}) {
-			node, ok := obj.(*slurmtypes.V0043Node)
+			node, ok := slurmversion.DefaultNode(obj)
 			if !ok {
 				return
 			}
@@ -498,8 +500,10 @@ func (e *secretEventHandler) enqueueRequest(
 
 	for _, controller := range controllerList.Items {
 		slurmKeyKey := controller.AuthSlurmKey()
+		slurmJwksKey := controller.SlurmJwksKey()
 		jwtHs256KeyKey := controller.AuthJwtHs256Key()
 		if secretKey.String() != slurmKeyKey.String() &&
+			secretKey.String() != slurmJwksKey.String() &&
 			secretKey.String() != jwtHs256KeyKey.String() {
 			continue
 		}
@@ -517,3 +521,78 @@ func (e *secretEventHandler) enqueueRequest(
 		}
 	}
 }
+
+var _ handler.EventHandler = &configMapEventHandler{}
+
+type configMapEventHandler struct {
+	client.Reader
+	refResolver *refresolver.RefResolver
+}
+
+func (e *configMapEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *configMapEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *configMapEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *configMapEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *configMapEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	logger := log.FromContext(ctx)
+
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	configMapKey := client.ObjectKeyFromObject(configMap)
+
+	controllerList := &slinkyv1alpha1.ControllerList{}
+	if err := e.List(ctx, controllerList, client.InNamespace(configMap.Namespace)); err != nil {
+		logger.Error(err, "failed to list controller CRs")
+	}
+
+	for _, controller := range controllerList.Items {
+		// Only the users of the controller are provisioned on NodeSet pods.
+		if configMapKey.String() != controller.UsersKey().String() {
+			continue
+		}
+
+		list, err := e.refResolver.GetNodeSetsForController(ctx, &controller)
+		if err != nil {
+			logger.Error(err, "failed to list NodeSet CRs")
+			continue
+		}
+
+		for _, item := range list.Items {
+			objectutils.EnqueueRequest(q, &item)
+		}
+	}
+}
@@ -25,8 +25,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/metrics"
 	"github.com/SlinkyProject/slurm-operator/internal/utils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/historycontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/mathutils"
@@ -49,6 +51,7 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 		if apierrors.IsNotFound(err) {
 			logger.V(3).Info("NodeSet has been deleted.", "request", req)
 			r.expectations.DeleteExpectations(logger, req.String())
+			metrics.DeleteNodeSet(req.NamespacedName)
 			return nil
 		}
 		return err
@@ -58,6 +61,18 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 	nodeset = nodeset.DeepCopy()
 	key := objectutils.KeyFunc(nodeset)
 
+	if err := r.applySlurmKeyRotation(ctx, nodeset); err != nil {
+		return err
+	}
+
+	if err := r.applyUsers(ctx, nodeset); err != nil {
+		return err
+	}
+
+	if err := r.applySharedVolumes(ctx, nodeset); err != nil {
+		return err
+	}
+
 	if err := r.adoptOrphanRevisions(ctx, nodeset); err != nil {
 		return err
 	}
@@ -99,6 +114,69 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 	return r.syncStatus(ctx, nodeset, nodesetPods, currentRevision, updateRevision, collisionCount, hash)
 }
 
+// applySlurmKeyRotation adds the Slurm key rotation annotations of the
+// Controller to the pod template, so that a new revision is rolled out in each
+// phase of the rotation. The NodeSet must be a copy.
+func (r *NodeSetReconciler) applySlurmKeyRotation(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
+	controller, err := r.refResolver.GetController(ctx, nodeset.Spec.ControllerRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	annotations := builder.SlurmKeyRotationAnnotations(controller)
+	if len(annotations) == 0 {
+		return nil
+	}
+	podMetadata := &nodeset.Spec.Template.PodMetadata
+	podMetadata.Annotations = structutils.MergeMaps(podMetadata.Annotations, annotations)
+	return nil
+}
+
+// applyUsers adds the users annotation of the Controller to the pod template,
+// so the users provisioned by its LoginSets are added to the pods, and a new
+// revision is rolled out when they change. The NodeSet must be a copy.
+func (r *NodeSetReconciler) applyUsers(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
+	controller, err := r.refResolver.GetController(ctx, nodeset.Spec.ControllerRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	annotations, err := r.builder.UsersAnnotations(ctx, controller)
+	if err != nil {
+		return err
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	podMetadata := &nodeset.Spec.Template.PodMetadata
+	podMetadata.Annotations = structutils.MergeMaps(podMetadata.Annotations, annotations)
+	return nil
+}
+
+// applySharedVolumes adds the shared volumes annotation of the Controller to
+// the pod template, so a new revision is rolled out when the shared volumes
+// change.
+func (r *NodeSetReconciler) applySharedVolumes(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
+	controller, err := r.refResolver.GetController(ctx, nodeset.Spec.ControllerRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	annotations := builder.SharedVolumesAnnotations(controller, nodeset.Namespace, slinkyv1alpha1.SharedVolumeComponentWorker)
+	if len(annotations) == 0 {
+		return nil
+	}
+	podMetadata := &nodeset.Spec.Template.PodMetadata
+	podMetadata.Annotations = structutils.MergeMaps(podMetadata.Annotations, annotations)
+	return nil
+}
+
 // adoptOrphanRevisions adopts any orphaned ControllerRevisions that match nodeset's Selector. If all adoptions are
 // successful the returned error is nil.
 func (r *NodeSetReconciler) adoptOrphanRevisions(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
@@ -232,6 +310,10 @@ func (r *NodeSetReconciler) sync(
 		return err
 	}
 
+	if err := r.syncJobRequeue(ctx, nodeset, pods); err != nil {
+		return err
+	}
+
 	if err := r.syncCordon(ctx, nodeset, pods); err != nil {
 		return err
 	}
@@ -257,8 +339,8 @@ func (r *NodeSetReconciler) syncClusterWorkerService(ctx context.Context, nodese
 		}
 	}
 
-	clusterName := nodeset.Spec.ControllerRef.Name
-	if err := nodesetutils.SetOwnerReferences(r.Client, ctx, service, clusterName); err != nil {
+	controllerKey := nodeset.Spec.ControllerRef.NamespacedName()
+	if err := nodesetutils.SetOwnerReferences(r.Client, ctx, service, controllerKey); err != nil {
 		return err
 	}
 
@@ -310,6 +392,9 @@ func (r *NodeSetReconciler) syncCordon(
 		// If pod is cordoned, drain the Slurm node
 		case podIsCordoned:
 			reason := fmt.Sprintf("Pod (%s) was cordoned", klog.KObj(pod))
+			if message := pod.Annotations[slinkyv1alpha1.AnnotationPodCordonReason]; message != "" {
+				reason = message
+			}
 			if err := r.syncSlurmNodeDrain(ctx, nodeset, pod, reason); err != nil {
 				return err
 			}
@@ -366,6 +451,45 @@ func (r *NodeSetReconciler) syncSlurmDeadline(
 	return nil
 }
 
+// syncJobRequeue will requeue the running Slurm jobs of NodeSet pods which
+// terminated without being drained (e.g. evicted, or their Kubernetes node was
+// lost), in accordance with the JobRequeuePolicy.
+// NOTE: the NodeSet controller only deletes running pods once their Slurm node
+// is drained, so any pod with running jobs was not terminated by it.
+func (r *NodeSetReconciler) syncJobRequeue(
+	ctx context.Context,
+	nodeset *slinkyv1alpha1.NodeSet,
+	pods []*corev1.Pod,
+) error {
+	logger := log.FromContext(ctx)
+
+	if nodeset.Spec.JobRequeuePolicy != slinkyv1alpha1.RequeueNodeSetJobRequeuePolicyType {
+		return nil
+	}
+
+	syncJobRequeueFn := func(i int) error {
+		pod := pods[i]
+		if !podutils.IsTerminating(pod) && !podutils.IsFailed(pod) && !podutils.IsSucceeded(pod) {
+			return nil
+		}
+		reason := fmt.Sprintf("Pod (%s) terminated with running jobs", klog.KObj(pod))
+		jobIds, err := r.slurmControl.RequeueNodeJobs(ctx, nodeset, pod, reason)
+		if err != nil {
+			return err
+		}
+		if len(jobIds) > 0 {
+			logger.Info("Requeued Slurm jobs of terminated NodeSet Pod",
+				"pod", klog.KObj(pod), "jobs", jobIds)
+		}
+		return nil
+	}
+	if _, err := utils.SlowStartBatch(len(pods), utils.SlowStartInitialBatchSize, syncJobRequeueFn); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // syncNodeSet will reconcile NodeSet pod replica counts.
 // Pods will be:
 //   - Scaled out when: `replicaCount < replicasWant“
@@ -491,9 +615,8 @@ func (r *NodeSetReconciler) newNodeSetPod(
 	ordinal int,
 	revisionHash string,
 ) (*corev1.Pod, error) {
-	controller := &slinkyv1alpha1.Controller{}
-	key := nodeset.Spec.ControllerRef.NamespacedName()
-	if err := r.Get(ctx, key, controller); err != nil {
+	controller, err := r.refResolver.GetAllowedController(ctx, nodeset.Spec.ControllerRef, nodeset.Namespace)
+	if err != nil {
 		return nil, err
 	}
 
@@ -823,6 +946,7 @@ func (r *NodeSetReconciler) makePodUncordon(ctx context.Context, pod *corev1.Pod
 	toUpdate := pod.DeepCopy()
 	logger.Info("Uncordon Pod", "Pod", klog.KObj(toUpdate))
 	delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodCordon)
+	delete(toUpdate.Annotations, slinkyv1alpha1.AnnotationPodCordonReason)
 	if err := r.Patch(ctx, toUpdate, client.StrategicMergeFrom(pod)); err != nil {
 		return err
 	}
@@ -987,8 +1111,8 @@ func (r *NodeSetReconciler) syncClusterWorkerPDB(
 		}
 	}
 
-	clusterName := nodeset.Spec.ControllerRef.Name
-	if err := nodesetutils.SetOwnerReferences(r.Client, ctx, podDisruptionBudget, clusterName); err != nil {
+	controllerKey := nodeset.Spec.ControllerRef.NamespacedName()
+	if err := nodesetutils.SetOwnerReferences(r.Client, ctx, podDisruptionBudget, controllerKey); err != nil {
 		return err
 	}
 
@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,10 +27,13 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/slurmcontrol"
 	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/metrics"
 	"github.com/SlinkyProject/slurm-operator/internal/utils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/historycontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/mathutils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/podutils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmversion"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
 	slurmconditions "github.com/SlinkyProject/slurm-operator/pkg/conditions"
 )
@@ -102,6 +106,7 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 	if err != nil {
 		return err
 	}
+	metrics.SetNodeSetSlurmNodes(objectutils.NamespacedName(nodeset), slurmNodeStatus.StateCounts())
 
 	newStatus := &slinkyv1alpha1.NodeSetStatus{
 		Replicas:            replicaStatus.Replicas,
@@ -120,6 +125,18 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 		Conditions:          []metav1.Condition{},
 	}
 	newStatus.Conditions = append(newStatus.Conditions, nodeset.Status.Conditions...)
+	clientCond := r.slurmClientCondition(nodeset)
+	meta.SetStatusCondition(&newStatus.Conditions, clientCond)
+
+	key := klog.KObj(nodeset).String()
+	if clientCond.Status != metav1.ConditionTrue {
+		// Resync the NodeSet, with backoff, until the Slurm client is available,
+		// as Slurm node operations are skipped in the meantime.
+		slurmClientBackoff.Next(key, time.Now())
+		durationStore.Push(key, slurmClientBackoff.Get(key))
+	} else {
+		slurmClientBackoff.Reset(key)
+	}
 
 	if apiequality.Semantic.DeepEqual(nodeset.Status, newStatus) {
 		logger.V(2).Info("NodeSet Status has not changed, skipping status update", "status", nodeset.Status)
@@ -130,7 +147,6 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 		return err
 	}
 
-	key := klog.KObj(nodeset).String()
 	if nodeset.Spec.MinReadySeconds >= 0 && (newStatus.ReadyReplicas != newStatus.AvailableReplicas) {
 		// Resync the NodeSet after MinReadySeconds as a last line of defense to guard against clock-skew.
 		durationStore.Push(key, (time.Duration(nodeset.Spec.MinReadySeconds)*time.Second)+time.Second)
@@ -142,6 +158,26 @@ func (r *NodeSetReconciler) syncNodeSetStatus(
 	return nil
 }
 
+// slurmClientCondition returns the SlurmClientReady condition of the NodeSet.
+func (r *NodeSetReconciler) slurmClientCondition(nodeset *slinkyv1alpha1.NodeSet) metav1.Condition {
+	controllerKey := nodeset.Spec.ControllerRef.NamespacedName()
+	slurmClient := r.ClientMap.Get(controllerKey)
+	if slurmClient == nil {
+		return metav1.Condition{
+			Type:    slinkyv1alpha1.NodeSetConditionSlurmClientReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  slinkyv1alpha1.SlurmClientReasonUnavailable,
+			Message: "Slurm client unavailable: Controller " + controllerKey.String() + " has no ready RestApi, nor an internal slurmrestd",
+		}
+	}
+	return metav1.Condition{
+		Type:    slinkyv1alpha1.NodeSetConditionSlurmClientReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  slinkyv1alpha1.SlurmClientReasonAvailable,
+		Message: "Slurm REST API " + string(slurmversion.VersionOf(slurmClient)),
+	}
+}
+
 type replicaStatus struct {
 	Replicas    int32
 	Ready       int32
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -324,6 +325,14 @@ func TestNodeSetReconciler_syncNodeSetStatus(t *testing.T) {
 					NodeSetHash:       "12345",
 					CollisionCount:    ptr.To[int32](0),
 					Selector:          "app.kubernetes.io/instance=foo,app.kubernetes.io/name=slurmd",
+					Conditions: []metav1.Condition{
+						{
+							Type:    slinkyv1alpha1.NodeSetConditionSlurmClientReady,
+							Status:  metav1.ConditionTrue,
+							Reason:  slinkyv1alpha1.SlurmClientReasonAvailable,
+							Message: "Slurm REST API v0.0.43",
+						},
+					},
 				},
 				wantErr: false,
 			}
@@ -381,6 +390,69 @@ func TestNodeSetReconciler_syncNodeSetStatus(t *testing.T) {
 					NodeSetHash:         "12345",
 					CollisionCount:      ptr.To[int32](0),
 					Selector:            "app.kubernetes.io/instance=foo,app.kubernetes.io/name=slurmd",
+					Conditions: []metav1.Condition{
+						{
+							Type:    slinkyv1alpha1.NodeSetConditionSlurmClientReady,
+							Status:  metav1.ConditionTrue,
+							Reason:  slinkyv1alpha1.SlurmClientReasonAvailable,
+							Message: "Slurm REST API v0.0.43",
+						},
+					},
+				},
+				wantErr: false,
+			}
+		}(),
+		func() testCaseFields {
+			nodeset := newNodeSet("foo", controller.Name, 2)
+			pods := make([]*corev1.Pod, 0)
+			for i := range 2 {
+				pod := nodesetutils.NewNodeSetPod(nodeset, controller, i, hash)
+				pod = makePodHealthy(pod)
+				pods = append(pods, pod)
+			}
+			podList := &corev1.PodList{
+				Items: structutils.DereferenceList(pods),
+			}
+			revision := &appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						history.ControllerRevisionHashLabel: hash,
+					},
+				},
+			}
+			c := fake.NewClientBuilder().WithRuntimeObjects(nodeset, podList, revision).WithStatusSubresource(nodeset).Build()
+
+			return testCaseFields{
+				name: "No Slurm client",
+				fields: fields{
+					Client:    c,
+					ClientMap: clientmap.NewClientMap(),
+				},
+				args: args{
+					ctx:             context.TODO(),
+					nodeset:         nodeset,
+					pods:            pods,
+					currentRevision: revision,
+					updateRevision:  revision,
+					collisionCount:  0,
+					hash:            hash,
+				},
+				wantStatus: &slinkyv1alpha1.NodeSetStatus{
+					Replicas:          2,
+					ReadyReplicas:     2,
+					AvailableReplicas: 2,
+					UpdatedReplicas:   2,
+					NodeSetHash:       "12345",
+					CollisionCount:    ptr.To[int32](0),
+					Selector:          "app.kubernetes.io/instance=foo,app.kubernetes.io/name=slurmd",
+					Conditions: []metav1.Condition{
+						{
+							Type:    slinkyv1alpha1.NodeSetConditionSlurmClientReady,
+							Status:  metav1.ConditionFalse,
+							Reason:  slinkyv1alpha1.SlurmClientReasonUnavailable,
+							Message: "Slurm client unavailable: Controller default/slurm has no ready RestApi, nor an internal slurmrestd",
+						},
+					},
 				},
 				wantErr: false,
 			}
@@ -395,7 +467,8 @@ func TestNodeSetReconciler_syncNodeSetStatus(t *testing.T) {
 			got := &slinkyv1alpha1.NodeSet{}
 			key := client.ObjectKeyFromObject(tt.args.nodeset)
 			if err := r.Get(tt.args.ctx, key, got); err == nil {
-				if diff := cmp.Diff(tt.wantStatus, &got.Status); diff != "" {
+				if diff := cmp.Diff(tt.wantStatus, &got.Status,
+					cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
 					t.Errorf("unexpected status (-want,+got):\n%s", diff)
 				}
 			}
@@ -57,7 +57,7 @@ func newNodeSetController(client client.Client, clientMap *clientmap.ClientMap)
 		eventRecorder:  eventRecorder,
 		historyControl: historycontrol.NewHistoryControl(client),
 		podControl:     podcontrol.NewPodControl(client, eventRecorder),
-		slurmControl:   slurmcontrol.NewSlurmControl(clientMap),
+		slurmControl:   slurmcontrol.NewSlurmControl(clientMap, eventRecorder),
 		expectations:   kubecontroller.NewUIDTrackingControllerExpectations(kubecontroller.NewControllerExpectations()),
 	}
 	r.builder = builder.New(r.Client)
@@ -449,6 +449,101 @@ func TestNodeSetReconciler_sync(t *testing.T) {
 	}
 }
 
+func TestNodeSetReconciler_syncJobRequeue(t *testing.T) {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	nodeset := newNodeSet("foo", controller.Name, 1)
+	requeueNodeSet := nodeset.DeepCopy()
+	requeueNodeSet.Spec.JobRequeuePolicy = slinkyv1alpha1.RequeueNodeSetJobRequeuePolicyType
+	pod := makePodHealthy(nodesetutils.NewNodeSetPod(nodeset, controller, 0, ""))
+	terminatingPod := pod.DeepCopy()
+	terminatingPod.DeletionTimestamp = ptr.To(metav1.Now())
+	evictedPod := pod.DeepCopy()
+	evictedPod.Status.Phase = corev1.PodFailed
+	evictedPod.Status.Reason = "Evicted"
+	newClientMap := func() *clientmap.ClientMap {
+		nodeList := &slurmtypes.V0043NodeList{
+			Items: []slurmtypes.V0043Node{
+				{
+					V0043Node: api.V0043Node{
+						Name:  ptr.To(nodesetutils.GetNodeName(pod)),
+						State: ptr.To([]api.V0043NodeState{api.V0043NodeStateALLOCATED}),
+					},
+				},
+			},
+		}
+		jobList := &slurmtypes.V0043JobInfoList{
+			Items: []slurmtypes.V0043JobInfo{
+				{
+					V0043JobInfo: api.V0043JobInfo{
+						JobId:     ptr.To[int32](1),
+						JobState:  ptr.To([]api.V0043JobInfoJobState{api.V0043JobInfoJobStateRUNNING}),
+						BatchFlag: ptr.To(true),
+						Requeue:   ptr.To(true),
+						Nodes:     ptr.To(nodesetutils.GetNodeName(pod)),
+					},
+				},
+			},
+		}
+		sclient := newFakeClientList(sinterceptor.Funcs{}, nodeList, jobList)
+		return newClientMap(controller.Name, sclient)
+	}
+	tests := []struct {
+		name     string
+		nodeset  *slinkyv1alpha1.NodeSet
+		pod      *corev1.Pod
+		wantDown bool
+	}{
+		{
+			name:    "No policy",
+			nodeset: nodeset,
+			pod:     terminatingPod,
+		},
+		{
+			name:    "Running pod",
+			nodeset: requeueNodeSet,
+			pod:     pod,
+		},
+		{
+			name:     "Terminating pod",
+			nodeset:  requeueNodeSet,
+			pod:      terminatingPod,
+			wantDown: true,
+		},
+		{
+			name:     "Evicted pod",
+			nodeset:  requeueNodeSet,
+			pod:      evictedPod,
+			wantDown: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			r := newNodeSetController(fake.NewFakeClient(tt.nodeset.DeepCopy()), newClientMap())
+			// The running jobs are captured while the pod is in service.
+			if _, err := r.slurmControl.GetNodeDeadlines(ctx, tt.nodeset, []*corev1.Pod{pod}); err != nil {
+				t.Fatalf("SlurmControl.GetNodeDeadlines() error = %v", err)
+			}
+			if err := r.syncJobRequeue(ctx, tt.nodeset, []*corev1.Pod{tt.pod}); err != nil {
+				t.Errorf("NodeSetReconciler.syncJobRequeue() error = %v", err)
+			}
+			gotSlurmNode := &slurmtypes.V0043Node{}
+			sc := r.ClientMap.Get(tt.nodeset.Spec.ControllerRef.NamespacedName())
+			if err := sc.Get(ctx, slurmclient.ObjectKey(nodesetutils.GetNodeName(tt.pod)), gotSlurmNode); err != nil {
+				t.Fatalf("slurmclient.Get() error = %v", err)
+			}
+			if got := gotSlurmNode.GetStateAsSet().Has(api.V0043NodeStateDOWN); got != tt.wantDown {
+				t.Errorf("SlurmNode Has DOWN = %v, want %v", got, tt.wantDown)
+			}
+		})
+	}
+}
+
 func TestNodeSetReconciler_syncNodeSet(t *testing.T) {
 	utilruntime.Must(slinkyv1alpha1.AddToScheme(clientgoscheme.Scheme))
 	type fields struct {
@@ -5,14 +5,17 @@ package slurmcontrol
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/puttsk/hostlist"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
@@ -25,7 +28,10 @@ import (
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
 	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/metrics"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/podinfo"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/podutils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/timestore"
 	slurmconditions "github.com/SlinkyProject/slurm-operator/pkg/conditions"
 )
@@ -47,26 +53,53 @@ type SlurmControlInterface interface {
 	CalculateNodeStatus(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (SlurmNodeStatus, error)
 	// GetNodeDeadlines returns a map of node to its deadline time.Time calculated from running jobs.
 	GetNodeDeadlines(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (*timestore.TimeStore, error)
+	// RequeueNodeJobs handles adding the DOWN state to the slurm node with running jobs,
+	// such that slurmctld requeues them, and returns their job IDs. The jobs are
+	// those captured by GetNodeDeadlines, before the node was possibly set DOWN.
+	RequeueNodeJobs(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) ([]int32, error)
 }
 
+const (
+	// SlurmClientUnavailableReason is the reason of the events recorded when a
+	// Slurm node operation is skipped, as the NodeSet has no Slurm client.
+	SlurmClientUnavailableReason = "SlurmClientUnavailable"
+	// JobsRequeuedReason is the reason of the events recorded when the running
+	// jobs of a Slurm node are requeued, as its pod terminated.
+	JobsRequeuedReason = "JobsRequeued"
+
+	// skippedEventInterval is the minimum time between the events recorded for
+	// the same skipped operation.
+	skippedEventInterval = 5 * time.Minute
+)
+
 // realSlurmControl is the default implementation of SlurmControlInterface.
 type realSlurmControl struct {
 	clientMap *clientmap.ClientMap
+	recorder  record.EventRecorder
+
+	// nodeJobs are the running jobs of the Slurm nodes, by Slurm node name,
+	// by NodeSet.
+	nodeJobs   map[string]map[string][]nodeJob
+	nodeJobsMu sync.Mutex
+
+	// skippedEvents is the last time a skipped operation was recorded, by
+	// NodeSet, operation and target.
+	skippedEvents map[string]time.Time
+	skippedMu     sync.Mutex
 }
 
 // RefreshNodeCache implements SlurmControlInterface.
 func (r *realSlurmControl) RefreshNodeCache(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet) error {
-	logger := log.FromContext(ctx)
-
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do RefreshNodeCache()")
+		r.skipped(ctx, nodeset, nil, "RefreshNodeCache")
 		return nil
 	}
 
 	nodeList := &slurmtypes.V0043NodeList{}
 	opts := &slurmclient.ListOptions{RefreshCache: true}
 	if err := slurmClient.List(ctx, nodeList, opts); err != nil {
+		r.observeError(nodeset, "list_nodes", err)
 		return err
 	}
 
@@ -75,16 +108,15 @@ func (r *realSlurmControl) RefreshNodeCache(ctx context.Context, nodeset *slinky
 
 // GetNodeNames implements SlurmControlInterface.
 func (r *realSlurmControl) GetNodeNames(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) ([]string, error) {
-	logger := log.FromContext(ctx)
-
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do GetNodeNames()")
+		r.skipped(ctx, nodeset, nil, "GetNodeNames")
 		return nil, nil
 	}
 
 	nodeList := &slurmtypes.V0043NodeList{}
 	if err := slurmClient.List(ctx, nodeList); err != nil {
+		r.observeError(nodeset, "list_nodes", err)
 		return nil, err
 	}
 
@@ -112,14 +144,14 @@ func (r *realSlurmControl) UpdateNodeWithPodInfo(ctx context.Context, nodeset *s
 
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do UpdateNodeWithPodInfo()",
-			"pod", klog.KObj(pod))
+		r.skipped(ctx, nodeset, pod, "UpdateNodeWithPodInfo")
 		return nil
 	}
 
 	slurmNode := &slurmtypes.V0043Node{}
 	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
 	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+		r.observeError(nodeset, "get_node", err)
 		if tolerateError(err) {
 			return nil
 		}
@@ -146,6 +178,7 @@ func (r *realSlurmControl) UpdateNodeWithPodInfo(ctx context.Context, nodeset *s
 		Comment: ptr.To(podInfo.ToString()),
 	}
 	if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+		r.observeError(nodeset, "update_node", err)
 		if !tolerateError(err) {
 			return err
 		}
@@ -157,6 +190,7 @@ func (r *realSlurmControl) UpdateNodeWithPodInfo(ctx context.Context, nodeset *s
 			State: ptr.To([]api.V0043UpdateNodeMsgState{api.V0043UpdateNodeMsgStateIDLE}),
 		}
 		if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+			r.observeError(nodeset, "update_node", err)
 			if tolerateError(err) {
 				return nil
 			}
@@ -174,15 +208,17 @@ func (r *realSlurmControl) MakeNodeDrain(ctx context.Context, nodeset *slinkyv1a
 
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do MakeNodeDrain()",
-			"pod", klog.KObj(pod))
+		r.skipped(ctx, nodeset, pod, "MakeNodeDrain")
 		return nil
 	}
 
 	slurmNode := &slurmtypes.V0043Node{}
 	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
 	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+		r.observeError(nodeset, "get_node", err)
 		if tolerateError(err) {
+			logger.V(1).Info("Slurm node not found, skipping drain request",
+				"pod", klog.KObj(pod), "err", err)
 			return nil
 		}
 		return err
@@ -194,7 +230,11 @@ func (r *realSlurmControl) MakeNodeDrain(ctx context.Context, nodeset *slinkyv1a
 		State:  ptr.To([]api.V0043UpdateNodeMsgState{api.V0043UpdateNodeMsgStateDRAIN}),
 		Reason: ptr.To(nodeReasonPrefix + " " + reason),
 	}
-	if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+	start := time.Now()
+	err := slurmClient.Update(ctx, slurmNode, req)
+	metrics.ObserveNodeDrain(objectutils.NamespacedName(nodeset), metrics.OperationDrain, start, err)
+	if err != nil {
+		r.observeError(nodeset, "update_node", err)
 		if tolerateError(err) {
 			return nil
 		}
@@ -210,15 +250,17 @@ func (r *realSlurmControl) MakeNodeUndrain(ctx context.Context, nodeset *slinkyv
 
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do MakeNodeUndrain()",
-			"pod", klog.KObj(pod))
+		r.skipped(ctx, nodeset, pod, "MakeNodeUndrain")
 		return nil
 	}
 
 	slurmNode := &slurmtypes.V0043Node{}
 	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
 	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+		r.observeError(nodeset, "get_node", err)
 		if tolerateError(err) {
+			logger.V(1).Info("Slurm node not found, skipping undrain request",
+				"pod", klog.KObj(pod), "err", err)
 			return nil
 		}
 		return err
@@ -242,7 +284,11 @@ func (r *realSlurmControl) MakeNodeUndrain(ctx context.Context, nodeset *slinkyv
 		State:  ptr.To([]api.V0043UpdateNodeMsgState{api.V0043UpdateNodeMsgStateUNDRAIN}),
 		Reason: ptr.To(nodeReasonPrefix + " " + reason),
 	}
-	if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+	start := time.Now()
+	err := slurmClient.Update(ctx, slurmNode, req)
+	metrics.ObserveNodeDrain(objectutils.NamespacedName(nodeset), metrics.OperationUndrain, start, err)
+	if err != nil {
+		r.observeError(nodeset, "update_node", err)
 		if tolerateError(err) {
 			return nil
 		}
@@ -254,18 +300,17 @@ func (r *realSlurmControl) MakeNodeUndrain(ctx context.Context, nodeset *slinkyv
 
 // IsNodeDrain implements SlurmControlInterface.
 func (r *realSlurmControl) IsNodeDrain(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) (bool, error) {
-	logger := log.FromContext(ctx)
-
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do IsNodeDrain()",
-			"pod", klog.KObj(pod))
-		return true, nil
+		// Never assume the node is drain, so it is drained once possible.
+		r.skipped(ctx, nodeset, pod, "IsNodeDrain")
+		return false, nil
 	}
 
 	slurmNode := &slurmtypes.V0043Node{}
 	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
 	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+		r.observeError(nodeset, "get_node", err)
 		if tolerateError(err) {
 			return true, nil
 		}
@@ -278,18 +323,18 @@ func (r *realSlurmControl) IsNodeDrain(ctx context.Context, nodeset *slinkyv1alp
 
 // IsNodeDrained implements SlurmControlInterface.
 func (r *realSlurmControl) IsNodeDrained(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod) (bool, error) {
-	logger := log.FromContext(ctx)
-
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do IsNodeDrained()",
-			"pod", klog.KObj(pod))
-		return true, nil
+		// Never assume the node is drained, the pod is deleted once it is.
+		// The NodeSet is resynced with backoff until the Slurm client is available.
+		r.skipped(ctx, nodeset, pod, "IsNodeDrained")
+		return false, nil
 	}
 
 	slurmNode := &slurmtypes.V0043Node{}
 	key := slurmobject.ObjectKey(nodesetutils.GetNodeName(pod))
 	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+		r.observeError(nodeset, "get_node", err)
 		if tolerateError(err) {
 			return true, nil
 		}
@@ -329,21 +374,43 @@ type SlurmNodeStatus struct {
 	NodeStates map[string][]corev1.PodCondition
 }
 
+// StateCounts returns the number of Slurm nodes in each base and flag state,
+// keyed by the lowercase state name.
+func (s SlurmNodeStatus) StateCounts() map[string]int32 {
+	return map[string]int32{
+		"allocated":      s.Allocated,
+		"down":           s.Down,
+		"error":          s.Error,
+		"future":         s.Future,
+		"idle":           s.Idle,
+		"mixed":          s.Mixed,
+		"unknown":        s.Unknown,
+		"completing":     s.Completing,
+		"drain":          s.Drain,
+		"fail":           s.Fail,
+		"invalid":        s.Invalid,
+		"invalid_reg":    s.InvalidReg,
+		"maintenance":    s.Maintenance,
+		"not_responding": s.NotResponding,
+		"undrain":        s.Undrain,
+	}
+}
+
 // CalculateNodeStatus implements SlurmControlInterface.
 func (r *realSlurmControl) CalculateNodeStatus(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod) (SlurmNodeStatus, error) {
-	logger := log.FromContext(ctx)
 	status := SlurmNodeStatus{
 		NodeStates: make(map[string][]corev1.PodCondition),
 	}
 
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do CalculateNodeStatus()")
+		r.skipped(ctx, nodeset, nil, "CalculateNodeStatus")
 		return status, nil
 	}
 
 	nodeList := &slurmtypes.V0043NodeList{}
 	if err := slurmClient.List(ctx, nodeList); err != nil {
+		r.observeError(nodeset, "list_nodes", err)
 		if tolerateError(err) {
 			return status, nil
 		}
@@ -448,7 +515,7 @@ func (r *realSlurmControl) GetNodeDeadlines(ctx context.Context, nodeset *slinky
 
 	slurmClient := r.lookupClient(nodeset)
 	if slurmClient == nil {
-		logger.V(2).Info("no client for nodeset, cannot do GetNodeDeadlines()")
+		r.skipped(ctx, nodeset, nil, "GetNodeDeadlines")
 		return ts, nil
 	}
 
@@ -460,9 +527,11 @@ func (r *realSlurmControl) GetNodeDeadlines(ctx context.Context, nodeset *slinky
 
 	jobList := &slurmtypes.V0043JobInfoList{}
 	if err := slurmClient.List(ctx, jobList); err != nil {
+		r.observeError(nodeset, "list_jobs", err)
 		return nil, err
 	}
 
+	nodeJobs := make(map[string][]nodeJob)
 	for _, job := range jobList.Items {
 		if !job.GetStateAsSet().Has(api.V0043JobInfoJobStateRUNNING) {
 			continue
@@ -488,23 +557,221 @@ func (r *realSlurmControl) GetNodeDeadlines(ctx context.Context, nodeset *slinky
 		}
 
 		// Push time/duration into the fancy map for each node allocated to the job.
+		// Only batch jobs may be requeued, when allowed (e.g. `--requeue`).
+		runningJob := nodeJob{
+			jobId:   ptr.Deref(job.JobId, 0),
+			requeue: ptr.Deref(job.BatchFlag, false) && ptr.Deref(job.Requeue, false),
+		}
 		for _, slurmNodeName := range slurmNodeNames {
 			ts.Push(slurmNodeName, startTime.Add(timeLimit))
+			if slurmNodeNamesSet.Has(slurmNodeName) {
+				nodeJobs[slurmNodeName] = append(nodeJobs[slurmNodeName], runningJob)
+			}
 		}
 	}
+	r.captureNodeJobs(nodeset, pods, nodeJobs)
 
 	return ts, nil
 }
 
+// RequeueNodeJobs implements SlurmControlInterface.
+// The jobs of the Slurm node are those captured by GetNodeDeadlines while the
+// pod was in service, as the preStop hook of slurmd may set the node DOWN, and
+// delete a dynamic node, before the pod is observed terminating. slurmctld
+// requeues, or fails, the jobs of a DOWN node either way.
+func (r *realSlurmControl) RequeueNodeJobs(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, reason string) ([]int32, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(nodeset)
+	if slurmClient == nil {
+		r.skipped(ctx, nodeset, pod, "RequeueNodeJobs")
+		return nil, nil
+	}
+
+	slurmNodeName := nodesetutils.GetNodeName(pod)
+	jobs := r.getNodeJobs(nodeset, slurmNodeName)
+	if len(jobs) == 0 {
+		logger.V(1).Info("Node has no running jobs, skipping requeue request",
+			"node", slurmNodeName)
+		return nil, nil
+	}
+	var requeueJobIds, failJobIds []int32
+	for _, job := range jobs {
+		if job.requeue {
+			requeueJobIds = append(requeueJobIds, job.jobId)
+		} else {
+			failJobIds = append(failJobIds, job.jobId)
+		}
+	}
+
+	alreadyDown := false
+	slurmNode := &slurmtypes.V0043Node{}
+	key := slurmobject.ObjectKey(slurmNodeName)
+	if err := slurmClient.Get(ctx, key, slurmNode); err != nil {
+		r.observeError(nodeset, "get_node", err)
+		if !tolerateError(err) {
+			return nil, err
+		}
+		// The preStop hook deletes a dynamic node once it is DOWN.
+		alreadyDown = true
+	} else if slurmNode.GetStateAsSet().Has(api.V0043NodeStateDOWN) {
+		if strings.Contains(ptr.Deref(slurmNode.Reason, ""), nodeReasonPrefix) {
+			logger.V(1).Info("Node is already down by slurm-operator, skipping requeue request",
+				"node", slurmNode.GetKey(), "nodeState", slurmNode.State)
+			r.forgetNodeJobs(nodeset, slurmNodeName)
+			return nil, nil
+		}
+		// The preStop hook, or an administrator, set the node DOWN.
+		alreadyDown = true
+	}
+
+	if alreadyDown {
+		logger.Info("slurm node already down, its jobs were requeued",
+			"pod", klog.KObj(pod), "requeueJobs", requeueJobIds, "failJobs", failJobIds)
+	} else {
+		logger.Info("make slurm node down, requeueing its jobs",
+			"pod", klog.KObj(pod), "requeueJobs", requeueJobIds, "failJobs", failJobIds)
+		req := api.V0043UpdateNodeMsg{
+			State:  ptr.To([]api.V0043UpdateNodeMsgState{api.V0043UpdateNodeMsgStateDOWN}),
+			Reason: ptr.To(nodeReasonPrefix + " " + reason),
+		}
+		if err := slurmClient.Update(ctx, slurmNode, req); err != nil {
+			r.observeError(nodeset, "update_node", err)
+			if tolerateError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+	r.forgetNodeJobs(nodeset, slurmNodeName)
+
+	if r.recorder != nil {
+		action := "Set Slurm node %s down"
+		if alreadyDown {
+			action = "Slurm node %s was already down"
+		}
+		r.recorder.Eventf(nodeset, corev1.EventTypeWarning, JobsRequeuedReason,
+			action+", as pod %s terminated: requeued jobs %v, failed jobs %v which cannot be requeued",
+			slurmNodeName, klog.KObj(pod), requeueJobIds, failJobIds)
+	}
+
+	return append(requeueJobIds, failJobIds...), nil
+}
+
+// nodeJob is a running job of a Slurm node.
+type nodeJob struct {
+	jobId int32
+	// requeue is true for a batch job which may be requeued (e.g. `--requeue`).
+	requeue bool
+}
+
+// captureNodeJobs records the running jobs of the Slurm nodes of the NodeSet
+// pods which are in service, for RequeueNodeJobs. The jobs last captured for
+// the other pods are kept, as their Slurm nodes may already be DOWN.
+func (r *realSlurmControl) captureNodeJobs(nodeset *slinkyv1alpha1.NodeSet, pods []*corev1.Pod, jobs map[string][]nodeJob) {
+	r.nodeJobsMu.Lock()
+	defer r.nodeJobsMu.Unlock()
+
+	key := objectutils.KeyFunc(nodeset)
+	if nodeset.Spec.JobRequeuePolicy != slinkyv1alpha1.RequeueNodeSetJobRequeuePolicyType {
+		delete(r.nodeJobs, key)
+		return
+	}
+	if r.nodeJobs == nil {
+		r.nodeJobs = make(map[string]map[string][]nodeJob)
+	}
+
+	captured := make(map[string][]nodeJob)
+	for _, pod := range pods {
+		slurmNodeName := nodesetutils.GetNodeName(pod)
+		if podutils.IsTerminating(pod) || podutils.IsFailed(pod) || podutils.IsSucceeded(pod) {
+			if last, ok := r.nodeJobs[key][slurmNodeName]; ok {
+				captured[slurmNodeName] = last
+			}
+			continue
+		}
+		if len(jobs[slurmNodeName]) > 0 {
+			captured[slurmNodeName] = jobs[slurmNodeName]
+		}
+	}
+	r.nodeJobs[key] = captured
+}
+
+// getNodeJobs returns the running jobs last captured for the Slurm node.
+func (r *realSlurmControl) getNodeJobs(nodeset *slinkyv1alpha1.NodeSet, slurmNodeName string) []nodeJob {
+	r.nodeJobsMu.Lock()
+	defer r.nodeJobsMu.Unlock()
+	return r.nodeJobs[objectutils.KeyFunc(nodeset)][slurmNodeName]
+}
+
+// forgetNodeJobs forgets the running jobs captured for the Slurm node, once
+// they are requeued.
+func (r *realSlurmControl) forgetNodeJobs(nodeset *slinkyv1alpha1.NodeSet, slurmNodeName string) {
+	r.nodeJobsMu.Lock()
+	defer r.nodeJobsMu.Unlock()
+	delete(r.nodeJobs[objectutils.KeyFunc(nodeset)], slurmNodeName)
+}
+
+// observeError records a failed slurmrestd request against the NodeSet's Controller.
+func (r *realSlurmControl) observeError(nodeset *slinkyv1alpha1.NodeSet, operation string, err error) {
+	if tolerateError(err) {
+		return
+	}
+	metrics.IncSlurmRestErrors(nodeset.Spec.ControllerRef.NamespacedName(), operation)
+}
+
+// skipped records that the operation on the Slurm node of the pod, or on all
+// Slurm nodes of the NodeSet if pod is nil, was skipped, as the NodeSet has no
+// Slurm client, so it is visible on the NodeSet. The event is recorded at most
+// once per skippedEventInterval for each operation and target.
+func (r *realSlurmControl) skipped(ctx context.Context, nodeset *slinkyv1alpha1.NodeSet, pod *corev1.Pod, operation string) {
+	logger := log.FromContext(ctx)
+	target := "Slurm nodes"
+	if pod != nil {
+		target = fmt.Sprintf("Slurm node %s", nodesetutils.GetNodeName(pod))
+		logger = logger.WithValues("pod", klog.KObj(pod))
+	}
+	logger.V(1).Info("no client for nodeset, skipped Slurm node operation",
+		"operation", operation)
+	if r.recorder == nil {
+		return
+	}
+
+	now := time.Now()
+	key := strings.Join([]string{objectutils.KeyFunc(nodeset), operation, target}, "/")
+	r.skippedMu.Lock()
+	if r.skippedEvents == nil {
+		r.skippedEvents = make(map[string]time.Time)
+	}
+	for k, last := range r.skippedEvents {
+		if now.Sub(last) >= skippedEventInterval {
+			delete(r.skippedEvents, k)
+		}
+	}
+	_, recent := r.skippedEvents[key]
+	if !recent {
+		r.skippedEvents[key] = now
+	}
+	r.skippedMu.Unlock()
+	if recent {
+		return
+	}
+
+	r.recorder.Eventf(nodeset, corev1.EventTypeWarning, SlurmClientUnavailableReason,
+		"Skipped %s() of %s: Controller %s has no Slurm client",
+		operation, target, nodeset.Spec.ControllerRef.NamespacedName())
+}
+
 func (r *realSlurmControl) lookupClient(nodeset *slinkyv1alpha1.NodeSet) slurmclient.Client {
 	return r.clientMap.Get(nodeset.Spec.ControllerRef.NamespacedName())
 }
 
 var _ SlurmControlInterface = &realSlurmControl{}
 
-func NewSlurmControl(clusters *clientmap.ClientMap) SlurmControlInterface {
+func NewSlurmControl(clusters *clientmap.ClientMap, recorder record.EventRecorder) SlurmControlInterface {
 	return &realSlurmControl{
 		clientMap: clusters,
+		recorder:  recorder,
 	}
 }
 
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"net/http"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
 
@@ -113,7 +116,7 @@ var _ = Describe("SlurmControlInterface", func() {
 			}
 			sclient = fake.NewClientBuilder().WithUpdateFn(updateFn).WithObjects(node).Build()
 			controllers := newSlurmClientMap(controller.Name, sclient)
-			slurmcontrol = NewSlurmControl(controllers)
+			slurmcontrol = NewSlurmControl(controllers, record.NewFakeRecorder(10))
 
 			By("Update Slurm pod info")
 			err := slurmcontrol.UpdateNodeWithPodInfo(ctx, nodeset, pod)
@@ -152,7 +155,7 @@ var _ = Describe("SlurmControlInterface", func() {
 			}
 			sclient = fake.NewClientBuilder().WithUpdateFn(updateFn).WithObjects(node).Build()
 			controllers := newSlurmClientMap(controller.Name, sclient)
-			slurmcontrol = NewSlurmControl(controllers)
+			slurmcontrol = NewSlurmControl(controllers, record.NewFakeRecorder(10))
 
 			By("Draining matching Slurm node")
 			err := slurmcontrol.MakeNodeDrain(ctx, nodeset, pod, "drain")
@@ -184,7 +187,7 @@ var _ = Describe("SlurmControlInterface", func() {
 			}
 			sclient = fake.NewClientBuilder().WithUpdateFn(updateFn).WithObjects(node).Build()
 			controllers := newSlurmClientMap(controller.Name, sclient)
-			slurmcontrol = NewSlurmControl(controllers)
+			slurmcontrol = NewSlurmControl(controllers, record.NewFakeRecorder(10))
 
 			By("Update Slurm pod info")
 			err := slurmcontrol.UpdateNodeWithPodInfo(ctx, nodeset, pod)
@@ -257,7 +260,7 @@ var _ = Describe("SlurmControlInterface", func() {
 			}
 			sclient = fake.NewClientBuilder().WithUpdateFn(updateFn).WithObjects(node).Build()
 			controllers := newSlurmClientMap(controller.Name, sclient)
-			slurmcontrol = NewSlurmControl(controllers)
+			slurmcontrol = NewSlurmControl(controllers, record.NewFakeRecorder(10))
 
 			By("Draining matching Slurm node")
 			err := slurmcontrol.MakeNodeUndrain(ctx, nodeset, pod, "undrain")
@@ -379,7 +382,7 @@ var _ = Describe("SlurmControlInterface", func() {
 			}
 			sclient = fake.NewClientBuilder().WithLists(nodeList, jobList).Build()
 			controllers := newSlurmClientMap(controller.Name, sclient)
-			slurmcontrol = NewSlurmControl(controllers)
+			slurmcontrol = NewSlurmControl(controllers, record.NewFakeRecorder(10))
 
 			By("Getting TimeStore")
 			ts, err := slurmcontrol.GetNodeDeadlines(ctx, nodeset, pods)
@@ -767,6 +770,263 @@ func Test_realSlurmControl_IsNodeDrained(t *testing.T) {
 	}
 }
 
+func Test_realSlurmControl_NoClient(t *testing.T) {
+	ctx := context.Background()
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	nodeset := newNodeSet("foo", controller.Name, 1)
+	pod := nodesetutils.NewNodeSetPod(nodeset, controller, 0, "")
+	recorder := record.NewFakeRecorder(20)
+	r := &realSlurmControl{
+		clientMap: clientmap.NewClientMap(),
+		recorder:  recorder,
+	}
+
+	if err := r.MakeNodeDrain(ctx, nodeset, pod, "test"); err != nil {
+		t.Errorf("realSlurmControl.MakeNodeDrain() error = %v", err)
+	}
+	if err := r.MakeNodeUndrain(ctx, nodeset, pod, "test"); err != nil {
+		t.Errorf("realSlurmControl.MakeNodeUndrain() error = %v", err)
+	}
+	if got, err := r.IsNodeDrained(ctx, nodeset, pod); err != nil || got {
+		t.Errorf("realSlurmControl.IsNodeDrained() = %v, %v, want false", got, err)
+	}
+	if got, err := r.RequeueNodeJobs(ctx, nodeset, pod, "test"); err != nil || len(got) != 0 {
+		t.Errorf("realSlurmControl.RequeueNodeJobs() = %v, %v, want none", got, err)
+	}
+	if got, err := r.IsNodeDrain(ctx, nodeset, pod); err != nil || got {
+		t.Errorf("realSlurmControl.IsNodeDrain() = %v, %v, want false", got, err)
+	}
+	if err := r.UpdateNodeWithPodInfo(ctx, nodeset, pod); err != nil {
+		t.Errorf("realSlurmControl.UpdateNodeWithPodInfo() error = %v", err)
+	}
+	if err := r.RefreshNodeCache(ctx, nodeset); err != nil {
+		t.Errorf("realSlurmControl.RefreshNodeCache() error = %v", err)
+	}
+	if _, err := r.GetNodeNames(ctx, nodeset, []*corev1.Pod{pod}); err != nil {
+		t.Errorf("realSlurmControl.GetNodeNames() error = %v", err)
+	}
+	if _, err := r.CalculateNodeStatus(ctx, nodeset, []*corev1.Pod{pod}); err != nil {
+		t.Errorf("realSlurmControl.CalculateNodeStatus() error = %v", err)
+	}
+	if _, err := r.GetNodeDeadlines(ctx, nodeset, []*corev1.Pod{pod}); err != nil {
+		t.Errorf("realSlurmControl.GetNodeDeadlines() error = %v", err)
+	}
+
+	for _, operation := range []string{
+		"MakeNodeDrain", "MakeNodeUndrain", "IsNodeDrained", "RequeueNodeJobs", "IsNodeDrain",
+		"UpdateNodeWithPodInfo", "RefreshNodeCache", "GetNodeNames", "CalculateNodeStatus", "GetNodeDeadlines",
+	} {
+		select {
+		case event := <-recorder.Events:
+			want := corev1.EventTypeWarning + " " + SlurmClientUnavailableReason + " Skipped " + operation + "()"
+			if !strings.HasPrefix(event, want) {
+				t.Errorf("event = %q, want prefix %q", event, want)
+			}
+		default:
+			t.Errorf("no event for %s()", operation)
+		}
+	}
+
+	// The events of the same skipped operations are rate limited.
+	if err := r.MakeNodeDrain(ctx, nodeset, pod, "test"); err != nil {
+		t.Errorf("realSlurmControl.MakeNodeDrain() error = %v", err)
+	}
+	if err := r.RefreshNodeCache(ctx, nodeset); err != nil {
+		t.Errorf("realSlurmControl.RefreshNodeCache() error = %v", err)
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("unexpected event = %q", event)
+	default:
+	}
+	pod2 := nodesetutils.NewNodeSetPod(nodeset, controller, 1, "")
+	if err := r.MakeNodeDrain(ctx, nodeset, pod2, "test"); err != nil {
+		t.Errorf("realSlurmControl.MakeNodeDrain() error = %v", err)
+	}
+	select {
+	case <-recorder.Events:
+	default:
+		t.Error("no event for MakeNodeDrain() of another Slurm node")
+	}
+}
+
+func Test_realSlurmControl_RequeueNodeJobs(t *testing.T) {
+	ctx := context.Background()
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	nodeset := newNodeSet("foo", controller.Name, 2)
+	nodeset.Spec.JobRequeuePolicy = slinkyv1alpha1.RequeueNodeSetJobRequeuePolicyType
+	pod := nodesetutils.NewNodeSetPod(nodeset, controller, 0, "")
+	pod2 := nodesetutils.NewNodeSetPod(nodeset, controller, 1, "")
+	terminatingPod := pod.DeepCopy()
+	terminatingPod.DeletionTimestamp = ptr.To(metav1.Now())
+	newNode := func(state api.V0043NodeState, reason string) *types.V0043Node {
+		return &types.V0043Node{
+			V0043Node: api.V0043Node{
+				Name:   ptr.To(nodesetutils.GetNodeName(pod)),
+				State:  ptr.To([]api.V0043NodeState{state}),
+				Reason: ptr.To(reason),
+			},
+		}
+	}
+	newJob := func(jobId int32, state api.V0043JobInfoJobState, batch bool, pods ...*corev1.Pod) types.V0043JobInfo {
+		nodeNames := []string{}
+		for _, pod := range pods {
+			nodeNames = append(nodeNames, nodesetutils.GetNodeName(pod))
+		}
+		nodes, err := hostlist.Compress(nodeNames)
+		if err != nil {
+			panic(err)
+		}
+		return types.V0043JobInfo{
+			V0043JobInfo: api.V0043JobInfo{
+				JobId:     ptr.To(jobId),
+				JobState:  ptr.To([]api.V0043JobInfoJobState{state}),
+				BatchFlag: ptr.To(batch),
+				Requeue:   ptr.To(true),
+				Nodes:     ptr.To(nodes),
+			},
+		}
+	}
+	jobList := &types.V0043JobInfoList{
+		Items: []types.V0043JobInfo{
+			newJob(1, api.V0043JobInfoJobStateRUNNING, true, pod),
+			newJob(2, api.V0043JobInfoJobStateRUNNING, false, pod, pod2),
+			newJob(3, api.V0043JobInfoJobStateRUNNING, true, pod2),
+			newJob(4, api.V0043JobInfoJobStateCOMPLETED, true, pod),
+		},
+	}
+	tests := []struct {
+		name    string
+		policy  slinkyv1alpha1.NodeSetJobRequeuePolicyType
+		node    *types.V0043Node
+		jobList *types.V0043JobInfoList
+		// preStop sets the node DOWN before the pod is observed terminating,
+		// so its jobs are no longer running when requeued.
+		preStop   bool
+		want      []int32
+		wantDown  bool
+		wantEvent string
+		wantErr   bool
+	}{
+		{
+			name:      "Running jobs",
+			node:      newNode(api.V0043NodeStateMIXED, ""),
+			jobList:   jobList,
+			want:      []int32{1, 2},
+			wantDown:  true,
+			wantEvent: "Set Slurm node",
+		},
+		{
+			name:    "No running jobs",
+			node:    newNode(api.V0043NodeStateIDLE, ""),
+			jobList: &types.V0043JobInfoList{},
+			want:    nil,
+		},
+		{
+			name:    "JobRequeuePolicy None",
+			policy:  slinkyv1alpha1.NoneNodeSetJobRequeuePolicyType,
+			node:    newNode(api.V0043NodeStateMIXED, ""),
+			jobList: jobList,
+			want:    nil,
+		},
+		{
+			name:      "DOWN by preStop",
+			node:      newNode(api.V0043NodeStateDOWN, "preStop"),
+			jobList:   jobList,
+			preStop:   true,
+			want:      []int32{1, 2},
+			wantEvent: "was already down",
+		},
+		{
+			name:    "DOWN by slurm-operator",
+			node:    newNode(api.V0043NodeStateDOWN, nodeReasonPrefix+" test"),
+			jobList: jobList,
+			want:    nil,
+		},
+		{
+			name:      "Node deleted by preStop",
+			jobList:   jobList,
+			preStop:   true,
+			want:      []int32{1, 2},
+			wantEvent: "was already down",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeset := nodeset.DeepCopy()
+			if tt.policy != "" {
+				nodeset.Spec.JobRequeuePolicy = tt.policy
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &realSlurmControl{
+				clientMap: newSlurmClientMap(controller.Name, fake.NewClientBuilder().WithLists(tt.jobList).Build()),
+				recorder:  recorder,
+			}
+			if _, err := r.GetNodeDeadlines(ctx, nodeset, []*corev1.Pod{pod, pod2}); err != nil {
+				t.Fatalf("realSlurmControl.GetNodeDeadlines() error = %v", err)
+			}
+			jobList := tt.jobList
+			if tt.preStop {
+				jobList = &types.V0043JobInfoList{}
+				r.clientMap = newSlurmClientMap(controller.Name, fake.NewClientBuilder().WithLists(jobList).Build())
+				if _, err := r.GetNodeDeadlines(ctx, nodeset, []*corev1.Pod{terminatingPod, pod2}); err != nil {
+					t.Fatalf("realSlurmControl.GetNodeDeadlines() error = %v", err)
+				}
+			}
+
+			var gotDown bool
+			updateFn := func(_ context.Context, obj object.Object, req any, opts ...client.UpdateOption) error {
+				r, ok := req.(api.V0043UpdateNodeMsg)
+				if !ok {
+					return errors.New("failed to cast request object")
+				}
+				gotDown = slices.Contains(ptr.Deref(r.State, nil), api.V0043UpdateNodeMsgStateDOWN)
+				return nil
+			}
+			builder := fake.NewClientBuilder().WithUpdateFn(updateFn).WithLists(jobList)
+			if tt.node != nil {
+				builder = builder.WithObjects(tt.node)
+			}
+			r.clientMap = newSlurmClientMap(controller.Name, builder.Build())
+			got, err := r.RequeueNodeJobs(ctx, nodeset, terminatingPod, "test")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("realSlurmControl.RequeueNodeJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("realSlurmControl.RequeueNodeJobs() = %v, want %v", got, tt.want)
+			}
+			if gotDown != tt.wantDown {
+				t.Errorf("Slurm node DOWN = %v, want %v", gotDown, tt.wantDown)
+			}
+			select {
+			case event := <-recorder.Events:
+				if tt.wantEvent == "" {
+					t.Errorf("unexpected event = %q", event)
+				} else if want := "requeued jobs [1], failed jobs [2]"; !strings.Contains(event, want) || !strings.Contains(event, tt.wantEvent) {
+					t.Errorf("event = %q, want %q and %q", event, tt.wantEvent, want)
+				}
+			default:
+				if tt.wantEvent != "" {
+					t.Error("no event for RequeueNodeJobs()")
+				}
+			}
+
+			// The requeued jobs are forgotten.
+			if got, _ := r.RequeueNodeJobs(ctx, nodeset, terminatingPod, "test"); got != nil {
+				t.Errorf("realSlurmControl.RequeueNodeJobs() again = %v, want nil", got)
+			}
+		})
+	}
+}
+
 func Test_realSlurmControl_CalculateNodeStatus(t *testing.T) {
 	ctx := context.Background()
 	controller := &slinkyv1alpha1.Controller{
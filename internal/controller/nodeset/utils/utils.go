@@ -13,6 +13,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	k8scontroller "k8s.io/kubernetes/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -220,10 +221,11 @@ func GetPersistentVolumeClaimName(nodeset *slinkyv1alpha1.NodeSet, claim *corev1
 	return fmt.Sprintf("%s-%s-%d", claim.Name, nodeset.Name, ordinal)
 }
 
-// SetOwnerReferences modifies the object with all NodeSets as non-controller owners.
-func SetOwnerReferences(r client.Client, ctx context.Context, object metav1.Object, clusterName string) error {
+// SetOwnerReferences modifies the object with all NodeSets of the controller, in
+// the namespace of the object, as non-controller owners.
+func SetOwnerReferences(r client.Client, ctx context.Context, object metav1.Object, controllerKey types.NamespacedName) error {
 	nodesetList := &slinkyv1alpha1.NodeSetList{}
-	if err := r.List(ctx, nodesetList); err != nil {
+	if err := r.List(ctx, nodesetList, client.InNamespace(object.GetNamespace())); err != nil {
 		return err
 	}
 
@@ -231,7 +233,7 @@ func SetOwnerReferences(r client.Client, ctx context.Context, object metav1.Obje
 		controllerutil.WithBlockOwnerDeletion(true),
 	}
 	for _, nodeset := range nodesetList.Items {
-		if nodeset.Spec.ControllerRef.Name != clusterName {
+		if !nodeset.Spec.ControllerRef.IsMatch(controllerKey) {
 			continue
 		}
 		if err := controllerutil.SetOwnerReference(&nodeset, object, r.Scheme(), opts...); err != nil {
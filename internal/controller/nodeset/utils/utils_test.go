@@ -4,6 +4,7 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -12,12 +13,19 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 )
 
+func init() {
+	utilruntime.Must(slinkyv1alpha1.AddToScheme(scheme.Scheme))
+}
+
 func newNodeSet(name string) *slinkyv1alpha1.NodeSet {
 	petMounts := []corev1.VolumeMount{
 		{Name: "datadir", MountPath: "/tmp/zookeeper"},
@@ -540,3 +548,47 @@ func TestGetPersistentVolumeClaimName(t *testing.T) {
 		})
 	}
 }
+
+func TestSetOwnerReferences(t *testing.T) {
+	newNodeSet := func(name, namespace string, controllerRef slinkyv1alpha1.ObjectReference) *slinkyv1alpha1.NodeSet {
+		return &slinkyv1alpha1.NodeSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       types.UID(namespace + "/" + name),
+			},
+			Spec: slinkyv1alpha1.NodeSetSpec{
+				ControllerRef: controllerRef,
+			},
+		}
+	}
+	controllerRef := slinkyv1alpha1.ObjectReference{Name: "slurm", Namespace: "slurm"}
+	otherRef := slinkyv1alpha1.ObjectReference{Name: "slurm", Namespace: "other"}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			newNodeSet("foo", "tenant", controllerRef),
+			newNodeSet("bar", "tenant", controllerRef),
+			newNodeSet("baz", "tenant", otherRef),
+			newNodeSet("foo", "slurm", controllerRef),
+		).
+		Build()
+	object := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm-workers-slurm",
+			Namespace: "tenant",
+		},
+	}
+
+	if err := SetOwnerReferences(c, context.TODO(), object, controllerRef.NamespacedName()); err != nil {
+		t.Fatalf("SetOwnerReferences() error = %v", err)
+	}
+	got := []string{}
+	for _, ref := range object.OwnerReferences {
+		got = append(got, string(ref.UID))
+	}
+	want := []string{"tenant/bar", "tenant/foo"}
+	if !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("SetOwnerReferences() = %v, want %v", got, want)
+	}
+}
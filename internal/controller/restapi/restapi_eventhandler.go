@@ -138,10 +138,23 @@ func (e *secretEventHandler) enqueueRequest(
 		logger.Error(err, "failed to list controller CRs")
 	}
 
+	restapiList := &slinkyv1alpha1.RestApiList{}
+	if err := e.List(ctx, restapiList, client.InNamespace(secret.Namespace)); err != nil {
+		logger.Error(err, "failed to list restapi CRs")
+	}
+
+	for _, restapi := range restapiList.Items {
+		if restapi.HasTls() && secretKey.String() == restapi.TlsSecretKey().String() {
+			objectutils.EnqueueRequest(q, &restapi)
+		}
+	}
+
 	for _, controller := range controllerList.Items {
 		slurmKeyKey := controller.AuthSlurmKey()
+		slurmJwksKey := controller.SlurmJwksKey()
 		jwtHs256KeyKey := controller.AuthJwtHs256Key()
 		if secretKey.String() != slurmKeyKey.String() &&
+			secretKey.String() != slurmJwksKey.String() &&
 			secretKey.String() != jwtHs256KeyKey.String() {
 			continue
 		}
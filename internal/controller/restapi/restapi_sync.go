@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
@@ -49,6 +50,28 @@ func (r *RestapiReconciler) Sync(ctx context.Context, req reconcile.Request) err
 				return nil
 			},
 		},
+		{
+			Name: "TlsSecret",
+			Sync: func(ctx context.Context, restapi *slinkyv1alpha1.RestApi) error {
+				// A Secret named by secretName (e.g. issued by cert-manager)
+				// is waited for, and reported by the TlsSecretReady condition.
+				if !restapi.GeneratesTlsSecret() {
+					return nil
+				}
+				// Only generate the certificate, never replace an existing one.
+				if err := r.Get(ctx, restapi.TlsSecretKey(), &corev1.Secret{}); !apierrors.IsNotFound(err) {
+					return err
+				}
+				object, err := r.builder.BuildRestapiTlsSecret(restapi)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
 		{
 			Name: "Deployment",
 			Sync: func(ctx context.Context, restapi *slinkyv1alpha1.RestApi) error {
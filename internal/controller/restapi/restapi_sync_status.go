@@ -7,8 +7,10 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -30,6 +32,16 @@ func (r *RestapiReconciler) syncStatus(
 	}
 	newStatus.Conditions = append(newStatus.Conditions, restapi.Status.Conditions...)
 
+	cond, err := r.tlsSecretCondition(ctx, restapi)
+	if err != nil {
+		return err
+	}
+	if cond != nil {
+		meta.SetStatusCondition(&newStatus.Conditions, *cond)
+	} else {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1alpha1.RestApiConditionTlsSecretReady)
+	}
+
 	if apiequality.Semantic.DeepEqual(restapi.Status, newStatus) {
 		logger.V(2).Info("Restapi Status has not changed, skipping status update",
 			"restapi", klog.KObj(restapi), "status", restapi.Status)
@@ -44,6 +56,37 @@ func (r *RestapiReconciler) syncStatus(
 	return nil
 }
 
+// tlsSecretCondition returns the TlsSecretReady condition, or nil if TLS is
+// not used.
+func (r *RestapiReconciler) tlsSecretCondition(
+	ctx context.Context,
+	restapi *slinkyv1alpha1.RestApi,
+) (*metav1.Condition, error) {
+	if !restapi.HasTls() {
+		return nil, nil
+	}
+
+	key := restapi.TlsSecretKey()
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return &metav1.Condition{
+			Type:    slinkyv1alpha1.RestApiConditionTlsSecretReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  slinkyv1alpha1.TlsSecretReasonNotFound,
+			Message: fmt.Sprintf("Waiting for the TLS Secret %s to be created", key),
+		}, nil
+	}
+	return &metav1.Condition{
+		Type:    slinkyv1alpha1.RestApiConditionTlsSecretReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  slinkyv1alpha1.TlsSecretReasonAvailable,
+		Message: fmt.Sprintf("TLS Secret %s exists", key),
+	}, nil
+}
+
 func (r *RestapiReconciler) updateStatus(
 	ctx context.Context,
 	cluster *slinkyv1alpha1.RestApi,
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package restapi
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func TestRestapiReconciler_tlsSecretCondition(t *testing.T) {
+	controller := testutils.NewController("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), nil)
+	newRestapi := func(secretName string) *slinkyv1alpha1.RestApi {
+		restapi := testutils.NewRestapi("slurm", controller)
+		restapi.Spec.Tls = &slinkyv1alpha1.RestApiTls{
+			SecretName: secretName,
+			Proxy: slinkyv1alpha1.ContainerMinimal{
+				Image: "socat",
+			},
+		}
+		return restapi
+	}
+	tlsSecret := func(restapi *slinkyv1alpha1.RestApi) *corev1.Secret {
+		key := restapi.TlsSecretKey()
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+	tests := []struct {
+		name       string
+		client     client.Client
+		restapi    *slinkyv1alpha1.RestApi
+		wantCond   bool
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:    "No TLS",
+			client:  fake.NewFakeClient(),
+			restapi: testutils.NewRestapi("slurm", controller),
+		},
+		{
+			name:       "Named Secret not found",
+			client:     fake.NewFakeClient(),
+			restapi:    newRestapi("slurm-restapi-cert"),
+			wantCond:   true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: slinkyv1alpha1.TlsSecretReasonNotFound,
+		},
+		{
+			name:       "Named Secret exists",
+			client:     fake.NewFakeClient(tlsSecret(newRestapi("slurm-restapi-cert"))),
+			restapi:    newRestapi("slurm-restapi-cert"),
+			wantCond:   true,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: slinkyv1alpha1.TlsSecretReasonAvailable,
+		},
+		{
+			name:       "Generated Secret exists",
+			client:     fake.NewFakeClient(tlsSecret(newRestapi(""))),
+			restapi:    newRestapi(""),
+			wantCond:   true,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: slinkyv1alpha1.TlsSecretReasonAvailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReconciler(tt.client)
+			cond, err := r.tlsSecretCondition(context.TODO(), tt.restapi)
+			if err != nil {
+				t.Fatalf("tlsSecretCondition() error = %v", err)
+			}
+			if (cond != nil) != tt.wantCond {
+				t.Fatalf("tlsSecretCondition() = %v, wantCond %v", cond, tt.wantCond)
+			}
+			if cond == nil {
+				return
+			}
+			if cond.Status != tt.wantStatus || cond.Reason != tt.wantReason {
+				t.Errorf("tlsSecretCondition() = %v/%v, want %v/%v", cond.Status, cond.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
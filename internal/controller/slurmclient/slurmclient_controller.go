@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -24,6 +25,7 @@ import (
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/durationstore"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/failover"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 )
 
@@ -32,15 +34,21 @@ const (
 
 	// BackoffGCInterval is the time that has to pass before next iteration of backoff GC is run
 	BackoffGCInterval = 1 * time.Minute
+
+	// externalTokenRefresh is how often the token of an external slurmrestd is read again.
+	externalTokenRefresh = 5 * time.Minute
 )
 
 func init() {
 	flag.IntVar(&maxConcurrentReconciles, "slurmclient-workers", maxConcurrentReconciles, "Max concurrent workers for SlurmClient controller.")
+	flag.StringVar((*string)(&endpointPolicy), "slurmclient-endpoint-policy", string(endpointPolicy), "How the Slurm client selects between the ready RestApis of a Controller (failover, round-robin).")
 }
 
 var (
 	maxConcurrentReconciles = 1
 
+	endpointPolicy = failover.PolicyFailover
+
 	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
 	durationStore = durationstore.NewDurationStore(durationstore.Greater)
 
@@ -56,11 +64,19 @@ type SlurmClientReconciler struct {
 	ClientMap *clientmap.ClientMap
 	EventCh   chan event.GenericEvent
 
+	// transports holds the RestApi endpoints of the client of each Controller.
+	transports sync.Map
+	// caChecksums holds the checksum of the RestApi CA certificates trusted by
+	// the transport of each Controller.
+	caChecksums sync.Map
+
 	refResolver   *refresolver.RefResolver
 	eventRecorder record.EventRecorderLogger
 }
 
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=restapis,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -100,6 +116,12 @@ func (r *SlurmClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(ControllerName).
 		For(&slinkyv1alpha1.Controller{}).
+		Watches(&slinkyv1alpha1.RestApi{}, &restapiEventHandler{
+			Reader: mgr.GetClient(),
+		}).
+		Watches(&appsv1.Deployment{}, &restapiEventHandler{
+			Reader: mgr.GetClient(),
+		}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
@@ -115,6 +137,9 @@ func NewReconciler(c client.Client, cm *clientmap.ClientMap, ec chan event.Gener
 	if ec == nil {
 		panic("EventCh cannot be nil")
 	}
+	if endpointPolicy != failover.PolicyFailover && endpointPolicy != failover.PolicyRoundRobin {
+		panic("unknown slurmclient-endpoint-policy: " + endpointPolicy)
+	}
 	return &SlurmClientReconciler{
 		Client: c,
 		Scheme: s,
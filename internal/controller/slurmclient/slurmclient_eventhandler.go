@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmclient
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+var _ handler.EventHandler = &restapiEventHandler{}
+
+// restapiEventHandler enqueues the Controller of a RestApi, or of the
+// Deployment of a RestApi, so the endpoints of its client are updated.
+type restapiEventHandler struct {
+	client.Reader
+}
+
+func (e *restapiEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *restapiEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *restapiEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *restapiEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *restapiEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	logger := log.FromContext(ctx)
+
+	var restapi *slinkyv1alpha1.RestApi
+	switch o := obj.(type) {
+	case *slinkyv1alpha1.RestApi:
+		restapi = o
+	case *appsv1.Deployment:
+		owner := metav1.GetControllerOf(o)
+		if owner == nil || owner.Kind != slinkyv1alpha1.RestApiKind {
+			return
+		}
+		restapi = &slinkyv1alpha1.RestApi{}
+		restapiKey := types.NamespacedName{Namespace: o.Namespace, Name: owner.Name}
+		if err := e.Get(ctx, restapiKey, restapi); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "failed to get RestApi owning Deployment", "restapi", restapiKey)
+			}
+			return
+		}
+	default:
+		return
+	}
+
+	q.Add(reconcile.Request{NamespacedName: restapi.Spec.ControllerRef.NamespacedName()})
+}
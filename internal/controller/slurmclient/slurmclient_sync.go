@@ -5,14 +5,20 @@ package slurmclient
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -25,6 +31,9 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/builder"
 	nodesetcontroller "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/failover"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmversion"
 )
 
 // Sync implements control logic for synchronizing a Restapi.
@@ -35,125 +44,275 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 	if err := r.Get(ctx, req.NamespacedName, controller); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("Removed slurm client", "controller", req)
-			_ = r.ClientMap.Remove(req.NamespacedName)
+			r.removeClient(req.NamespacedName)
 			return nil
 		}
 		return err
 	}
 	controllerKey := client.ObjectKeyFromObject(controller)
 
-	server, err := r.getRestApiServer(ctx, controller)
+	servers, err := r.getRestApiServers(ctx, controller)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			_ = r.ClientMap.Remove(controllerKey)
+			r.removeClient(controllerKey)
 			durationStore.Push(controllerKey.String(), 10*time.Second)
 			return nil
 		}
 		return err
 	}
-
-	if ok, err := r.isRestapiReady(ctx, controller); err != nil || !ok {
-		_ = r.ClientMap.Remove(controllerKey)
+	if len(servers) == 0 {
+		r.removeClient(controllerKey)
 		durationStore.Push(controllerKey.String(), 10*time.Second)
-		return err
+		return nil
 	}
 
-	signingKey, err := r.refResolver.GetSecretKeyRef(ctx, controller.AuthJwtHs256Ref(), controller.Namespace)
+	authToken, refresh, err := r.getAuthToken(ctx, controller)
 	if err != nil {
 		return err
 	}
 
-	lifetime := 15 * time.Minute
-	refresh := lifetime * 4 / 5
-	authToken, err := slurmjwt.NewToken(signingKey).
-		WithLifetime(lifetime).
-		NewSignedToken()
-	if err != nil {
-		return fmt.Errorf("failed to create Slurm auth token: %w", err)
+	if t := durationStore.Peek(controllerKey.String()); t == 0 {
+		logger.Info("Refresh token before expiration", "refresh", time.Now().Add(refresh))
+		durationStore.Push(controllerKey.String(), refresh)
 	}
 
-	authTokenClaims, err := slurmjwt.ParseTokenClaims(authToken, signingKey)
-	if err != nil {
-		return fmt.Errorf("failed to parse Slurm auth token: %w", err)
+	transport, loaded := r.getTransport(controllerKey)
+	if err := transport.SetEndpoints(servers); err != nil {
+		return err
 	}
-	exp, err := authTokenClaims.GetExpirationTime()
+	logger.V(1).Info("Slurm client endpoints", "endpoints", transport.Endpoints())
+
+	caCertificates, err := r.getRestApiCaCertificates(ctx, controller)
 	if err != nil {
-		return fmt.Errorf("failed to get expiration time: %w", err)
+		return err
+	}
+	checksum := crypto.CheckSum(caCertificates)
+	if prev, ok := r.caChecksums.Load(controllerKey.String()); !loaded || !ok || prev != checksum {
+		base, err := newBaseTransport(caCertificates)
+		if err != nil {
+			return err
+		}
+		transport.SetBase(base)
+		r.caChecksums.Store(controllerKey.String(), checksum)
 	}
 
-	if t := durationStore.Peek(controllerKey.String()); t == 0 {
-		logger.Info("Refresh token before expiration", "exp", exp, "refresh", time.Now().Add(refresh))
-		durationStore.Push(controllerKey.String(), refresh)
+	httpClient := &http.Client{
+		Transport: transport,
 	}
+	version, versionErr := negotiateVersion(ctx, httpClient, servers[0], authToken)
 
 	// There is an existing client, handle in-place updates
-	if slurmClient := r.ClientMap.Get(controllerKey); slurmClient != nil {
-		slurmClient.SetServer(server)
-		slurmClient.SetToken(authToken)
-		return nil
+	if slurmClient := r.ClientMap.Get(controllerKey); slurmClient != nil && loaded {
+		currentVersion := slurmversion.VersionOf(slurmClient)
+		if versionErr != nil {
+			logger.Error(versionErr, "Failed to negotiate Slurm REST API version, keeping the current one",
+				"controller", controllerKey.String(), "version", currentVersion)
+			version = currentVersion
+		}
+		if version == currentVersion {
+			slurmClient.SetToken(authToken)
+			return nil
+		}
+		logger.Info("Slurm REST API version changed, replacing slurm client",
+			"controller", controllerKey.String(), "old", currentVersion, "new", version)
+	} else if versionErr != nil {
+		return versionErr
 	}
 
 	config := &slurmclient.Config{
-		Server:    server,
-		AuthToken: authToken,
+		// The transport replaces the server with a ready endpoint.
+		Server:     servers[0],
+		AuthToken:  authToken,
+		HTTPClient: httpClient,
 	}
 	options := &slurmclient.ClientOptions{
 		DisableFor: []slurmobject.Object{
-			&slurmtypes.V0041ControllerPing{},
+			slurmversion.NewObject(version, slurmtypes.ObjectTypeV0043ControllerPing),
 		},
 	}
-	slurmClient, err := slurmclient.NewClient(config, options)
+	baseClient, err := slurmclient.NewClient(config, options)
 	if err != nil {
 		return fmt.Errorf("failed to create slurm client: %w", err)
 	}
+	slurmClient := slurmversion.NewClient(baseClient, version)
 	nodesetcontroller.SetEventHandler(slurmClient, r.EventCh)
 
 	if r.ClientMap.Add(controllerKey, slurmClient) {
-		logger.Info("Added slurm client", "controller", controllerKey.String())
+		logger.Info("Added slurm client", "controller", controllerKey.String(), "version", version)
 	}
 
 	return nil
 }
 
-func (r *SlurmClientReconciler) getRestApiServer(ctx context.Context, controller *slinkyv1alpha1.Controller) (string, error) {
+// getAuthToken returns the JWT which the client authenticates to slurmrestd
+// with, and when it should be refreshed.
+func (r *SlurmClientReconciler) getAuthToken(ctx context.Context, controller *slinkyv1alpha1.Controller) (string, time.Duration, error) {
 	logger := log.FromContext(ctx)
 
-	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
+	if slurmrestd := controller.ExternalSlurmrestd(); slurmrestd != nil && slurmrestd.TokenRef != nil {
+		// The token is managed outside of the operator, read it again in case it was rotated.
+		token, err := r.refResolver.GetSecretKeyRef(ctx, slurmrestd.TokenRef, controller.Namespace)
+		if err != nil {
+			return "", 0, err
+		}
+		return strings.TrimSpace(string(token)), externalTokenRefresh, nil
+	}
+
+	signingRef := controller.AuthJwtSigningRef()
+	signingKey, err := r.refResolver.GetSecretKeyRef(ctx, signingRef, controller.Namespace)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
-	if len(restapiList.Items) == 0 {
-		return "", errors.New(http.StatusText(http.StatusNotFound))
+
+	lifetime := 15 * time.Minute
+	refresh := lifetime * 4 / 5
+	newToken := slurmjwt.NewToken(signingKey).
+		WithLifetime(lifetime)
+	authToken, err := newToken.NewSignedToken()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create Slurm auth token: %w", err)
 	}
 
-	server := fmt.Sprintf("http://%s:%d", restapiList.Items[0].ServiceFQDNShort(), builder.SlurmrestdPort)
-	if val := os.Getenv("DEBUG"); val == "1" {
-		logger.Info("overriding restapi URL with localhost")
-		server = fmt.Sprintf("http://localhost:%d", builder.SlurmrestdPort)
+	authTokenClaims, err := slurmjwt.ParseTokenClaims(authToken, signingKey)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse Slurm auth token: %w", err)
+	}
+	exp, err := authTokenClaims.GetExpirationTime()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get expiration time: %w", err)
 	}
+	logger.V(1).Info("Created Slurm auth token", "exp", exp)
+
+	return authToken, refresh, nil
+}
+
+// negotiateVersion returns the preferred Slurm REST API version served by
+// slurmrestd, which the operator supports.
+func negotiateVersion(ctx context.Context, httpClient *http.Client, server, token string) (slurmversion.Version, error) {
+	versions, err := slurmversion.Discover(ctx, httpClient, server, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover Slurm REST API versions: %w", err)
+	}
+	return slurmversion.Negotiate(versions)
+}
+
+// getTransport returns the transport of the client of the Controller, and
+// whether it already existed.
+func (r *SlurmClientReconciler) getTransport(controllerKey client.ObjectKey) (*failover.Transport, bool) {
+	transport, loaded := r.transports.LoadOrStore(controllerKey.String(), failover.NewTransport(nil, endpointPolicy))
+	return transport.(*failover.Transport), loaded
+}
 
-	return server, nil
+func (r *SlurmClientReconciler) removeClient(controllerKey client.ObjectKey) {
+	_ = r.ClientMap.Remove(controllerKey)
+	r.transports.Delete(controllerKey.String())
+	r.caChecksums.Delete(controllerKey.String())
 }
 
-func (r *SlurmClientReconciler) isRestapiReady(ctx context.Context, controller *slinkyv1alpha1.Controller) (bool, error) {
+// newBaseTransport returns the transport of the client, which trusts the CA
+// certificates of the RestApis, in PEM format, in addition to the system CAs.
+func newBaseTransport(caCertificates []byte) (http.RoundTripper, error) {
+	if len(caCertificates) == 0 {
+		return nil, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caCertificates) {
+		return nil, errors.New("failed to parse RestApi CA certificates")
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return base, nil
+}
+
+// getRestApiCaCertificates returns the CA certificates, in PEM format, of the
+// RestApis of the Controller which terminate TLS.
+func (r *SlurmClientReconciler) getRestApiCaCertificates(ctx context.Context, controller *slinkyv1alpha1.Controller) ([]byte, error) {
+	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(restapiList.Items, func(a, b slinkyv1alpha1.RestApi) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	out := []byte{}
+	for _, restapi := range restapiList.Items {
+		if !restapi.HasTls() {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, restapi.TlsSecretKey(), secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		out = append(out, builder.RestapiCaCertificate(secret)...)
+	}
+	return out, nil
+}
+
+// getRestApiServers returns the URLs of the RestApis of the Controller with
+// ready replicas, ordered by name, followed by the internal slurmrestd of the
+// Controller if enabled, and the slurmrestd of the external cluster if any. It
+// returns a NotFound error if the Controller has none of them. The internal
+// slurmrestd serves plain HTTP, so it is left out when any RestApi has TLS, such
+// that the JWTs are never sent in clear text.
+func (r *SlurmClientReconciler) getRestApiServers(ctx context.Context, controller *slinkyv1alpha1.Controller) ([]string, error) {
 	logger := log.FromContext(ctx)
 
 	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	hasInternal := controller.Spec.InternalSlurmrestd != nil
+	external := controller.ExternalSlurmrestd()
+	if len(restapiList.Items) == 0 && !hasInternal && external == nil {
+		return nil, apierrors.NewNotFound(slinkyv1alpha1.GroupVersion.WithResource("restapis").GroupResource(), controller.Name)
+	}
+	slices.SortFunc(restapiList.Items, func(a, b slinkyv1alpha1.RestApi) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if val := os.Getenv("DEBUG"); val == "1" {
+		logger.Info("overriding restapi URL with localhost")
+		return []string{fmt.Sprintf("http://localhost:%d", builder.SlurmrestdPort)}, nil
 	}
 
+	servers := []string{}
+	hasTls := false
 	for _, restapi := range restapiList.Items {
+		if restapi.HasTls() {
+			hasTls = true
+		}
 		deployment := &appsv1.Deployment{}
 		deploymentKey := restapi.Key()
 		if err := r.Get(ctx, deploymentKey, deployment); err != nil {
-			return false, err
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
 		}
-		if deployment.Status.ReadyReplicas > 0 {
-			logger.V(2).Info("Restapi deployment ready replica count", "replicas", deployment.Status.ReadyReplicas)
-			return true, nil
+		if deployment.Status.ReadyReplicas == 0 {
+			logger.V(1).Info("Restapi has no ready replicas, skipping...", "restapi", klog.KObj(&restapi))
+			continue
 		}
+		servers = append(servers, fmt.Sprintf("%s://%s:%d", builder.RestapiScheme(&restapi), restapi.ServiceFQDNShort(), builder.SlurmrestdPort))
+	}
+	if hasInternal && hasTls {
+		logger.V(1).Info("Restapi has TLS, skipping the internal slurmrestd...", "controller", klog.KObj(controller))
+	} else if hasInternal {
+		servers = append(servers, builder.ControllerSlurmrestdURL(controller))
+	}
+	if external != nil {
+		servers = append(servers, strings.TrimSuffix(external.URL, "/"))
 	}
 
-	return false, nil
+	return servers, nil
 }
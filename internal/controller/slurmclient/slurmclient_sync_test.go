@@ -0,0 +1,323 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+)
+
+func newRestapi(name string, controller *slinkyv1alpha1.Controller) *slinkyv1alpha1.RestApi {
+	return &slinkyv1alpha1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: controller.Namespace,
+			Name:      name,
+		},
+		Spec: slinkyv1alpha1.RestApiSpec{
+			ControllerRef: slinkyv1alpha1.ObjectReference{
+				Namespace: controller.Namespace,
+				Name:      controller.Name,
+			},
+		},
+	}
+}
+
+func newRestapiDeployment(restapi *slinkyv1alpha1.RestApi, readyReplicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: restapi.Key().Namespace,
+			Name:      restapi.Key().Name,
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: readyReplicas,
+		},
+	}
+}
+
+func restapiServer(restapi *slinkyv1alpha1.RestApi) string {
+	return fmt.Sprintf("http://%s:%d", restapi.ServiceFQDNShort(), builder.SlurmrestdPort)
+}
+
+func TestSlurmClientReconciler_getRestApiServers(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	a := newRestapi("a", controller)
+	b := newRestapi("b", controller)
+	notReady := newRestapi("not-ready", controller)
+	noDeployment := newRestapi("no-deployment", controller)
+	tlsRestapi := newRestapi("tls", controller)
+	tlsRestapi.Spec.Tls = &slinkyv1alpha1.RestApiTls{}
+	tlsNotReady := newRestapi("tls-not-ready", controller)
+	tlsNotReady.Spec.Tls = &slinkyv1alpha1.RestApiTls{}
+	internal := controller.DeepCopy()
+	internal.Spec.InternalSlurmrestd = &slinkyv1alpha1.ContainerMinimal{}
+	internalServer := fmt.Sprintf("http://%s:%d", internal.InternalRestapiServiceFQDNShort(), builder.SlurmrestdPort)
+	external := controller.DeepCopy()
+	external.Spec.External = &slinkyv1alpha1.ExternalController{
+		Host: "slurmctld.example.com",
+		Slurmrestd: &slinkyv1alpha1.ExternalSlurmrestd{
+			URL: "https://slurmrestd.example.com:6820/",
+		},
+	}
+	externalServer := "https://slurmrestd.example.com:6820"
+	other := newRestapi("other", &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "other"},
+	})
+	tests := []struct {
+		name         string
+		controller   *slinkyv1alpha1.Controller
+		objects      []runtime.Object
+		want         []string
+		wantTls      bool
+		wantNotFound bool
+	}{
+		{
+			name:         "No RestApi",
+			objects:      []runtime.Object{other, newRestapiDeployment(other, 1)},
+			wantNotFound: true,
+		},
+		{
+			name: "Ready RestApis",
+			objects: []runtime.Object{
+				b, newRestapiDeployment(b, 2),
+				notReady, newRestapiDeployment(notReady, 0),
+				noDeployment,
+				a, newRestapiDeployment(a, 1),
+				other, newRestapiDeployment(other, 1),
+			},
+			want: []string{restapiServer(a), restapiServer(b)},
+		},
+		{
+			name:    "No ready RestApi",
+			objects: []runtime.Object{notReady, newRestapiDeployment(notReady, 0)},
+			want:    []string{},
+		},
+		{
+			name:       "Internal slurmrestd",
+			controller: internal,
+			objects:    []runtime.Object{other, newRestapiDeployment(other, 1)},
+			want:       []string{internalServer},
+		},
+		{
+			name:       "Internal slurmrestd after ready RestApis",
+			controller: internal,
+			objects: []runtime.Object{
+				a, newRestapiDeployment(a, 1),
+				notReady, newRestapiDeployment(notReady, 0),
+			},
+			want: []string{restapiServer(a), internalServer},
+		},
+		{
+			name:       "Internal slurmrestd with TLS RestApi",
+			controller: internal,
+			objects: []runtime.Object{
+				tlsRestapi, newRestapiDeployment(tlsRestapi, 1),
+			},
+			want:    []string{fmt.Sprintf("https://%s:%d", tlsRestapi.ServiceFQDNShort(), builder.SlurmrestdPort)},
+			wantTls: true,
+		},
+		{
+			name:       "Internal slurmrestd with unavailable TLS RestApi",
+			controller: internal,
+			objects:    []runtime.Object{tlsNotReady, newRestapiDeployment(tlsNotReady, 0)},
+			want:       []string{},
+			wantTls:    true,
+		},
+		{
+			name:       "External slurmrestd",
+			controller: external,
+			objects:    []runtime.Object{other, newRestapiDeployment(other, 1)},
+			want:       []string{externalServer},
+		},
+		{
+			name:       "External slurmrestd after ready RestApis",
+			controller: external,
+			objects:    []runtime.Object{a, newRestapiDeployment(a, 1)},
+			want:       []string{restapiServer(a), externalServer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient(tt.objects...)
+			r := NewReconciler(c, clientmap.NewClientMap(), make(chan event.GenericEvent))
+			ctrl := controller
+			if tt.controller != nil {
+				ctrl = tt.controller
+			}
+			got, err := r.getRestApiServers(context.Background(), ctrl)
+			if tt.wantNotFound {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("getRestApiServers() error = %v, want NotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getRestApiServers() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("getRestApiServers() (-want,+got):\n%s", diff)
+			}
+			for _, server := range got {
+				if tt.wantTls && strings.HasPrefix(server, "http://") {
+					t.Errorf("getRestApiServers() = %v, has plain HTTP server %q", got, server)
+				}
+			}
+		})
+	}
+}
+
+func TestSlurmClientReconciler_getRestApiCaCertificates(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	plain := newRestapi("plain", controller)
+	tlsRestapi := newRestapi("tls", controller)
+	tlsRestapi.Spec.Tls = &slinkyv1alpha1.RestApiTls{}
+	noSecret := newRestapi("no-secret", controller)
+	noSecret.Spec.Tls = &slinkyv1alpha1.RestApiTls{}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: tlsRestapi.TlsSecretKey().Namespace,
+			Name:      tlsRestapi.TlsSecretKey().Name,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: []byte("certificate"),
+			"ca.crt":          []byte("ca"),
+		},
+	}
+
+	c := fake.NewFakeClient(plain, newRestapiDeployment(plain, 1), tlsRestapi, newRestapiDeployment(tlsRestapi, 1), noSecret, secret)
+	r := NewReconciler(c, clientmap.NewClientMap(), make(chan event.GenericEvent))
+
+	servers, err := r.getRestApiServers(context.Background(), controller)
+	if err != nil {
+		t.Fatalf("getRestApiServers() error = %v", err)
+	}
+	wantServers := []string{
+		restapiServer(plain),
+		fmt.Sprintf("https://%s:%d", tlsRestapi.ServiceFQDNShort(), builder.SlurmrestdPort),
+	}
+	if diff := cmp.Diff(wantServers, servers); diff != "" {
+		t.Errorf("getRestApiServers() (-want,+got):\n%s", diff)
+	}
+
+	got, err := r.getRestApiCaCertificates(context.Background(), controller)
+	if err != nil {
+		t.Fatalf("getRestApiCaCertificates() error = %v", err)
+	}
+	if string(got) != "ca" {
+		t.Errorf("getRestApiCaCertificates() = %s, want %s", got, "ca")
+	}
+
+	if _, err := newBaseTransport(got); err == nil {
+		t.Errorf("newBaseTransport() with invalid certificates, want error")
+	}
+	if base, err := newBaseTransport(nil); err != nil || base != nil {
+		t.Errorf("newBaseTransport() = %v, %v, want nil", base, err)
+	}
+}
+
+func TestSlurmClientReconciler_getAuthToken(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			JwtHs256KeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "jwt"},
+				Key:                  "jwt_hs256.key",
+			},
+		},
+	}
+	external := controller.DeepCopy()
+	external.Spec.External = &slinkyv1alpha1.ExternalController{
+		Host: "slurmctld.example.com",
+		Slurmrestd: &slinkyv1alpha1.ExternalSlurmrestd{
+			URL: "https://slurmrestd.example.com:6820",
+			TokenRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "token"},
+				Key:                  "SLURM_JWT",
+			},
+		},
+	}
+	jwtSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "jwt"},
+		Data:       map[string][]byte{"jwt_hs256.key": []byte("secret")},
+	}
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "token"},
+		Data:       map[string][]byte{"SLURM_JWT": []byte("external-token\n")},
+	}
+	tests := []struct {
+		name        string
+		controller  *slinkyv1alpha1.Controller
+		objects     []runtime.Object
+		wantToken   string
+		wantRefresh time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "Signed token",
+			controller:  controller,
+			objects:     []runtime.Object{jwtSecret},
+			wantRefresh: 12 * time.Minute,
+		},
+		{
+			name:        "External token",
+			controller:  external,
+			objects:     []runtime.Object{tokenSecret},
+			wantToken:   "external-token",
+			wantRefresh: externalTokenRefresh,
+		},
+		{
+			name:       "External token not found",
+			controller: external,
+			objects:    []runtime.Object{jwtSecret},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient(tt.objects...)
+			r := NewReconciler(c, clientmap.NewClientMap(), make(chan event.GenericEvent))
+			got, refresh, err := r.getAuthToken(context.Background(), tt.controller)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getAuthToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got == "" || (tt.wantToken != "" && got != tt.wantToken) {
+				t.Errorf("getAuthToken() token = %q, want %q", got, tt.wantToken)
+			}
+			if refresh != tt.wantRefresh {
+				t.Errorf("getAuthToken() refresh = %v, want %v", refresh, tt.wantRefresh)
+			}
+		})
+	}
+}
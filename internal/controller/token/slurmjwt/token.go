@@ -4,10 +4,13 @@
 package slurmjwt
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
 	"fmt"
 	"math"
 	"time"
 
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/mathutils"
 	jwt "github.com/golang-jwt/jwt/v5"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -24,15 +27,27 @@ type Token struct {
 	method     jwt.SigningMethod
 	username   string
 	lifetime   time.Duration
+	groups     []string
+	audience   []string
+	keyID      string
 }
 
+// NewToken returns a Token signed with the key. A PEM encoded RSA private key
+// signs with RS256, identified by its `kid` in the JWKS; any other key is an
+// HS256 key.
 func NewToken(signingKey []byte) *Token {
-	return &Token{
+	t := &Token{
 		signingKey: signingKey,
 		method:     jwt.SigningMethodHS256,
 		username:   "slurm",
 		lifetime:   infinite * time.Second,
 	}
+	if crypto.IsPem(signingKey) {
+		t.method = jwt.SigningMethodRS256
+		// An invalid key fails when signing.
+		t.keyID, _ = rs256KeyID(signingKey)
+	}
+	return t
 }
 
 func (t *Token) WithLifetime(lifetime time.Duration) *Token {
@@ -45,14 +60,30 @@ func (t *Token) WithUsername(username string) *Token {
 	return t
 }
 
+func (t *Token) WithGroups(groups ...string) *Token {
+	t.groups = groups
+	return t
+}
+
+func (t *Token) WithAudience(audience ...string) *Token {
+	t.audience = audience
+	return t
+}
+
 // Ref: https://slurm.schedmd.com/jwt.html#compatibility
 type TokenClaims struct {
 	jwt.RegisteredClaims `json:",inline"`
 
-	SlurmUsername string `json:"sun"`
+	SlurmUsername string   `json:"sun"`
+	Groups        []string `json:"groups,omitempty"`
 }
 
 func (t *Token) NewSignedToken() (string, error) {
+	key, err := signingKey(t.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
 	now := time.Now()
 	claims := TokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -63,11 +94,18 @@ func (t *Token) NewSignedToken() (string, error) {
 			NotBefore: jwt.NewNumericDate(now),
 		},
 		SlurmUsername: t.username,
+		Groups:        t.groups,
+	}
+	if len(t.audience) > 0 {
+		claims.Audience = t.audience
 	}
 
 	token := jwt.NewWithClaims(t.method, claims)
+	if t.keyID != "" {
+		token.Header["kid"] = t.keyID
+	}
 
-	tokenString, err := token.SignedString(t.signingKey)
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -75,9 +113,59 @@ func (t *Token) NewSignedToken() (string, error) {
 	return tokenString, nil
 }
 
+// KeyFingerprint returns a fingerprint of the signing key, which identifies the
+// key without revealing it. For an RSA key, this is of its public key.
+func KeyFingerprint(signingKey []byte) string {
+	if publicKey, err := RS256PublicKey(signingKey); err == nil {
+		return "SHA256:" + crypto.CheckSum(x509.MarshalPKCS1PublicKey(publicKey))
+	}
+	return "SHA256:" + crypto.CheckSum(signingKey)
+}
+
+// KeyID returns an identifier of the signing key, for use as a JWK `kid`.
+func KeyID(signingKey []byte) string {
+	if keyID, err := rs256KeyID(signingKey); err == nil {
+		return keyID
+	}
+	return crypto.CheckSum(signingKey)[:16]
+}
+
+// RS256PublicKey returns the public key of a PEM encoded RSA private key.
+func RS256PublicKey(signingKey []byte) (*rsa.PublicKey, error) {
+	privateKey, err := crypto.ParseRsaPrivateKey(signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &privateKey.PublicKey, nil
+}
+
+func rs256KeyID(signingKey []byte) (string, error) {
+	publicKey, err := RS256PublicKey(signingKey)
+	if err != nil {
+		return "", err
+	}
+	return crypto.CheckSum(x509.MarshalPKCS1PublicKey(publicKey))[:16], nil
+}
+
+// signingKey returns the key in the form expected by the signing method.
+func signingKey(key []byte) (any, error) {
+	if !crypto.IsPem(key) {
+		return key, nil
+	}
+	return crypto.ParseRsaPrivateKey(key)
+}
+
+// verifyingKey returns the key in the form expected by the verifying method.
+func verifyingKey(key []byte) (any, error) {
+	if !crypto.IsPem(key) {
+		return key, nil
+	}
+	return RS256PublicKey(key)
+}
+
 func ParseTokenClaims(tokenString string, signingKey []byte) (jwt.MapClaims, error) {
 	signingKeyFunc := func(token *jwt.Token) (any, error) {
-		return signingKey, nil
+		return verifyingKey(signingKey)
 	}
 
 	claims := jwt.MapClaims{}
@@ -91,7 +179,7 @@ func ParseTokenClaims(tokenString string, signingKey []byte) (jwt.MapClaims, err
 
 func VerifyToken(tokenString string, signingKey []byte) (bool, error) {
 	signingKeyFunc := func(token *jwt.Token) (any, error) {
-		return signingKey, nil
+		return verifyingKey(signingKey)
 	}
 
 	token, err := jwt.Parse(tokenString, signingKeyFunc)
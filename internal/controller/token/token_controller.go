@@ -59,7 +59,8 @@ type TokenReconciler struct {
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=tokens,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=tokens/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=tokens/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -105,6 +106,10 @@ func (r *TokenReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&slinkyv1alpha1.Token{}).
 		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, &secretEventHandler{}).
+		Watches(&corev1.Namespace{}, &namespaceEventHandler{
+			Reader: mgr.GetClient(),
+		}).
 		Complete(r)
 }
 
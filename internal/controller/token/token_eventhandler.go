@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+var _ handler.EventHandler = &secretEventHandler{}
+
+// secretEventHandler enqueues the Token which delivered the JWT into a target
+// Secret, which may be in another namespace.
+type secretEventHandler struct{}
+
+func (e *secretEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *secretEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *secretEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *secretEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *secretEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+
+	name := secret.Labels[slinkyv1alpha1.LabelTokenName]
+	namespace := secret.Labels[slinkyv1alpha1.LabelTokenNamespace]
+	if name == "" || namespace == "" {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	})
+}
+
+var _ handler.EventHandler = &namespaceEventHandler{}
+
+// namespaceEventHandler enqueues Tokens which deliver JWTs into a namespace,
+// as the namespace may have changed which Tokens it allows.
+type namespaceEventHandler struct {
+	client.Reader
+}
+
+func (e *namespaceEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *namespaceEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	oldNamespace, _ := evt.ObjectOld.(*corev1.Namespace)
+	newNamespace, _ := evt.ObjectNew.(*corev1.Namespace)
+	if oldNamespace != nil && newNamespace != nil &&
+		oldNamespace.Annotations[slinkyv1alpha1.AnnotationTokenSourceNamespaces] == newNamespace.Annotations[slinkyv1alpha1.AnnotationTokenSourceNamespaces] {
+		return
+	}
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *namespaceEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *namespaceEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *namespaceEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	logger := log.FromContext(ctx)
+
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+
+	tokenList := &slinkyv1alpha1.TokenList{}
+	if err := e.List(ctx, tokenList); err != nil {
+		logger.Error(err, "failed to list token CRs")
+		return
+	}
+
+	for _, token := range tokenList.Items {
+		for _, target := range token.Spec.Targets {
+			if token.TargetKey(target).Namespace != namespace.Name {
+				continue
+			}
+			q.Add(reconcile.Request{
+				NamespacedName: token.Key(),
+			})
+			break
+		}
+	}
+}
@@ -8,14 +8,17 @@ import (
 	"fmt"
 	"time"
 
+	jwt "github.com/golang-jwt/jwt/v5"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/metrics"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 )
 
@@ -32,7 +35,9 @@ func (r *TokenReconciler) Sync(ctx context.Context, req reconcile.Request) error
 	if err := r.Get(ctx, req.NamespacedName, token); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("Token has been deleted", "request", req)
-			return nil
+			metrics.DeleteToken(req.NamespacedName)
+			// Target Secrets in other namespaces cannot be garbage collected.
+			return r.deleteTargets(ctx, req.NamespacedName, nil)
 		}
 		return err
 	}
@@ -62,8 +67,11 @@ func (r *TokenReconciler) Sync(ctx context.Context, req reconcile.Request) error
 				if err != nil {
 					return err
 				}
-				jwtHs256Ref := token.JwtHs256Ref()
-				signingKey, err := r.refResolver.GetSecretKeyRef(ctx, &jwtHs256Ref.SecretKeySelector, jwtHs256Ref.Namespace)
+				signingRef, err := r.refResolver.GetTokenSigningKeyRef(ctx, token)
+				if err != nil {
+					return err
+				}
+				signingKey, err := r.refResolver.GetSecretKeyRef(ctx, &signingRef.SecretKeySelector, signingRef.Namespace)
 				if err != nil {
 					return err
 				}
@@ -87,7 +95,9 @@ func (r *TokenReconciler) Sync(ctx context.Context, req reconcile.Request) error
 				durationStore.Push(key, 30*time.Second)
 
 				refreshTime := expirationTime.Add(-token.Lifetime() * 1 / 5)
-				if now.Before(refreshTime) {
+				if !isClaimsMatch(token, authTokenClaims) {
+					logger.V(1).Info("token claims do not match the spec, refreshing")
+				} else if now.Before(refreshTime) {
 					logger.V(2).Info("token is not near expiration time yet, skipping...", "expirationTime", expirationTime)
 					return nil
 				}
@@ -99,10 +109,17 @@ func (r *TokenReconciler) Sync(ctx context.Context, req reconcile.Request) error
 				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
 					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
 				}
+				metrics.IncTokenRefreshes(token.Key())
 
 				return nil
 			},
 		},
+		{
+			Name: "Targets",
+			Sync: func(ctx context.Context, token *slinkyv1alpha1.Token) error {
+				return r.syncTargets(ctx, token)
+			},
+		},
 	}
 
 	for _, s := range syncSteps {
@@ -119,3 +136,25 @@ func (r *TokenReconciler) Sync(ctx context.Context, req reconcile.Request) error
 
 	return r.syncStatus(ctx, token)
 }
+
+// isClaimsMatch returns true if the JWT claims match those requested by the Token.
+func isClaimsMatch(token *slinkyv1alpha1.Token, claims jwt.MapClaims) bool {
+	if claims == nil {
+		return false
+	}
+	if sun, _ := claims["sun"].(string); sun != token.Username() {
+		return false
+	}
+	audience, err := claims.GetAudience()
+	if err != nil || !set.New(audience...).Equal(set.New(token.Spec.Audience...)) {
+		return false
+	}
+	groups := []string{}
+	if list, ok := claims["groups"].([]any); ok {
+		for _, item := range list {
+			group, _ := item.(string)
+			groups = append(groups, group)
+		}
+	}
+	return set.New(groups...).Equal(set.New(token.Spec.Groups...))
+}
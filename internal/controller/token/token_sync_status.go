@@ -9,6 +9,7 @@ import (
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -17,6 +18,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/metrics"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
 )
@@ -32,13 +34,25 @@ func (r *TokenReconciler) syncStatus(
 	if err != nil {
 		return err
 	}
-	jwtHs256Ref := token.JwtHs256Ref()
-	signingKey, err := r.refResolver.GetSecretKeyRef(ctx, &jwtHs256Ref.SecretKeySelector, jwtHs256Ref.Namespace)
+	signingRef, err := r.refResolver.GetTokenSigningKeyRef(ctx, token)
+	if err != nil {
+		return err
+	}
+	signingKey, err := r.refResolver.GetSecretKeyRef(ctx, &signingRef.SecretKeySelector, signingRef.Namespace)
 	if err != nil {
 		return err
 	}
 
 	authTokenClaims, err := slurmjwt.ParseTokenClaims(string(authToken), signingKey)
+	if err != nil && *signingRef != *token.JwtRef() {
+		// During a key rotation, the JWT may not have been reissued with the signing key yet.
+		jwtRef := token.JwtRef()
+		signingKey, err = r.refResolver.GetSecretKeyRef(ctx, &jwtRef.SecretKeySelector, jwtRef.Namespace)
+		if err != nil {
+			return err
+		}
+		authTokenClaims, err = slurmjwt.ParseTokenClaims(string(authToken), signingKey)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to parse Slurm auth token: %w", err)
 	}
@@ -52,9 +66,40 @@ func (r *TokenReconciler) syncStatus(
 		issuedAt = ptr.To(metav1.NewTime(iat.Time))
 	}
 
+	exp, err := authTokenClaims.GetExpirationTime()
+	if err != nil {
+		return fmt.Errorf("failed to get expiration time: %w", err)
+	}
+
+	var expiresAt *metav1.Time
+	if exp != nil {
+		expiresAt = ptr.To(metav1.NewTime(exp.Time))
+		metrics.SetTokenExpiration(token.Key(), exp.Time)
+	}
+
+	// The JWT was refreshed if it replaced one that was previously observed.
+	lastRefreshTime := token.Status.LastRefreshTime
+	if token.Status.IssuedAt != nil && issuedAt != nil && !token.Status.IssuedAt.Equal(issuedAt) {
+		lastRefreshTime = issuedAt
+	}
+
+	targetsCond, err := r.targetsCondition(ctx, token)
+	if err != nil {
+		return err
+	}
+
 	newStatus := &slinkyv1alpha1.TokenStatus{
-		IssuedAt:   issuedAt,
-		Conditions: structutils.MergeList(token.Status.Conditions),
+		IssuedAt:        issuedAt,
+		ExpiresAt:       expiresAt,
+		LastRefreshTime: lastRefreshTime,
+		KeyFingerprint:  slurmjwt.KeyFingerprint(signingKey),
+		Conditions:      structutils.MergeList(token.Status.Conditions),
+	}
+
+	if targetsCond == nil {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1alpha1.TokenConditionTargetsReady)
+	} else {
+		meta.SetStatusCondition(&newStatus.Conditions, *targetsCond)
 	}
 
 	if apiequality.Semantic.DeepEqual(token.Status, newStatus) {
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"testing"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/token/slurmjwt"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+func Test_isClaimsMatch(t *testing.T) {
+	signingKey := crypto.NewSigningKey()
+	parse := func(token *slurmjwt.Token) jwt.MapClaims {
+		tokenString, err := token.NewSignedToken()
+		if err != nil {
+			panic(err)
+		}
+		claims, err := slurmjwt.ParseTokenClaims(tokenString, signingKey)
+		if err != nil {
+			panic(err)
+		}
+		return claims
+	}
+	newToken := func(spec slinkyv1alpha1.TokenSpec) *slinkyv1alpha1.Token {
+		return &slinkyv1alpha1.Token{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "slurm",
+				Name:      "foo",
+			},
+			Spec: spec,
+		}
+	}
+	type args struct {
+		token  *slinkyv1alpha1.Token
+		claims jwt.MapClaims
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "nil claims",
+			args: args{
+				token: newToken(slinkyv1alpha1.TokenSpec{Username: "foo"}),
+			},
+			want: false,
+		},
+		{
+			name: "username",
+			args: args{
+				token:  newToken(slinkyv1alpha1.TokenSpec{Username: "foo"}),
+				claims: parse(slurmjwt.NewToken(signingKey).WithUsername("foo")),
+			},
+			want: true,
+		},
+		{
+			name: "different username",
+			args: args{
+				token:  newToken(slinkyv1alpha1.TokenSpec{Username: "foo"}),
+				claims: parse(slurmjwt.NewToken(signingKey).WithUsername("bar")),
+			},
+			want: false,
+		},
+		{
+			name: "groups and audience",
+			args: args{
+				token: newToken(slinkyv1alpha1.TokenSpec{
+					Username: "foo",
+					Groups:   []string{"a", "b"},
+					Audience: []string{"slurmrestd"},
+				}),
+				claims: parse(slurmjwt.NewToken(signingKey).
+					WithUsername("foo").
+					WithGroups("b", "a").
+					WithAudience("slurmrestd")),
+			},
+			want: true,
+		},
+		{
+			name: "missing groups",
+			args: args{
+				token: newToken(slinkyv1alpha1.TokenSpec{
+					Username: "foo",
+					Groups:   []string{"a"},
+				}),
+				claims: parse(slurmjwt.NewToken(signingKey).WithUsername("foo")),
+			},
+			want: false,
+		},
+		{
+			name: "different audience",
+			args: args{
+				token: newToken(slinkyv1alpha1.TokenSpec{
+					Username: "foo",
+					Audience: []string{"foo"},
+				}),
+				claims: parse(slurmjwt.NewToken(signingKey).
+					WithUsername("foo").
+					WithAudience("bar")),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isClaimsMatch(tt.args.token, tt.args.claims); got != tt.want {
+				t.Errorf("isClaimsMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
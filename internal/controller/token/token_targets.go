@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+type targetState struct {
+	target slinkyv1alpha1.TokenTarget
+	key    types.NamespacedName
+	reason string
+}
+
+// getTargetStates returns the delivery state of each target of the Token.
+func (r *TokenReconciler) getTargetStates(ctx context.Context, token *slinkyv1alpha1.Token) ([]targetState, error) {
+	states := make([]targetState, 0, len(token.Spec.Targets))
+	for _, target := range token.Spec.Targets {
+		key := token.TargetKey(target)
+
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: key.Namespace}, namespace); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			namespace = nil
+		}
+
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, key, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			secret = nil
+		}
+
+		states = append(states, targetState{
+			target: target,
+			key:    key,
+			reason: getTargetReason(token, namespace, secret),
+		})
+	}
+	return states, nil
+}
+
+// getTargetReason returns the delivery state of a target, given its namespace
+// and Secret, either of which may not exist.
+func getTargetReason(token *slinkyv1alpha1.Token, namespace *corev1.Namespace, secret *corev1.Secret) string {
+	switch {
+	case namespace == nil || !token.IsTargetNamespaceAllowed(namespace):
+		return slinkyv1alpha1.TokenTargetsReasonNotAllowed
+	case secret == nil:
+		return slinkyv1alpha1.TokenTargetsReasonPending
+	case secret.Labels[slinkyv1alpha1.LabelTokenName] != token.Name ||
+		secret.Labels[slinkyv1alpha1.LabelTokenNamespace] != token.Namespace:
+		return slinkyv1alpha1.TokenTargetsReasonConflict
+	default:
+		return slinkyv1alpha1.TokenTargetsReasonDelivered
+	}
+}
+
+// syncTargets delivers the JWT of the Token to its allowed targets, and
+// deletes the target Secrets which are no longer allowed or desired.
+func (r *TokenReconciler) syncTargets(ctx context.Context, token *slinkyv1alpha1.Token) error {
+	logger := log.FromContext(ctx)
+
+	states, err := r.getTargetStates(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	desired := map[types.NamespacedName]bool{}
+	if len(states) > 0 {
+		authToken, err := r.refResolver.GetSecretKeyRef(ctx, token.SecretRef(), token.Namespace)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			switch state.reason {
+			case slinkyv1alpha1.TokenTargetsReasonNotAllowed, slinkyv1alpha1.TokenTargetsReasonConflict:
+				logger.Info("Skipping token target", "secret", state.key, "reason", state.reason)
+				continue
+			}
+			desired[state.key] = true
+			object, err := r.builder.BuildTokenTargetSecret(token, state.target, string(authToken))
+			if err != nil {
+				return fmt.Errorf("failed to build: %w", err)
+			}
+			if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+				return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+			}
+		}
+	}
+
+	return r.deleteTargets(ctx, token.Key(), desired)
+}
+
+// deleteTargets deletes the target Secrets of the Token which are not desired.
+func (r *TokenReconciler) deleteTargets(ctx context.Context, tokenKey types.NamespacedName, desired map[types.NamespacedName]bool) error {
+	token := &slinkyv1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tokenKey.Name,
+			Namespace: tokenKey.Namespace,
+		},
+	}
+	secretList := &corev1.SecretList{}
+	opts := []client.ListOption{
+		client.MatchingLabels(labels.NewBuilder().WithTokenTargetLabels(token).Build()),
+	}
+	if err := r.List(ctx, secretList, opts...); err != nil {
+		return err
+	}
+	for _, secret := range secretList.Items {
+		if desired[client.ObjectKeyFromObject(&secret)] {
+			continue
+		}
+		if err := objectutils.DeleteObject(r.Client, ctx, &secret); err != nil {
+			return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(&secret), err)
+		}
+	}
+	return nil
+}
+
+// targetsCondition returns the TargetsReady condition, or nil if the Token has no targets.
+func (r *TokenReconciler) targetsCondition(ctx context.Context, token *slinkyv1alpha1.Token) (*metav1.Condition, error) {
+	if len(token.Spec.Targets) == 0 {
+		return nil, nil
+	}
+	states, err := r.getTargetStates(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := &metav1.Condition{
+		Type:               slinkyv1alpha1.TokenConditionTargetsReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: token.Generation,
+		Reason:             slinkyv1alpha1.TokenTargetsReasonDelivered,
+		Message:            fmt.Sprintf("The JWT was delivered to %d target(s).", len(states)),
+	}
+	// Report the first problem, in order of severity.
+	for _, reason := range []string{
+		slinkyv1alpha1.TokenTargetsReasonNotAllowed,
+		slinkyv1alpha1.TokenTargetsReasonConflict,
+		slinkyv1alpha1.TokenTargetsReasonPending,
+	} {
+		keys := []string{}
+		for _, state := range states {
+			if state.reason == reason {
+				keys = append(keys, state.key.String())
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = reason
+		cond.Message = targetsMessage(reason, keys)
+		break
+	}
+	return cond, nil
+}
+
+func targetsMessage(reason string, keys []string) string {
+	secrets := strings.Join(keys, ", ")
+	switch reason {
+	case slinkyv1alpha1.TokenTargetsReasonNotAllowed:
+		return fmt.Sprintf("The namespace of target(s) %s does not allow this namespace by the %s annotation.",
+			secrets, slinkyv1alpha1.AnnotationTokenSourceNamespaces)
+	case slinkyv1alpha1.TokenTargetsReasonConflict:
+		return fmt.Sprintf("Target(s) %s already exist and were not created by this Token.", secrets)
+	default:
+		return fmt.Sprintf("Delivering the JWT to target(s) %s.", secrets)
+	}
+}
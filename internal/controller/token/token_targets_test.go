@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+func newNamespace(name, sources string) *corev1.Namespace {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if sources != "" {
+		namespace.Annotations = map[string]string{
+			slinkyv1alpha1.AnnotationTokenSourceNamespaces: sources,
+		}
+	}
+	return namespace
+}
+
+func Test_getTargetReason(t *testing.T) {
+	token := &slinkyv1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "foo",
+		},
+	}
+	managed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				slinkyv1alpha1.LabelTokenName:      "foo",
+				slinkyv1alpha1.LabelTokenNamespace: "slurm",
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		namespace *corev1.Namespace
+		secret    *corev1.Secret
+		want      string
+	}{
+		{
+			name: "Namespace not found",
+			want: slinkyv1alpha1.TokenTargetsReasonNotAllowed,
+		},
+		{
+			name:      "Same namespace",
+			namespace: newNamespace("slurm", ""),
+			want:      slinkyv1alpha1.TokenTargetsReasonPending,
+		},
+		{
+			name:      "Not allowed",
+			namespace: newNamespace("apps", "other"),
+			want:      slinkyv1alpha1.TokenTargetsReasonNotAllowed,
+		},
+		{
+			name:      "Allowed",
+			namespace: newNamespace("apps", "other, slurm"),
+			secret:    managed,
+			want:      slinkyv1alpha1.TokenTargetsReasonDelivered,
+		},
+		{
+			name:      "Allowed by wildcard",
+			namespace: newNamespace("apps", "*"),
+			want:      slinkyv1alpha1.TokenTargetsReasonPending,
+		},
+		{
+			name:      "Conflict",
+			namespace: newNamespace("apps", "*"),
+			secret:    &corev1.Secret{},
+			want:      slinkyv1alpha1.TokenTargetsReasonConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getTargetReason(token, tt.namespace, tt.secret); got != tt.want {
+				t.Errorf("getTargetReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenReconciler_syncTargets(t *testing.T) {
+	token := &slinkyv1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "foo",
+		},
+		Spec: slinkyv1alpha1.TokenSpec{
+			Username: "foo",
+			Targets: []slinkyv1alpha1.TokenTarget{
+				{Name: "jwt", Namespace: "apps", Format: slinkyv1alpha1.TokenFormatEnv},
+				{Name: "jwt", Namespace: "denied"},
+			},
+		},
+	}
+	jwtSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      token.SecretKey().Name,
+		},
+		Data: map[string][]byte{
+			token.SecretRef().Key: []byte("jwt"),
+		},
+	}
+	stale := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "old",
+			Name:      "jwt",
+			Labels: map[string]string{
+				slinkyv1alpha1.LabelTokenName:      "foo",
+				slinkyv1alpha1.LabelTokenNamespace: "slurm",
+			},
+		},
+	}
+	c := fake.NewFakeClient(token, jwtSecret, stale,
+		newNamespace("slurm", ""), newNamespace("apps", "slurm"), newNamespace("denied", ""))
+	r := NewReconciler(c)
+	ctx := context.TODO()
+
+	if err := r.syncTargets(ctx, token); err != nil {
+		t.Fatalf("TokenReconciler.syncTargets() error = %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "jwt"}, secret); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := secret.StringData["slurm.env"]; got != "SLURM_JWT=jwt\n" {
+		t.Errorf("secret.StringData[slurm.env] = %q, want %q", got, "SLURM_JWT=jwt\n")
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "denied", Name: "jwt"}, secret); !apierrors.IsNotFound(err) {
+		t.Errorf("Get() denied target error = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(stale), secret); !apierrors.IsNotFound(err) {
+		t.Errorf("Get() stale target error = %v, want NotFound", err)
+	}
+
+	cond, err := r.targetsCondition(ctx, token)
+	if err != nil {
+		t.Fatalf("TokenReconciler.targetsCondition() error = %v", err)
+	}
+	if cond.Reason != slinkyv1alpha1.TokenTargetsReasonNotAllowed || cond.Status != metav1.ConditionFalse {
+		t.Errorf("TokenReconciler.targetsCondition() = %v", cond)
+	}
+}
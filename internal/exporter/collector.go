@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0043"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+)
+
+const (
+	namespace = "slurm"
+
+	// defaultTimeout bounds the slurmrestd requests made for each Controller on scrape.
+	defaultTimeout = 10 * time.Second
+)
+
+var (
+	clusterLabels = []string{"namespace", "controller"}
+
+	upDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "up"),
+		"Whether the last scrape of slurmrestd for the Controller succeeded.",
+		clusterLabels, nil,
+	)
+	nodeStatesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "nodes"),
+		"Number of Slurm nodes by base state.",
+		append(clusterLabels, "state"), nil,
+	)
+	nodesDrainDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "nodes_drain"),
+		"Number of Slurm nodes with the DRAIN flag.",
+		clusterLabels, nil,
+	)
+	nodeCpusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "node", "cpus"),
+		"Number of CPUs of the Slurm node.",
+		append(clusterLabels, "node"), nil,
+	)
+	nodeCpusAllocDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "node", "cpus_alloc"),
+		"Number of allocated CPUs of the Slurm node.",
+		append(clusterLabels, "node"), nil,
+	)
+	nodeMemoryDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "node", "memory_bytes"),
+		"Real memory of the Slurm node, in bytes.",
+		append(clusterLabels, "node"), nil,
+	)
+	nodeMemoryAllocDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "node", "memory_alloc_bytes"),
+		"Allocated memory of the Slurm node, in bytes.",
+		append(clusterLabels, "node"), nil,
+	)
+	jobStatesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "jobs"),
+		"Number of Slurm jobs by partition and state.",
+		append(clusterLabels, "partition", "state"), nil,
+	)
+	partitionNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "partition", "nodes"),
+		"Number of Slurm nodes in the partition.",
+		append(clusterLabels, "partition"), nil,
+	)
+	partitionCpusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "partition", "cpus"),
+		"Number of CPUs in the partition.",
+		append(clusterLabels, "partition"), nil,
+	)
+)
+
+// schedulerDescs are the descriptors of schedulerStats, by name.
+var schedulerDescs = map[string]*prometheus.Desc{}
+
+// schedulerStats are the scheduler statistics (sdiag) exported as gauges.
+var schedulerStats = []struct {
+	name  string
+	help  string
+	value func(stats *api.V0043StatsMsg) *int64
+}{
+	{"server_thread_count", "Number of current active slurmctld threads.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.ServerThreadCount })},
+	{"agent_queue_size", "Number of enqueued outgoing RPC requests.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.AgentQueueSize })},
+	{"dbd_agent_queue_size", "Number of messages for SlurmDBD that are queued.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.DbdAgentQueueSize })},
+	{"jobs_submitted", "Number of jobs submitted since the last reset.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.JobsSubmitted })},
+	{"jobs_started", "Number of jobs started since the last reset.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.JobsStarted })},
+	{"jobs_completed", "Number of jobs completed since the last reset.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.JobsCompleted })},
+	{"jobs_canceled", "Number of jobs canceled since the last reset.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.JobsCanceled })},
+	{"jobs_failed", "Number of jobs failed since the last reset.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.JobsFailed })},
+	{"schedule_cycle_last_microseconds", "Time of the last main scheduling cycle.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.ScheduleCycleLast })},
+	{"schedule_cycle_mean_microseconds", "Mean time of the main scheduling cycles since the last reset.", func(s *api.V0043StatsMsg) *int64 { return s.ScheduleCycleMean }},
+	{"schedule_queue_length", "Number of jobs pending in the main scheduling queue.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.ScheduleQueueLength })},
+	{"bf_cycle_last_microseconds", "Time of the last backfill scheduling cycle.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.BfCycleLast })},
+	{"bf_cycle_mean_microseconds", "Mean time of the backfill scheduling cycles since the last reset.", func(s *api.V0043StatsMsg) *int64 { return s.BfCycleMean }},
+	{"bf_backfilled_jobs", "Number of jobs started through backfilling since the last Slurm start.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.BfBackfilledJobs })},
+	{"bf_queue_length", "Number of jobs pending to be processed by backfilling.", int32Stat(func(s *api.V0043StatsMsg) *int32 { return s.BfQueueLen })},
+}
+
+func init() {
+	for _, stat := range schedulerStats {
+		schedulerDescs[stat.name] = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "scheduler", stat.name),
+			stat.help,
+			clusterLabels, nil,
+		)
+	}
+}
+
+func int32Stat(fn func(stats *api.V0043StatsMsg) *int32) func(stats *api.V0043StatsMsg) *int64 {
+	return func(stats *api.V0043StatsMsg) *int64 {
+		val := fn(stats)
+		if val == nil {
+			return nil
+		}
+		return ptr.To(int64(*val))
+	}
+}
+
+// nodeBaseStates are the mutually exclusive Slurm node base states.
+var nodeBaseStates = []api.V0043NodeState{
+	api.V0043NodeStateALLOCATED,
+	api.V0043NodeStateDOWN,
+	api.V0043NodeStateERROR,
+	api.V0043NodeStateFUTURE,
+	api.V0043NodeStateIDLE,
+	api.V0043NodeStateMIXED,
+	api.V0043NodeStateUNKNOWN,
+}
+
+// Collector is a prometheus.Collector which exports Slurm job, partition,
+// node, and scheduler metrics for each Controller that has a Slurm client.
+type Collector struct {
+	clientMap *clientmap.ClientMap
+	timeout   time.Duration
+}
+
+// NewCollector returns a Collector using the clients of the ClientMap.
+func NewCollector(clientMap *clientmap.ClientMap) *Collector {
+	return &Collector{
+		clientMap: clientMap,
+		timeout:   defaultTimeout,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- nodeStatesDesc
+	ch <- nodesDrainDesc
+	ch <- nodeCpusDesc
+	ch <- nodeCpusAllocDesc
+	ch <- nodeMemoryDesc
+	ch <- nodeMemoryAllocDesc
+	ch <- jobStatesDesc
+	ch <- partitionNodesDesc
+	ch <- partitionCpusDesc
+	for _, desc := range schedulerDescs {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, key := range c.clientMap.Keys() {
+		slurmClient := c.clientMap.Get(key)
+		if slurmClient == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		up := 1.0
+		if err := c.collect(ctx, ch, key, slurmClient); err != nil {
+			log.FromContext(ctx).Error(err, "failed to collect Slurm metrics", "controller", key)
+			up = 0
+		}
+		cancel()
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up, key.Namespace, key.Name)
+	}
+}
+
+func (c *Collector) collect(ctx context.Context, ch chan<- prometheus.Metric, key types.NamespacedName, slurmClient slurmclient.Client) error {
+	labels := []string{key.Namespace, key.Name}
+
+	nodeList := &slurmtypes.V0043NodeList{}
+	if err := slurmClient.List(ctx, nodeList); err != nil {
+		return err
+	}
+	collectNodes(ch, labels, nodeList)
+
+	jobList := &slurmtypes.V0043JobInfoList{}
+	if err := slurmClient.List(ctx, jobList); err != nil {
+		return err
+	}
+	collectJobs(ch, labels, jobList)
+
+	partitionList := &slurmtypes.V0043PartitionInfoList{}
+	if err := slurmClient.List(ctx, partitionList); err != nil {
+		return err
+	}
+	collectPartitions(ch, labels, partitionList)
+
+	statsList := &slurmtypes.V0043StatsList{}
+	if err := slurmClient.List(ctx, statsList); err != nil {
+		return err
+	}
+	collectScheduler(ch, labels, statsList)
+
+	return nil
+}
+
+func collectNodes(ch chan<- prometheus.Metric, labels []string, nodeList *slurmtypes.V0043NodeList) {
+	states := make(map[api.V0043NodeState]int, len(nodeBaseStates))
+	drain := 0
+	for _, node := range nodeList.Items {
+		stateSet := node.GetStateAsSet()
+		for _, state := range nodeBaseStates {
+			if stateSet.Has(state) {
+				states[state]++
+				break
+			}
+		}
+		if stateSet.Has(api.V0043NodeStateDRAIN) {
+			drain++
+		}
+
+		nodeLabels := append(labels, ptr.Deref(node.Name, ""))
+		ch <- prometheus.MustNewConstMetric(nodeCpusDesc, prometheus.GaugeValue,
+			float64(ptr.Deref(node.Cpus, 0)), nodeLabels...)
+		ch <- prometheus.MustNewConstMetric(nodeCpusAllocDesc, prometheus.GaugeValue,
+			float64(ptr.Deref(node.AllocCpus, 0)), nodeLabels...)
+		// Slurm reports memory in megabytes.
+		ch <- prometheus.MustNewConstMetric(nodeMemoryDesc, prometheus.GaugeValue,
+			float64(ptr.Deref(node.RealMemory, 0))*1024*1024, nodeLabels...)
+		ch <- prometheus.MustNewConstMetric(nodeMemoryAllocDesc, prometheus.GaugeValue,
+			float64(ptr.Deref(node.AllocMemory, 0))*1024*1024, nodeLabels...)
+	}
+	for _, state := range nodeBaseStates {
+		ch <- prometheus.MustNewConstMetric(nodeStatesDesc, prometheus.GaugeValue,
+			float64(states[state]), append(labels, strings.ToLower(string(state)))...)
+	}
+	ch <- prometheus.MustNewConstMetric(nodesDrainDesc, prometheus.GaugeValue, float64(drain), labels...)
+}
+
+func collectJobs(ch chan<- prometheus.Metric, labels []string, jobList *slurmtypes.V0043JobInfoList) {
+	type jobKey struct {
+		partition string
+		state     string
+	}
+	jobs := make(map[jobKey]int)
+	for _, job := range jobList.Items {
+		state := "unknown"
+		if jobStates := ptr.Deref(job.JobState, nil); len(jobStates) > 0 {
+			state = strings.ToLower(string(jobStates[0]))
+		}
+		jobs[jobKey{partition: ptr.Deref(job.Partition, ""), state: state}]++
+	}
+	for key, count := range jobs {
+		ch <- prometheus.MustNewConstMetric(jobStatesDesc, prometheus.GaugeValue,
+			float64(count), append(labels, key.partition, key.state)...)
+	}
+}
+
+func collectPartitions(ch chan<- prometheus.Metric, labels []string, partitionList *slurmtypes.V0043PartitionInfoList) {
+	for _, partition := range partitionList.Items {
+		partitionLabels := append(labels, ptr.Deref(partition.Name, ""))
+		var nodes, cpus int32
+		if partition.Nodes != nil {
+			nodes = ptr.Deref(partition.Nodes.Total, 0)
+		}
+		if partition.Cpus != nil {
+			cpus = ptr.Deref(partition.Cpus.Total, 0)
+		}
+		ch <- prometheus.MustNewConstMetric(partitionNodesDesc, prometheus.GaugeValue, float64(nodes), partitionLabels...)
+		ch <- prometheus.MustNewConstMetric(partitionCpusDesc, prometheus.GaugeValue, float64(cpus), partitionLabels...)
+	}
+}
+
+func collectScheduler(ch chan<- prometheus.Metric, labels []string, statsList *slurmtypes.V0043StatsList) {
+	if len(statsList.Items) == 0 {
+		return
+	}
+	stats := &statsList.Items[0].V0043StatsMsg
+	for _, stat := range schedulerStats {
+		val := stat.value(stats)
+		if val == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(schedulerDescs[stat.name], prometheus.GaugeValue, float64(*val), labels...)
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0043"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+)
+
+func TestCollector_Collect(t *testing.T) {
+	key := types.NamespacedName{Namespace: "slurm", Name: "slurm"}
+	nodeList := &slurmtypes.V0043NodeList{
+		Items: []slurmtypes.V0043Node{
+			{V0043Node: api.V0043Node{
+				Name:        ptr.To("node-0"),
+				State:       ptr.To([]api.V0043NodeState{api.V0043NodeStateIDLE}),
+				Cpus:        ptr.To[int32](8),
+				AllocCpus:   ptr.To[int32](0),
+				RealMemory:  ptr.To[int64](1024),
+				AllocMemory: ptr.To[int64](0),
+			}},
+			{V0043Node: api.V0043Node{
+				Name:        ptr.To("node-1"),
+				State:       ptr.To([]api.V0043NodeState{api.V0043NodeStateALLOCATED, api.V0043NodeStateDRAIN}),
+				Cpus:        ptr.To[int32](8),
+				AllocCpus:   ptr.To[int32](8),
+				RealMemory:  ptr.To[int64](1024),
+				AllocMemory: ptr.To[int64](512),
+			}},
+		},
+	}
+	jobList := &slurmtypes.V0043JobInfoList{
+		Items: []slurmtypes.V0043JobInfo{
+			{V0043JobInfo: api.V0043JobInfo{
+				JobId:     ptr.To[int32](1),
+				Partition: ptr.To("all"),
+				JobState:  ptr.To([]api.V0043JobInfoJobState{api.V0043JobInfoJobStateRUNNING}),
+			}},
+			{V0043JobInfo: api.V0043JobInfo{
+				JobId:     ptr.To[int32](2),
+				Partition: ptr.To("all"),
+				JobState:  ptr.To([]api.V0043JobInfoJobState{api.V0043JobInfoJobStatePENDING}),
+			}},
+		},
+	}
+	partitionList := &slurmtypes.V0043PartitionInfoList{
+		Items: []slurmtypes.V0043PartitionInfo{
+			{V0043PartitionInfo: api.V0043PartitionInfo{
+				Name: ptr.To("all"),
+			}},
+		},
+	}
+	statsList := &slurmtypes.V0043StatsList{
+		Items: []slurmtypes.V0043Stats{
+			{V0043StatsMsg: api.V0043StatsMsg{
+				ServerThreadCount: ptr.To[int32](3),
+				JobsSubmitted:     ptr.To[int32](2),
+			}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		lists  []object.ObjectList
+		metric string
+		want   string
+	}{
+		{
+			name:   "nodes",
+			lists:  []object.ObjectList{nodeList, jobList, partitionList, statsList},
+			metric: "slurm_nodes_drain",
+			want: `
+# HELP slurm_nodes_drain Number of Slurm nodes with the DRAIN flag.
+# TYPE slurm_nodes_drain gauge
+slurm_nodes_drain{controller="slurm",namespace="slurm"} 1
+`,
+		},
+		{
+			name:   "node memory",
+			lists:  []object.ObjectList{nodeList, jobList, partitionList, statsList},
+			metric: "slurm_node_memory_alloc_bytes",
+			want: `
+# HELP slurm_node_memory_alloc_bytes Allocated memory of the Slurm node, in bytes.
+# TYPE slurm_node_memory_alloc_bytes gauge
+slurm_node_memory_alloc_bytes{controller="slurm",namespace="slurm",node="node-0"} 0
+slurm_node_memory_alloc_bytes{controller="slurm",namespace="slurm",node="node-1"} 5.36870912e+08
+`,
+		},
+		{
+			name:   "jobs",
+			lists:  []object.ObjectList{nodeList, jobList, partitionList, statsList},
+			metric: "slurm_jobs",
+			want: `
+# HELP slurm_jobs Number of Slurm jobs by partition and state.
+# TYPE slurm_jobs gauge
+slurm_jobs{controller="slurm",namespace="slurm",partition="all",state="pending"} 1
+slurm_jobs{controller="slurm",namespace="slurm",partition="all",state="running"} 1
+`,
+		},
+		{
+			name:   "scheduler",
+			lists:  []object.ObjectList{nodeList, jobList, partitionList, statsList},
+			metric: "slurm_scheduler_server_thread_count",
+			want: `
+# HELP slurm_scheduler_server_thread_count Number of current active slurmctld threads.
+# TYPE slurm_scheduler_server_thread_count gauge
+slurm_scheduler_server_thread_count{controller="slurm",namespace="slurm"} 3
+`,
+		},
+		{
+			name:   "up",
+			lists:  []object.ObjectList{nodeList, jobList, partitionList, statsList},
+			metric: "slurm_up",
+			want: `
+# HELP slurm_up Whether the last scrape of slurmrestd for the Controller succeeded.
+# TYPE slurm_up gauge
+slurm_up{controller="slurm",namespace="slurm"} 1
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMap := clientmap.NewClientMap()
+			clientMap.Add(key, fake.NewClientBuilder().WithLists(tt.lists...).Build())
+			c := NewCollector(clientMap)
+			if err := testutil.CollectAndCompare(c, strings.NewReader(tt.want), tt.metric); err != nil {
+				t.Errorf("CollectAndCompare() = %v", err)
+			}
+		})
+	}
+}
+
+func TestCollector_Collect_Empty(t *testing.T) {
+	c := NewCollector(clientmap.NewClientMap())
+	if got := testutil.CollectAndCount(c); got != 0 {
+		t.Errorf("CollectAndCount() = %v, want %v", got, 0)
+	}
+}
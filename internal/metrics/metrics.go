@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "slurm_operator"
+
+	labelNamespace = "namespace"
+	labelName      = "name"
+	labelState     = "state"
+	labelOperation = "operation"
+	labelResult    = "result"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+const (
+	OperationDrain   = "drain"
+	OperationUndrain = "undrain"
+)
+
+var (
+	// NodeSetSlurmNodes is the number of Slurm nodes of a NodeSet, by state.
+	NodeSetSlurmNodes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "nodeset",
+			Name:      "slurm_nodes",
+			Help:      "Number of Slurm nodes of the NodeSet by Slurm node state.",
+		},
+		[]string{labelNamespace, labelName, labelState},
+	)
+
+	// NodeDrainOperations counts drain and undrain requests made to Slurm.
+	NodeDrainOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "nodeset",
+			Name:      "slurm_node_drain_operations_total",
+			Help:      "Total number of Slurm node drain and undrain operations.",
+		},
+		[]string{labelNamespace, labelName, labelOperation, labelResult},
+	)
+
+	// NodeDrainDuration is the latency of drain and undrain requests made to Slurm.
+	NodeDrainDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "nodeset",
+			Name:      "slurm_node_drain_duration_seconds",
+			Help:      "Latency of Slurm node drain and undrain operations.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{labelOperation},
+	)
+
+	// SlurmRestErrors counts failed slurmrestd requests.
+	SlurmRestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "slurmrestd",
+			Name:      "request_errors_total",
+			Help:      "Total number of failed slurmrestd requests, by Controller and operation.",
+		},
+		[]string{labelNamespace, labelName, labelOperation},
+	)
+
+	// TokenRefreshes counts the times a Token was refreshed.
+	TokenRefreshes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "token",
+			Name:      "refreshes_total",
+			Help:      "Total number of Token refreshes.",
+		},
+		[]string{labelNamespace, labelName},
+	)
+
+	// TokenExpiration is the expiration time of a Token's current JWT.
+	TokenExpiration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "token",
+			Name:      "expiration_timestamp_seconds",
+			Help:      "Expiration time of the Token's JWT, in seconds since the epoch.",
+		},
+		[]string{labelNamespace, labelName},
+	)
+
+	// ClientMapClients reports the Controllers which have a Slurm client.
+	ClientMapClients = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "clientmap",
+			Name:      "client",
+			Help:      "Slurm client membership of the ClientMap, by Controller. Value is always 1.",
+		},
+		[]string{labelNamespace, labelName},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		NodeSetSlurmNodes,
+		NodeDrainOperations,
+		NodeDrainDuration,
+		SlurmRestErrors,
+		TokenRefreshes,
+		TokenExpiration,
+		ClientMapClients,
+	)
+}
+
+// SetNodeSetSlurmNodes records the number of Slurm nodes in each state.
+func SetNodeSetSlurmNodes(key types.NamespacedName, states map[string]int32) {
+	for state, count := range states {
+		NodeSetSlurmNodes.WithLabelValues(key.Namespace, key.Name, state).Set(float64(count))
+	}
+}
+
+// DeleteNodeSet removes all metrics of the NodeSet.
+func DeleteNodeSet(key types.NamespacedName) {
+	labels := prometheus.Labels{labelNamespace: key.Namespace, labelName: key.Name}
+	NodeSetSlurmNodes.DeletePartialMatch(labels)
+	NodeDrainOperations.DeletePartialMatch(labels)
+}
+
+// ObserveNodeDrain records a drain or undrain operation and its latency.
+func ObserveNodeDrain(key types.NamespacedName, operation string, start time.Time, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	NodeDrainOperations.WithLabelValues(key.Namespace, key.Name, operation, result).Inc()
+	NodeDrainDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// IncSlurmRestErrors records a failed slurmrestd request for the Controller.
+func IncSlurmRestErrors(key types.NamespacedName, operation string) {
+	SlurmRestErrors.WithLabelValues(key.Namespace, key.Name, operation).Inc()
+}
+
+// IncTokenRefreshes records a refresh of the Token.
+func IncTokenRefreshes(key types.NamespacedName) {
+	TokenRefreshes.WithLabelValues(key.Namespace, key.Name).Inc()
+}
+
+// SetTokenExpiration records the expiration time of the Token.
+func SetTokenExpiration(key types.NamespacedName, expiration time.Time) {
+	TokenExpiration.WithLabelValues(key.Namespace, key.Name).Set(float64(expiration.Unix()))
+}
+
+// DeleteToken removes all metrics of the Token.
+func DeleteToken(key types.NamespacedName) {
+	labels := prometheus.Labels{labelNamespace: key.Namespace, labelName: key.Name}
+	TokenRefreshes.DeletePartialMatch(labels)
+	TokenExpiration.DeletePartialMatch(labels)
+}
+
+// SetClientMapClient records the ClientMap membership of the Controller.
+func SetClientMapClient(key types.NamespacedName, present bool) {
+	if present {
+		ClientMapClients.WithLabelValues(key.Namespace, key.Name).Set(1)
+	} else {
+		ClientMapClients.DeleteLabelValues(key.Namespace, key.Name)
+	}
+}
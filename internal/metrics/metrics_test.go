@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSetNodeSetSlurmNodes(t *testing.T) {
+	key := types.NamespacedName{Namespace: "slurm", Name: "foo"}
+	SetNodeSetSlurmNodes(key, map[string]int32{
+		"idle":      2,
+		"allocated": 1,
+	})
+	if got := testutil.ToFloat64(NodeSetSlurmNodes.WithLabelValues("slurm", "foo", "idle")); got != 2 {
+		t.Errorf("NodeSetSlurmNodes(idle) = %v, want %v", got, 2)
+	}
+	if got := testutil.ToFloat64(NodeSetSlurmNodes.WithLabelValues("slurm", "foo", "allocated")); got != 1 {
+		t.Errorf("NodeSetSlurmNodes(allocated) = %v, want %v", got, 1)
+	}
+
+	DeleteNodeSet(key)
+	if got := testutil.CollectAndCount(NodeSetSlurmNodes); got != 0 {
+		t.Errorf("CollectAndCount() = %v, want %v", got, 0)
+	}
+}
+
+func TestObserveNodeDrain(t *testing.T) {
+	key := types.NamespacedName{Namespace: "slurm", Name: "bar"}
+	start := time.Now()
+	ObserveNodeDrain(key, OperationDrain, start, nil)
+	ObserveNodeDrain(key, OperationDrain, start, errors.New("failed"))
+	ObserveNodeDrain(key, OperationUndrain, start, nil)
+
+	tests := []struct {
+		name      string
+		operation string
+		result    string
+		want      float64
+	}{
+		{
+			name:      "drain success",
+			operation: OperationDrain,
+			result:    ResultSuccess,
+			want:      1,
+		},
+		{
+			name:      "drain error",
+			operation: OperationDrain,
+			result:    ResultError,
+			want:      1,
+		},
+		{
+			name:      "undrain success",
+			operation: OperationUndrain,
+			result:    ResultSuccess,
+			want:      1,
+		},
+		{
+			name:      "undrain error",
+			operation: OperationUndrain,
+			result:    ResultError,
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testutil.ToFloat64(NodeDrainOperations.WithLabelValues(key.Namespace, key.Name, tt.operation, tt.result))
+			if got != tt.want {
+				t.Errorf("NodeDrainOperations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	key := types.NamespacedName{Namespace: "slurm", Name: "token"}
+	expiration := time.Unix(1700000000, 0)
+	IncTokenRefreshes(key)
+	SetTokenExpiration(key, expiration)
+	if got := testutil.ToFloat64(TokenRefreshes.WithLabelValues(key.Namespace, key.Name)); got != 1 {
+		t.Errorf("TokenRefreshes = %v, want %v", got, 1)
+	}
+	if got := testutil.ToFloat64(TokenExpiration.WithLabelValues(key.Namespace, key.Name)); got != float64(expiration.Unix()) {
+		t.Errorf("TokenExpiration = %v, want %v", got, expiration.Unix())
+	}
+
+	DeleteToken(key)
+	if got := testutil.CollectAndCount(TokenExpiration); got != 0 {
+		t.Errorf("CollectAndCount() = %v, want %v", got, 0)
+	}
+}
+
+func TestSetClientMapClient(t *testing.T) {
+	key := types.NamespacedName{Namespace: "slurm", Name: "slurm"}
+	SetClientMapClient(key, true)
+	if got := testutil.CollectAndCount(ClientMapClients); got != 1 {
+		t.Errorf("CollectAndCount() = %v, want %v", got, 1)
+	}
+	SetClientMapClient(key, false)
+	if got := testutil.CollectAndCount(ClientMapClients); got != 0 {
+		t.Errorf("CollectAndCount() = %v, want %v", got, 0)
+	}
+}
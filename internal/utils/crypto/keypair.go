@@ -165,3 +165,23 @@ func (o *KeyPair) PublicKey() []byte {
 	b := ssh.MarshalAuthorizedKey(key)
 	return b
 }
+
+// ParseRsaPrivateKey parses an RSA private key in PEM format, as returned by
+// PrivateKey(), or in PKCS#1 or PKCS#8 format.
+func ParseRsaPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+	return privateKey, nil
+}
+
+// IsPem returns true if the data is PEM encoded.
+func IsPem(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil
+}
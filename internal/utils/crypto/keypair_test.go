@@ -115,3 +115,51 @@ func TestNewKeyPair(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRsaPrivateKey(t *testing.T) {
+	newPrivateKey := func(opts ...Option) []byte {
+		keyPair, err := NewKeyPair(opts...)
+		if err != nil {
+			panic(err)
+		}
+		return keyPair.PrivateKey()
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantPem bool
+		wantErr bool
+	}{
+		{
+			name:    "RSA",
+			data:    newPrivateKey(WithType(KeyPairRsa)),
+			wantPem: true,
+		},
+		{
+			name:    "Ed25519",
+			data:    newPrivateKey(WithType(KeyPairEd25519)),
+			wantPem: true,
+			wantErr: true,
+		},
+		{
+			name:    "Not PEM",
+			data:    NewSigningKey(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRsaPrivateKey(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRsaPrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got == nil {
+				t.Errorf("ParseRsaPrivateKey() = nil")
+			}
+			if got := IsPem(tt.data); got != tt.wantPem {
+				t.Errorf("IsPem() = %v, want %v", got, tt.wantPem)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SshPublicKey returns the public SSH key, in `authorized_keys` format, of a
+// private SSH key in PEM format.
+func SshPublicKey(privateKey []byte) ([]byte, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return ssh.MarshalAuthorizedKey(signer.PublicKey()), nil
+}
+
+// SshAuthorizedKey returns the public SSH key, in `authorized_keys` format, of
+// a public SSH key in `authorized_keys` format, without its options and
+// comment. It returns an error if the key cannot be parsed, e.g. when it is a
+// private key.
+func SshAuthorizedKey(publicKey []byte) ([]byte, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return ssh.MarshalAuthorizedKey(key), nil
+}
+
+// newCaSigner returns the signer of an SSH CA private key in PEM format. RSA
+// keys sign with SHA-512, as OpenSSH no longer accepts SHA-1 signatures.
+func newCaSigner(caPrivateKey []byte) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey(caPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA private key: %w", err)
+	}
+	if signer.PublicKey().Type() != ssh.KeyAlgoRSA {
+		return signer, nil
+	}
+	algorithmSigner, ok := signer.(ssh.AlgorithmSigner)
+	if !ok {
+		return nil, errors.New("RSA CA private key cannot sign with SHA-512")
+	}
+	return ssh.NewSignerWithAlgorithms(algorithmSigner, []string{ssh.KeyAlgoRSASHA512})
+}
+
+// SignSshHostCertificate returns the host certificate, in `authorized_keys`
+// format, of a public SSH host key in `authorized_keys` format, signed by an
+// SSH CA private key in PEM format. The certificate is valid for the
+// principals (host names), and does not expire.
+func SignSshHostCertificate(caPrivateKey, hostPublicKey []byte, keyId string, principals []string) ([]byte, error) {
+	signer, err := newCaSigner(caPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(hostPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse host public key: %w", err)
+	}
+	cert := &ssh.Certificate{
+		Key:             publicKey,
+		CertType:        ssh.HostCert,
+		KeyId:           keyId,
+		ValidPrincipals: principals,
+		ValidAfter:      0,
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, signer); err != nil {
+		return nil, fmt.Errorf("failed to sign host certificate: %w", err)
+	}
+	return ssh.MarshalAuthorizedKey(cert), nil
+}
+
+// IsSshHostCertificateValid returns true if the host certificate, in
+// `authorized_keys` format, certifies the public SSH host key for exactly the
+// principals, and is signed by the SSH CA private key.
+func IsSshHostCertificateValid(certificate, caPrivateKey, hostPublicKey []byte, principals []string) bool {
+	key, _, _, _, err := ssh.ParseAuthorizedKey(certificate)
+	if err != nil {
+		return false
+	}
+	cert, ok := key.(*ssh.Certificate)
+	if !ok || cert.CertType != ssh.HostCert || len(cert.ValidPrincipals) == 0 {
+		return false
+	}
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(hostPublicKey)
+	if err != nil {
+		return false
+	}
+	caSigner, err := ssh.ParsePrivateKey(caPrivateKey)
+	if err != nil {
+		return false
+	}
+	if !bytes.Equal(cert.SignatureKey.Marshal(), caSigner.PublicKey().Marshal()) {
+		return false
+	}
+	// Verifies the validity period and signature of the certificate.
+	checker := &ssh.CertChecker{}
+	if err := checker.CheckCert(cert.ValidPrincipals[0], cert); err != nil {
+		return false
+	}
+	return bytes.Equal(cert.Key.Marshal(), publicKey.Marshal()) &&
+		slices.Equal(cert.ValidPrincipals, principals)
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignSshHostCertificate(t *testing.T) {
+	hostKey, err := NewKeyPair(WithType(KeyPairEd25519))
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	otherKey, err := NewKeyPair(WithType(KeyPairEd25519))
+	if err != nil {
+		t.Fatalf("NewKeyPair() error = %v", err)
+	}
+	principals := []string{"slurm", "slurm.slurm"}
+	tests := []struct {
+		name   string
+		caType KeyPairType
+	}{
+		{
+			name:   "ED25519 CA",
+			caType: KeyPairEd25519,
+		},
+		{
+			name:   "RSA CA",
+			caType: KeyPairRsa,
+		},
+		{
+			name:   "ECDSA CA",
+			caType: KeyPairEcdsa,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caKey, err := NewKeyPair(WithType(tt.caType))
+			if err != nil {
+				t.Fatalf("NewKeyPair() error = %v", err)
+			}
+			caPubKey, err := SshPublicKey(caKey.PrivateKey())
+			if err != nil {
+				t.Fatalf("SshPublicKey() error = %v", err)
+			}
+			if !bytes.Equal(caPubKey, caKey.PublicKey()) {
+				t.Errorf("SshPublicKey() = %s, want %s", caPubKey, caKey.PublicKey())
+			}
+
+			cert, err := SignSshHostCertificate(caKey.PrivateKey(), hostKey.PublicKey(), "slurm", principals)
+			if err != nil {
+				t.Fatalf("SignSshHostCertificate() error = %v", err)
+			}
+			if !IsSshHostCertificateValid(cert, caKey.PrivateKey(), hostKey.PublicKey(), principals) {
+				t.Errorf("IsSshHostCertificateValid() = false, want true")
+			}
+			if IsSshHostCertificateValid(cert, caKey.PrivateKey(), otherKey.PublicKey(), principals) {
+				t.Errorf("IsSshHostCertificateValid() = true with another host key, want false")
+			}
+			if IsSshHostCertificateValid(cert, otherKey.PrivateKey(), hostKey.PublicKey(), principals) {
+				t.Errorf("IsSshHostCertificateValid() = true with another CA, want false")
+			}
+			if IsSshHostCertificateValid(cert, caKey.PrivateKey(), hostKey.PublicKey(), principals[:1]) {
+				t.Errorf("IsSshHostCertificateValid() = true with other principals, want false")
+			}
+			if IsSshHostCertificateValid(nil, caKey.PrivateKey(), hostKey.PublicKey(), principals) {
+				t.Errorf("IsSshHostCertificateValid() = true without certificate, want false")
+			}
+		})
+	}
+}
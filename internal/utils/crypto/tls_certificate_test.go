@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package crypto
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+func TestNewTlsCertificate(t *testing.T) {
+	if _, _, err := NewTlsCertificate(nil, time.Hour); err == nil {
+		t.Errorf("NewTlsCertificate() without DNS names, want error")
+	}
+
+	dnsNames := []string{"slurm-restapi", "slurm-restapi.slurm"}
+	certificate, privateKey, err := NewTlsCertificate(dnsNames, time.Hour)
+	if err != nil {
+		t.Fatalf("NewTlsCertificate() error = %v", err)
+	}
+	keyPair, err := tls.X509KeyPair(certificate, privateKey)
+	if err != nil {
+		t.Fatalf("X509KeyPair() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certificate) {
+		t.Fatalf("AppendCertsFromPEM() = false")
+	}
+	for _, dnsName := range dnsNames {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: dnsName, Roots: roots}); err != nil {
+			t.Errorf("Verify(%s) error = %v", dnsName, err)
+		}
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "other", Roots: roots}); err == nil {
+		t.Errorf("Verify(other) want error")
+	}
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package failover
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+var ErrNoEndpoints = errors.New("no endpoints available")
+
+// Policy selects the endpoint which is tried first for a request.
+type Policy string
+
+const (
+	// PolicyFailover sends requests to the same endpoint until it fails, then
+	// to the next endpoint.
+	PolicyFailover Policy = "failover"
+	// PolicyRoundRobin sends each request to the next endpoint.
+	PolicyRoundRobin Policy = "round-robin"
+)
+
+// Transport{} is an http.RoundTripper which sends requests to one of several
+// equivalent endpoints. The scheme and host of the request URL are replaced by
+// those of the endpoint. When an endpoint cannot be reached, the request is
+// retried on the other endpoints. When an endpoint responds that it is
+// unavailable, only idempotent requests are retried, as a proxy may have
+// forwarded the request.
+type Transport struct {
+	policy Policy
+
+	mu sync.Mutex
+	// Underlying transport
+	base      http.RoundTripper
+	endpoints []*url.URL
+	// Index of the endpoint which is tried first
+	next int
+}
+
+// NewTransport() returns a Transport without endpoints, which sends requests
+// with the base transport. If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, policy Policy) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		base:   base,
+		policy: policy,
+	}
+}
+
+// SetEndpoints() replaces the endpoints, as URLs (e.g. `http://host:port`).
+// The current endpoint is kept if it is still available.
+func (t *Transport) SetEndpoints(endpoints []string) error {
+	urls := make([]*url.URL, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to parse endpoint (%s): %w", endpoint, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("endpoint must have a scheme and host: %s", endpoint)
+		}
+		urls = append(urls, u)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	next := 0
+	if current := t.current(); current != nil {
+		next = max(slices.IndexFunc(urls, func(u *url.URL) bool {
+			return u.String() == current.String()
+		}), 0)
+	}
+	t.endpoints = urls
+	t.next = next
+	return nil
+}
+
+// SetBase() replaces the underlying transport (e.g. to trust another CA).
+// If base is nil, http.DefaultTransport is used.
+func (t *Transport) SetBase(base http.RoundTripper) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.base = base
+}
+
+// Endpoints() returns the endpoints, starting with the current endpoint.
+func (t *Transport) Endpoints() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]string, 0, len(t.endpoints))
+	for i := range t.endpoints {
+		out = append(out, t.endpoints[(t.next+i)%len(t.endpoints)].String())
+	}
+	return out
+}
+
+func (t *Transport) current() *url.URL {
+	if len(t.endpoints) == 0 {
+		return nil
+	}
+	return t.endpoints[t.next%len(t.endpoints)]
+}
+
+// order returns the endpoints in the order they are tried for a request, and
+// the underlying transport to send it with.
+func (t *Transport) order() ([]*url.URL, http.RoundTripper) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(t.endpoints)
+	out := make([]*url.URL, 0, n)
+	for i := range n {
+		out = append(out, t.endpoints[(t.next+i)%n])
+	}
+	if t.policy == PolicyRoundRobin && n > 0 {
+		t.next = (t.next + 1) % n
+	}
+	return out, t.base
+}
+
+// markFailed moves to the next endpoint, if the failed endpoint is the
+// current endpoint.
+func (t *Transport) markFailed(endpoint *url.URL) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.policy == PolicyFailover && t.current() == endpoint {
+		t.next = (t.next + 1) % len(t.endpoints)
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoints, base := t.order()
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	var errs []error
+	for i, endpoint := range endpoints {
+		outReq := req.Clone(req.Context())
+		outReq.URL.Scheme = endpoint.Scheme
+		outReq.URL.Host = endpoint.Host
+		outReq.Host = ""
+		if i > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				errs = append(errs, errors.New("the request body cannot be sent again"))
+				break
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			outReq.Body = body
+		}
+
+		resp, err := base.RoundTrip(outReq)
+		if err == nil && !isUnavailable(resp.StatusCode) {
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+		t.markFailed(endpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.Host, err))
+			if !isIdempotent(req) && !isDialError(err) {
+				break
+			}
+			continue
+		}
+		if i == len(endpoints)-1 || !isIdempotent(req) {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		errs = append(errs, fmt.Errorf("%s: %s", endpoint.Host, resp.Status))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// isUnavailable returns true if the status code indicates that the endpoint,
+// or the proxy in front of it, cannot serve requests right now.
+func isUnavailable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdempotent returns true if the request can be sent again after it may have
+// been processed, as http.Transport considers it.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+// isDialError returns true if the connection to the endpoint could not be
+// established, so the request was never sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
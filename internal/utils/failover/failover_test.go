@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package failover
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newServer(t *testing.T, name string, statusCode int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(name + ":" + r.URL.Path + ":" + string(body)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doRequest(t *testing.T, transport *Transport, body string) (string, error) {
+	t.Helper()
+	return doMethodRequest(t, transport, http.MethodGet, body)
+}
+
+func doMethodRequest(t *testing.T, transport *Transport, method, body string) (string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, "http://placeholder/slurm/v0.0.43/ping", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	got, _ := io.ReadAll(resp.Body)
+	return string(got), nil
+}
+
+func TestTransport_Failover(t *testing.T) {
+	a := newServer(t, "a", http.StatusOK)
+	b := newServer(t, "b", http.StatusOK)
+	unavailable := newServer(t, "unavailable", http.StatusServiceUnavailable)
+	closed := newServer(t, "closed", http.StatusOK)
+	closed.Close()
+
+	transport := NewTransport(nil, PolicyFailover)
+	if _, err := doRequest(t, transport, ""); err == nil {
+		t.Errorf("RoundTrip() without endpoints, want error")
+	}
+
+	if err := transport.SetEndpoints([]string{closed.URL, unavailable.URL, a.URL, b.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	for range 2 {
+		got, err := doRequest(t, transport, "body")
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		if want := "a:/slurm/v0.0.43/ping:body"; got != want {
+			t.Errorf("RoundTrip() = %v, want %v", got, want)
+		}
+	}
+	if diff := cmp.Diff([]string{a.URL, b.URL, closed.URL, unavailable.URL}, transport.Endpoints()); diff != "" {
+		t.Errorf("Endpoints() (-want,+got):\n%s", diff)
+	}
+
+	// The current endpoint is kept.
+	if err := transport.SetEndpoints([]string{b.URL, a.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{a.URL, b.URL}, transport.Endpoints()); diff != "" {
+		t.Errorf("Endpoints() (-want,+got):\n%s", diff)
+	}
+
+	// All endpoints are unavailable.
+	if err := transport.SetEndpoints([]string{closed.URL, unavailable.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	got, err := doRequest(t, transport, "")
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if want := "unavailable:/slurm/v0.0.43/ping:"; got != want {
+		t.Errorf("RoundTrip() = %v, want %v", got, want)
+	}
+	if err := transport.SetEndpoints([]string{closed.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	if _, err := doRequest(t, transport, ""); err == nil {
+		t.Errorf("RoundTrip() to closed endpoint, want error")
+	}
+}
+
+func TestTransport_NotIdempotent(t *testing.T) {
+	a := newServer(t, "a", http.StatusOK)
+	unavailable := newServer(t, "unavailable", http.StatusServiceUnavailable)
+	closed := newServer(t, "closed", http.StatusOK)
+	closed.Close()
+
+	transport := NewTransport(nil, PolicyFailover)
+	if err := transport.SetEndpoints([]string{closed.URL, unavailable.URL, a.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+
+	// The request is retried after a connection error, but not after an
+	// unavailable response, as it may have been processed.
+	got, err := doMethodRequest(t, transport, http.MethodPost, "body")
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if want := "unavailable:/slurm/v0.0.43/ping:body"; got != want {
+		t.Errorf("RoundTrip() = %v, want %v", got, want)
+	}
+	// The unavailable endpoint is no longer tried first.
+	got, err = doMethodRequest(t, transport, http.MethodDelete, "")
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if want := "a:/slurm/v0.0.43/ping:"; got != want {
+		t.Errorf("RoundTrip() = %v, want %v", got, want)
+	}
+
+	// An idempotent request is retried after an unavailable response.
+	if err := transport.SetEndpoints([]string{unavailable.URL, a.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	got, err = doMethodRequest(t, transport, http.MethodGet, "")
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if want := "a:/slurm/v0.0.43/ping:"; got != want {
+		t.Errorf("RoundTrip() = %v, want %v", got, want)
+	}
+}
+
+func Test_isDialError(t *testing.T) {
+	closed := newServer(t, "closed", http.StatusOK)
+	closed.Close()
+
+	req, err := http.NewRequest(http.MethodPost, closed.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	_, err = http.DefaultTransport.RoundTrip(req)
+	if !isDialError(err) {
+		t.Errorf("isDialError(%v) = false, want true", err)
+	}
+	if isDialError(io.ErrUnexpectedEOF) {
+		t.Errorf("isDialError(%v) = true, want false", io.ErrUnexpectedEOF)
+	}
+}
+
+func TestTransport_RoundRobin(t *testing.T) {
+	a := newServer(t, "a", http.StatusOK)
+	b := newServer(t, "b", http.StatusOK)
+
+	transport := NewTransport(nil, PolicyRoundRobin)
+	if err := transport.SetEndpoints([]string{a.URL, b.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	want := []string{"a", "b", "a", "b"}
+	for i := range want {
+		got, err := doRequest(t, transport, "")
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		if got[:1] != want[i] {
+			t.Errorf("RoundTrip() #%d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestTransport_SetEndpoints(t *testing.T) {
+	transport := NewTransport(nil, PolicyFailover)
+	for _, endpoint := range []string{"localhost:6820", "http://", "://bad"} {
+		if err := transport.SetEndpoints([]string{endpoint}); err == nil {
+			t.Errorf("SetEndpoints(%q) want error", endpoint)
+		}
+	}
+}
+
+func TestTransport_SetBase(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tls"))
+	}))
+	t.Cleanup(server.Close)
+
+	transport := NewTransport(nil, PolicyFailover)
+	if err := transport.SetEndpoints([]string{server.URL}); err != nil {
+		t.Fatalf("SetEndpoints() error = %v", err)
+	}
+	if _, err := doRequest(t, transport, ""); err == nil {
+		t.Errorf("RoundTrip() with an unknown CA, want error")
+	}
+
+	transport.SetBase(server.Client().Transport)
+	got, err := doRequest(t, transport, "")
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if got != "tls" {
+		t.Errorf("RoundTrip() = %v, want %v", got, "tls")
+	}
+}
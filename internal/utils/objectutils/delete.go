@@ -9,8 +9,10 @@ import (
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -32,6 +34,8 @@ func DeleteObject(c client.Client, ctx context.Context, newObj client.Object) er
 		oldObj = &appsv1.Deployment{}
 	case *appsv1.StatefulSet:
 		oldObj = &appsv1.StatefulSet{}
+	case *batchv1.Job:
+		oldObj = &batchv1.Job{}
 	case *slinkyv1alpha1.Controller:
 		oldObj = &slinkyv1alpha1.Controller{}
 	case *slinkyv1alpha1.RestApi:
@@ -60,7 +64,13 @@ func DeleteObject(c client.Client, ctx context.Context, newObj client.Object) er
 		return nil
 	}
 
-	if err := c.Delete(ctx, oldObj); err != nil {
+	opts := []client.DeleteOption{}
+	if _, ok := oldObj.(*batchv1.Job); ok {
+		// Jobs orphan their pods by default.
+		opts = append(opts, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	}
+
+	if err := c.Delete(ctx, oldObj, opts...); err != nil {
 		return fmt.Errorf("error deleting %s: %w", key, err)
 	}
 
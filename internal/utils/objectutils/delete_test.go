@@ -9,6 +9,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -86,6 +87,18 @@ func TestDeleteObject(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Job",
+			args: args{
+				c:   fake.NewFakeClient(),
+				ctx: context.TODO(),
+				newObj: &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+			},
+		},
 		{
 			name: "Controller",
 			args: args{
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,10 +32,14 @@ func SyncObject(c client.Client, ctx context.Context, newObj client.Object, shou
 		oldObj = &corev1.Secret{}
 	case *corev1.Service:
 		oldObj = &corev1.Service{}
+	case *corev1.PersistentVolumeClaim:
+		oldObj = &corev1.PersistentVolumeClaim{}
 	case *appsv1.Deployment:
 		oldObj = &appsv1.Deployment{}
 	case *appsv1.StatefulSet:
 		oldObj = &appsv1.StatefulSet{}
+	case *batchv1.Job:
+		oldObj = &batchv1.Job{}
 	case *slinkyv1alpha1.Controller:
 		oldObj = &slinkyv1alpha1.Controller{}
 	case *slinkyv1alpha1.RestApi:
@@ -102,6 +107,13 @@ func SyncObject(c client.Client, ctx context.Context, newObj client.Object, shou
 		obj.Annotations = structutils.MergeMaps(obj.Annotations, o.Annotations)
 		obj.Labels = structutils.MergeMaps(obj.Labels, o.Labels)
 		obj.Spec = o.Spec
+	case *corev1.PersistentVolumeClaim:
+		obj := oldObj.(*corev1.PersistentVolumeClaim)
+		patch = client.MergeFrom(obj.DeepCopy())
+		obj.Annotations = structutils.MergeMaps(obj.Annotations, o.Annotations)
+		obj.Labels = structutils.MergeMaps(obj.Labels, o.Labels)
+		// Only the requested resources are mutable, to expand the volume.
+		obj.Spec.Resources.Requests = o.Spec.Resources.Requests
 	case *appsv1.Deployment:
 		obj := oldObj.(*appsv1.Deployment)
 		patch = client.MergeFrom(obj.DeepCopy())
@@ -122,6 +134,12 @@ func SyncObject(c client.Client, ctx context.Context, newObj client.Object, shou
 		obj.Spec.Replicas = o.Spec.Replicas
 		obj.Spec.Template = o.Spec.Template
 		obj.Spec.UpdateStrategy = o.Spec.UpdateStrategy
+	case *batchv1.Job:
+		obj := oldObj.(*batchv1.Job)
+		patch = client.MergeFrom(obj.DeepCopy())
+		// The pod template of a Job is immutable; it must be recreated instead.
+		obj.Annotations = structutils.MergeMaps(obj.Annotations, o.Annotations)
+		obj.Labels = structutils.MergeMaps(obj.Labels, o.Labels)
 	case *slinkyv1alpha1.Controller:
 		obj := oldObj.(*slinkyv1alpha1.Controller)
 		patch = client.MergeFrom(obj.DeepCopy())
@@ -9,6 +9,7 @@ import (
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -72,6 +73,19 @@ func TestSyncObject(t *testing.T) {
 				shouldUpdate: true,
 			},
 		},
+		{
+			name: "PersistentVolumeClaim",
+			args: args{
+				c:   fake.NewFakeClient(),
+				ctx: context.TODO(),
+				newObj: &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				shouldUpdate: true,
+			},
+		},
 		{
 			name: "Deployment",
 			args: args{
@@ -98,6 +112,19 @@ func TestSyncObject(t *testing.T) {
 				shouldUpdate: true,
 			},
 		},
+		{
+			name: "Job",
+			args: args{
+				c:   fake.NewFakeClient(),
+				ctx: context.TODO(),
+				newObj: &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				shouldUpdate: true,
+			},
+		},
 		{
 			name: "Controller",
 			args: args{
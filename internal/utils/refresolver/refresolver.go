@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -34,6 +35,21 @@ func (r *RefResolver) GetController(ctx context.Context, ref slinkyv1alpha1.Obje
 	return obj, nil
 }
 
+// GetAllowedController returns the Controller of the reference, of a NodeSet or
+// LoginSet in the namespace. It returns a Forbidden error if the Controller does
+// not allow the namespace.
+func (r *RefResolver) GetAllowedController(ctx context.Context, ref slinkyv1alpha1.ObjectReference, namespace string) (*slinkyv1alpha1.Controller, error) {
+	obj, err := r.GetController(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !obj.IsNamespaceAllowed(namespace) {
+		gr := slinkyv1alpha1.GroupVersion.WithResource("controllers").GroupResource()
+		return nil, apierrors.NewForbidden(gr, obj.Name, fmt.Errorf("namespace %q is not in the allowedNamespaces of Controller %s/%s", namespace, obj.Namespace, obj.Name))
+	}
+	return obj, nil
+}
+
 func (r *RefResolver) GetAccounting(ctx context.Context, ref slinkyv1alpha1.ObjectReference) (*slinkyv1alpha1.Accounting, error) {
 	obj := &slinkyv1alpha1.Accounting{}
 	key := ref.NamespacedName()
@@ -51,6 +67,9 @@ func (r *RefResolver) GetNodeSetsForController(ctx context.Context, controller *
 
 	out := &slinkyv1alpha1.NodeSetList{}
 	for _, item := range list.Items {
+		if !controller.IsNamespaceAllowed(item.Namespace) {
+			continue
+		}
 		if item.Spec.ControllerRef.IsMatch(objectutils.NamespacedName(controller)) {
 			out.Items = append(out.Items, item)
 		}
@@ -67,6 +86,9 @@ func (r *RefResolver) GetLoginSetsForController(ctx context.Context, controller
 
 	out := &slinkyv1alpha1.LoginSetList{}
 	for _, item := range list.Items {
+		if !controller.IsNamespaceAllowed(item.Namespace) {
+			continue
+		}
 		if item.Spec.ControllerRef.IsMatch(objectutils.NamespacedName(controller)) {
 			out.Items = append(out.Items, item)
 		}
@@ -107,6 +129,30 @@ func (r *RefResolver) GetControllersForAccounting(ctx context.Context, accountin
 	return out, nil
 }
 
+// GetFederationForController returns the Federation which the Controller is a
+// member of, or nil. If the Controller is a member of multiple Federations, the
+// first by namespace and name is returned.
+func (r *RefResolver) GetFederationForController(ctx context.Context, controller *slinkyv1alpha1.Controller) (*slinkyv1alpha1.Federation, error) {
+	list := &slinkyv1alpha1.FederationList{}
+	// A Federation may reference Controllers of other namespaces.
+	if err := r.client.List(ctx, list); err != nil {
+		return nil, err
+	}
+
+	var out *slinkyv1alpha1.Federation
+	for i := range list.Items {
+		item := &list.Items[i]
+		if !item.HasController(objectutils.NamespacedName(controller)) {
+			continue
+		}
+		if out == nil || objectutils.NamespacedName(item).String() < objectutils.NamespacedName(out).String() {
+			out = item
+		}
+	}
+
+	return out, nil
+}
+
 func (r *RefResolver) GetSecretKeyRef(ctx context.Context, selector *corev1.SecretKeySelector, namespace string) ([]byte, error) {
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{
@@ -124,3 +170,34 @@ func (r *RefResolver) GetSecretKeyRef(ctx context.Context, selector *corev1.Secr
 
 	return data, nil
 }
+
+// GetTokenSigningKeyRef returns the key which the Token should be signed with.
+// While a Controller using the Token's HS256 key is rotating it, this is the
+// Controller's RS256 key until Slurm verifies with the new key, then the new key.
+func (r *RefResolver) GetTokenSigningKeyRef(ctx context.Context, token *slinkyv1alpha1.Token) (*slinkyv1alpha1.JwtSecretKeySelector, error) {
+	if token.Spec.JwtRs256KeyRef != nil {
+		return token.JwtRef(), nil
+	}
+	ref := token.JwtHs256Ref()
+
+	list := &slinkyv1alpha1.ControllerList{}
+	if err := r.client.List(ctx, list, client.InNamespace(ref.Namespace)); err != nil {
+		return nil, err
+	}
+
+	for _, item := range list.Items {
+		if item.AuthJwtHs256Key() != token.JwtHs256Key() {
+			continue
+		}
+		signingRef := item.AuthJwtHs256SigningRef()
+		if signingRef.Name == ref.Name && signingRef.Key == ref.Key {
+			continue
+		}
+		return &slinkyv1alpha1.JwtSecretKeySelector{
+			SecretKeySelector: *signingRef,
+			Namespace:         ref.Namespace,
+		}, nil
+	}
+
+	return ref, nil
+}
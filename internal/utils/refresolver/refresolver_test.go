@@ -11,6 +11,7 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -102,6 +103,59 @@ func TestRefResolver_GetController(t *testing.T) {
 	}
 }
 
+func TestRefResolver_GetAllowedController(t *testing.T) {
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: "slurm",
+		},
+		Spec: slinkyv1alpha1.ControllerSpec{
+			AllowedNamespaces: []string{"tenant"},
+		},
+	}
+	ref := slinkyv1alpha1.ObjectReference{
+		Name:      "slurm",
+		Namespace: "slurm",
+	}
+	tests := []struct {
+		name          string
+		namespace     string
+		wantForbidden bool
+	}{
+		{
+			name:      "same namespace",
+			namespace: "slurm",
+		},
+		{
+			name:      "allowed namespace",
+			namespace: "tenant",
+		},
+		{
+			name:          "other namespace",
+			namespace:     "other",
+			wantForbidden: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RefResolver{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(controller.DeepCopy()).
+					Build(),
+			}
+			got, err := r.GetAllowedController(context.TODO(), ref, tt.namespace)
+			if apierrors.IsForbidden(err) != tt.wantForbidden {
+				t.Errorf("RefResolver.GetAllowedController() error = %v, wantForbidden %v", err, tt.wantForbidden)
+				return
+			}
+			if !tt.wantForbidden && (err != nil || got == nil) {
+				t.Errorf("RefResolver.GetAllowedController() = %v, %v", got, err)
+			}
+		})
+	}
+}
+
 func TestRefResolver_GetAccounting(t *testing.T) {
 	type fields struct {
 		client client.Client
@@ -253,6 +307,69 @@ func TestRefResolver_GetNodeSetsForController(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "namespace not allowed",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(&slinkyv1alpha1.NodeSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "slurm-foo",
+							Namespace: "tenant",
+						},
+						Spec: slinkyv1alpha1.NodeSetSpec{
+							ControllerRef: slinkyv1alpha1.ObjectReference{
+								Name:      "slurm",
+								Namespace: metav1.NamespaceDefault,
+							},
+						},
+					}).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				controller: &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: metav1.NamespaceDefault,
+					},
+				},
+			},
+			want: 0,
+		},
+		{
+			name: "namespace allowed",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(&slinkyv1alpha1.NodeSet{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "slurm-foo",
+							Namespace: "tenant",
+						},
+						Spec: slinkyv1alpha1.NodeSetSpec{
+							ControllerRef: slinkyv1alpha1.ObjectReference{
+								Name:      "slurm",
+								Namespace: metav1.NamespaceDefault,
+							},
+						},
+					}).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				controller: &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: metav1.NamespaceDefault,
+					},
+					Spec: slinkyv1alpha1.ControllerSpec{
+						AllowedNamespaces: []string{"tenant"},
+					},
+				},
+			},
+			want: 1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -550,6 +667,128 @@ func TestRefResolver_GetControllersForAccounting(t *testing.T) {
 	}
 }
 
+func TestRefResolver_GetFederationForController(t *testing.T) {
+	newFederation := func(name string, controllers ...string) *slinkyv1alpha1.Federation {
+		federation := &slinkyv1alpha1.Federation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		for _, controller := range controllers {
+			federation.Spec.Clusters = append(federation.Spec.Clusters, slinkyv1alpha1.FederationCluster{
+				ControllerRef: slinkyv1alpha1.ObjectReference{
+					Name:      controller,
+					Namespace: metav1.NamespaceDefault,
+				},
+			})
+		}
+		return federation
+	}
+	controller := &slinkyv1alpha1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	type fields struct {
+		client client.Client
+	}
+	type args struct {
+		ctx        context.Context
+		controller *slinkyv1alpha1.Controller
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Not a member",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newFederation("fed", "slurm1", "slurm2")).
+					Build(),
+			},
+			args: args{
+				ctx:        context.TODO(),
+				controller: controller,
+			},
+			want: "",
+		},
+		{
+			name: "Member",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newFederation("fed", "slurm", "slurm1")).
+					WithObjects(newFederation("other", "slurm1", "slurm2")).
+					Build(),
+			},
+			args: args{
+				ctx:        context.TODO(),
+				controller: controller,
+			},
+			want: "fed",
+		},
+		{
+			name: "Member of multiple",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newFederation("fed2", "slurm", "slurm2")).
+					WithObjects(newFederation("fed1", "slurm", "slurm1")).
+					Build(),
+			},
+			args: args{
+				ctx:        context.TODO(),
+				controller: controller,
+			},
+			want: "fed1",
+		},
+		{
+			name: "Member of another namespace",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(func() *slinkyv1alpha1.Federation {
+						federation := newFederation("fed", "slurm")
+						federation.Namespace = "other"
+						return federation
+					}()).
+					Build(),
+			},
+			args: args{
+				ctx:        context.TODO(),
+				controller: controller,
+			},
+			want: "fed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RefResolver{
+				client: tt.fields.client,
+			}
+			got, err := r.GetFederationForController(tt.args.ctx, tt.args.controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefResolver.GetFederationForController() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			name := ""
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("RefResolver.GetFederationForController() = %v, want %v", name, tt.want)
+			}
+		})
+	}
+}
+
 func TestRefResolver_GetSecretKeyRef(t *testing.T) {
 	type fields struct {
 		client client.Client
@@ -630,3 +869,181 @@ func TestRefResolver_GetSecretKeyRef(t *testing.T) {
 		})
 	}
 }
+
+func TestRefResolver_GetTokenSigningKeyRef(t *testing.T) {
+	newController := func(reason string) *slinkyv1alpha1.Controller {
+		controller := &slinkyv1alpha1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "slurm",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: slinkyv1alpha1.ControllerSpec{
+				JwtHs256KeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "jwt-old",
+					},
+					Key: "jwt_hs256.key",
+				},
+				JwtHs256KeyRotation: &slinkyv1alpha1.JwtKeyRotation{
+					NewKeyRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "jwt-new",
+						},
+						Key: "jwt_hs256.key",
+					},
+				},
+				JwtRs256KeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "jwt-bridge",
+					},
+					Key: "jwt_rs256.key",
+				},
+			},
+		}
+		if reason != "" {
+			controller.Status.Conditions = []metav1.Condition{
+				{Type: slinkyv1alpha1.ControllerConditionJwtHs256KeyRotation, Reason: reason},
+			}
+		}
+		return controller
+	}
+	token := &slinkyv1alpha1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "token",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: slinkyv1alpha1.TokenSpec{
+			JwtHs256KeyRef: slinkyv1alpha1.JwtSecretKeySelector{
+				SecretKeySelector: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "jwt-old",
+					},
+					Key: "jwt_hs256.key",
+				},
+			},
+		},
+	}
+	rs256Token := token.DeepCopy()
+	rs256Token.Spec.JwtHs256KeyRef = slinkyv1alpha1.JwtSecretKeySelector{}
+	rs256Token.Spec.JwtRs256KeyRef = &slinkyv1alpha1.JwtSecretKeySelector{
+		SecretKeySelector: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "jwt-rs256",
+			},
+			Key: "jwt_rs256.key",
+		},
+	}
+	type fields struct {
+		client client.Client
+	}
+	type args struct {
+		ctx   context.Context
+		token *slinkyv1alpha1.Token
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "No controllers",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					Build(),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				token: token,
+			},
+			want: "jwt-old",
+		},
+		{
+			name: "Not started",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newController("")).
+					Build(),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				token: token,
+			},
+			want: "jwt-old",
+		},
+		{
+			name: "Rolling out",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newController(slinkyv1alpha1.JwtKeyRotationReasonRollingOut)).
+					Build(),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				token: token,
+			},
+			want: "jwt-bridge",
+		},
+		{
+			name: "Reissuing tokens",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newController(slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens)).
+					Build(),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				token: token,
+			},
+			want: "jwt-bridge",
+		},
+		{
+			name: "Ready to retire",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newController(slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire)).
+					Build(),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				token: token,
+			},
+			want: "jwt-new",
+		},
+		{
+			name: "RS256",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newController(slinkyv1alpha1.JwtKeyRotationReasonReissuingTokens)).
+					Build(),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				token: rs256Token,
+			},
+			want: "jwt-rs256",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RefResolver{
+				client: tt.fields.client,
+			}
+			got, err := r.GetTokenSigningKeyRef(tt.args.ctx, tt.args.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefResolver.GetTokenSigningKeyRef() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Name != tt.want || got.Namespace != metav1.NamespaceDefault {
+				t.Errorf("RefResolver.GetTokenSigningKeyRef() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmconf
+
+import (
+	"strings"
+
+	"k8s.io/utils/set"
+)
+
+// Line keys which define a Slurm object, rather than a single parameter.
+const (
+	keyNodeName      = "nodename"
+	keyPartitionName = "partitionname"
+	keyNodeSet       = "nodeset"
+	keyDownNodes     = "downnodes"
+)
+
+// keyDefault is the special name which sets defaults for subsequent lines.
+const keyDefault = "DEFAULT"
+
+// keyAll is the special node list which references all nodes.
+const keyAll = "ALL"
+
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_PARAMETERS
+var knownKeys = newKeySet(
+	"AccountingStorageBackupHost",
+	"AccountingStorageEnforce",
+	"AccountingStorageExternalHost",
+	"AccountingStorageHost",
+	"AccountingStorageParameters",
+	"AccountingStoragePass",
+	"AccountingStoragePort",
+	"AccountingStorageTRES",
+	"AccountingStorageType",
+	"AccountingStorageUser",
+	"AccountingStoreFlags",
+	"AcctGatherEnergyType",
+	"AcctGatherFilesystemType",
+	"AcctGatherInterconnectType",
+	"AcctGatherNodeFreq",
+	"AcctGatherProfileType",
+	"AllowSpecResourcesUsage",
+	"AuthAltParameters",
+	"AuthAltTypes",
+	"AuthInfo",
+	"AuthType",
+	"BatchStartTimeout",
+	"BcastExclude",
+	"BcastParameters",
+	"BurstBufferType",
+	"CertgenParameters",
+	"CertgenType",
+	"CertmgrParameters",
+	"CertmgrType",
+	"CliFilterParameters",
+	"CliFilterPlugins",
+	"ClusterName",
+	"CommunicationParameters",
+	"CompleteWait",
+	"CpuFreqDef",
+	"CpuFreqGovernors",
+	"CredType",
+	"DataParserParameters",
+	"DebugFlags",
+	"DefCpuPerGPU",
+	"DefMemPerCPU",
+	"DefMemPerGPU",
+	"DefMemPerNode",
+	"DependencyParameters",
+	"DisableRootJobs",
+	"EioTimeout",
+	"EnforcePartLimits",
+	"Epilog",
+	"EpilogMsgTime",
+	"EpilogSlurmctld",
+	"EpilogTimeout",
+	"FairShareDampeningFactor",
+	"FederationParameters",
+	"FirstJobId",
+	"GetEnvTimeout",
+	"GpuFreqDef",
+	"GresTypes",
+	"GroupUpdateForce",
+	"GroupUpdateTime",
+	"HashPlugin",
+	"HealthCheckInterval",
+	"HealthCheckNodeState",
+	"HealthCheckProgram",
+	"InactiveLimit",
+	"InteractiveStepOptions",
+	"JobAcctGatherFrequency",
+	"JobAcctGatherParams",
+	"JobAcctGatherType",
+	"JobCompHost",
+	"JobCompLoc",
+	"JobCompParams",
+	"JobCompPass",
+	"JobCompPort",
+	"JobCompType",
+	"JobCompUser",
+	"JobContainerType",
+	"JobDefaults",
+	"JobFileAppend",
+	"JobRequeue",
+	"JobSubmitPlugins",
+	"KillOnBadExit",
+	"KillWait",
+	"LaunchParameters",
+	"Licenses",
+	"LogTimeFormat",
+	"MailDomain",
+	"MailProg",
+	"MaxArraySize",
+	"MaxBatchRequeue",
+	"MaxDBDMsgs",
+	"MaxJobCount",
+	"MaxJobId",
+	"MaxMemPerCPU",
+	"MaxMemPerNode",
+	"MaxNodeCount",
+	"MaxStepCount",
+	"MaxTasksPerNode",
+	"MCSParameters",
+	"MCSPlugin",
+	"MessageTimeout",
+	"MinJobAge",
+	"MpiDefault",
+	"MpiParams",
+	"NodeFeaturesPlugins",
+	"OverTimeLimit",
+	"PluginDir",
+	"PlugStackConfig",
+	"PreemptExemptTime",
+	"PreemptMode",
+	"PreemptParameters",
+	"PreemptType",
+	"PrEpParameters",
+	"PrEpPlugins",
+	"PriorityCalcPeriod",
+	"PriorityDecayHalfLife",
+	"PriorityFavorSmall",
+	"PriorityFlags",
+	"PriorityMaxAge",
+	"PriorityParameters",
+	"PrioritySiteFactorParameters",
+	"PrioritySiteFactorPlugin",
+	"PriorityType",
+	"PriorityUsageResetPeriod",
+	"PriorityWeightAge",
+	"PriorityWeightAssoc",
+	"PriorityWeightFairshare",
+	"PriorityWeightJobSize",
+	"PriorityWeightPartition",
+	"PriorityWeightQOS",
+	"PriorityWeightTRES",
+	"PrivateData",
+	"ProctrackType",
+	"Prolog",
+	"PrologEpilogTimeout",
+	"PrologFlags",
+	"PrologSlurmctld",
+	"PrologTimeout",
+	"PropagatePrioProcess",
+	"PropagateResourceLimits",
+	"PropagateResourceLimitsExcept",
+	"RebootProgram",
+	"ReconfigFlags",
+	"RequeueExit",
+	"RequeueExitHold",
+	"ResumeFailProgram",
+	"ResumeProgram",
+	"ResumeRate",
+	"ResumeTimeout",
+	"ResvEpilog",
+	"ResvOverRun",
+	"ResvProlog",
+	"ReturnToService",
+	"SchedulerParameters",
+	"SchedulerTimeSlice",
+	"SchedulerType",
+	"ScronParameters",
+	"SelectType",
+	"SelectTypeParameters",
+	"SlurmctldAddr",
+	"SlurmctldDebug",
+	"SlurmctldHost",
+	"SlurmctldLogFile",
+	"SlurmctldParameters",
+	"SlurmctldPidFile",
+	"SlurmctldPort",
+	"SlurmctldPrimaryOffProg",
+	"SlurmctldPrimaryOnProg",
+	"SlurmctldSyslogDebug",
+	"SlurmctldTimeout",
+	"SlurmdDebug",
+	"SlurmdLogFile",
+	"SlurmdParameters",
+	"SlurmdPidFile",
+	"SlurmdPort",
+	"SlurmdSpoolDir",
+	"SlurmdSyslogDebug",
+	"SlurmdTimeout",
+	"SlurmdUser",
+	"SlurmSchedLogFile",
+	"SlurmSchedLogLevel",
+	"SlurmUser",
+	"SrunEpilog",
+	"SrunPortRange",
+	"SrunProlog",
+	"StateSaveLocation",
+	"SuspendExcNodes",
+	"SuspendExcParts",
+	"SuspendExcStates",
+	"SuspendProgram",
+	"SuspendRate",
+	"SuspendTime",
+	"SuspendTimeout",
+	"SwitchParameters",
+	"SwitchType",
+	"TaskEpilog",
+	"TaskPlugin",
+	"TaskProlog",
+	"TCPTimeout",
+	"TLSParameters",
+	"TLSType",
+	"TmpFS",
+	"TopologyParam",
+	"TopologyPlugin",
+	"TrackWCKey",
+	"TreeWidth",
+	"UnkillableStepProgram",
+	"UnkillableStepTimeout",
+	"UsePAM",
+	"VSizeFactor",
+	"WaitTime",
+	"X11Parameters",
+)
+
+// Keys which may be specified more than once.
+var multiKeys = newKeySet(
+	"Epilog",
+	"EpilogSlurmctld",
+	"Prolog",
+	"PrologSlurmctld",
+	"SlurmctldHost",
+)
+
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_NODE-CONFIGURATION
+var nodeNameKeys = newKeySet(
+	"NodeName",
+	"BcastAddr",
+	"Boards",
+	"CoreSpecCount",
+	"CoresPerSocket",
+	"CPUBind",
+	"CPUs",
+	"CpuSpecList",
+	"Feature",
+	"Features",
+	"Gres",
+	"MemSpecLimit",
+	"NodeAddr",
+	"NodeHostname",
+	"Parameters",
+	"Port",
+	"Procs",
+	"RealMemory",
+	"Reason",
+	"RestrictedCoresPerGPU",
+	"Sockets",
+	"SocketsPerBoard",
+	"State",
+	"ThreadsPerCore",
+	"TmpDisk",
+	"Topology",
+	"Weight",
+)
+
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_PARTITION-CONFIGURATION
+var partitionNameKeys = newKeySet(
+	"PartitionName",
+	"AllocNodes",
+	"AllowAccounts",
+	"AllowGroups",
+	"AllowQos",
+	"Alternate",
+	"CpuBind",
+	"Default",
+	"DefaultTime",
+	"DefCpuPerGPU",
+	"DefMemPerCPU",
+	"DefMemPerGPU",
+	"DefMemPerNode",
+	"DenyAccounts",
+	"DenyQos",
+	"DisableRootJobs",
+	"ExclusiveTopo",
+	"ExclusiveUser",
+	"GraceTime",
+	"Hidden",
+	"LLN",
+	"MaxCPUsPerNode",
+	"MaxCPUsPerSocket",
+	"MaxMemPerCPU",
+	"MaxMemPerNode",
+	"MaxNodes",
+	"MaxTime",
+	"MinNodes",
+	"Nodes",
+	"OverSubscribe",
+	"OverTimeLimit",
+	"PowerDownOnIdle",
+	"PreemptMode",
+	"PriorityJobFactor",
+	"PriorityTier",
+	"QOS",
+	"ReqResv",
+	"ResumeTimeout",
+	"RootOnly",
+	"SelectTypeParameters",
+	"State",
+	"SuspendTime",
+	"SuspendTimeout",
+	"Topology",
+	"TRESBillingWeights",
+)
+
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_NODESET-CONFIGURATION
+var nodeSetKeys = newKeySet(
+	"NodeSet",
+	"Feature",
+	"Nodes",
+)
+
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_DOWN-NODE-CONFIGURATION
+var downNodesKeys = newKeySet(
+	"DownNodes",
+	"Reason",
+	"State",
+)
+
+func newKeySet(keys ...string) set.Set[string] {
+	out := set.New[string]()
+	for _, key := range keys {
+		out.Insert(strings.ToLower(key))
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmconf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/puttsk/hostlist"
+	"k8s.io/utils/set"
+)
+
+// nodeRef is a reference to nodes or NodeSets from a line.
+type nodeRef struct {
+	line  int
+	owner string
+	nodes string
+}
+
+// Lint parses slurm.conf formatted data and checks it for common mistakes.
+// Warnings are returned for content that slurmctld tolerates but is likely
+// unintended (e.g. unknown or duplicate keys), or which may only be valid later
+// (e.g. references to NodeSets which are not created yet). Errors are returned
+// for content that slurmctld would reject (e.g. malformed lines).
+func Lint(data string) ([]string, []error) {
+	warns := []string{}
+	lines, errs := Parse(data)
+
+	seenKeys := map[string]int{}
+	nodeNames := set.New[string]()
+	nodeSetNames := set.New[string]()
+	partitionNames := set.New[string]()
+	refs := []nodeRef{}
+
+	for _, line := range lines {
+		key := strings.ToLower(line.Key())
+		name := line.Value()
+		switch key {
+		case keyNodeName:
+			w, e := lintObjectLine(&line, nodeNameKeys)
+			warns = append(warns, w...)
+			errs = append(errs, e...)
+			if strings.EqualFold(name, keyDefault) {
+				continue
+			}
+			hosts, err := hostlist.Expand(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: malformed NodeName %q: %w", line.Number, name, err))
+				continue
+			}
+			for _, host := range hosts {
+				if nodeNames.Has(host) {
+					errs = append(errs, fmt.Errorf("line %d: NodeName %q is defined more than once", line.Number, host))
+				}
+				nodeNames.Insert(host)
+			}
+
+		case keyPartitionName:
+			w, e := lintObjectLine(&line, partitionNameKeys)
+			warns = append(warns, w...)
+			errs = append(errs, e...)
+			if strings.EqualFold(name, keyDefault) {
+				continue
+			}
+			if partitionNames.Has(name) {
+				errs = append(errs, fmt.Errorf("line %d: PartitionName %q is defined more than once", line.Number, name))
+			}
+			partitionNames.Insert(name)
+			if nodes, ok := line.Get("Nodes"); ok {
+				refs = append(refs, nodeRef{line: line.Number, owner: "PartitionName=" + name, nodes: nodes})
+			}
+
+		case keyNodeSet:
+			w, e := lintObjectLine(&line, nodeSetKeys)
+			warns = append(warns, w...)
+			errs = append(errs, e...)
+			if nodeSetNames.Has(name) {
+				errs = append(errs, fmt.Errorf("line %d: NodeSet %q is defined more than once", line.Number, name))
+			}
+			nodeSetNames.Insert(name)
+			_, hasFeature := line.Get("Feature")
+			nodes, hasNodes := line.Get("Nodes")
+			if !hasFeature && !hasNodes {
+				errs = append(errs, fmt.Errorf("line %d: NodeSet %q requires Feature or Nodes", line.Number, name))
+			}
+			if hasNodes {
+				refs = append(refs, nodeRef{line: line.Number, owner: "NodeSet=" + name, nodes: nodes})
+			}
+
+		case keyDownNodes:
+			w, e := lintObjectLine(&line, downNodesKeys)
+			warns = append(warns, w...)
+			errs = append(errs, e...)
+
+		default:
+			if len(line.Params) > 1 {
+				errs = append(errs, fmt.Errorf("line %d: unexpected parameters after %s", line.Number, line.Key()))
+			}
+			if !knownKeys.Has(key) {
+				warns = append(warns, fmt.Sprintf("line %d: unknown key: %s", line.Number, line.Key()))
+			}
+			if prev, ok := seenKeys[key]; ok && !multiKeys.Has(key) {
+				warns = append(warns, fmt.Sprintf("line %d: %s was already specified on line %d, the last value is used",
+					line.Number, line.Key(), prev))
+			}
+			seenKeys[key] = line.Number
+		}
+	}
+
+	for _, ref := range refs {
+		for _, item := range splitNodeList(ref.nodes) {
+			if strings.EqualFold(item, keyAll) || nodeSetNames.Has(item) {
+				continue
+			}
+			hosts, err := hostlist.Expand(item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %s has malformed Nodes %q: %w", ref.line, ref.owner, item, err))
+				continue
+			}
+			for _, host := range hosts {
+				if !nodeNames.Has(host) {
+					warns = append(warns, fmt.Sprintf("line %d: %s references undefined NodeSet or NodeName: %s",
+						ref.line, ref.owner, host))
+				}
+			}
+		}
+	}
+
+	return warns, errs
+}
+
+// lintObjectLine checks the parameters of a line defining a Slurm object.
+func lintObjectLine(line *Line, known set.Set[string]) ([]string, []error) {
+	warns := []string{}
+	errs := []error{}
+
+	seen := set.New[string]()
+	for _, param := range line.Params {
+		key := strings.ToLower(param.Key)
+		if seen.Has(key) {
+			errs = append(errs, fmt.Errorf("line %d: %s=%s has duplicate key: %s",
+				line.Number, line.Key(), line.Value(), param.Key))
+		}
+		seen.Insert(key)
+		if !known.Has(key) {
+			warns = append(warns, fmt.Sprintf("line %d: %s=%s has unknown key: %s",
+				line.Number, line.Key(), line.Value(), param.Key))
+		}
+	}
+
+	return warns, errs
+}
+
+// splitNodeList splits a comma separated node list, respecting hostlist
+// brackets (e.g. `foo-[0-3,5],bar`).
+func splitNodeList(nodes string) []string {
+	out := []string{}
+	depth := 0
+	start := 0
+	for i, r := range nodes {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				if item := nodes[start:i]; item != "" {
+					out = append(out, item)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if item := nodes[start:]; item != "" {
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmconf
+
+import (
+	"strings"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestLint(t *testing.T) {
+	type args struct {
+		data string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantWarns int
+		wantErrs  int
+	}{
+		{
+			name: "empty",
+			args: args{
+				data: "",
+			},
+		},
+		{
+			name: "valid",
+			args: args{
+				data: strings.Join([]string{
+					"ClusterName=slurm",
+					"SlurmctldHost=slurm-controller-0(slurm-controller.slurm)",
+					"Prolog=00-exit.sh",
+					"Prolog=01-exit.sh",
+					"NodeName=DEFAULT RealMemory=1024",
+					"NodeName=foo-[0-1] NodeAddr=foo Features=foo",
+					"NodeSet=foo Feature=foo",
+					"NodeSet=bar Nodes=foo-[0-1]",
+					"PartitionName=DEFAULT MaxTime=UNLIMITED",
+					"PartitionName=foo Nodes=foo",
+					"PartitionName=bar Nodes=bar,foo-0",
+					"PartitionName=all Nodes=ALL Default=YES",
+					"DownNodes=foo-1 State=DOWN Reason=maintenance",
+				}, "\n"),
+			},
+		},
+		{
+			name: "unknown and duplicate keys",
+			args: args{
+				data: strings.Join([]string{
+					"ClusterName=slurm",
+					"clustername=slurm",
+					"FooBar=baz",
+					"PartitionName=foo Nodes=ALL FooBar=baz",
+				}, "\n"),
+			},
+			wantWarns: 3,
+		},
+		{
+			name: "malformed lines",
+			args: args{
+				data: strings.Join([]string{
+					"SlurmUser",
+					"SlurmctldParameters=enable_configless enable_stepmgr",
+					"NodeSet=foo",
+					"NodeSet=bar Feature=bar",
+					"NodeSet=bar Feature=bar",
+					"PartitionName=foo Nodes=bar Nodes=bar",
+					"PartitionName=foo Nodes=bar",
+				}, "\n"),
+			},
+			wantErrs: 6,
+		},
+		{
+			name: "undefined references",
+			args: args{
+				data: strings.Join([]string{
+					"NodeSet=foo Feature=foo",
+					"PartitionName=foo Nodes=foo,bar",
+					"PartitionName=baz Nodes=baz-[0-1]",
+					"NodeSet=qux Nodes=qux-0",
+				}, "\n"),
+			},
+			wantWarns: 4,
+		},
+		{
+			name: "case-insensitive DEFAULT and ALL",
+			args: args{
+				data: strings.Join([]string{
+					"NodeName=default RealMemory=1024",
+					"NodeName=Default RealMemory=1024",
+					"PartitionName=default MaxTime=UNLIMITED",
+					"PartitionName=Default MaxTime=UNLIMITED",
+					"PartitionName=all Nodes=all",
+				}, "\n"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warns, errs := Lint(tt.args.data)
+			if len(warns) != tt.wantWarns {
+				t.Errorf("Lint() warns = %v, wantWarns %v", warns, tt.wantWarns)
+			}
+			if len(errs) != tt.wantErrs {
+				t.Errorf("Lint() errs = %v, wantErrs %v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func Test_splitNodeList(t *testing.T) {
+	type args struct {
+		nodes string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "empty",
+			args: args{
+				nodes: "",
+			},
+			want: []string{},
+		},
+		{
+			name: "single",
+			args: args{
+				nodes: "foo",
+			},
+			want: []string{"foo"},
+		},
+		{
+			name: "hostlist",
+			args: args{
+				nodes: "foo-[0-3,5],bar,baz-[1,2]",
+			},
+			want: []string{"foo-[0-3,5]", "bar", "baz-[1,2]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitNodeList(tt.args.nodes); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("splitNodeList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
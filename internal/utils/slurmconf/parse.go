@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmconf
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Param is a single `key=value` pair of a configuration line.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Line is a parsed configuration line, made of one or more parameters.
+type Line struct {
+	// Number is the 1-based line number within the parsed data.
+	Number int
+	// Params are the parameters of the line, in order of appearance.
+	Params []Param
+}
+
+// Key returns the key of the first parameter, which identifies the line.
+func (l *Line) Key() string {
+	return l.Params[0].Key
+}
+
+// Value returns the value of the first parameter.
+func (l *Line) Value() string {
+	return l.Params[0].Value
+}
+
+// Get returns the value of the parameter matching key, case-insensitive.
+func (l *Line) Get(key string) (string, bool) {
+	for _, param := range l.Params {
+		if strings.EqualFold(param.Key, key) {
+			return param.Value, true
+		}
+	}
+	return "", false
+}
+
+// Parse parses slurm.conf formatted data into lines of parameters.
+// Comments, blank lines, and `Include` directives are skipped.
+// Malformed lines are reported as errors and excluded from the result.
+// Ref: https://slurm.schedmd.com/slurm.conf.html
+func Parse(data string) ([]Line, []error) {
+	lines := []Line{}
+	errs := []error{}
+
+	for i, raw := range strings.Split(data, "\n") {
+		number := i + 1
+		text := strings.TrimSpace(stripComment(raw))
+		if text == "" || isInclude(text) {
+			continue
+		}
+
+		tokens, err := tokenize(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", number, err))
+			continue
+		}
+
+		line := Line{
+			Number: number,
+			Params: make([]Param, 0, len(tokens)),
+		}
+		malformed := false
+		for _, token := range tokens {
+			key, val, ok := strings.Cut(token, "=")
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Errorf("line %d: expected `key=value`, got: %s", number, token))
+				malformed = true
+			case key == "":
+				errs = append(errs, fmt.Errorf("line %d: missing key: %s", number, token))
+				malformed = true
+			case val == "":
+				errs = append(errs, fmt.Errorf("line %d: missing value for key: %s", number, key))
+				malformed = true
+			}
+			line.Params = append(line.Params, Param{Key: key, Value: strings.Trim(val, `"`)})
+		}
+		if malformed {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, errs
+}
+
+// stripComment removes a trailing comment, honoring escaped `\#`.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// isInclude returns true if the line is an `Include` directive.
+func isInclude(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "include")
+}
+
+// tokenize splits the line by whitespace, keeping double-quoted text together.
+func tokenize(line string) ([]string, error) {
+	tokens := []string{}
+	var token strings.Builder
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote: %s", line)
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmconf
+
+import (
+	"strings"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		data string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     []Line
+		wantErrs int
+	}{
+		{
+			name: "empty",
+			args: args{
+				data: "",
+			},
+			want: []Line{},
+		},
+		{
+			name: "comments and includes",
+			args: args{
+				data: strings.Join([]string{
+					"#",
+					"### GENERAL ###",
+					"  ",
+					"include /etc/slurm/foo.conf",
+					"ClusterName=slurm # trailing comment",
+				}, "\n"),
+			},
+			want: []Line{
+				{Number: 5, Params: []Param{{Key: "ClusterName", Value: "slurm"}}},
+			},
+		},
+		{
+			name: "multiple params",
+			args: args{
+				data: strings.Join([]string{
+					"PartitionName=foo Nodes=foo Default=YES",
+					`DownNodes=foo-0 State=DOWN Reason="foo bar"`,
+				}, "\n"),
+			},
+			want: []Line{
+				{Number: 1, Params: []Param{
+					{Key: "PartitionName", Value: "foo"},
+					{Key: "Nodes", Value: "foo"},
+					{Key: "Default", Value: "YES"},
+				}},
+				{Number: 2, Params: []Param{
+					{Key: "DownNodes", Value: "foo-0"},
+					{Key: "State", Value: "DOWN"},
+					{Key: "Reason", Value: "foo bar"},
+				}},
+			},
+		},
+		{
+			name: "malformed",
+			args: args{
+				data: strings.Join([]string{
+					"ClusterName",
+					"=slurm",
+					"SlurmUser=",
+					`PartitionName=foo Nodes="foo`,
+					"MinJobAge=2",
+				}, "\n"),
+			},
+			want: []Line{
+				{Number: 5, Params: []Param{{Key: "MinJobAge", Value: "2"}}},
+			},
+			wantErrs: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := Parse(tt.args.data)
+			if len(errs) != tt.wantErrs {
+				t.Errorf("Parse() errs = %v, wantErrs %v", errs, tt.wantErrs)
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLine_Get(t *testing.T) {
+	line := &Line{
+		Number: 1,
+		Params: []Param{
+			{Key: "PartitionName", Value: "foo"},
+			{Key: "Nodes", Value: "bar"},
+		},
+	}
+	type args struct {
+		key string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "found",
+			args:   args{key: "Nodes"},
+			want:   "bar",
+			wantOk: true,
+		},
+		{
+			name:   "found, case-insensitive",
+			args:   args{key: "nodes"},
+			want:   "bar",
+			wantOk: true,
+		},
+		{
+			name:   "not found",
+			args:   args{key: "MaxTime"},
+			want:   "",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := line.Get(tt.args.key)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Line.Get() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	v0042 "github.com/SlinkyProject/slurm-client/api/v0042"
+	v0043 "github.com/SlinkyProject/slurm-client/api/v0043"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	slurmobject "github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+)
+
+// objects are the constructors of the objects of each version, by the type of
+// the equivalent object of the Default version.
+var objects = map[Version]map[slurmobject.ObjectType]func() slurmobject.Object{
+	V0042: {
+		slurmtypes.ObjectTypeV0043Node:           func() slurmobject.Object { return &slurmtypes.V0042Node{} },
+		slurmtypes.ObjectTypeV0043JobInfo:        func() slurmobject.Object { return &slurmtypes.V0042JobInfo{} },
+		slurmtypes.ObjectTypeV0043PartitionInfo:  func() slurmobject.Object { return &slurmtypes.V0042PartitionInfo{} },
+		slurmtypes.ObjectTypeV0043Stats:          func() slurmobject.Object { return &slurmtypes.V0042Stats{} },
+		slurmtypes.ObjectTypeV0043ControllerPing: func() slurmobject.Object { return &slurmtypes.V0042ControllerPing{} },
+	},
+	V0041: {
+		slurmtypes.ObjectTypeV0043Node:           func() slurmobject.Object { return &slurmtypes.V0041Node{} },
+		slurmtypes.ObjectTypeV0043JobInfo:        func() slurmobject.Object { return &slurmtypes.V0041JobInfo{} },
+		slurmtypes.ObjectTypeV0043PartitionInfo:  func() slurmobject.Object { return &slurmtypes.V0041PartitionInfo{} },
+		slurmtypes.ObjectTypeV0043Stats:          func() slurmobject.Object { return &slurmtypes.V0041Stats{} },
+		slurmtypes.ObjectTypeV0043ControllerPing: func() slurmobject.Object { return &slurmtypes.V0041ControllerPing{} },
+	},
+}
+
+// lists are the constructors of the object lists of each version, by the type
+// of the equivalent object of the Default version.
+var lists = map[Version]map[slurmobject.ObjectType]func() slurmobject.ObjectList{
+	V0042: {
+		slurmtypes.ObjectTypeV0043Node:           func() slurmobject.ObjectList { return &slurmtypes.V0042NodeList{} },
+		slurmtypes.ObjectTypeV0043JobInfo:        func() slurmobject.ObjectList { return &slurmtypes.V0042JobInfoList{} },
+		slurmtypes.ObjectTypeV0043PartitionInfo:  func() slurmobject.ObjectList { return &slurmtypes.V0042PartitionInfoList{} },
+		slurmtypes.ObjectTypeV0043Stats:          func() slurmobject.ObjectList { return &slurmtypes.V0042StatsList{} },
+		slurmtypes.ObjectTypeV0043ControllerPing: func() slurmobject.ObjectList { return &slurmtypes.V0042ControllerPingList{} },
+	},
+	V0041: {
+		slurmtypes.ObjectTypeV0043Node:           func() slurmobject.ObjectList { return &slurmtypes.V0041NodeList{} },
+		slurmtypes.ObjectTypeV0043JobInfo:        func() slurmobject.ObjectList { return &slurmtypes.V0041JobInfoList{} },
+		slurmtypes.ObjectTypeV0043PartitionInfo:  func() slurmobject.ObjectList { return &slurmtypes.V0041PartitionInfoList{} },
+		slurmtypes.ObjectTypeV0043Stats:          func() slurmobject.ObjectList { return &slurmtypes.V0041StatsList{} },
+		slurmtypes.ObjectTypeV0043ControllerPing: func() slurmobject.ObjectList { return &slurmtypes.V0041ControllerPingList{} },
+	},
+}
+
+// NewObject returns an object of the version, of the type of an object of the
+// Default version.
+func NewObject(version Version, objectType slurmobject.ObjectType) slurmobject.Object {
+	if newObject, ok := objects[version][objectType]; ok {
+		return newObject()
+	}
+	switch objectType {
+	case slurmtypes.ObjectTypeV0043Node:
+		return &slurmtypes.V0043Node{}
+	case slurmtypes.ObjectTypeV0043JobInfo:
+		return &slurmtypes.V0043JobInfo{}
+	case slurmtypes.ObjectTypeV0043PartitionInfo:
+		return &slurmtypes.V0043PartitionInfo{}
+	case slurmtypes.ObjectTypeV0043Stats:
+		return &slurmtypes.V0043Stats{}
+	case slurmtypes.ObjectTypeV0043ControllerPing:
+		return &slurmtypes.V0043ControllerPing{}
+	}
+	return nil
+}
+
+// Client is a Slurm client which takes the objects of the Default version, and
+// exchanges them with slurmrestd in the negotiated version.
+type Client struct {
+	slurmclient.Client
+
+	version Version
+}
+
+// NewClient returns a client which exchanges objects with slurmrestd in the
+// version.
+func NewClient(client slurmclient.Client, version Version) *Client {
+	return &Client{
+		Client:  client,
+		version: version,
+	}
+}
+
+// Version returns the negotiated version of the client.
+func (c *Client) Version() Version {
+	return c.version
+}
+
+// VersionOf returns the negotiated version of the client, or the Default
+// version for a client which does not negotiate.
+func VersionOf(client slurmclient.Client) Version {
+	if c, ok := client.(*Client); ok {
+		return c.Version()
+	}
+	return Default
+}
+
+// Get implements Client.
+func (c *Client) Get(ctx context.Context, key slurmobject.ObjectKey, obj slurmobject.Object, opts ...slurmclient.GetOption) error {
+	newObject, ok := objects[c.version][obj.GetType()]
+	if !ok {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	out := newObject()
+	if err := c.Client.Get(ctx, key, out, opts...); err != nil {
+		return err
+	}
+	return convert(out, obj)
+}
+
+// List implements Client.
+func (c *Client) List(ctx context.Context, list slurmobject.ObjectList, opts ...slurmclient.ListOption) error {
+	newList, ok := lists[c.version][list.GetType()]
+	if !ok {
+		return c.Client.List(ctx, list, opts...)
+	}
+	out := newList()
+	if err := c.Client.List(ctx, out, opts...); err != nil {
+		return err
+	}
+	return convert(out, list)
+}
+
+// Update implements Client.
+func (c *Client) Update(ctx context.Context, obj slurmobject.Object, req any, opts ...slurmclient.UpdateOption) error {
+	newObject, ok := objects[c.version][obj.GetType()]
+	if !ok {
+		return c.Client.Update(ctx, obj, req, opts...)
+	}
+	out := newObject()
+	if err := convert(obj, out); err != nil {
+		return err
+	}
+	versionedReq, err := c.versionedRequest(req)
+	if err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, out, versionedReq, opts...)
+}
+
+// Delete implements Client.
+func (c *Client) Delete(ctx context.Context, obj slurmobject.Object, opts ...slurmclient.DeleteOption) error {
+	newObject, ok := objects[c.version][obj.GetType()]
+	if !ok {
+		return c.Client.Delete(ctx, obj, opts...)
+	}
+	out := newObject()
+	if err := convert(obj, out); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, out, opts...)
+}
+
+// GetInformer implements Client. The informer holds objects of the negotiated
+// version; see DefaultNode.
+func (c *Client) GetInformer(objectType slurmobject.ObjectType) slurmclient.InformerCache {
+	return c.Client.GetInformer(c.ObjectType(objectType))
+}
+
+// ObjectType returns the type, in the negotiated version, of the type of an
+// object of the Default version.
+func (c *Client) ObjectType(objectType slurmobject.ObjectType) slurmobject.ObjectType {
+	if obj := NewObject(c.version, objectType); obj != nil {
+		return obj.GetType()
+	}
+	return objectType
+}
+
+// versionedRequest returns the request, of the Default version, in the
+// negotiated version.
+func (c *Client) versionedRequest(req any) (any, error) {
+	msg, ok := req.(v0043.V0043UpdateNodeMsg)
+	if !ok {
+		return req, nil
+	}
+	switch c.version {
+	case V0042:
+		out := v0042.V0042UpdateNodeMsg{}
+		err := convert(msg, &out)
+		return out, err
+	case V0041:
+		out := v0041.V0041UpdateNodeMsg{}
+		err := convert(msg, &out)
+		return out, err
+	}
+	return req, nil
+}
+
+// DefaultNode returns the node, of any version, in the Default version.
+func DefaultNode(obj any) (*slurmtypes.V0043Node, bool) {
+	switch o := obj.(type) {
+	case *slurmtypes.V0043Node:
+		return o, true
+	case *slurmtypes.V0042Node, *slurmtypes.V0041Node:
+		out := &slurmtypes.V0043Node{}
+		if err := convert(o, out); err != nil {
+			return nil, false
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// convert copies the fields of in into out, by their JSON names. A field whose
+// type changed between versions fails the conversion, rather than being
+// silently dropped; it must be converted explicitly.
+func convert(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to convert %T to %T: %w", in, out, err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmversion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	v0041 "github.com/SlinkyProject/slurm-client/api/v0041"
+	v0043 "github.com/SlinkyProject/slurm-client/api/v0043"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	slurmobject "github.com/SlinkyProject/slurm-client/pkg/object"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+)
+
+func TestClient_V0041(t *testing.T) {
+	ctx := context.Background()
+	node := &slurmtypes.V0041Node{
+		V0041Node: v0041.V0041Node{
+			Name:    ptr.To("node-0"),
+			Comment: ptr.To("comment"),
+			State:   ptr.To([]v0041.V0041NodeState{v0041.V0041NodeStateIDLE, v0041.V0041NodeStateDRAIN}),
+		},
+	}
+	var gotReq any
+	updateFn := func(_ context.Context, _ slurmobject.Object, req any, _ ...slurmclient.UpdateOption) error {
+		gotReq = req
+		return nil
+	}
+	c := NewClient(fake.NewClientBuilder().WithObjects(node).WithUpdateFn(updateFn).Build(), V0041)
+	if VersionOf(c) != V0041 {
+		t.Errorf("VersionOf() = %v, want %v", VersionOf(c), V0041)
+	}
+
+	got := &slurmtypes.V0043Node{}
+	if err := c.Get(ctx, "node-0", got); err != nil {
+		t.Fatalf("Client.Get() error = %v", err)
+	}
+	if ptr.Deref(got.Comment, "") != "comment" || !got.GetStateAsSet().Has(v0043.V0043NodeStateDRAIN) {
+		t.Errorf("Client.Get() = %v", got.V0043Node)
+	}
+
+	list := &slurmtypes.V0043NodeList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatalf("Client.List() error = %v", err)
+	}
+	if len(list.Items) != 1 || ptr.Deref(list.Items[0].Name, "") != "node-0" {
+		t.Errorf("Client.List() = %v", list.Items)
+	}
+
+	req := v0043.V0043UpdateNodeMsg{
+		State:  ptr.To([]v0043.V0043UpdateNodeMsgState{v0043.V0043UpdateNodeMsgStateUNDRAIN}),
+		Reason: ptr.To("reason"),
+	}
+	if err := c.Update(ctx, got, req); err != nil {
+		t.Fatalf("Client.Update() error = %v", err)
+	}
+	msg, ok := gotReq.(v0041.V0041UpdateNodeMsg)
+	if !ok {
+		t.Fatalf("Client.Update() req = %T, want V0041UpdateNodeMsg", gotReq)
+	}
+	if ptr.Deref(msg.Reason, "") != "reason" || (*msg.State)[0] != v0041.V0041UpdateNodeMsgStateUNDRAIN {
+		t.Errorf("Client.Update() req = %v", msg)
+	}
+
+	if c.ObjectType(slurmtypes.ObjectTypeV0043Node) != slurmtypes.ObjectTypeV0041Node {
+		t.Errorf("Client.ObjectType() = %v", c.ObjectType(slurmtypes.ObjectTypeV0043Node))
+	}
+	if converted, ok := DefaultNode(node); !ok || ptr.Deref(converted.Name, "") != "node-0" {
+		t.Errorf("DefaultNode() = %v, %v", converted, ok)
+	}
+}
+
+func Test_convert(t *testing.T) {
+	type in struct {
+		Name  string `json:"name"`
+		Count string `json:"count"`
+	}
+	type out struct {
+		Name  string `json:"name"`
+		Count int32  `json:"count"`
+	}
+	tests := []struct {
+		name    string
+		in      any
+		wantErr bool
+	}{
+		{
+			name: "Same types",
+			in: struct {
+				Name  string `json:"name"`
+				Count int64  `json:"count"`
+			}{Name: "node-0", Count: 1},
+		},
+		{
+			name:    "Type changed",
+			in:      in{Name: "node-0", Count: "1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &out{}
+			err := convert(tt.in, got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				typeErr := &json.UnmarshalTypeError{}
+				if !errors.As(err, &typeErr) || typeErr.Field != "count" {
+					t.Errorf("convert() error = %v, want UnmarshalTypeError of count", err)
+				}
+				return
+			}
+			if got.Name != "node-0" || got.Count != 1 {
+				t.Errorf("convert() = %v", got)
+			}
+		})
+	}
+}
+
+func TestVersionOf(t *testing.T) {
+	if got := VersionOf(fake.NewFakeClient()); got != Default {
+		t.Errorf("VersionOf() = %v, want %v", got, Default)
+	}
+}
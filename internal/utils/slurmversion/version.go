@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmversion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Version is a version of the Slurm REST API (e.g. `v0.0.43`).
+type Version string
+
+const (
+	V0041 Version = "v0.0.41"
+	V0042 Version = "v0.0.42"
+	V0043 Version = "v0.0.43"
+
+	// Default is the version the operator is written against.
+	Default = V0043
+)
+
+// Supported are the versions the operator can manage, in order of preference.
+var Supported = []Version{V0043, V0042, V0041}
+
+// ErrUnsupported is returned when slurmrestd supports none of the Supported versions.
+var ErrUnsupported = errors.New("no supported Slurm REST API version")
+
+const (
+	openapiPath          = "/openapi/v3"
+	headerSlurmUserToken = "X-SLURM-USER-TOKEN"
+)
+
+var slurmPathRegexp = regexp.MustCompile(`^/slurm/(v\d+\.\d+\.\d+)/`)
+
+// Discover returns the versions of the Slurm REST API served by slurmrestd, as
+// found in the paths of its OpenAPI specification.
+func Discover(ctx context.Context, httpClient *http.Client, server, token string) ([]Version, error) {
+	url := strings.TrimSuffix(server, "/") + openapiPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(headerSlurmUserToken, token)
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI specification: %w", err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get OpenAPI specification: %s", http.StatusText(res.StatusCode))
+	}
+
+	spec := struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI specification: %w", err)
+	}
+
+	versions := []Version{}
+	for path := range spec.Paths {
+		match := slurmPathRegexp.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+		version := Version(match[1])
+		if !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// Negotiate returns the preferred Supported version among the versions served
+// by slurmrestd.
+func Negotiate(versions []Version) (Version, error) {
+	for _, version := range Supported {
+		if slices.Contains(versions, version) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("%w: slurmrestd serves %v, the operator supports %v", ErrUnsupported, versions, Supported)
+}
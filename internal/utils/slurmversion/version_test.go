@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmversion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != openapiPath || r.Header.Get(headerSlurmUserToken) != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"openapi": "3.0.2", "paths": {
+			"/slurm/v0.0.42/nodes/": {},
+			"/slurm/v0.0.41/ping/": {},
+			"/slurm/v0.0.42/ping/": {},
+			"/slurmdb/v0.0.42/jobs/": {},
+			"/openapi/v3": {}
+		}}`))
+	}))
+	defer server.Close()
+
+	got, err := Discover(context.Background(), server.Client(), server.URL+"/", "token")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if want := []Version{V0041, V0042}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %v, want %v", got, want)
+	}
+
+	if _, err := Discover(context.Background(), server.Client(), server.URL, "other"); err == nil {
+		t.Errorf("Discover() with an invalid token, want error")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []Version
+		want     Version
+		wantErr  bool
+	}{
+		{
+			name:     "Latest",
+			versions: []Version{V0041, V0042, V0043},
+			want:     V0043,
+		},
+		{
+			name:     "Older",
+			versions: []Version{"v0.0.40", V0041},
+			want:     V0041,
+		},
+		{
+			name:     "Newer",
+			versions: []Version{V0043, "v0.0.44"},
+			want:     V0043,
+		},
+		{
+			name:     "Unsupported",
+			versions: []Version{"v0.0.39", "v0.0.40"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Negotiate(tt.versions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Negotiate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Negotiate() error = %v, want %v", err, ErrUnsupported)
+			}
+			if got != tt.want {
+				t.Errorf("Negotiate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -272,3 +272,30 @@ func NewToken(name string, jwtHs256KeySecret *corev1.Secret) *slinkyv1alpha1.Tok
 		},
 	}
 }
+
+func NewFederation(name string, accounting *slinkyv1alpha1.Accounting, controllers ...*slinkyv1alpha1.Controller) *slinkyv1alpha1.Federation {
+	accountingRef := slinkyv1alpha1.ObjectReference{}
+	if accounting != nil {
+		accountingRef = NewObjectRef(accounting)
+	}
+	clusters := make([]slinkyv1alpha1.FederationCluster, 0, len(controllers))
+	for _, controller := range controllers {
+		clusters = append(clusters, slinkyv1alpha1.FederationCluster{
+			ControllerRef: NewObjectRef(controller),
+		})
+	}
+	return &slinkyv1alpha1.Federation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: slinkyv1alpha1.FederationAPIVersion,
+			Kind:       slinkyv1alpha1.FederationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: slinkyv1alpha1.FederationSpec{
+			AccountingRef: accountingRef,
+			Clusters:      clusters,
+		},
+	}
+}
@@ -57,7 +57,9 @@ func (r *AccountingSetWebhook) ValidateCreate(ctx context.Context, obj runtime.O
 	accounting := obj.(*slinkyv1alpha1.Accounting)
 	accountinglog.Info("validate create", "accounting", klog.KObj(accounting))
 
-	return nil, nil
+	warns, errs := validateAccounting(accounting)
+
+	return warns, utilerrors.NewAggregate(errs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -83,5 +85,8 @@ func validateAccounting(obj *slinkyv1alpha1.Accounting) (admission.Warnings, []e
 	var warns admission.Warnings
 	var errs []error
 
+	errs = append(errs, validateJwtKeyRotation(obj.Spec.JwtHs256KeyRef, obj.Spec.JwtRs256KeyRef, obj.Spec.JwtHs256KeyRotation)...)
+	errs = append(errs, validateJwtRs256KeyRef(obj.Spec.JwtHs256KeyRef, obj.Spec.JwtRs256KeyRef)...)
+
 	return warns, errs
 }
@@ -7,14 +7,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
 	"regexp"
 	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,6 +28,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmconf"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
 )
 
@@ -51,6 +58,11 @@ var _ webhook.CustomDefaulter = &ControllerWebhook{}
 func (r *ControllerWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	controller := obj.(*slinkyv1alpha1.Controller)
 	controllerlog.Info("default", "controller", klog.KObj(controller))
+
+	if external := controller.Spec.External; external != nil && external.Port == 0 {
+		external.Port = builder.SlurmctldPort
+	}
+
 	return nil
 }
 
@@ -100,13 +112,52 @@ func (r *ControllerWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.O
 	if newController.ClusterName() != oldController.ClusterName() {
 		errs = append(errs, errors.New("cannot change ClusterName after deployment"))
 	}
-	if !apiequality.Semantic.DeepEqual(newController.Spec.SlurmKeyRef.LocalObjectReference, oldController.Spec.SlurmKeyRef.LocalObjectReference) {
-		errs = append(errs, errors.New("cannot change SlurmKeyRef after deployment"))
+	if newController.IsExternal() != oldController.IsExternal() {
+		errs = append(errs, errors.New("cannot add or remove External after deployment"))
+	}
+	if !apiequality.Semantic.DeepEqual(newController.Spec.SlurmKeyRef.LocalObjectReference, oldController.Spec.SlurmKeyRef.LocalObjectReference) &&
+		!isSlurmKeyRotationRetired(oldController, newController) {
+		errs = append(errs, errors.New("cannot change SlurmKeyRef after deployment, except to the new key of a SlurmKeyRotation which is ReadyToRetire"))
+	}
+	if oldRotation, newRotation := oldController.Spec.SlurmKeyRotation, newController.Spec.SlurmKeyRotation; oldRotation != nil && newRotation != nil &&
+		!apiequality.Semantic.DeepEqual(oldRotation.NewKeyRef, newRotation.NewKeyRef) {
+		errs = append(errs, errors.New("cannot change SlurmKeyRotation.NewKeyRef while the rotation is in progress"))
+	}
+	if oldController.Spec.SlurmKeyRotation != nil && newController.Spec.SlurmKeyRotation == nil &&
+		!isSlurmKeyRotationRetired(oldController, newController) {
+		cond := meta.FindStatusCondition(oldController.Status.Conditions, slinkyv1alpha1.ControllerConditionSlurmKeyRotation)
+		if cond != nil && (cond.Reason == slinkyv1alpha1.SlurmKeyRotationReasonSwitching || cond.Reason == slinkyv1alpha1.SlurmKeyRotationReasonSwitchingBack) {
+			warns = append(warns, fmt.Sprintf("removing SlurmKeyRotation while %s may cause authentication failures until all Slurm pods are rolled out", cond.Reason))
+		}
+	}
+	if !apiequality.Semantic.DeepEqual(newController.Spec.JwtHs256KeyRef.LocalObjectReference, oldController.Spec.JwtHs256KeyRef.LocalObjectReference) &&
+		!isJwtKeyRotationRetired(oldController, newController) {
+		errs = append(errs, errors.New("cannot change JwtHs256KeyRef after deployment, except to the new key of a JwtHs256KeyRotation which is ReadyToRetire"))
 	}
-	if !apiequality.Semantic.DeepEqual(newController.Spec.JwtHs256KeyRef.LocalObjectReference, oldController.Spec.JwtHs256KeyRef.LocalObjectReference) {
-		errs = append(errs, errors.New("cannot change JwtHs256KeyRef after deployment"))
+	if oldRotation, newRotation := oldController.Spec.JwtHs256KeyRotation, newController.Spec.JwtHs256KeyRotation; oldRotation != nil && newRotation != nil &&
+		!apiequality.Semantic.DeepEqual(oldRotation.NewKeyRef, newRotation.NewKeyRef) {
+		errs = append(errs, errors.New("cannot change JwtHs256KeyRotation.NewKeyRef while the rotation is in progress"))
 	}
 
+	for _, newVolume := range newController.Spec.SharedVolumes {
+		for _, oldVolume := range oldController.Spec.SharedVolumes {
+			if newVolume.Name != oldVolume.Name || newVolume.ClaimTemplate == nil || oldVolume.ClaimTemplate == nil {
+				continue
+			}
+			newSpec := newVolume.ClaimTemplate.DeepCopy()
+			newSpec.Resources.Requests = oldVolume.ClaimTemplate.Resources.Requests
+			if !apiequality.Semantic.DeepEqual(newSpec, oldVolume.ClaimTemplate) {
+				warns = append(warns, fmt.Sprintf("only the resource requests of the claimTemplate of shared volume %q are updated after deployment", newVolume.Name))
+			}
+		}
+	}
+
+	removedWarns, err := r.validateRemovedNamespaces(ctx, oldController, newController)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	warns = append(warns, removedWarns...)
+
 	// We use volumeClaimTemplates to handle the controller savestate PVC.
 	// StatefulSet does not allow update of that field.
 	if newController.Spec.Persistence.Enabled != oldController.Spec.Persistence.Enabled {
@@ -150,6 +201,20 @@ func (r *ControllerWebhook) validateController(ctx context.Context, obj *slinkyv
 		"topology.yaml",
 	}
 
+	errs = append(errs, validateJwtKeyRotation(obj.Spec.JwtHs256KeyRef, obj.Spec.JwtRs256KeyRef, obj.Spec.JwtHs256KeyRotation)...)
+	errs = append(errs, r.validateJwtKeyRotationAccounting(ctx, obj)...)
+	errs = append(errs, validateSlurmKeyRotation(obj.Spec.SlurmKeyRef, obj.Spec.SlurmKeyRotation)...)
+	errs = append(errs, validateJwtRs256KeyRef(obj.Spec.JwtHs256KeyRef, obj.Spec.JwtRs256KeyRef)...)
+	sharedVolumesWarns, sharedVolumesErrs := validateSharedVolumes(obj.Spec.SharedVolumes)
+	warns = append(warns, sharedVolumesWarns...)
+	errs = append(errs, sharedVolumesErrs...)
+	externalWarns, externalErrs := validateExternalController(obj)
+	warns = append(warns, externalWarns...)
+	errs = append(errs, externalErrs...)
+	allowedNamespacesWarns, allowedNamespacesErrs := validateAllowedNamespaces(obj)
+	warns = append(warns, allowedNamespacesWarns...)
+	errs = append(errs, allowedNamespacesErrs...)
+
 	refs := obj.Spec.ConfigFileRefs
 	for _, ref := range refs {
 		configMap := &corev1.ConfigMap{}
@@ -158,7 +223,12 @@ func (r *ControllerWebhook) validateController(ctx context.Context, obj *slinkyv
 			Namespace: obj.Namespace,
 		}
 		if err := r.Get(ctx, configMapKey, configMap); err != nil {
-			errs = append(errs, err)
+			if apierrors.IsNotFound(err) {
+				// It may be created after the Controller.
+				warns = append(warns, fmt.Sprintf("the configFileRef was not found, its config files are not checked: %s", configMapKey))
+			} else {
+				errs = append(errs, err)
+			}
 			continue
 		}
 		configFiles := structutils.Keys(configMap.Data)
@@ -171,6 +241,248 @@ func (r *ControllerWebhook) validateController(ctx context.Context, obj *slinkyv
 			}
 		}
 	}
+	// The external slurmctld has its own slurm.conf.
+	if len(errs) > 0 || obj.IsExternal() {
+		return warns, errs
+	}
+
+	// Dry-run the slurm.conf that would be given to slurmctld.
+	slurmConf, err := builder.New(r.Client).BuildSlurmConf(obj)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to render slurm.conf: %w", err))
+		return warns, errs
+	}
+	confWarns, confErrs := slurmconf.Lint(slurmConf)
+	for _, warn := range confWarns {
+		warns = append(warns, fmt.Sprintf("slurm.conf: %s", warn))
+	}
+	for _, err := range confErrs {
+		errs = append(errs, fmt.Errorf("slurm.conf: %w", err))
+	}
+
+	return warns, errs
+}
+
+// isJwtKeyRotationRetired returns true if the JwtHs256KeyRef is being changed to
+// the new key of a rotation which is ready to retire the current key.
+func isJwtKeyRotationRetired(oldController, newController *slinkyv1alpha1.Controller) bool {
+	rotation := oldController.Spec.JwtHs256KeyRotation
+	if rotation == nil || !apiequality.Semantic.DeepEqual(rotation.NewKeyRef, newController.Spec.JwtHs256KeyRef) {
+		return false
+	}
+	cond := meta.FindStatusCondition(oldController.Status.Conditions, slinkyv1alpha1.ControllerConditionJwtHs256KeyRotation)
+	return cond != nil && cond.Reason == slinkyv1alpha1.JwtKeyRotationReasonReadyToRetire
+}
+
+// validateJwtKeyRotation validates a JWT HS256 key rotation. The RS256 key is
+// required as Tokens are signed with it until Slurm verifies with the new key.
+func validateJwtKeyRotation(current corev1.SecretKeySelector, rs256 *corev1.SecretKeySelector, rotation *slinkyv1alpha1.JwtKeyRotation) []error {
+	var errs []error
+	if rotation == nil {
+		return errs
+	}
+	if rotation.NewKeyRef.Name == "" || rotation.NewKeyRef.Key == "" {
+		errs = append(errs, errors.New("JwtHs256KeyRotation.NewKeyRef must specify a name and key"))
+	}
+	if apiequality.Semantic.DeepEqual(rotation.NewKeyRef, current) {
+		errs = append(errs, errors.New("JwtHs256KeyRotation.NewKeyRef must differ from JwtHs256KeyRef"))
+	}
+	if rs256 == nil {
+		errs = append(errs, errors.New("JwtHs256KeyRotation requires JwtRs256KeyRef, which signs the Tokens until Slurm verifies with the new key"))
+	}
+	return errs
+}
+
+// validateJwtKeyRotationAccounting validates that slurmdbd accepts the Tokens
+// signed with the RS256 key of the Controller during a JWT HS256 key rotation.
+func (r *ControllerWebhook) validateJwtKeyRotationAccounting(ctx context.Context, obj *slinkyv1alpha1.Controller) []error {
+	var errs []error
+	if obj.Spec.JwtHs256KeyRotation == nil || obj.Spec.JwtRs256KeyRef == nil || obj.Spec.AccountingRef.Name == "" {
+		return errs
+	}
+	accounting := &slinkyv1alpha1.Accounting{}
+	if err := r.Get(ctx, obj.Spec.AccountingRef.NamespacedName(), accounting); err != nil {
+		if !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+		return errs
+	}
+	if accounting.AuthJwtHs256Key() != obj.AuthJwtHs256Key() {
+		return errs
+	}
+	if !apiequality.Semantic.DeepEqual(accounting.Spec.JwtRs256KeyRef, obj.Spec.JwtRs256KeyRef) {
+		errs = append(errs, fmt.Errorf("JwtHs256KeyRotation requires Accounting %s to use the same JwtRs256KeyRef, so slurmdbd accepts the Tokens signed with it", klog.KObj(accounting)))
+	}
+	return errs
+}
+
+// isSlurmKeyRotationRetired returns true if the SlurmKeyRef is being changed to
+// the new key of a rotation which is ready to retire the current key.
+func isSlurmKeyRotationRetired(oldController, newController *slinkyv1alpha1.Controller) bool {
+	rotation := oldController.Spec.SlurmKeyRotation
+	if rotation == nil || !apiequality.Semantic.DeepEqual(rotation.NewKeyRef, newController.Spec.SlurmKeyRef) {
+		return false
+	}
+	cond := meta.FindStatusCondition(oldController.Status.Conditions, slinkyv1alpha1.ControllerConditionSlurmKeyRotation)
+	return cond != nil && cond.Reason == slinkyv1alpha1.SlurmKeyRotationReasonReadyToRetire
+}
+
+func validateSlurmKeyRotation(current corev1.SecretKeySelector, rotation *slinkyv1alpha1.SlurmKeyRotation) []error {
+	var errs []error
+	if rotation == nil {
+		return errs
+	}
+	if rotation.NewKeyRef.Name == "" || rotation.NewKeyRef.Key == "" {
+		errs = append(errs, errors.New("SlurmKeyRotation.NewKeyRef must specify a name and key"))
+	}
+	if apiequality.Semantic.DeepEqual(rotation.NewKeyRef, current) {
+		errs = append(errs, errors.New("SlurmKeyRotation.NewKeyRef must differ from SlurmKeyRef"))
+	}
+	return errs
+}
+
+func validateSharedVolumes(volumes []slinkyv1alpha1.SharedVolume) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+	names := map[string]bool{}
+	mountPaths := map[string]bool{}
+	for _, volume := range volumes {
+		if names[volume.Name] {
+			errs = append(errs, fmt.Errorf("SharedVolumes has duplicate name: %s", volume.Name))
+		}
+		names[volume.Name] = true
+		if !path.IsAbs(volume.MountPath) {
+			errs = append(errs, fmt.Errorf("SharedVolumes[%s].MountPath must be an absolute path: %s", volume.Name, volume.MountPath))
+		}
+		mountPath := path.Clean(volume.MountPath)
+		if mountPaths[mountPath] {
+			errs = append(errs, fmt.Errorf("SharedVolumes has duplicate mountPath: %s", volume.MountPath))
+		}
+		mountPaths[mountPath] = true
+		if (volume.ExistingClaim == "") == (volume.ClaimTemplate == nil) {
+			errs = append(errs, fmt.Errorf("SharedVolumes[%s] must specify exactly one of existingClaim or claimTemplate", volume.Name))
+			continue
+		}
+		if volume.ClaimTemplate != nil && !slices.Contains(volume.ClaimTemplate.AccessModes, corev1.ReadWriteMany) &&
+			!(volume.ReadOnly && slices.Contains(volume.ClaimTemplate.AccessModes, corev1.ReadOnlyMany)) {
+			warns = append(warns, fmt.Sprintf("SharedVolumes[%s].ClaimTemplate may not be mountable by pods on different nodes without the ReadWriteMany access mode", volume.Name))
+		}
+	}
+	return warns, errs
+}
+
+func validateAllowedNamespaces(obj *slinkyv1alpha1.Controller) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+	for _, namespace := range obj.Spec.AllowedNamespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			errs = append(errs, fmt.Errorf("AllowedNamespaces has an invalid namespace %q: %s", namespace, msg))
+		}
+		if namespace == obj.Namespace {
+			warns = append(warns, fmt.Sprintf("AllowedNamespaces does not need the namespace of the Controller: %s", namespace))
+		}
+	}
+	if len(obj.Spec.AllowedNamespaces) > 0 && len(obj.Spec.SharedVolumes) > 0 {
+		warns = append(warns, "SharedVolumes are mounted, in the AllowedNamespaces, from the claims of the same name in each namespace, which are not created by the operator")
+	}
+	return warns, errs
+}
+
+// validateRemovedNamespaces warns about the NodeSets and LoginSets of the
+// Controller in namespaces which it no longer allows.
+func (r *ControllerWebhook) validateRemovedNamespaces(ctx context.Context, oldController, newController *slinkyv1alpha1.Controller) (admission.Warnings, error) {
+	var warns admission.Warnings
+	if r.Client == nil {
+		return warns, nil
+	}
+	removed := func(namespace string) bool {
+		return oldController.IsNamespaceAllowed(namespace) && !newController.IsNamespaceAllowed(namespace)
+	}
+	if !slices.ContainsFunc(oldController.Spec.AllowedNamespaces, removed) {
+		return warns, nil
+	}
+
+	controllerKey := client.ObjectKeyFromObject(newController)
+	nodesetList := &slinkyv1alpha1.NodeSetList{}
+	if err := r.List(ctx, nodesetList); err != nil {
+		return warns, err
+	}
+	for _, nodeset := range nodesetList.Items {
+		if removed(nodeset.Namespace) && nodeset.Spec.ControllerRef.IsMatch(controllerKey) {
+			warns = append(warns, fmt.Sprintf("NodeSet %s is no longer allowed to reference the Controller, its pods lose the Slurm key when restarted", klog.KObj(&nodeset)))
+		}
+	}
+	loginsetList := &slinkyv1alpha1.LoginSetList{}
+	if err := r.List(ctx, loginsetList); err != nil {
+		return warns, err
+	}
+	for _, loginset := range loginsetList.Items {
+		if removed(loginset.Namespace) && loginset.Spec.ControllerRef.IsMatch(controllerKey) {
+			warns = append(warns, fmt.Sprintf("LoginSet %s is no longer allowed to reference the Controller, its pods lose the Slurm key when restarted", klog.KObj(&loginset)))
+		}
+	}
+	return warns, nil
+}
+
+func validateJwtRs256KeyRef(hs256 corev1.SecretKeySelector, rs256 *corev1.SecretKeySelector) []error {
+	var errs []error
+	if rs256 == nil {
+		return errs
+	}
+	if rs256.Name == "" || rs256.Key == "" {
+		errs = append(errs, errors.New("JwtRs256KeyRef must specify a name and key"))
+	}
+	if rs256.Name == hs256.Name && rs256.Key == hs256.Key {
+		errs = append(errs, errors.New("JwtRs256KeyRef must differ from JwtHs256KeyRef"))
+	}
+	return errs
+}
+
+// validateExternalController validates a Controller whose slurmctld runs
+// outside of Kubernetes, which the operator neither configures nor restarts.
+func validateExternalController(obj *slinkyv1alpha1.Controller) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+	external := obj.Spec.External
+	if external == nil {
+		return warns, errs
+	}
+
+	if external.Host == "" {
+		errs = append(errs, errors.New("External.Host must be specified"))
+	}
+	if obj.Spec.InternalSlurmrestd != nil {
+		errs = append(errs, errors.New("InternalSlurmrestd cannot be used with External, use External.Slurmrestd instead"))
+	}
+	if obj.Spec.AccountingRef.Name != "" {
+		errs = append(errs, errors.New("AccountingRef cannot be used with External, accounting is configured by the external cluster"))
+	}
+	if obj.Spec.SlurmKeyRotation != nil || obj.Spec.JwtHs256KeyRotation != nil {
+		errs = append(errs, errors.New("SlurmKeyRotation and JwtHs256KeyRotation cannot be used with External, the keys are rotated by the external cluster"))
+	}
+	if obj.Spec.JwtRs256KeyRef != nil {
+		errs = append(errs, errors.New("JwtRs256KeyRef cannot be used with External"))
+	}
+
+	slurmrestd := external.Slurmrestd
+	if slurmrestd == nil {
+		warns = append(warns, "External.Slurmrestd is not specified, the Slurm nodes of NodeSets cannot be drained, so their pods are not deleted on scale-in")
+		return warns, errs
+	}
+	restdUrl, err := url.Parse(slurmrestd.URL)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("External.Slurmrestd.URL is not a valid URL: %w", err))
+	case restdUrl.Scheme != "https" && restdUrl.Scheme != "http":
+		errs = append(errs, fmt.Errorf("External.Slurmrestd.URL must use the https or http scheme: %s", slurmrestd.URL))
+	case restdUrl.Host == "":
+		errs = append(errs, fmt.Errorf("External.Slurmrestd.URL has no host: %s", slurmrestd.URL))
+	case restdUrl.Scheme == "http":
+		warns = append(warns, fmt.Sprintf("External.Slurmrestd.URL does not use https, the token can be read in transit: %s", slurmrestd.URL))
+	}
+	if ref := slurmrestd.TokenRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		errs = append(errs, errors.New("External.Slurmrestd.TokenRef must specify a name and key"))
+	}
 
 	return warns, errs
 }
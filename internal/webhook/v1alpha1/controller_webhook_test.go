@@ -5,6 +5,16 @@ package v1alpha1
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
 )
 
 var _ = Describe("Controller Webhook", func() {
@@ -12,6 +22,16 @@ var _ = Describe("Controller Webhook", func() {
 		It("Should fill in the default value if a required field is empty", func() {
 			// TODO(user): Add your logic here
 		})
+
+		It("Should default the port of an external slurmctld", func(ctx SpecContext) {
+			controller := &slinkyv1alpha1.Controller{
+				Spec: slinkyv1alpha1.ControllerSpec{
+					External: &slinkyv1alpha1.ExternalController{Host: "slurmctld.example.com"},
+				},
+			}
+			Expect((&ControllerWebhook{}).Default(ctx, controller)).To(Succeed())
+			Expect(controller.Spec.External.Port).To(Equal(int32(builder.SlurmctldPort)))
+		})
 	})
 
 	Context("When creating Controller under Validating Webhook", func() {
@@ -22,5 +42,230 @@ var _ = Describe("Controller Webhook", func() {
 		It("Should admit if all required fields are provided", func() {
 			// TODO(user): Add your logic here
 		})
+
+		It("Should warn about NodeSets and ConfigMaps which are not created yet", func(ctx SpecContext) {
+			s := runtime.NewScheme()
+			utilruntime.Must(clientgoscheme.AddToScheme(s))
+			utilruntime.Must(slinkyv1alpha1.AddToScheme(s))
+			r := &ControllerWebhook{
+				Client: fake.NewClientBuilder().WithScheme(s).Build(),
+			}
+			controller := &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "slurm",
+					Namespace: "slurm",
+				},
+				Spec: slinkyv1alpha1.ControllerSpec{
+					ExtraConf: "PartitionName=all Nodes=slinky Default=YES",
+					ConfigFileRefs: []slinkyv1alpha1.ObjectReference{
+						{Name: "config"},
+					},
+				},
+			}
+			warns, errs := r.validateController(ctx, controller)
+			Expect(errs).To(BeEmpty())
+			Expect(warns).To(HaveLen(2))
+		})
+
+		Context("With an external slurmctld", func() {
+			var controller *slinkyv1alpha1.Controller
+
+			BeforeEach(func() {
+				controller = &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.ControllerSpec{
+						External: &slinkyv1alpha1.ExternalController{
+							Host: "slurmctld.example.com",
+							Port: 6817,
+							Slurmrestd: &slinkyv1alpha1.ExternalSlurmrestd{
+								URL: "https://slurmrestd.example.com:6820",
+							},
+						},
+					},
+				}
+			})
+
+			It("Should admit an external slurmctld", func() {
+				warns, errs := validateExternalController(controller)
+				Expect(errs).To(BeEmpty())
+				Expect(warns).To(BeEmpty())
+			})
+
+			It("Should warn without an external slurmrestd", func() {
+				controller.Spec.External.Slurmrestd = nil
+				warns, errs := validateExternalController(controller)
+				Expect(errs).To(BeEmpty())
+				Expect(warns).To(HaveLen(1))
+			})
+
+			It("Should deny an invalid slurmrestd URL", func() {
+				controller.Spec.External.Slurmrestd.URL = "ftp://slurmrestd.example.com"
+				_, errs := validateExternalController(controller)
+				Expect(errs).To(HaveLen(1))
+			})
+
+			It("Should deny an internal slurmrestd and accounting", func() {
+				controller.Spec.InternalSlurmrestd = &slinkyv1alpha1.ContainerMinimal{}
+				controller.Spec.AccountingRef = slinkyv1alpha1.ObjectReference{Name: "slurm"}
+				_, errs := validateExternalController(controller)
+				Expect(errs).To(HaveLen(2))
+			})
+		})
+
+		Context("With allowed namespaces", func() {
+			var controller *slinkyv1alpha1.Controller
+
+			BeforeEach(func() {
+				controller = &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.ControllerSpec{
+						AllowedNamespaces: []string{"tenant"},
+					},
+				}
+			})
+
+			It("Should admit allowed namespaces", func() {
+				warns, errs := validateAllowedNamespaces(controller)
+				Expect(errs).To(BeEmpty())
+				Expect(warns).To(BeEmpty())
+			})
+
+			It("Should deny an invalid namespace", func() {
+				controller.Spec.AllowedNamespaces = append(controller.Spec.AllowedNamespaces, "Tenant_B")
+				_, errs := validateAllowedNamespaces(controller)
+				Expect(errs).NotTo(BeEmpty())
+			})
+
+			It("Should warn about the namespace of the Controller", func() {
+				controller.Spec.AllowedNamespaces = append(controller.Spec.AllowedNamespaces, "slurm")
+				warns, errs := validateAllowedNamespaces(controller)
+				Expect(errs).To(BeEmpty())
+				Expect(warns).To(HaveLen(1))
+			})
+
+			It("Should warn about shared volumes", func() {
+				controller.Spec.SharedVolumes = []slinkyv1alpha1.SharedVolume{{Name: "home"}}
+				warns, errs := validateAllowedNamespaces(controller)
+				Expect(errs).To(BeEmpty())
+				Expect(warns).To(HaveLen(1))
+			})
+
+			It("Should warn about NodeSets and LoginSets in removed namespaces", func(ctx SpecContext) {
+				s := runtime.NewScheme()
+				utilruntime.Must(slinkyv1alpha1.AddToScheme(s))
+				nodeset := &slinkyv1alpha1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "tenant",
+					},
+					Spec: slinkyv1alpha1.NodeSetSpec{
+						ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm", Namespace: "slurm"},
+					},
+				}
+				loginset := &slinkyv1alpha1.LoginSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "login",
+						Namespace: "tenant",
+					},
+					Spec: slinkyv1alpha1.LoginSetSpec{
+						ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm", Namespace: "slurm"},
+					},
+				}
+				r := &ControllerWebhook{
+					Client: fake.NewClientBuilder().WithScheme(s).WithObjects(nodeset, loginset).Build(),
+				}
+
+				warns, err := r.validateRemovedNamespaces(ctx, controller, controller)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(warns).To(BeEmpty())
+
+				newController := controller.DeepCopy()
+				newController.Spec.AllowedNamespaces = nil
+				warns, err = r.validateRemovedNamespaces(ctx, controller, newController)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(warns).To(HaveLen(2))
+			})
+		})
+
+		Context("With a JWT HS256 key rotation", func() {
+			var controller *slinkyv1alpha1.Controller
+			var accounting *slinkyv1alpha1.Accounting
+			var r *ControllerWebhook
+
+			BeforeEach(func() {
+				jwtHs256KeyRef := corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "jwt-old"},
+					Key:                  "jwt_hs256.key",
+				}
+				jwtRs256KeyRef := &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "jwt-rs256"},
+					Key:                  "jwt_rs256.key",
+				}
+				controller = &slinkyv1alpha1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.ControllerSpec{
+						JwtHs256KeyRef: jwtHs256KeyRef,
+						JwtRs256KeyRef: jwtRs256KeyRef,
+						JwtHs256KeyRotation: &slinkyv1alpha1.JwtKeyRotation{
+							NewKeyRef: corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "jwt-new"},
+								Key:                  "jwt_hs256.key",
+							},
+						},
+						AccountingRef: slinkyv1alpha1.ObjectReference{Name: "accounting", Namespace: "slurm"},
+					},
+				}
+				accounting = &slinkyv1alpha1.Accounting{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "accounting",
+						Namespace: "slurm",
+					},
+					Spec: slinkyv1alpha1.AccountingSpec{
+						JwtHs256KeyRef: jwtHs256KeyRef,
+						JwtRs256KeyRef: jwtRs256KeyRef,
+					},
+				}
+				s := runtime.NewScheme()
+				utilruntime.Must(slinkyv1alpha1.AddToScheme(s))
+				r = &ControllerWebhook{
+					Client: fake.NewClientBuilder().WithScheme(s).WithObjects(accounting).Build(),
+				}
+			})
+
+			It("Should admit a rotation with an RS256 key", func(ctx SpecContext) {
+				Expect(validateJwtKeyRotation(controller.Spec.JwtHs256KeyRef, controller.Spec.JwtRs256KeyRef, controller.Spec.JwtHs256KeyRotation)).To(BeEmpty())
+				Expect(r.validateJwtKeyRotationAccounting(ctx, controller)).To(BeEmpty())
+			})
+
+			It("Should deny a rotation without an RS256 key", func() {
+				Expect(validateJwtKeyRotation(controller.Spec.JwtHs256KeyRef, nil, controller.Spec.JwtHs256KeyRotation)).To(HaveLen(1))
+			})
+
+			It("Should deny an Accounting with another RS256 key", func(ctx SpecContext) {
+				controller.Spec.JwtRs256KeyRef = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "jwt-other"},
+					Key:                  "jwt_rs256.key",
+				}
+				Expect(r.validateJwtKeyRotationAccounting(ctx, controller)).To(HaveLen(1))
+			})
+
+			It("Should admit an Accounting with another HS256 key", func(ctx SpecContext) {
+				controller.Spec.JwtHs256KeyRef.Name = "jwt-controller"
+				controller.Spec.JwtRs256KeyRef = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "jwt-other"},
+					Key:                  "jwt_rs256.key",
+				}
+				Expect(r.validateJwtKeyRotationAccounting(ctx, controller)).To(BeEmpty())
+			})
+		})
 	})
 })
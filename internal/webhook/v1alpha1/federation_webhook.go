@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+type FederationWebhook struct {
+	client.Client
+}
+
+// log is for logging in this package.
+var federationlog = logf.Log.WithName("federation-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *FederationWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&slinkyv1alpha1.Federation{}).
+		WithDefaulter(r).
+		WithValidator(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-slinky-slurm-net-v1alpha1-federation,mutating=true,failurePolicy=fail,sideEffects=None,groups=slinky.slurm.net,resources=federations,verbs=create;update,versions=v1alpha1,name=mfederation.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomDefaulter = &FederationWebhook{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *FederationWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	federation := obj.(*slinkyv1alpha1.Federation)
+	federationlog.Info("default", "federation", klog.KObj(federation))
+
+	// References default to the namespace of the Federation.
+	if federation.Spec.AccountingRef.Namespace == "" {
+		federation.Spec.AccountingRef.Namespace = federation.Namespace
+	}
+	for i := range federation.Spec.Clusters {
+		if federation.Spec.Clusters[i].ControllerRef.Namespace == "" {
+			federation.Spec.Clusters[i].ControllerRef.Namespace = federation.Namespace
+		}
+	}
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-slinky-slurm-net-v1alpha1-federation,mutating=false,failurePolicy=fail,sideEffects=None,groups=slinky.slurm.net,resources=federations,verbs=create;update,versions=v1alpha1,name=vfederation.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &FederationWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *FederationWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	federation := obj.(*slinkyv1alpha1.Federation)
+	federationlog.Info("validate create", "federation", klog.KObj(federation))
+
+	warns, errs := r.validateFederation(ctx, federation)
+
+	return warns, utilerrors.NewAggregate(errs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *FederationWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	newFederation := newObj.(*slinkyv1alpha1.Federation)
+	oldFederation := oldObj.(*slinkyv1alpha1.Federation)
+	federationlog.Info("validate update", "newFederation", klog.KObj(newFederation))
+
+	warns, errs := r.validateFederation(ctx, newFederation)
+
+	if !newFederation.Spec.AccountingRef.IsMatch(oldFederation.Spec.AccountingRef.NamespacedName()) {
+		errs = append(errs, errors.New("cannot change AccountingRef after deployment"))
+	}
+
+	return warns, utilerrors.NewAggregate(errs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *FederationWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	federation := obj.(*slinkyv1alpha1.Federation)
+	federationlog.Info("validate delete", "federation", klog.KObj(federation))
+
+	// The federation is removed from slurmdbd by a Job of a member cluster.
+	if r.Client == nil || r.hasAccountingMember(ctx, federation) {
+		return nil, nil
+	}
+	return admission.Warnings{
+		fmt.Sprintf("federation %s cannot be removed from slurmdbd, without its Accounting or a member Controller, remove it with `sacctmgr remove federation %s`",
+			federation.FederationName(), federation.FederationName()),
+	}, nil
+}
+
+// hasAccountingMember returns true if the Accounting and a member Controller of
+// the Federation exist, or they could not be read.
+func (r *FederationWebhook) hasAccountingMember(ctx context.Context, federation *slinkyv1alpha1.Federation) bool {
+	accounting := &slinkyv1alpha1.Accounting{}
+	if err := r.Get(ctx, federation.Spec.AccountingRef.NamespacedName(), accounting); err != nil {
+		return !apierrors.IsNotFound(err)
+	}
+	for _, cluster := range federation.Spec.Clusters {
+		controller := &slinkyv1alpha1.Controller{}
+		if err := r.Get(ctx, cluster.ControllerRef.NamespacedName(), controller); err == nil || !apierrors.IsNotFound(err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FederationWebhook) validateFederation(ctx context.Context, obj *slinkyv1alpha1.Federation) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	if obj.Spec.AccountingRef.Name == "" {
+		errs = append(errs, errors.New("AccountingRef must be specified"))
+	}
+	if obj.Spec.AccountingRef.Namespace != obj.Namespace {
+		errs = append(errs, fmt.Errorf("AccountingRef must be in the namespace of the Federation (%s)", obj.Namespace))
+	}
+
+	list := &slinkyv1alpha1.FederationList{}
+	if r.Client != nil {
+		if err := r.List(ctx, list, client.InNamespace(obj.Namespace)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	controllerKeys := set.New[string]()
+	for _, cluster := range obj.Spec.Clusters {
+		key := cluster.ControllerRef.NamespacedName()
+		if cluster.ControllerRef.Name == "" {
+			errs = append(errs, errors.New("ControllerRef of a cluster must be specified"))
+			continue
+		}
+		if key.Namespace != obj.Namespace {
+			errs = append(errs, fmt.Errorf("Controller %s must be in the namespace of the Federation (%s)", key, obj.Namespace))
+		}
+		if controllerKeys.Has(key.String()) {
+			errs = append(errs, fmt.Errorf("Controller %s is specified more than once", key))
+		}
+		controllerKeys.Insert(key.String())
+
+		for _, feature := range cluster.Features {
+			if feature == "" || strings.ContainsAny(feature, ", \t\n") {
+				errs = append(errs, fmt.Errorf("feature %q of Controller %s must not be empty, nor contain commas or whitespace", feature, key))
+			}
+		}
+
+		// Ref: https://slurm.schedmd.com/federation.html
+		// A cluster can only be a member of one federation.
+		for _, other := range list.Items {
+			if other.Name != obj.Name && other.HasController(key) {
+				errs = append(errs, fmt.Errorf("Controller %s is already a member of Federation %s", key, klog.KObj(&other)))
+			}
+		}
+	}
+
+	return warns, errs
+}
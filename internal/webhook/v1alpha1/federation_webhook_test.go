@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Federation Webhook", func() {
+	var federation *slinkyv1alpha1.Federation
+	var r *FederationWebhook
+
+	BeforeEach(func() {
+		r = &FederationWebhook{}
+		federation = &slinkyv1alpha1.Federation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fed",
+				Namespace: "slurm",
+			},
+			Spec: slinkyv1alpha1.FederationSpec{
+				AccountingRef: slinkyv1alpha1.ObjectReference{Name: "slurm"},
+				Clusters: []slinkyv1alpha1.FederationCluster{
+					{ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm1"}},
+					{ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm2"}, Features: []string{"gpu"}},
+				},
+			},
+		}
+	})
+
+	Context("When creating Federation under Defaulting Webhook", func() {
+		It("Should default the namespace of references", func(ctx SpecContext) {
+			Expect(r.Default(ctx, federation)).To(Succeed())
+			Expect(federation.Spec.AccountingRef.Namespace).To(Equal("slurm"))
+			Expect(federation.Spec.Clusters[0].ControllerRef.Namespace).To(Equal("slurm"))
+			Expect(federation.Spec.Clusters[1].ControllerRef.Namespace).To(Equal("slurm"))
+		})
+	})
+
+	Context("When creating Federation under Validating Webhook", func() {
+		It("Should deny a cluster specified more than once", func(ctx SpecContext) {
+			federation.Spec.Clusters = append(federation.Spec.Clusters, federation.Spec.Clusters[0])
+			Expect(r.Default(ctx, federation)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, federation)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should deny a cluster in another namespace", func(ctx SpecContext) {
+			federation.Spec.Clusters[0].ControllerRef.Namespace = "other"
+			Expect(r.Default(ctx, federation)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, federation)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should deny a feature with a comma", func(ctx SpecContext) {
+			federation.Spec.Clusters[1].Features = []string{"gpu,fast"}
+			Expect(r.Default(ctx, federation)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, federation)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should admit if all required fields are provided", func(ctx SpecContext) {
+			Expect(r.Default(ctx, federation)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, federation)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When deleting Federation under Validating Webhook", func() {
+		var s *runtime.Scheme
+
+		BeforeEach(func(ctx SpecContext) {
+			s = runtime.NewScheme()
+			utilruntime.Must(slinkyv1alpha1.AddToScheme(s))
+			Expect(r.Default(ctx, federation)).To(Succeed())
+		})
+
+		It("Should not warn when the federation can be removed from slurmdbd", func(ctx SpecContext) {
+			accounting := &slinkyv1alpha1.Accounting{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "slurm", Name: "slurm"},
+			}
+			controller := &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "slurm", Name: "slurm2"},
+			}
+			r.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(accounting, controller).Build()
+			warns, err := r.ValidateDelete(ctx, federation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).To(BeEmpty())
+		})
+
+		It("Should warn when no member Controller exists", func(ctx SpecContext) {
+			accounting := &slinkyv1alpha1.Accounting{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "slurm", Name: "slurm"},
+			}
+			r.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(accounting).Build()
+			warns, err := r.ValidateDelete(ctx, federation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).To(HaveLen(1))
+		})
+
+		It("Should warn when the Accounting does not exist", func(ctx SpecContext) {
+			controller := &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "slurm", Name: "slurm1"},
+			}
+			r.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(controller).Build()
+			warns, err := r.ValidateDelete(ctx, federation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).To(HaveLen(1))
+		})
+	})
+})
@@ -5,21 +5,30 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
 )
 
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-type LoginSetWebhook struct{}
+type LoginSetWebhook struct {
+	client.Client
+}
 
 // log is for logging in this package.
 var loginsetlog = logf.Log.WithName("loginset-resource")
@@ -42,6 +51,11 @@ func (r *LoginSetWebhook) Default(ctx context.Context, obj runtime.Object) error
 	loginset := obj.(*slinkyv1alpha1.LoginSet)
 	loginsetlog.Info("default", "loginset", klog.KObj(loginset))
 
+	// The Controller defaults to the namespace of the LoginSet.
+	if loginset.Spec.ControllerRef.Namespace == "" {
+		loginset.Spec.ControllerRef.Namespace = loginset.Namespace
+	}
+
 	return nil
 }
 
@@ -57,7 +71,7 @@ func (r *LoginSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object
 	loginset := obj.(*slinkyv1alpha1.LoginSet)
 	loginsetlog.Info("validate create", "loginset", klog.KObj(loginset))
 
-	warns, errs := validateLoginSet(loginset)
+	warns, errs := r.validateLoginSet(ctx, loginset)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -68,7 +82,7 @@ func (r *LoginSetWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Obj
 	_ = oldObj.(*slinkyv1alpha1.LoginSet)
 	loginsetlog.Info("validate update", "newLoginset", klog.KObj(newLoginset))
 
-	warns, errs := validateLoginSet(newLoginset)
+	warns, errs := r.validateLoginSet(ctx, newLoginset)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -81,9 +95,82 @@ func (r *LoginSetWebhook) ValidateDelete(ctx context.Context, obj runtime.Object
 	return nil, nil
 }
 
-func validateLoginSet(obj *slinkyv1alpha1.LoginSet) (admission.Warnings, []error) {
+func (r *LoginSetWebhook) validateLoginSet(ctx context.Context, obj *slinkyv1alpha1.LoginSet) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
 
+	refWarns, refErrs := validateControllerNamespace(ctx, r.Client, obj.Spec.ControllerRef, obj.Namespace)
+	warns = append(warns, refWarns...)
+	errs = append(errs, refErrs...)
+
+	claimWarns, claimErrs := validateSharedVolumeClaims(ctx, r.Client, obj.Spec.ControllerRef, obj.Namespace, slinkyv1alpha1.SharedVolumeComponentLogin)
+	warns = append(warns, claimWarns...)
+	errs = append(errs, claimErrs...)
+
+	if !obj.HasSssd() && len(obj.Spec.Users) == 0 {
+		warns = append(warns, "neither SssdConfRef nor Users are set, only root can log in")
+	}
+	errs = append(errs, validateLoginSetUsers(obj.Spec.Users)...)
+
+	if ca := obj.Spec.SshCertificateAuthority; ca != nil {
+		if ref := ca.UserCaPublicKeyRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+			errs = append(errs, errors.New("SshCertificateAuthority.UserCaPublicKeyRef must specify a name and key"))
+		}
+		if ref := ca.HostCaKeyRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+			errs = append(errs, errors.New("SshCertificateAuthority.HostCaKeyRef must specify a name and key"))
+		}
+		if ca.HostCaKeyRef != nil && !ca.SignHostKeys {
+			warns = append(warns, "SshCertificateAuthority.HostCaKeyRef is ignored unless SignHostKeys is set")
+		}
+	}
+
+	if autoscaling := obj.Spec.Autoscaling; autoscaling != nil {
+		if autoscaling.MinReplicasOrDefault() > autoscaling.MaxReplicas {
+			errs = append(errs, fmt.Errorf("Autoscaling.MinReplicas (%d) must not be greater than Autoscaling.MaxReplicas (%d)",
+				autoscaling.MinReplicasOrDefault(), autoscaling.MaxReplicas))
+		}
+		if autoscaling.ScaleDownDelay != nil && autoscaling.ScaleDownDelay.Duration < 0 {
+			errs = append(errs, errors.New("Autoscaling.ScaleDownDelay must not be negative"))
+		}
+		if autoscaling.TargetSessionsPerPod == nil && autoscaling.TargetCPUUtilizationPercentage == nil {
+			warns = append(warns, "Autoscaling has no target, the replicas are only kept within MinReplicas and MaxReplicas")
+		}
+	}
+
+	if drain := obj.Spec.UpdateStrategy.SessionDrain; drain != nil {
+		if drain.DrainTimeout != nil && drain.DrainTimeout.Duration <= 0 {
+			errs = append(errs, errors.New("UpdateStrategy.SessionDrain.DrainTimeout must be positive"))
+		}
+		if !obj.HasSessionDrain() {
+			warns = append(warns, "UpdateStrategy.SessionDrain is ignored unless the UpdateStrategy.Type is SessionDrain")
+		}
+	}
+
 	return warns, errs
 }
+
+// userNameRegex matches the portable usernames of useradd(8).
+var userNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
+func validateLoginSetUsers(users []slinkyv1alpha1.LoginSetUser) []error {
+	var errs []error
+
+	for _, user := range users {
+		if !userNameRegex.MatchString(user.Name) {
+			errs = append(errs, fmt.Errorf("Users: name must match regex `%s`: %s", userNameRegex.String(), user.Name))
+		}
+		if user.Group != "" && !userNameRegex.MatchString(user.Group) {
+			errs = append(errs, fmt.Errorf("Users: group must match regex `%s`: %s", userNameRegex.String(), user.Group))
+		}
+		for field, path := range map[string]string{"home": user.Home, "shell": user.Shell} {
+			if path != "" && (!filepath.IsAbs(path) || strings.ContainsAny(path, ":\n")) {
+				errs = append(errs, fmt.Errorf("Users: %s must be an absolute path: %s", field, path))
+			}
+		}
+	}
+	if _, err := builder.MergeUsers(users); err != nil {
+		errs = append(errs, fmt.Errorf("Users: %w", err))
+	}
+
+	return errs
+}
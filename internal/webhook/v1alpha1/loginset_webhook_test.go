@@ -5,22 +5,80 @@ package v1alpha1
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 )
 
 var _ = Describe("LoginSet Webhook", func() {
+	var loginset *slinkyv1alpha1.LoginSet
+	var r *LoginSetWebhook
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		utilruntime.Must(slinkyv1alpha1.AddToScheme(s))
+		controller := &slinkyv1alpha1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "slurm",
+				Namespace: "slurm",
+			},
+			Spec: slinkyv1alpha1.ControllerSpec{
+				AllowedNamespaces: []string{"tenant"},
+			},
+		}
+		r = &LoginSetWebhook{
+			Client: fake.NewClientBuilder().WithScheme(s).WithObjects(controller).Build(),
+		}
+		loginset = &slinkyv1alpha1.LoginSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "login",
+				Namespace: "slurm",
+			},
+			Spec: slinkyv1alpha1.LoginSetSpec{
+				ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm"},
+			},
+		}
+	})
+
 	Context("When creating LoginSet under Defaulting Webhook", func() {
-		It("Should fill in the default value if a required field is empty", func() {
-			// TODO(user): Add your logic here
+		It("Should fill in the default value if a required field is empty", func(ctx SpecContext) {
+			Expect(r.Default(ctx, loginset)).To(Succeed())
+			Expect(loginset.Spec.ControllerRef.Namespace).To(Equal("slurm"))
 		})
 	})
 
 	Context("When creating LoginSet under Validating Webhook", func() {
-		It("Should deny if a required field is empty", func() {
-			// TODO(user): Add your logic here
+		It("Should deny if a required field is empty", func(ctx SpecContext) {
+			loginset.Spec.ControllerRef.Name = ""
+			Expect(r.Default(ctx, loginset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, loginset)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should admit if all required fields are provided", func(ctx SpecContext) {
+			Expect(r.Default(ctx, loginset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, loginset)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit a Controller which allows the namespace", func(ctx SpecContext) {
+			loginset.Namespace = "tenant"
+			loginset.Spec.ControllerRef.Namespace = "slurm"
+			Expect(r.Default(ctx, loginset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, loginset)
+			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("Should admit if all required fields are provided", func() {
-			// TODO(user): Add your logic here
+		It("Should deny a Controller which does not allow the namespace", func(ctx SpecContext) {
+			loginset.Namespace = "other"
+			loginset.Spec.ControllerRef.Namespace = "slurm"
+			Expect(r.Default(ctx, loginset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, loginset)
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
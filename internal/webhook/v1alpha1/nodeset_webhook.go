@@ -5,13 +5,17 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -21,7 +25,9 @@ import (
 
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-type NodeSetWebhook struct{}
+type NodeSetWebhook struct {
+	client.Client
+}
 
 // log is for logging in this package.
 var nodesetlog = logf.Log.WithName("nodeset-resource")
@@ -43,6 +49,10 @@ func (r *NodeSetWebhook) Default(ctx context.Context, obj runtime.Object) error
 	nodeset := obj.(*slinkyv1alpha1.NodeSet)
 	nodesetlog.Info("default", "nodeset", klog.KObj(nodeset))
 
+	// The Controller defaults to the namespace of the NodeSet.
+	if nodeset.Spec.ControllerRef.Namespace == "" {
+		nodeset.Spec.ControllerRef.Namespace = nodeset.Namespace
+	}
 	if nodeset.Spec.RevisionHistoryLimit == nil {
 		nodeset.Spec.RevisionHistoryLimit = ptr.To[int32](0)
 	}
@@ -63,7 +73,7 @@ func (r *NodeSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object)
 	nodeset := obj.(*slinkyv1alpha1.NodeSet)
 	nodesetlog.Info("validate create", "nodeset", klog.KObj(nodeset))
 
-	warns, errs := validateNodeSet(nodeset)
+	warns, errs := r.validateNodeSet(ctx, nodeset)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -74,7 +84,7 @@ func (r *NodeSetWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Obje
 	_ = oldObj.(*slinkyv1alpha1.NodeSet)
 	nodesetlog.Info("validate update", "newNodeSet", klog.KObj(newNodeSet))
 
-	warns, errs := validateNodeSet(newNodeSet)
+	warns, errs := r.validateNodeSet(ctx, newNodeSet)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -87,10 +97,22 @@ func (r *NodeSetWebhook) ValidateDelete(ctx context.Context, obj runtime.Object)
 	return nil, nil
 }
 
-func validateNodeSet(obj *slinkyv1alpha1.NodeSet) (admission.Warnings, []error) {
+func (r *NodeSetWebhook) validateNodeSet(ctx context.Context, obj *slinkyv1alpha1.NodeSet) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
 
+	refWarns, refErrs := validateControllerNamespace(ctx, r.Client, obj.Spec.ControllerRef, obj.Namespace)
+	warns = append(warns, refWarns...)
+	errs = append(errs, refErrs...)
+
+	claimWarns, claimErrs := validateSharedVolumeClaims(ctx, r.Client, obj.Spec.ControllerRef, obj.Namespace, slinkyv1alpha1.SharedVolumeComponentWorker)
+	warns = append(warns, claimWarns...)
+	errs = append(errs, claimErrs...)
+
+	registrationWarns, registrationErrs := validateNodeRegistration(ctx, r.Client, obj)
+	warns = append(warns, registrationWarns...)
+	errs = append(errs, registrationErrs...)
+
 	switch obj.Spec.UpdateStrategy.Type {
 	case slinkyv1alpha1.RollingUpdateNodeSetStrategyType:
 		// valid
@@ -124,3 +146,100 @@ func validateNodeSet(obj *slinkyv1alpha1.NodeSet) (admission.Warnings, []error)
 
 	return warns, errs
 }
+
+// validateControllerNamespace validates that the Controller of the reference,
+// of a NodeSet or LoginSet in the namespace, allows the namespace.
+func validateControllerNamespace(ctx context.Context, c client.Client, ref slinkyv1alpha1.ObjectReference, namespace string) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	if ref.Name == "" {
+		errs = append(errs, errors.New("ControllerRef must be specified"))
+		return warns, errs
+	}
+	if ref.Namespace == namespace || c == nil {
+		return warns, errs
+	}
+
+	controller := &slinkyv1alpha1.Controller{}
+	if err := c.Get(ctx, ref.NamespacedName(), controller); err != nil {
+		if !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		} else {
+			warns = append(warns, fmt.Sprintf("Controller %s does not exist yet, it must allow namespace %q in AllowedNamespaces", ref.NamespacedName(), namespace))
+		}
+		return warns, errs
+	}
+	if !controller.IsNamespaceAllowed(namespace) {
+		errs = append(errs, fmt.Errorf("Controller %s does not allow namespace %q, add it to its AllowedNamespaces", klog.KObj(controller), namespace))
+	}
+
+	return warns, errs
+}
+
+// validateNodeRegistration validates that a NodeSet with static node
+// registration does not use an external Controller, whose `slurm.conf` has no
+// `NodeName` lines for its pods.
+func validateNodeRegistration(ctx context.Context, c client.Client, obj *slinkyv1alpha1.NodeSet) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	if obj.Spec.NodeRegistration != slinkyv1alpha1.StaticNodeSetNodeRegistrationType ||
+		obj.Spec.ControllerRef.Name == "" || c == nil {
+		return warns, errs
+	}
+
+	controller := &slinkyv1alpha1.Controller{}
+	if err := c.Get(ctx, obj.Spec.ControllerRef.NamespacedName(), controller); err != nil {
+		if !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		} else {
+			warns = append(warns, fmt.Sprintf("Controller %s does not exist yet, NodeRegistration %s cannot be used if it is External",
+				obj.Spec.ControllerRef.NamespacedName(), slinkyv1alpha1.StaticNodeSetNodeRegistrationType))
+		}
+		return warns, errs
+	}
+	if controller.IsExternal() {
+		errs = append(errs, fmt.Errorf("NodeRegistration %s cannot be used with External Controller %s, use %s",
+			slinkyv1alpha1.StaticNodeSetNodeRegistrationType, klog.KObj(controller), slinkyv1alpha1.DynamicNodeSetNodeRegistrationType))
+	}
+
+	return warns, errs
+}
+
+// validateSharedVolumeClaims warns about the existing claims of the shared
+// volumes of the component, which do not exist in the namespace of a NodeSet or
+// LoginSet in another namespace than its Controller. The pods cannot start
+// until they exist. The claims of claim templates are created by the operator.
+func validateSharedVolumeClaims(ctx context.Context, c client.Client, ref slinkyv1alpha1.ObjectReference, namespace string, component slinkyv1alpha1.SharedVolumeComponent) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	if ref.Name == "" || ref.Namespace == namespace || c == nil {
+		return warns, errs
+	}
+
+	controller := &slinkyv1alpha1.Controller{}
+	if err := c.Get(ctx, ref.NamespacedName(), controller); err != nil {
+		if !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+		return warns, errs
+	}
+	for _, volume := range controller.SharedVolumesFor(component) {
+		if volume.ExistingClaim == "" {
+			continue
+		}
+		key := controller.SharedVolumeClaimKey(&volume, namespace)
+		claim := &corev1.PersistentVolumeClaim{}
+		if err := c.Get(ctx, key, claim); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			} else {
+				warns = append(warns, fmt.Sprintf("PersistentVolumeClaim %s of SharedVolumes[%s] does not exist yet, pods cannot start until it is created", key, volume.Name))
+			}
+		}
+	}
+
+	return warns, errs
+}
@@ -5,22 +5,155 @@ package v1alpha1
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
 )
 
 var _ = Describe("NodeSet Webhook", func() {
+	var nodeset *slinkyv1alpha1.NodeSet
+	var r *NodeSetWebhook
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(s))
+		utilruntime.Must(slinkyv1alpha1.AddToScheme(s))
+		controller := &slinkyv1alpha1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "slurm",
+				Namespace: "slurm",
+			},
+			Spec: slinkyv1alpha1.ControllerSpec{
+				AllowedNamespaces: []string{"tenant"},
+				SharedVolumes: []slinkyv1alpha1.SharedVolume{
+					{Name: "home", MountPath: "/home", ExistingClaim: "home"},
+				},
+			},
+		}
+		claim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "home",
+				Namespace: "slurm",
+			},
+		}
+		r = &NodeSetWebhook{
+			Client: fake.NewClientBuilder().WithScheme(s).WithObjects(controller, claim).Build(),
+		}
+		nodeset = &slinkyv1alpha1.NodeSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "slurm",
+			},
+			Spec: slinkyv1alpha1.NodeSetSpec{
+				ControllerRef: slinkyv1alpha1.ObjectReference{Name: "slurm"},
+			},
+		}
+	})
+
 	Context("When creating NodeSet under Defaulting Webhook", func() {
-		It("Should fill in the default value if a required field is empty", func() {
-			// TODO(user): Add your logic here
+		It("Should fill in the default value if a required field is empty", func(ctx SpecContext) {
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			Expect(nodeset.Spec.ControllerRef.Namespace).To(Equal("slurm"))
+			Expect(nodeset.Spec.UpdateStrategy.Type).To(Equal(slinkyv1alpha1.RollingUpdateNodeSetStrategyType))
 		})
 	})
 
 	Context("When creating NodeSet under Validating Webhook", func() {
-		It("Should deny if a required field is empty", func() {
-			// TODO(user): Add your logic here
+		It("Should deny if a required field is empty", func(ctx SpecContext) {
+			nodeset.Spec.ControllerRef.Name = ""
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should admit if all required fields are provided", func(ctx SpecContext) {
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit a Controller which allows the namespace", func(ctx SpecContext) {
+			nodeset.Namespace = "tenant"
+			nodeset.Spec.ControllerRef.Namespace = "slurm"
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a Controller which does not allow the namespace", func(ctx SpecContext) {
+			nodeset.Namespace = "other"
+			nodeset.Spec.ControllerRef.Namespace = "slurm"
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should warn about a shared volume claim which does not exist in the namespace", func(ctx SpecContext) {
+			nodeset.Namespace = "tenant"
+			nodeset.Spec.ControllerRef.Namespace = "slurm"
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			warns, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).To(ContainElement(ContainSubstring("tenant/home")))
+
+			Expect(r.Create(ctx, &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "home", Namespace: "tenant"},
+			})).To(Succeed())
+			warns, err = r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).To(BeEmpty())
+		})
+
+		It("Should deny static node registration with an External Controller", func(ctx SpecContext) {
+			nodeset.Spec.NodeRegistration = slinkyv1alpha1.StaticNodeSetNodeRegistrationType
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			_, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Create(ctx, &slinkyv1alpha1.Controller{
+				ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "slurm"},
+				Spec: slinkyv1alpha1.ControllerSpec{
+					External: &slinkyv1alpha1.ExternalController{Host: "slurmctld.example.com"},
+				},
+			})).To(Succeed())
+			nodeset.Spec.ControllerRef.Name = "external"
+			_, err = r.ValidateCreate(ctx, nodeset)
+			Expect(err).To(HaveOccurred())
+
+			nodeset.Spec.NodeRegistration = slinkyv1alpha1.DynamicNodeSetNodeRegistrationType
+			_, err = r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should not warn about a shared volume claim created from a claim template", func(ctx SpecContext) {
+			controller := &slinkyv1alpha1.Controller{}
+			Expect(r.Get(ctx, client.ObjectKey{Name: "slurm", Namespace: "slurm"}, controller)).To(Succeed())
+			controller.Spec.SharedVolumes = []slinkyv1alpha1.SharedVolume{
+				{Name: "scratch", MountPath: "/scratch", ClaimTemplate: &corev1.PersistentVolumeClaimSpec{}},
+			}
+			Expect(r.Update(ctx, controller)).To(Succeed())
+			nodeset.Namespace = "tenant"
+			nodeset.Spec.ControllerRef.Namespace = "slurm"
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			warns, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).To(BeEmpty())
 		})
 
-		It("Should admit if all required fields are provided", func() {
-			// TODO(user): Add your logic here
+		It("Should warn about a Controller which does not exist", func(ctx SpecContext) {
+			nodeset.Namespace = "tenant"
+			nodeset.Spec.ControllerRef = slinkyv1alpha1.ObjectReference{Name: "missing", Namespace: "slurm"}
+			Expect(r.Default(ctx, nodeset)).To(Succeed())
+			warns, err := r.ValidateCreate(ctx, nodeset)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warns).NotTo(BeEmpty())
 		})
 	})
 })
@@ -5,21 +5,31 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 )
 
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-type RestapiWebhook struct{}
+type RestapiWebhook struct {
+	client.Client
+}
 
 // log is for logging in this package.
 var restapilog = logf.Log.WithName("restapi-resource")
@@ -57,7 +67,7 @@ func (r *RestapiWebhook) ValidateCreate(ctx context.Context, obj runtime.Object)
 	restapi := obj.(*slinkyv1alpha1.RestApi)
 	restapilog.Info("validate create", "restapi", klog.KObj(restapi))
 
-	warns, errs := validateRestapi(restapi)
+	warns, errs := r.validateRestapi(ctx, restapi)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -68,7 +78,7 @@ func (r *RestapiWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Obje
 	_ = oldObj.(*slinkyv1alpha1.RestApi)
 	restapilog.Info("validate update", "newRestapi", klog.KObj(newRestapi))
 
-	warns, errs := validateRestapi(newRestapi)
+	warns, errs := r.validateRestapi(ctx, newRestapi)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -81,9 +91,89 @@ func (r *RestapiWebhook) ValidateDelete(ctx context.Context, obj runtime.Object)
 	return nil, nil
 }
 
-func validateRestapi(obj *slinkyv1alpha1.RestApi) (admission.Warnings, []error) {
+// The username claim is a value of the comma separated `AuthAltParameters`.
+var validUsernameClaimRegex = regexp.MustCompile(`^[^\s,=]+$`)
+
+func (r *RestapiWebhook) validateRestapi(ctx context.Context, obj *slinkyv1alpha1.RestApi) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	if obj.HasOidc() {
+		oidcWarns, oidcErrs := r.validateOidc(ctx, obj)
+		warns = append(warns, oidcWarns...)
+		errs = append(errs, oidcErrs...)
+	}
+
+	if obj.HasTls() && obj.Spec.Tls.Proxy.Image == "" {
+		errs = append(errs, errors.New("Tls.Proxy.Image must be specified"))
+	}
+
+	// The slurmrestd pods are configured with the slurm.conf of the Controller.
+	controller := &slinkyv1alpha1.Controller{}
+	if err := r.Get(ctx, obj.Spec.ControllerRef.NamespacedName(), controller); err != nil {
+		if !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	} else if controller.IsExternal() {
+		errs = append(errs, fmt.Errorf("Controller %s is External, use its External.Slurmrestd instead", klog.KObj(controller)))
+	}
+
+	return warns, errs
+}
+
+func (r *RestapiWebhook) validateOidc(ctx context.Context, obj *slinkyv1alpha1.RestApi) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
 
+	oidc := obj.Spec.Oidc
+	hasJwksUrl := oidc.JwksUrl != ""
+	hasJwks := oidc.Jwks != ""
+	if hasJwksUrl == hasJwks {
+		errs = append(errs, errors.New("exactly one of Oidc.JwksUrl or Oidc.Jwks must be specified"))
+	}
+
+	if hasJwksUrl {
+		jwksUrl, err := url.Parse(oidc.JwksUrl)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("Oidc.JwksUrl is not a valid URL: %w", err))
+		case jwksUrl.Scheme != "https" && jwksUrl.Scheme != "http":
+			errs = append(errs, fmt.Errorf("Oidc.JwksUrl must use the https or http scheme: %s", oidc.JwksUrl))
+		case jwksUrl.Host == "":
+			errs = append(errs, fmt.Errorf("Oidc.JwksUrl has no host: %s", oidc.JwksUrl))
+		case jwksUrl.Scheme == "http":
+			warns = append(warns, fmt.Sprintf("Oidc.JwksUrl does not use https, the JWKS can be tampered with in transit: %s", oidc.JwksUrl))
+		}
+	}
+
+	if hasJwks {
+		if err := builder.ValidateOidcJwks(oidc.Jwks); err != nil {
+			errs = append(errs, fmt.Errorf("Oidc.Jwks is invalid: %w", err))
+		}
+	}
+
+	if oidc.UsernameClaim != "" && !validUsernameClaimRegex.MatchString(oidc.UsernameClaim) {
+		errs = append(errs, fmt.Errorf("Oidc.UsernameClaim must not contain whitespace, commas or equal signs: %q", oidc.UsernameClaim))
+	}
+
+	// Slurm maps a single claim to the username for the whole cluster.
+	restapiList := &slinkyv1alpha1.RestApiList{}
+	if err := r.List(ctx, restapiList); err != nil {
+		errs = append(errs, err)
+		return warns, errs
+	}
+	for _, other := range restapiList.Items {
+		if other.Namespace == obj.Namespace && other.Name == obj.Name {
+			continue
+		}
+		if !other.HasOidc() || !other.Spec.ControllerRef.IsMatch(obj.Spec.ControllerRef.NamespacedName()) {
+			continue
+		}
+		if other.Spec.Oidc.UsernameClaim != oidc.UsernameClaim {
+			errs = append(errs, fmt.Errorf("Oidc.UsernameClaim %q conflicts with %q of RestApi %s, which uses the same Controller",
+				oidc.UsernameClaim, other.Spec.Oidc.UsernameClaim, objectutils.KeyFunc(&other)))
+		}
+	}
+
 	return warns, errs
 }
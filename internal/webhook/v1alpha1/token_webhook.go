@@ -5,11 +5,18 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -19,7 +26,9 @@ import (
 
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-type TokenWebhook struct{}
+type TokenWebhook struct {
+	client.Client
+}
 
 // log is for logging in this package.
 var tokenlog = logf.Log.WithName("token-resource")
@@ -57,7 +66,7 @@ func (r *TokenWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (
 	token := obj.(*slinkyv1alpha1.Token)
 	tokenlog.Info("validate create", "token", klog.KObj(token))
 
-	warns, errs := validateToken(token)
+	warns, errs := r.validateToken(ctx, token)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -68,7 +77,7 @@ func (r *TokenWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object
 	_ = oldObj.(*slinkyv1alpha1.Token)
 	tokenlog.Info("validate update", "newToken", klog.KObj(newToken))
 
-	warns, errs := validateToken(newToken)
+	warns, errs := r.validateToken(ctx, newToken)
 
 	return warns, utilerrors.NewAggregate(errs)
 }
@@ -81,9 +90,38 @@ func (r *TokenWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (
 	return nil, nil
 }
 
-func validateToken(obj *slinkyv1alpha1.Token) (admission.Warnings, []error) {
+func (r *TokenWebhook) validateToken(ctx context.Context, obj *slinkyv1alpha1.Token) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
 
+	hasHs256 := obj.Spec.JwtHs256KeyRef.Name != ""
+	hasRs256 := obj.Spec.JwtRs256KeyRef != nil
+	if hasHs256 == hasRs256 {
+		errs = append(errs, errors.New("exactly one of JwtHs256KeyRef or JwtRs256KeyRef must be specified"))
+	}
+
+	targetKeys := set.New(obj.SecretKey().String())
+	for _, target := range obj.Spec.Targets {
+		key := obj.TargetKey(target)
+		if targetKeys.Has(key.String()) {
+			errs = append(errs, fmt.Errorf("target Secret %s is specified more than once", key))
+		}
+		targetKeys.Insert(key.String())
+
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: key.Namespace}, namespace); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			}
+			warns = append(warns, fmt.Sprintf("the namespace of target Secret %s does not exist", key))
+			continue
+		}
+		if !obj.IsTargetNamespaceAllowed(namespace) {
+			errs = append(errs, fmt.Errorf("the namespace of target Secret %s does not allow namespace %s by the %s annotation",
+				key, obj.Namespace, slinkyv1alpha1.AnnotationTokenSourceNamespaces))
+		}
+	}
+
 	return warns, errs
 }
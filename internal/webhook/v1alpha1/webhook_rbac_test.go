@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	slinkyv1alpha1 "github.com/SlinkyProject/slurm-operator/api/v1alpha1"
+)
+
+const webhookRBACPath = "../../../helm/slurm-operator/templates/webhook/rbac.yaml"
+
+// loadWebhookClusterRole returns the ClusterRole of the webhook in the chart,
+// without the template directives.
+func loadWebhookClusterRole(t *testing.T) *rbacv1.ClusterRole {
+	t.Helper()
+	data, err := os.ReadFile(webhookRBACPath)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error = %v", err)
+	}
+	data = bytes.ReplaceAll(data, []byte(`{{ include "slurm-operator.apiGroup" . }}`), []byte(slinkyv1alpha1.GroupVersion.Group))
+	data = regexp.MustCompile(`(?s)\{\{-? */\*.*?\*/ *-?\}\}`).ReplaceAll(data, nil)
+	data = regexp.MustCompile(`(?m)^.*\{\{.*$\n?`).ReplaceAll(data, nil)
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		role := &rbacv1.ClusterRole{}
+		if err := decoder.Decode(role); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if role.Kind == "ClusterRole" {
+			return role
+		}
+	}
+	t.Fatalf("no ClusterRole in %s", webhookRBACPath)
+	return nil
+}
+
+// allows returns true if the rules allow all the verbs on the resource.
+func allows(rules []rbacv1.PolicyRule, gr schema.GroupResource, verbs ...string) bool {
+	for _, verb := range verbs {
+		allowed := false
+		for _, rule := range rules {
+			if slices.Contains(rule.APIGroups, gr.Group) &&
+				slices.Contains(rule.Resources, gr.Resource) &&
+				slices.Contains(rule.Verbs, verb) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// webhookReads returns the resources read by a client `Get` or `List` in the
+// non-test files of the package, by position.
+func webhookReads(t *testing.T, scheme *runtime.Scheme) map[string]schema.GroupResource {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("filepath.Glob() error = %v", err)
+	}
+
+	// The Go types of the scheme, by package path and type name.
+	kinds := make(map[string]schema.GroupVersionKind)
+	for gvk, typ := range scheme.AllKnownTypes() {
+		kinds[typ.PkgPath()+"."+typ.Name()] = gvk
+	}
+
+	reads := make(map[string]schema.GroupResource)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("parser.ParseFile() error = %v", err)
+		}
+		imports := make(map[string]string)
+		for _, spec := range f.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			name := filepath.Base(path)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = path
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "Get" && sel.Sel.Name != "List") {
+				return true
+			}
+			if ctx, ok := call.Args[0].(*ast.Ident); !ok || ctx.Name != "ctx" {
+				return true
+			}
+			pos := fset.Position(call.Pos()).String()
+			arg := call.Args[1]
+			if sel.Sel.Name == "Get" {
+				if len(call.Args) < 3 {
+					return true
+				}
+				arg = call.Args[2]
+			}
+
+			// The object is declared as `obj := &pkg.Type{}`.
+			ident, ok := arg.(*ast.Ident)
+			if !ok || ident.Obj == nil {
+				t.Errorf("%s: cannot resolve the object of %s()", pos, sel.Sel.Name)
+				return true
+			}
+			assign, ok := ident.Obj.Decl.(*ast.AssignStmt)
+			if !ok || len(assign.Rhs) != 1 {
+				t.Errorf("%s: cannot resolve the declaration of %s", pos, ident.Name)
+				return true
+			}
+			var typ *ast.SelectorExpr
+			if unary, ok := assign.Rhs[0].(*ast.UnaryExpr); ok {
+				if lit, ok := unary.X.(*ast.CompositeLit); ok {
+					typ, _ = lit.Type.(*ast.SelectorExpr)
+				}
+			}
+			if typ == nil {
+				t.Errorf("%s: cannot resolve the type of %s", pos, ident.Name)
+				return true
+			}
+			pkg, _ := typ.X.(*ast.Ident)
+			if pkg == nil {
+				t.Errorf("%s: cannot resolve the package of %s", pos, ident.Name)
+				return true
+			}
+			gvk, ok := kinds[imports[pkg.Name]+"."+typ.Sel.Name]
+			if !ok {
+				t.Errorf("%s: %s.%s is not in the scheme", pos, pkg.Name, typ.Sel.Name)
+				return true
+			}
+			gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+			gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+			reads[pos] = gvr.GroupResource()
+			return true
+		})
+	}
+	return reads
+}
+
+// Test_webhookRBAC checks that the ClusterRole of the webhook in the chart
+// allows every read of its cached client, as the envtest suite runs as admin.
+func Test_webhookRBAC(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	if err := slinkyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+
+	role := loadWebhookClusterRole(t)
+	reads := webhookReads(t, scheme)
+	if len(reads) == 0 {
+		t.Fatal("no client reads found")
+	}
+	for pos, gr := range reads {
+		// The cached client lists and watches the resource.
+		if !allows(role.Rules, gr, "get", "list", "watch") {
+			t.Errorf("%s: the webhook ClusterRole does not allow get, list, and watch on %s", pos, gr)
+		}
+	}
+}
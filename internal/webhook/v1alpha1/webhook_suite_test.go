@@ -101,10 +101,19 @@ var _ = BeforeSuite(func() {
 	}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&NodeSetWebhook{}).SetupWebhookWithManager(mgr)
+	err = (&NodeSetWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = (&LoginSetWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&LoginSetWebhook{}).SetupWebhookWithManager(mgr)
+	err = (&FederationWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:webhook